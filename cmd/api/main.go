@@ -1,50 +1,113 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
+	"cushon/internal/clock"
+	"cushon/internal/config"
 	"cushon/internal/handler"
 	"cushon/internal/middleware"
+	"cushon/internal/migrate"
 	"cushon/internal/repository"
+	"cushon/internal/repository/postgres"
+	"cushon/internal/scheduler"
 	"cushon/internal/service"
 
 	"github.com/gorilla/mux"
 )
 
 func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run starts the server and returns when it stops. Errors are returned rather than logged fatally so the
+// deferred clean-up, such as closing the database, still happens.
+func run() error {
 	// Check if certificates exist
 	certPath := "certs/server.crt"
 	keyPath := "certs/server.key"
 	if _, err := os.Stat(certPath); os.IsNotExist(err) {
-		log.Fatal("SSL certificate not found at:", certPath)
+		return fmt.Errorf("SSL certificate not found at: %s", certPath)
 	}
 	if _, err := os.Stat(keyPath); os.IsNotExist(err) {
-		log.Fatal("SSL key not found at:", keyPath)
+		return fmt.Errorf("SSL key not found at: %s", keyPath)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
 	// Initialize repositories
-	customerRepo := repository.NewInMemoryCustomerRepository()
-	fundRepo := repository.NewInMemoryFundRepository()
-	investmentRepo := repository.NewInMemoryInvestmentRepository()
-	employerRepo := repository.NewInMemoryEmployerRepository()
-	apiKeyRepo := repository.NewInMemoryAPIKeyRepository()
+	var repos *repositories
+	switch cfg.Storage {
+	case config.StoragePostgres:
+		db, err := postgres.Open(cfg.DatabaseURL)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
 
-	// Add a test API key
-	apiKeyRepo.AddKey("test-api-key")
+		if cfg.VerifySchema {
+			if err := verifySchema(db); err != nil {
+				return fmt.Errorf("database schema check failed: %w", err)
+			}
+		}
+
+		repos = newPostgresRepositories(db)
+	default:
+		repos = newInMemoryRepositories()
+	}
 
 	// Initialize services
-	customerService := service.NewDefaultCustomerService(customerRepo)
-	fundService := service.NewDefaultFundService(fundRepo)
-	investmentService := service.NewDefaultInvestmentService(investmentRepo)
-	employerService := service.NewDefaultEmployerService(employerRepo)
+	customerService := service.NewDefaultCustomerService(repos.customers, repos.employers, repos.glidePaths)
+	fundService := service.NewDefaultFundService(repos.funds, repos.fundPrices)
+	investmentService := service.NewDefaultInvestmentService(repos.investments, repos.customers, repos.employers, repos.allocations, repos.funds, repos.fundPrices)
+	employerService := service.NewDefaultEmployerService(repos.employers, repos.customers, repos.funds)
+	allocationService := service.NewDefaultAllocationService(repos.allocations, repos.customers, repos.employers, repos.funds)
+	portfolioService := service.NewDefaultPortfolioService(repos.customers, repos.investments, repos.funds, repos.fundPrices)
+	dealingService := service.NewDefaultDealingService(repos.investments, repos.fundPrices, repos.dealing, clock.System{}, cfg.DealingCutOff)
+	rebalancingService := service.NewDefaultRebalancingService(repos.investments, repos.customers, repos.employers, repos.allocations, repos.funds, repos.fundPrices, cfg.RebalancePolicy, clock.System{}, cfg.RebalanceTime)
+	lifestylingService := service.NewDefaultLifestylingService(repos.glidePaths, repos.customers, repos.allocations, repos.funds, rebalancingService, clock.System{}, cfg.RebalanceTime)
+	returnsService := service.NewDefaultReturnsService(repos.customers, repos.investments, repos.funds, repos.fundPrices, clock.System{})
+	scheduleService := service.NewDefaultScheduleService(repos.schedules, repos.customers, repos.employers, repos.allocations, repos.funds, investmentService, clock.System{})
 
 	// Initialize handlers
 	customerHandler := handler.NewCustomerHandler(customerService)
 	fundHandler := handler.NewFundHandler(fundService)
 	investmentHandler := handler.NewInvestmentHandler(investmentService)
 	employerHandler := handler.NewEmployerHandler(employerService)
+	portfolioHandler := handler.NewPortfolioHandler(portfolioService)
+	dealingHandler := handler.NewDealingHandler(dealingService)
+	allocationHandler := handler.NewAllocationHandler(allocationService)
+	rebalanceHandler := handler.NewRebalanceHandler(rebalancingService)
+	glidePathHandler := handler.NewGlidePathHandler(lifestylingService)
+	scheduleHandler := handler.NewScheduleHandler(scheduleService)
+	returnsHandler := handler.NewReturnsHandler(returnsService)
+
+	// Run background jobs in the server process
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	jobs := scheduler.New(clock.System{}, cfg.SchedulerInterval)
+	// Scheduled contributions are paid in before dealing so they are dealt the same day
+	jobs.Add("contribution schedules", scheduleService.RunDue)
+	jobs.Add("dealing", dealingService.RunDue)
+	// Customers are moved along their glide paths before everyone else is rebalanced
+	jobs.Add("glide paths", lifestylingService.RunDue)
+	jobs.Add("rebalancing", rebalancingService.RunDue)
+	jobs.Add("idempotency key expiry", func(now time.Time) error {
+		_, err := repos.idempotency.DeleteExpiredIdempotencyKeys(now)
+		return err
+	})
+	go jobs.Run(ctx)
 
 	// Create router
 	router := mux.NewRouter()
@@ -54,27 +117,146 @@ func main() {
 
 	// Create authenticated subrouter for all other endpoints
 	api := router.PathPrefix("/api").Subrouter()
-	api.Use(middleware.NewAuthMiddleware(apiKeyRepo))
+	api.Use(middleware.NewAuthMiddleware(repos.apiKeys))
+	api.Use(middleware.NewIdempotencyMiddleware(repos.idempotency, clock.System{}, cfg.IdempotencyTTL))
 
 	// Customer routes
 	api.HandleFunc("/customers", customerHandler.Create).Methods("POST")
+	api.HandleFunc("/customers", customerHandler.GetAll).Methods("GET")
+	api.HandleFunc("/customers/{id}", customerHandler.Get).Methods("GET")
+	api.HandleFunc("/customers/{id}", customerHandler.Update).Methods("PATCH")
+	api.HandleFunc("/customers/{id}", customerHandler.Delete).Methods("DELETE")
+	api.HandleFunc("/customers/{id}/portfolio", portfolioHandler.Get).Methods("GET")
+	api.HandleFunc("/customers/{id}/returns", returnsHandler.GetCustomerReturns).Methods("GET")
+	api.HandleFunc("/customers/{id}/isa-allowance", investmentHandler.GetISAAllowance).Methods("GET")
+	api.HandleFunc("/customers/{id}/allocation", allocationHandler.Get).Methods("GET")
+	api.HandleFunc("/customers/{id}/allocation", allocationHandler.Update).Methods("PUT")
+	api.HandleFunc("/customers/{id}/allocation", allocationHandler.Delete).Methods("DELETE")
+	api.HandleFunc("/customers/{id}/rebalance", rebalanceHandler.Preview).Methods("GET")
+	api.HandleFunc("/customers/{id}/rebalance", rebalanceHandler.Execute).Methods("POST")
+	api.HandleFunc("/customers/{id}/schedules", scheduleHandler.Create).Methods("POST")
+	api.HandleFunc("/customers/{id}/schedules", scheduleHandler.GetByCustomer).Methods("GET")
 
 	// Fund routes
 	api.HandleFunc("/funds", fundHandler.Create).Methods("POST")
 	api.HandleFunc("/funds", fundHandler.GetAll).Methods("GET")
+	api.HandleFunc("/funds/{id}", fundHandler.Get).Methods("GET")
+	api.HandleFunc("/funds/{id}", fundHandler.Update).Methods("PATCH")
+	api.HandleFunc("/funds/{id}/close", fundHandler.Close).Methods("POST")
+	api.HandleFunc("/funds/{id}/prices", fundHandler.AddPrice).Methods("POST")
+	api.HandleFunc("/funds/{id}/prices", fundHandler.GetPrices).Methods("GET")
+	api.HandleFunc("/funds/{id}/prices/latest", fundHandler.GetLatestPrice).Methods("GET")
+	api.HandleFunc("/funds/{id}/returns", returnsHandler.GetFundReturns).Methods("GET")
 
 	// Investment routes
 	api.HandleFunc("/investments", investmentHandler.Create).Methods("POST")
 	api.HandleFunc("/investments/{id}", investmentHandler.Get).Methods("GET")
 	api.HandleFunc("/investments", investmentHandler.GetAll).Methods("GET")
+	api.HandleFunc("/investments/{id}", investmentHandler.Update).Methods("PATCH")
+	api.HandleFunc("/investments/{id}/cancel", investmentHandler.Cancel).Methods("POST")
+	api.HandleFunc("/salary-contributions", investmentHandler.SalaryContribution).Methods("POST")
+	api.HandleFunc("/contributions", investmentHandler.Contribute).Methods("POST")
+	api.HandleFunc("/withdrawals", investmentHandler.Withdraw).Methods("POST")
+	api.HandleFunc("/switches", investmentHandler.Switch).Methods("POST")
+	api.HandleFunc("/switches/{id}", investmentHandler.GetSwitch).Methods("GET")
+
+	// Dealing routes
+	api.HandleFunc("/dealing/runs", dealingHandler.Run).Methods("POST")
+	api.HandleFunc("/dealing/batches", dealingHandler.GetAll).Methods("GET")
+	api.HandleFunc("/dealing/batches/{id}", dealingHandler.Get).Methods("GET")
+
+	// Rebalancing routes
+	api.HandleFunc("/rebalancing/runs", rebalanceHandler.Run).Methods("POST")
+
+	// Glide path routes
+	api.HandleFunc("/glide-paths", glidePathHandler.Create).Methods("POST")
+	api.HandleFunc("/glide-paths", glidePathHandler.GetAll).Methods("GET")
+	api.HandleFunc("/glide-paths/{id}", glidePathHandler.Get).Methods("GET")
+	api.HandleFunc("/glide-paths/runs", glidePathHandler.Run).Methods("POST")
+
+	// Contribution schedule routes
+	api.HandleFunc("/schedules/runs", scheduleHandler.Run).Methods("POST")
+	api.HandleFunc("/schedules/{id}", scheduleHandler.Get).Methods("GET")
+	api.HandleFunc("/schedules/{id}/pause", scheduleHandler.Pause).Methods("POST")
+	api.HandleFunc("/schedules/{id}/resume", scheduleHandler.Resume).Methods("POST")
 
 	// Employer routes
 	api.HandleFunc("/employers", employerHandler.Create).Methods("POST")
+	api.HandleFunc("/employers", employerHandler.GetAll).Methods("GET")
+	api.HandleFunc("/employers/{id}", employerHandler.Get).Methods("GET")
+	api.HandleFunc("/employers/{id}", employerHandler.Update).Methods("PATCH")
+	api.HandleFunc("/employers/{id}/deactivate", employerHandler.Deactivate).Methods("POST")
+	api.HandleFunc("/employers/{id}/customers", employerHandler.GetCustomers).Methods("GET")
+	api.HandleFunc("/employers/{id}/payroll", investmentHandler.ImportPayroll).Methods("POST")
 
 	// Start server
-	log.Println("Starting server on :8443")
-	err := http.ListenAndServeTLS(":8443", certPath, keyPath, router)
+	log.Printf("Starting server on :8443 using %s storage, dealing at %s UTC, glide paths and rebalancing at %s UTC", cfg.Storage, cfg.DealingCutOff, cfg.RebalanceTime)
+	return http.ListenAndServeTLS(":8443", certPath, keyPath, router)
+}
+
+// repositories groups the storage implementations used by the services
+type repositories struct {
+	customers   repository.CustomerRepository
+	funds       repository.FundRepository
+	fundPrices  repository.FundPriceRepository
+	investments repository.InvestmentRepository
+	dealing     repository.DealingRepository
+	employers   repository.EmployerRepository
+	allocations repository.AllocationRepository
+	glidePaths  repository.GlidePathRepository
+	schedules   repository.ScheduleRepository
+	idempotency repository.IdempotencyRepository
+	apiKeys     repository.APIKeyRepository
+}
+
+// newInMemoryRepositories creates repositories that keep all data in memory
+func newInMemoryRepositories() *repositories {
+	apiKeyRepo := repository.NewInMemoryAPIKeyRepository()
+
+	// Add a test API key. It is only ever kept in memory, so it can't be left behind in a real deployment.
+	apiKeyRepo.AddKey("test-api-key")
+
+	investmentRepo := repository.NewInMemoryInvestmentRepository()
+
+	return &repositories{
+		customers:   repository.NewInMemoryCustomerRepository(investmentRepo),
+		funds:       repository.NewInMemoryFundRepository(),
+		fundPrices:  repository.NewInMemoryFundPriceRepository(),
+		investments: investmentRepo,
+		dealing:     repository.NewInMemoryDealingRepository(investmentRepo),
+		employers:   repository.NewInMemoryEmployerRepository(),
+		allocations: repository.NewInMemoryAllocationRepository(),
+		glidePaths:  repository.NewInMemoryGlidePathRepository(),
+		schedules:   repository.NewInMemoryScheduleRepository(),
+		idempotency: repository.NewInMemoryIdempotencyRepository(),
+		apiKeys:     apiKeyRepo,
+	}
+}
+
+// verifySchema checks that the database schema is at the version this build expects
+func verifySchema(db *sql.DB) error {
+	migrator, err := migrate.New(db)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	return migrator.Verify()
+}
+
+// newPostgresRepositories creates repositories backed by a PostgreSQL database.
+// The schema must have been created beforehand with the migrate command, and API keys added with the apikey
+// command.
+func newPostgresRepositories(db *sql.DB) *repositories {
+	return &repositories{
+		customers:   postgres.NewCustomerRepository(db),
+		funds:       postgres.NewFundRepository(db),
+		fundPrices:  postgres.NewFundPriceRepository(db),
+		investments: postgres.NewInvestmentRepository(db),
+		dealing:     postgres.NewDealingRepository(db),
+		employers:   postgres.NewEmployerRepository(db),
+		allocations: postgres.NewAllocationRepository(db),
+		glidePaths:  postgres.NewGlidePathRepository(db),
+		schedules:   postgres.NewScheduleRepository(db),
+		idempotency: postgres.NewIdempotencyRepository(db),
+		apiKeys:     postgres.NewAPIKeyRepository(db),
 	}
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"cushon/internal/repository/postgres"
+)
+
+const usage = `Usage: apikey [-database-url URL] add
+
+Commands:
+  add    read an API key from standard input and allow it to call the API
+
+The key is read from standard input so it doesn't end up in the shell history or the process list.
+The database URL defaults to the CUSHON_DATABASE_URL environment variable.
+`
+
+func main() {
+	databaseURL := flag.String("database-url", os.Getenv("CUSHON_DATABASE_URL"), "PostgreSQL connection string")
+	flag.Usage = func() { fmt.Fprint(flag.CommandLine.Output(), usage) }
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 1 || args[0] != "add" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *databaseURL == "" {
+		log.Fatal("a database URL is required, set -database-url or CUSHON_DATABASE_URL")
+	}
+
+	key, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && key == "" {
+		log.Fatal("reading the API key: ", err)
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		log.Fatal("the API key can't be empty")
+	}
+
+	db, err := postgres.Open(*databaseURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := postgres.NewAPIKeyRepository(db).AddKey(key); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("API key added")
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"cushon/internal/migrate"
+	"cushon/internal/repository/postgres"
+)
+
+const usage = `Usage: migrate [-database-url URL] <command>
+
+Commands:
+  up              apply every pending migration
+  down            revert the latest applied migration
+  status          show the applied and pending migrations
+  to <version>    migrate up or down to the given version (0 reverts everything)
+  force <version> mark the given version as applied and clean after fixing a failed migration by hand
+
+The database URL defaults to the CUSHON_DATABASE_URL environment variable.
+`
+
+func main() {
+	databaseURL := flag.String("database-url", os.Getenv("CUSHON_DATABASE_URL"), "PostgreSQL connection string")
+	flag.Usage = func() { fmt.Fprint(flag.CommandLine.Output(), usage) }
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *databaseURL == "" {
+		log.Fatal("a database URL is required, set -database-url or CUSHON_DATABASE_URL")
+	}
+
+	db, err := postgres.Open(*databaseURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down()
+	case "to", "force":
+		var version uint64
+		if len(args) != 2 {
+			log.Fatalf("%s requires a version", args[0])
+		}
+		version, err = strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			log.Fatalf("invalid version %q", args[1])
+		}
+		if args[0] == "to" {
+			err = migrator.To(uint(version))
+		} else {
+			err = migrator.Force(uint(version))
+		}
+	case "status":
+		err = printStatus(migrator)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if args[0] != "status" {
+		if err := printStatus(migrator); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// printStatus writes the current version and the pending migrations to stdout
+func printStatus(migrator *migrate.Migrator) error {
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	state := "clean"
+	if status.Dirty {
+		state = "dirty"
+	}
+	fmt.Printf("version: %d (%s)\n", status.Version, state)
+	for _, migration := range status.Pending {
+		fmt.Printf("pending: %04d_%s\n", migration.Version, migration.Name)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"cushon/internal/model"
+)
+
+const usage = `Usage: payroll [-url URL] [-api-key KEY] [-idempotency-key KEY] [-insecure] <employer id> <file.csv>
+
+Imports an employer's CSV payroll file through the API. Every contribution in the file is invested, or none
+of them are and the rows that can't be imported are listed.
+
+The URL and API key default to the CUSHON_API_URL and CUSHON_API_KEY environment variables.
+`
+
+func main() {
+	apiURL := flag.String("url", envOr("CUSHON_API_URL", "https://localhost:8443"), "base URL of the API")
+	apiKey := flag.String("api-key", os.Getenv("CUSHON_API_KEY"), "API key to authenticate with")
+	idempotencyKey := flag.String("idempotency-key", "", "key that makes retrying the import safe")
+	insecure := flag.Bool("insecure", false, "skip TLS certificate verification, e.g. for a self-signed certificate")
+	flag.Usage = func() { fmt.Fprint(flag.CommandLine.Output(), usage) }
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	employerID, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		log.Fatalf("invalid employer ID %q", args[0])
+	}
+	if *apiKey == "" {
+		log.Fatal("an API key is required, set -api-key or CUSHON_API_KEY")
+	}
+
+	file, err := os.Open(args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/employers/%d/payroll", strings.TrimSuffix(*apiURL, "/"), employerID), file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("X-API-Key", *apiKey)
+	if *idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", *idempotencyKey)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	if *insecure {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if err := report(os.Stdout, resp); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// report writes the outcome of an import, returning an error when the file wasn't imported
+func report(w io.Writer, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		var imported model.PayrollImportResponse
+		if err := json.Unmarshal(body, &imported); err != nil {
+			return fmt.Errorf("unexpected response: %w", err)
+		}
+		fmt.Fprintf(w, "imported %d contributions totalling %s for employer %d\n", imported.Contributions, imported.Total, imported.EmployerID)
+		return nil
+	case http.StatusUnprocessableEntity:
+		var rejected model.PayrollErrorResponse
+		if err := json.Unmarshal(body, &rejected); err == nil && len(rejected.Rows) > 0 {
+			for _, row := range rejected.Rows {
+				fmt.Fprintf(w, "line %d: %s\n", row.Line, row.Error)
+			}
+			return fmt.Errorf("nothing was imported: %s", rejected.Error)
+		}
+	}
+	return fmt.Errorf("nothing was imported: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// envOr returns the value of an environment variable, or fallback when it isn't set
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,17 @@
+// Package clock lets code that depends on the current time be tested with a time of the test's choosing.
+package clock
+
+import "time"
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the Clock of the machine the server runs on
+type System struct{}
+
+// Now returns the current time in UTC
+func (System) Now() time.Time {
+	return time.Now().UTC()
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"cushon/internal/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr error
+	}{
+		{
+			name: "Defaults to in-memory storage",
+			env:  map[string]string{},
+			want: Config{
+				Storage:           StorageMemory,
+				DealingCutOff:     DefaultDealingCutOff,
+				SchedulerInterval: DefaultSchedulerInterval,
+				IdempotencyTTL:    DefaultIdempotencyTTL,
+				RebalanceTime:     DefaultRebalanceTime,
+				RebalancePolicy:   DefaultRebalancePolicy,
+			},
+		},
+		{
+			name: "Postgres storage",
+			env: map[string]string{
+				"CUSHON_STORAGE":      "postgres",
+				"CUSHON_DATABASE_URL": "postgres://localhost/cushon",
+			},
+			want: Config{
+				Storage:           StoragePostgres,
+				DatabaseURL:       "postgres://localhost/cushon",
+				DealingCutOff:     DefaultDealingCutOff,
+				SchedulerInterval: DefaultSchedulerInterval,
+				IdempotencyTTL:    DefaultIdempotencyTTL,
+				RebalanceTime:     DefaultRebalanceTime,
+				RebalancePolicy:   DefaultRebalancePolicy,
+			},
+		},
+		{
+			name: "Postgres storage with schema verification",
+			env: map[string]string{
+				"CUSHON_STORAGE":       "postgres",
+				"CUSHON_DATABASE_URL":  "postgres://localhost/cushon",
+				"CUSHON_VERIFY_SCHEMA": "true",
+			},
+			want: Config{
+				Storage:           StoragePostgres,
+				DatabaseURL:       "postgres://localhost/cushon",
+				VerifySchema:      true,
+				DealingCutOff:     DefaultDealingCutOff,
+				SchedulerInterval: DefaultSchedulerInterval,
+				IdempotencyTTL:    DefaultIdempotencyTTL,
+				RebalanceTime:     DefaultRebalanceTime,
+				RebalancePolicy:   DefaultRebalancePolicy,
+			},
+		},
+		{
+			name: "Dealing cut-off and scheduler interval",
+			env: map[string]string{
+				"CUSHON_DEALING_CUTOFF":     "15:30",
+				"CUSHON_SCHEDULER_INTERVAL": "30s",
+			},
+			want: Config{
+				Storage:           StorageMemory,
+				DealingCutOff:     model.TimeOfDay{Hour: 15, Minute: 30},
+				SchedulerInterval: 30 * time.Second,
+				IdempotencyTTL:    DefaultIdempotencyTTL,
+				RebalanceTime:     DefaultRebalanceTime,
+				RebalancePolicy:   DefaultRebalancePolicy,
+			},
+		},
+		{
+			name: "Idempotency key expiry",
+			env:  map[string]string{"CUSHON_IDEMPOTENCY_TTL": "48h"},
+			want: Config{
+				Storage:           StorageMemory,
+				DealingCutOff:     DefaultDealingCutOff,
+				SchedulerInterval: DefaultSchedulerInterval,
+				IdempotencyTTL:    48 * time.Hour,
+				RebalanceTime:     DefaultRebalanceTime,
+				RebalancePolicy:   DefaultRebalancePolicy,
+			},
+		},
+		{
+			name: "Rebalancing time and policy",
+			env: map[string]string{
+				"CUSHON_REBALANCE_TIME":      "07:45",
+				"CUSHON_REBALANCE_TOLERANCE": "2.5",
+				"CUSHON_REBALANCE_MIN_TRADE": "100.00",
+			},
+			want: Config{
+				Storage:           StorageMemory,
+				DealingCutOff:     DefaultDealingCutOff,
+				SchedulerInterval: DefaultSchedulerInterval,
+				IdempotencyTTL:    DefaultIdempotencyTTL,
+				RebalanceTime:     model.TimeOfDay{Hour: 7, Minute: 45},
+				RebalancePolicy:   model.RebalancePolicy{Tolerance: 2500, MinimumTrade: model.NewMoney(10000, model.DefaultCurrency)},
+			},
+		},
+		{
+			name:    "Invalid rebalance time",
+			env:     map[string]string{"CUSHON_REBALANCE_TIME": "25:00"},
+			wantErr: errors.New(`invalid CUSHON_REBALANCE_TIME: invalid time of day "25:00": must be written as HH:MM`),
+		},
+		{
+			name:    "Rebalance tolerance over 100",
+			env:     map[string]string{"CUSHON_REBALANCE_TOLERANCE": "150"},
+			wantErr: errors.New(`invalid CUSHON_REBALANCE_TOLERANCE "150": must be between 0 and 100`),
+		},
+		{
+			name:    "Non-positive minimum rebalance trade",
+			env:     map[string]string{"CUSHON_REBALANCE_MIN_TRADE": "0"},
+			wantErr: errors.New(`invalid CUSHON_REBALANCE_MIN_TRADE "0": must be greater than 0`),
+		},
+		{
+			name:    "Invalid idempotency key expiry",
+			env:     map[string]string{"CUSHON_IDEMPOTENCY_TTL": "-1h"},
+			wantErr: errors.New(`invalid CUSHON_IDEMPOTENCY_TTL "-1h": must be positive`),
+		},
+		{
+			name:    "Invalid dealing cut-off",
+			env:     map[string]string{"CUSHON_DEALING_CUTOFF": "noon"},
+			wantErr: errors.New(`invalid CUSHON_DEALING_CUTOFF: invalid time of day "noon": must be written as HH:MM`),
+		},
+		{
+			name:    "Invalid scheduler interval",
+			env:     map[string]string{"CUSHON_SCHEDULER_INTERVAL": "often"},
+			wantErr: errors.New(`invalid CUSHON_SCHEDULER_INTERVAL "often": time: invalid duration "often"`),
+		},
+		{
+			name:    "Non-positive scheduler interval",
+			env:     map[string]string{"CUSHON_SCHEDULER_INTERVAL": "0s"},
+			wantErr: errors.New(`invalid CUSHON_SCHEDULER_INTERVAL "0s": must be positive`),
+		},
+		{
+			name:    "Invalid schema verification flag",
+			env:     map[string]string{"CUSHON_VERIFY_SCHEMA": "sometimes"},
+			wantErr: errors.New(`invalid CUSHON_VERIFY_SCHEMA "sometimes": strconv.ParseBool: parsing "sometimes": invalid syntax`),
+		},
+		{
+			name:    "Postgres storage without database URL",
+			env:     map[string]string{"CUSHON_STORAGE": "postgres"},
+			wantErr: errors.New("CUSHON_DATABASE_URL is required when CUSHON_STORAGE is postgres"),
+		},
+		{
+			name:    "Unknown storage",
+			env:     map[string]string{"CUSHON_STORAGE": "mysql"},
+			wantErr: errors.New(`unknown CUSHON_STORAGE "mysql", must be "memory" or "postgres"`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := load(func(key string) string { return tt.env[key] })
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Errorf("load() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("load() unexpected error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("load() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// AllocationHandler handles HTTP requests for customers' target allocations
+type AllocationHandler struct {
+	allocationService service.Allocation
+}
+
+// NewAllocationHandler creates a new allocation handler
+func NewAllocationHandler(allocationService service.Allocation) *AllocationHandler {
+	return &AllocationHandler{
+		allocationService: allocationService,
+	}
+}
+
+// Get handles retrieving the allocation a customer's contributions are invested by
+func (h *AllocationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	allocation, err := h.allocationService.GetAllocation(uint(id))
+	if err != nil {
+		writeAllocationError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newTargetAllocationResponse(allocation))
+}
+
+// Update handles replacing a customer's target allocation
+func (h *AllocationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var updateRequest model.TargetAllocationUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updateRequest); err != nil {
+		if errors.Is(err, model.ErrInvalidPercent) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	allocation, err := h.allocationService.SetAllocation(uint(id), updateRequest)
+	if err != nil {
+		if errors.Is(err, service.ErrFundNotFound) || errors.Is(err, service.ErrFundNotOpen) {
+			// The request is well formed but names a fund that can't be invested in
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		writeAllocationError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newTargetAllocationResponse(allocation))
+}
+
+// Delete handles removing a customer's target allocation
+func (h *AllocationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.allocationService.DeleteAllocation(uint(id)); err != nil {
+		writeAllocationError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeAllocationError responds with 404 when the customer or their allocation doesn't exist and with
+// fallbackStatus otherwise
+func writeAllocationError(w http.ResponseWriter, err error, fallbackStatus int) {
+	if errors.Is(err, service.ErrCustomerNotFound) || errors.Is(err, service.ErrAllocationNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), fallbackStatus)
+}
+
+// newTargetAllocationResponse converts a target allocation into its API representation
+func newTargetAllocationResponse(allocation *model.TargetAllocation) model.TargetAllocationResponse {
+	response := model.TargetAllocationResponse{
+		CustomerID:      allocation.CustomerID,
+		Funds:           allocation.Funds,
+		EmployerDefault: allocation.EmployerDefault,
+	}
+	if !allocation.UpdatedAt.IsZero() {
+		response.UpdatedAt = &allocation.UpdatedAt
+	}
+	return response
+}
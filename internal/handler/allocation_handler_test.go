@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+	"cushon/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+func TestAllocationHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		customerID     string
+		mockAllocation *model.TargetAllocation
+		mockErr        error
+		expectedStatus int
+		expectedBody   model.TargetAllocationResponse
+	}{
+		{
+			name:       "Chosen allocation",
+			customerID: "1",
+			mockAllocation: &model.TargetAllocation{
+				CustomerID: 1,
+				Funds:      []model.FundAllocation{{FundID: 1, Percentage: 60000}, {FundID: 3, Percentage: 40000}},
+				UpdatedAt:  time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: model.TargetAllocationResponse{
+				CustomerID: 1,
+				Funds:      []model.FundAllocation{{FundID: 1, Percentage: 60000}, {FundID: 3, Percentage: 40000}},
+			},
+		},
+		{
+			name:       "Employer default fund",
+			customerID: "2",
+			mockAllocation: &model.TargetAllocation{
+				CustomerID:      2,
+				Funds:           []model.FundAllocation{{FundID: 2, Percentage: model.OneHundredPercent}},
+				EmployerDefault: true,
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: model.TargetAllocationResponse{
+				CustomerID:      2,
+				Funds:           []model.FundAllocation{{FundID: 2, Percentage: model.OneHundredPercent}},
+				EmployerDefault: true,
+			},
+		},
+		{
+			name:           "No allocation",
+			customerID:     "3",
+			mockErr:        service.ErrAllocationNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Customer not found",
+			customerID:     "99",
+			mockErr:        service.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid customer ID",
+			customerID:     "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAllocationHandler(&mocks.AllocationService{MockAllocation: tt.mockAllocation, MockErr: tt.mockErr})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/customers/{id}/allocation", handler.Get).Methods("GET")
+
+			req := httptest.NewRequest("GET", "/customers/"+tt.customerID+"/allocation", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response model.TargetAllocationResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Could not decode response: %v", err)
+			}
+			if fmt.Sprint(response.Funds) != fmt.Sprint(tt.expectedBody.Funds) || response.EmployerDefault != tt.expectedBody.EmployerDefault {
+				t.Errorf("handler returned wrong allocation: got %+v want %+v", response, tt.expectedBody)
+			}
+			if (response.UpdatedAt == nil) != tt.mockAllocation.UpdatedAt.IsZero() {
+				t.Errorf("UpdatedAt = %v, want it only for a chosen allocation", response.UpdatedAt)
+			}
+		})
+	}
+}
+
+func TestAllocationHandler_Update(t *testing.T) {
+	allocation := &model.TargetAllocation{
+		CustomerID: 1,
+		Funds:      []model.FundAllocation{{FundID: 1, Percentage: 60000}, {FundID: 3, Percentage: 40000}},
+		UpdatedAt:  time.Now(),
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Set allocation",
+			body:           `{"funds":[{"fund_id":1,"percentage":"60"},{"fund_id":3,"percentage":"40"}]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid percentage",
+			body:           `{"funds":[{"fund_id":1,"percentage":"sixty"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Percentages don't add up",
+			body:           `{"funds":[{"fund_id":1,"percentage":"60"}]}`,
+			mockErr:        fmt.Errorf("%w: percentages add up to 60, not 100", service.ErrInvalidAllocation),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Fund closed",
+			body:           `{"funds":[{"fund_id":2,"percentage":"100"}]}`,
+			mockErr:        service.ErrFundNotOpen,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Fund not found",
+			body:           `{"funds":[{"fund_id":99,"percentage":"100"}]}`,
+			mockErr:        service.ErrFundNotFound,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Customer not found",
+			body:           `{"funds":[{"fund_id":1,"percentage":"100"}]}`,
+			mockErr:        service.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAllocationHandler(&mocks.AllocationService{MockAllocation: allocation, MockErr: tt.mockErr})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/customers/{id}/allocation", handler.Update).Methods("PUT")
+
+			req := httptest.NewRequest("PUT", "/customers/1/allocation", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response model.TargetAllocationResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Could not decode response: %v", err)
+			}
+			if len(response.Funds) != 2 || response.Funds[0].Percentage != 60000 || response.UpdatedAt == nil {
+				t.Errorf("handler returned wrong allocation: got %+v", response)
+			}
+		})
+	}
+}
+
+func TestAllocationHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		customerID     string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Delete allocation",
+			customerID:     "1",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "No allocation",
+			customerID:     "1",
+			mockErr:        service.ErrAllocationNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid customer ID",
+			customerID:     "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAllocationHandler(&mocks.AllocationService{MockErr: tt.mockErr})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/customers/{id}/allocation", handler.Delete).Methods("DELETE")
+
+			req := httptest.NewRequest("DELETE", "/customers/"+tt.customerID+"/allocation", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
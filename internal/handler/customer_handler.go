@@ -4,7 +4,11 @@ import (
 	"cushon/internal/model"
 	"cushon/internal/service"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
 )
 
 // CustomerHandler handles customer-related HTTP requests
@@ -39,17 +43,153 @@ func (h *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
+		if errors.Is(err, service.ErrEmployerNotFound) || errors.Is(err, service.ErrEmployerInactive) {
+			// The request is well formed but links the customer to an employer we don't know or that has left the scheme
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	response := model.CustomerResponse{
-		ID:         customer.ID,
-		Name:       customer.Name,
-		EmployerID: customer.EmployerID,
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newCustomerResponse(customer))
+}
+
+// Get handles retrieving a customer
+func (h *CustomerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	customer, err := h.customerService.GetCustomer(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrCustomerNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newCustomerResponse(customer))
+}
+
+// GetAll handles listing customers, optionally filtered with the employer_id or retail query parameters
+func (h *CustomerHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	var filter model.CustomerFilter
+
+	if employerIDStr := r.URL.Query().Get("employer_id"); employerIDStr != "" {
+		employerID, err := strconv.ParseUint(employerIDStr, 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid employer ID", http.StatusBadRequest)
+			return
+		}
+		id := uint(employerID)
+		filter.EmployerID = &id
+	}
+
+	if retailStr := r.URL.Query().Get("retail"); retailStr != "" {
+		retail, err := strconv.ParseBool(retailStr)
+		if err != nil {
+			http.Error(w, "Invalid retail filter", http.StatusBadRequest)
+			return
+		}
+		filter.RetailOnly = retail
+	}
+
+	if filter.RetailOnly && filter.EmployerID != nil {
+		http.Error(w, "employer_id and retail filters cannot be combined", http.StatusBadRequest)
+		return
+	}
+
+	customers, err := h.customerService.ListCustomers(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]model.CustomerResponse, len(customers))
+	for i, customer := range customers {
+		response[i] = newCustomerResponse(customer)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// Update handles partial customer updates, e.g. renaming or moving a customer to another employer
+func (h *CustomerHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var updateRequest model.CustomerUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updateRequest); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	customer, err := h.customerService.UpdateCustomer(uint(id), updateRequest)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCustomerNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrEmployerNotFound), errors.Is(err, service.ErrEmployerInactive),
+			errors.Is(err, service.ErrGlidePathNotFound):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newCustomerResponse(customer))
+}
+
+// Delete handles customer deletion
+func (h *CustomerHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.customerService.DeleteCustomer(uint(id)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrCustomerNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrCustomerHasInvestments):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newCustomerResponse converts a customer into its API representation
+func newCustomerResponse(customer *model.Customer) model.CustomerResponse {
+	return model.CustomerResponse{
+		ID:            customer.ID,
+		Name:          customer.Name,
+		EmployerID:    customer.EmployerID,
+		DateOfBirth:   customer.DateOfBirth,
+		RetirementAge: customer.RetirementAge,
+		GlidePathID:   customer.GlidePathID,
+		CreatedAt:     customer.CreatedAt,
+		UpdatedAt:     customer.UpdatedAt,
+	}
+}
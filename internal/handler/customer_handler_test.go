@@ -11,6 +11,9 @@ import (
 
 	"cushon/internal/mocks"
 	"cushon/internal/model"
+	"cushon/internal/service"
+
+	"github.com/gorilla/mux"
 )
 
 func TestCustomerHandler_Create(t *testing.T) {
@@ -74,6 +77,17 @@ func TestCustomerHandler_Create(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   model.CustomerResponse{},
 		},
+		{
+			name: "Unknown employer",
+			requestBody: model.CustomerCreate{
+				Name:       "Jane Smith",
+				EmployerID: uintPtr(99),
+			},
+			mockCustomer:   nil,
+			mockErr:        service.ErrEmployerNotFound,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   model.CustomerResponse{},
+		},
 		{
 			name: "Invalid request body",
 			requestBody: model.CustomerCreate{
@@ -143,6 +157,307 @@ func TestCustomerHandler_Create(t *testing.T) {
 	}
 }
 
+func TestCustomerHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		customerID     string
+		mockCustomer   *model.Customer
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Get existing customer",
+			customerID:     "1",
+			mockCustomer:   &model.Customer{ID: 1, Name: "John Doe"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Customer not found",
+			customerID:     "99",
+			mockErr:        service.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid customer ID",
+			customerID:     "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.CustomerService{
+				MockCustomer: tt.mockCustomer,
+				MockErr:      tt.mockErr,
+			}
+			handler := NewCustomerHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/customers/{id}", handler.Get).Methods("GET")
+
+			req := httptest.NewRequest("GET", "/customers/"+tt.customerID, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response model.CustomerResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if response.ID != tt.mockCustomer.ID || response.Name != tt.mockCustomer.Name {
+					t.Errorf("handler returned wrong customer: got %+v want %+v", response, tt.mockCustomer)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomerHandler_GetAll(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockCustomers  []*model.Customer
+		mockErr        error
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:           "List all customers",
+			query:          "",
+			mockCustomers:  []*model.Customer{{ID: 1, Name: "John Doe"}, {ID: 2, Name: "Jane Smith", EmployerID: uintPtr(1)}},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "Filter by employer",
+			query:          "?employer_id=1",
+			mockCustomers:  []*model.Customer{{ID: 2, Name: "Jane Smith", EmployerID: uintPtr(1)}},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:           "Retail customers only",
+			query:          "?retail=true",
+			mockCustomers:  []*model.Customer{{ID: 1, Name: "John Doe"}},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:           "No customers",
+			query:          "",
+			mockCustomers:  []*model.Customer{},
+			expectedStatus: http.StatusOK,
+			expectedCount:  0,
+		},
+		{
+			name:           "Invalid employer ID",
+			query:          "?employer_id=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid retail filter",
+			query:          "?retail=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Conflicting filters",
+			query:          "?employer_id=1&retail=true",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Service error",
+			query:          "",
+			mockErr:        errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.CustomerService{
+				MockCustomers: tt.mockCustomers,
+				MockErr:       tt.mockErr,
+			}
+			handler := NewCustomerHandler(mockService)
+
+			req := httptest.NewRequest("GET", "/customers"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			handler.GetAll(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response []model.CustomerResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if len(response) != tt.expectedCount {
+					t.Errorf("handler returned %d customers, want %d", len(response), tt.expectedCount)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomerHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		customerID     string
+		body           string
+		mockErr        error
+		expectedStatus int
+		expectedName   string
+	}{
+		{
+			name:           "Rename customer",
+			customerID:     "1",
+			body:           `{"name":"Jane Doe"}`,
+			expectedStatus: http.StatusOK,
+			expectedName:   "Jane Doe",
+		},
+		{
+			name:           "Customer not found",
+			customerID:     "99",
+			body:           `{"name":"Jane Doe"}`,
+			mockErr:        service.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Unknown employer",
+			customerID:     "1",
+			body:           `{"employer_id":99}`,
+			mockErr:        service.ErrEmployerNotFound,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Unknown glide path",
+			customerID:     "1",
+			body:           `{"glide_path_id":99}`,
+			mockErr:        service.ErrGlidePathNotFound,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Retirement age out of range",
+			customerID:     "1",
+			body:           `{"retirement_age":40}`,
+			mockErr:        service.ErrInvalidRetirementPlan,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid date of birth",
+			customerID:     "1",
+			body:           `{"date_of_birth":"15/06/1970"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Empty name",
+			customerID:     "1",
+			body:           `{"name":""}`,
+			mockErr:        errors.New("customer name cannot be empty"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid request body",
+			customerID:     "1",
+			body:           "invalid json",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid customer ID",
+			customerID:     "abc",
+			body:           `{"name":"Jane Doe"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.CustomerService{
+				MockCustomer: &model.Customer{ID: 1, Name: tt.expectedName},
+				MockErr:      tt.mockErr,
+			}
+			handler := NewCustomerHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/customers/{id}", handler.Update).Methods("PATCH")
+
+			req := httptest.NewRequest("PATCH", "/customers/"+tt.customerID, bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response model.CustomerResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if response.Name != tt.expectedName {
+					t.Errorf("handler returned wrong Name: got %v want %v", response.Name, tt.expectedName)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomerHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		customerID     string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Delete customer",
+			customerID:     "1",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Customer not found",
+			customerID:     "99",
+			mockErr:        service.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Customer has investments",
+			customerID:     "1",
+			mockErr:        service.ErrCustomerHasInvestments,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Invalid customer ID",
+			customerID:     "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.CustomerService{MockErr: tt.mockErr}
+			handler := NewCustomerHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/customers/{id}", handler.Delete).Methods("DELETE")
+
+			req := httptest.NewRequest("DELETE", "/customers/"+tt.customerID, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
 // Helper function to create a pointer to uint
 func uintPtr(n uint) *uint {
 	return &n
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// DealingHandler handles requests for dealing customers' orders with fund managers
+type DealingHandler struct {
+	dealingService service.Dealing
+}
+
+// NewDealingHandler creates a new dealing handler
+func NewDealingHandler(dealingService service.Dealing) *DealingHandler {
+	return &DealingHandler{
+		dealingService: dealingService,
+	}
+}
+
+// Run handles dealing the pending orders for a dealing day straight away instead of waiting for the scheduler
+func (h *DealingHandler) Run(w http.ResponseWriter, r *http.Request) {
+	var runRequest model.DealingRunCreate
+	if err := json.NewDecoder(r.Body).Decode(&runRequest); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if runRequest.DealingDate.IsZero() {
+		http.Error(w, "Dealing date is required", http.StatusBadRequest)
+		return
+	}
+
+	batches, err := h.dealingService.RunDealing(runRequest.DealingDate)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotDealingDay):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrDealingNotOpen), errors.Is(err, service.ErrDuplicateDealingBatch),
+			errors.Is(err, service.ErrInvestmentStatusChanged):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newDealingBatchResponses(batches))
+}
+
+// GetAll handles listing dealing batches, optionally filtered with the date and fund_id query parameters
+func (h *DealingHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var filter model.DealingBatchFilter
+	if dateStr := query.Get("date"); dateStr != "" {
+		date, err := model.ParseDate(dateStr)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid date: %v", err), http.StatusBadRequest)
+			return
+		}
+		filter.DealingDate = &date
+	}
+	if fundIDStr := query.Get("fund_id"); fundIDStr != "" {
+		fundID, err := strconv.ParseUint(fundIDStr, 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid fund ID", http.StatusBadRequest)
+			return
+		}
+		id := uint(fundID)
+		filter.FundID = &id
+	}
+
+	batches, err := h.dealingService.ListBatches(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newDealingBatchResponses(batches))
+}
+
+// Get handles retrieving a dealing batch
+func (h *DealingHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid batch ID", http.StatusBadRequest)
+		return
+	}
+
+	batch, err := h.dealingService.GetBatch(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrDealingBatchNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newDealingBatchResponse(batch))
+}
+
+// newDealingBatchResponses converts dealing batches into their API representation
+func newDealingBatchResponses(batches []*model.DealingBatch) []model.DealingBatchResponse {
+	response := make([]model.DealingBatchResponse, len(batches))
+	for i, batch := range batches {
+		response[i] = newDealingBatchResponse(batch)
+	}
+	return response
+}
+
+// newDealingBatchResponse converts a dealing batch into its API representation
+func newDealingBatchResponse(batch *model.DealingBatch) model.DealingBatchResponse {
+	// Both sides of a batch are in the fund's currency, so netting them can't fail
+	net, _ := batch.Net()
+	return model.DealingBatchResponse{
+		ID:            batch.ID,
+		FundID:        batch.FundID,
+		DealingDate:   batch.DealingDate,
+		Price:         batch.Price,
+		Bought:        batch.Bought,
+		Sold:          batch.Sold,
+		Net:           net,
+		UnitsBought:   batch.UnitsBought,
+		UnitsSold:     batch.UnitsSold,
+		NetUnits:      batch.NetUnits(),
+		InvestmentIDs: batch.InvestmentIDs,
+		CreatedAt:     batch.CreatedAt,
+	}
+}
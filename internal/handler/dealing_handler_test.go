@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+	"cushon/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestDealingBatch returns a batch buying more than it sells in fund 1
+func newTestDealingBatch() *model.DealingBatch {
+	return &model.DealingBatch{
+		ID:            1,
+		FundID:        1,
+		DealingDate:   model.NewDate(2026, time.October, 16),
+		Price:         2000000,
+		Bought:        model.NewMoney(10000, model.DefaultCurrency),
+		Sold:          model.NewMoney(3000, model.DefaultCurrency),
+		UnitsBought:   50000000,
+		UnitsSold:     15000000,
+		InvestmentIDs: []uint{1, 2, 5},
+	}
+}
+
+// checkDealingBatchResponse checks that a response holds newTestDealingBatch with its net order worked out
+func checkDealingBatchResponse(t *testing.T, response model.DealingBatchResponse) {
+	t.Helper()
+	if response.FundID != 1 || response.Net != model.NewMoney(7000, model.DefaultCurrency) || response.NetUnits != 35000000 ||
+		len(response.InvestmentIDs) != 3 {
+		t.Errorf("handler returned batch %+v", response)
+	}
+}
+
+func TestDealingHandler_Run(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Dealing run",
+			body:           `{"dealing_date": "2026-10-16"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Missing dealing date",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid dealing date",
+			body:           `{"dealing_date": "16/10/2026"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Not a dealing day",
+			body:           `{"dealing_date": "2026-10-17"}`,
+			mockErr:        fmt.Errorf("%w: 2026-10-17 is a Saturday", service.ErrNotDealingDay),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Cut-off not passed",
+			body:           `{"dealing_date": "2026-10-16"}`,
+			mockErr:        service.ErrDealingNotOpen,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Concurrent run",
+			body:           `{"dealing_date": "2026-10-16"}`,
+			mockErr:        service.ErrInvestmentStatusChanged,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Service error",
+			body:           `{"dealing_date": "2026-10-16"}`,
+			mockErr:        errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewDealingHandler(&mocks.DealingService{
+				MockBatches: []*model.DealingBatch{newTestDealingBatch()},
+				MockErr:     tt.mockErr,
+			})
+
+			req := httptest.NewRequest("POST", "/dealing/runs", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			handler.Run(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusCreated {
+				var response []model.DealingBatchResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if len(response) != 1 {
+					t.Fatalf("handler returned %d batches, want 1", len(response))
+				}
+				checkDealingBatchResponse(t, response[0])
+			}
+		})
+	}
+}
+
+func TestDealingHandler_GetAll(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "All batches",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Batches for a date and fund",
+			query:          "?date=2026-10-16&fund_id=1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid date",
+			query:          "?date=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid fund ID",
+			query:          "?fund_id=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Service error",
+			mockErr:        errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewDealingHandler(&mocks.DealingService{
+				MockBatches: []*model.DealingBatch{newTestDealingBatch()},
+				MockErr:     tt.mockErr,
+			})
+
+			req := httptest.NewRequest("GET", "/dealing/batches"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			handler.GetAll(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response []model.DealingBatchResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if len(response) != 1 {
+					t.Fatalf("handler returned %d batches, want 1", len(response))
+				}
+				checkDealingBatchResponse(t, response[0])
+			}
+		})
+	}
+}
+
+func TestDealingHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		batchID        string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Existing batch",
+			batchID:        "1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid batch ID",
+			batchID:        "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Batch not found",
+			batchID:        "99",
+			mockErr:        service.ErrDealingBatchNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Service error",
+			batchID:        "1",
+			mockErr:        errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewDealingHandler(&mocks.DealingService{MockBatch: newTestDealingBatch(), MockErr: tt.mockErr})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/dealing/batches/{id}", handler.Get).Methods("GET")
+
+			req := httptest.NewRequest("GET", "/dealing/batches/"+tt.batchID, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response model.DealingBatchResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				checkDealingBatchResponse(t, response)
+			}
+		})
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"cushon/internal/model"
 	"cushon/internal/service"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
 )
 
 // EmployerHandler handles employer-related HTTP requests
@@ -33,12 +37,154 @@ func (h *EmployerHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := model.EmployerResponse{
-		ID:   employer.ID,
-		Name: employer.Name,
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newEmployerResponse(employer))
+}
+
+// Get handles retrieving an employer
+func (h *EmployerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid employer ID", http.StatusBadRequest)
+		return
+	}
+
+	employer, err := h.employerService.GetEmployer(uint(id))
+	if err != nil {
+		writeEmployerError(w, err, http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newEmployerResponse(employer))
+}
+
+// GetAll handles listing employers. Only active employers are listed when the active query parameter is true.
+func (h *EmployerHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	var filter model.EmployerFilter
+
+	if activeStr := r.URL.Query().Get("active"); activeStr != "" {
+		active, err := strconv.ParseBool(activeStr)
+		if err != nil {
+			http.Error(w, "Invalid active filter", http.StatusBadRequest)
+			return
+		}
+		filter.ActiveOnly = active
+	}
+
+	employers, err := h.employerService.ListEmployers(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]model.EmployerResponse, len(employers))
+	for i, employer := range employers {
+		response[i] = newEmployerResponse(employer)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// Update handles partial employer updates
+func (h *EmployerHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid employer ID", http.StatusBadRequest)
+		return
+	}
+
+	var updateRequest model.EmployerUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updateRequest); err != nil {
+		if errors.Is(err, model.ErrInvalidMoney) || errors.Is(err, model.ErrInvalidPercent) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	employer, err := h.employerService.UpdateEmployer(uint(id), updateRequest)
+	if err != nil {
+		if errors.Is(err, service.ErrFundNotFound) || errors.Is(err, service.ErrFundNotOpen) {
+			// The default fund can't be invested in
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		writeEmployerError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newEmployerResponse(employer))
+}
+
+// Deactivate handles marking an employer as having left the scheme
+func (h *EmployerHandler) Deactivate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid employer ID", http.StatusBadRequest)
+		return
+	}
+
+	employer, err := h.employerService.DeactivateEmployer(uint(id))
+	if err != nil {
+		writeEmployerError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newEmployerResponse(employer))
+}
+
+// GetCustomers handles listing the customers enrolled under an employer
+func (h *EmployerHandler) GetCustomers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid employer ID", http.StatusBadRequest)
+		return
+	}
+
+	customers, err := h.employerService.ListEmployees(uint(id))
+	if err != nil {
+		writeEmployerError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]model.CustomerResponse, len(customers))
+	for i, customer := range customers {
+		response[i] = newCustomerResponse(customer)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeEmployerError responds with 404 when the employer doesn't exist and with fallbackStatus otherwise
+func writeEmployerError(w http.ResponseWriter, err error, fallbackStatus int) {
+	if errors.Is(err, service.ErrEmployerNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), fallbackStatus)
+}
+
+// newEmployerResponse converts an employer into its API representation
+func newEmployerResponse(employer *model.Employer) model.EmployerResponse {
+	return model.EmployerResponse{
+		ID:                 employer.ID,
+		Name:               employer.Name,
+		Active:             employer.Active,
+		ContributionScheme: employer.ContributionScheme,
+		DefaultFundID:      employer.DefaultFundID,
+		CreatedAt:          employer.CreatedAt,
+		UpdatedAt:          employer.UpdatedAt,
+	}
+}
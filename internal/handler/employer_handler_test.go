@@ -10,6 +10,9 @@ import (
 
 	"cushon/internal/mocks"
 	"cushon/internal/model"
+	"cushon/internal/service"
+
+	"github.com/gorilla/mux"
 )
 
 func TestEmployerHandler_Create(t *testing.T) {
@@ -112,3 +115,342 @@ func TestEmployerHandler_Create(t *testing.T) {
 		})
 	}
 }
+
+func TestEmployerHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		employerID     string
+		mockEmployer   *model.Employer
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Get existing employer",
+			employerID:     "1",
+			mockEmployer:   &model.Employer{ID: 1, Name: "Test Company", Active: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Employer not found",
+			employerID:     "99",
+			mockErr:        service.ErrEmployerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid employer ID",
+			employerID:     "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.EmployerService{
+				MockEmployer: tt.mockEmployer,
+				MockErr:      tt.mockErr,
+			}
+			handler := NewEmployerHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/employers/{id}", handler.Get).Methods("GET")
+
+			req := httptest.NewRequest("GET", "/employers/"+tt.employerID, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response model.EmployerResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if response.ID != tt.mockEmployer.ID || response.Name != tt.mockEmployer.Name || !response.Active {
+					t.Errorf("handler returned wrong employer: got %+v want %+v", response, tt.mockEmployer)
+				}
+			}
+		})
+	}
+}
+
+func TestEmployerHandler_GetAll(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockEmployers  []*model.Employer
+		mockErr        error
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:           "List all employers",
+			query:          "",
+			mockEmployers:  []*model.Employer{{ID: 1, Name: "Test Company", Active: true}, {ID: 2, Name: "Old Company"}},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "Active employers only",
+			query:          "?active=true",
+			mockEmployers:  []*model.Employer{{ID: 1, Name: "Test Company", Active: true}},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:           "Invalid active filter",
+			query:          "?active=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Service error",
+			query:          "",
+			mockErr:        errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.EmployerService{
+				MockEmployers: tt.mockEmployers,
+				MockErr:       tt.mockErr,
+			}
+			handler := NewEmployerHandler(mockService)
+
+			req := httptest.NewRequest("GET", "/employers"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			handler.GetAll(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response []model.EmployerResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if len(response) != tt.expectedCount {
+					t.Errorf("handler returned %d employers, want %d", len(response), tt.expectedCount)
+				}
+			}
+		})
+	}
+}
+
+func TestEmployerHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		employerID     string
+		body           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Rename employer",
+			employerID:     "1",
+			body:           `{"name":"New Company"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Employer not found",
+			employerID:     "99",
+			body:           `{"name":"New Company"}`,
+			mockErr:        service.ErrEmployerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Empty name",
+			employerID:     "1",
+			body:           `{"name":""}`,
+			mockErr:        errors.New("employer name cannot be empty"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Set contribution scheme",
+			employerID:     "1",
+			body:           `{"name":"New Company","contribution_scheme":{"employee_rate":"5","employer_rate":"3","employer_cap":{"amount":"250.00","currency":"GBP"}}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid contribution rate",
+			employerID:     "1",
+			body:           `{"name":"New Company","contribution_scheme":{"employee_rate":"lots","employer_rate":"3"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Contribution rate over 100%",
+			employerID:     "1",
+			body:           `{"name":"New Company","contribution_scheme":{"employee_rate":"101","employer_rate":"3"}}`,
+			mockErr:        errors.New("contribution rates must be between 0% and 100% of salary"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Set default fund",
+			employerID:     "1",
+			body:           `{"default_fund_id":2}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Closed default fund",
+			employerID:     "1",
+			body:           `{"default_fund_id":2}`,
+			mockErr:        service.ErrFundNotOpen,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Invalid request body",
+			employerID:     "1",
+			body:           "invalid json",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.EmployerService{
+				MockEmployer: &model.Employer{ID: 1, Name: "New Company", Active: true},
+				MockErr:      tt.mockErr,
+			}
+			handler := NewEmployerHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/employers/{id}", handler.Update).Methods("PATCH")
+
+			req := httptest.NewRequest("PATCH", "/employers/"+tt.employerID, bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestEmployerHandler_Deactivate(t *testing.T) {
+	tests := []struct {
+		name           string
+		employerID     string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Deactivate employer",
+			employerID:     "1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Employer not found",
+			employerID:     "99",
+			mockErr:        service.ErrEmployerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid employer ID",
+			employerID:     "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.EmployerService{
+				MockEmployer: &model.Employer{ID: 1, Name: "Test Company", Active: false},
+				MockErr:      tt.mockErr,
+			}
+			handler := NewEmployerHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/employers/{id}/deactivate", handler.Deactivate).Methods("POST")
+
+			req := httptest.NewRequest("POST", "/employers/"+tt.employerID+"/deactivate", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response model.EmployerResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if response.Active {
+					t.Error("handler returned an active employer after deactivating it")
+				}
+			}
+		})
+	}
+}
+
+func TestEmployerHandler_GetCustomers(t *testing.T) {
+	tests := []struct {
+		name           string
+		employerID     string
+		mockEmployees  []*model.Customer
+		mockErr        error
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:           "Employer with employees",
+			employerID:     "1",
+			mockEmployees:  []*model.Customer{{ID: 1, Name: "John Doe", EmployerID: uintPtr(1)}, {ID: 2, Name: "Jane Smith", EmployerID: uintPtr(1)}},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "Employer without employees",
+			employerID:     "1",
+			mockEmployees:  []*model.Customer{},
+			expectedStatus: http.StatusOK,
+			expectedCount:  0,
+		},
+		{
+			name:           "Employer not found",
+			employerID:     "99",
+			mockErr:        service.ErrEmployerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid employer ID",
+			employerID:     "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.EmployerService{
+				MockEmployees: tt.mockEmployees,
+				MockErr:       tt.mockErr,
+			}
+			handler := NewEmployerHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/employers/{id}/customers", handler.GetCustomers).Methods("GET")
+
+			req := httptest.NewRequest("GET", "/employers/"+tt.employerID+"/customers", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response []model.CustomerResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if len(response) != tt.expectedCount {
+					t.Errorf("handler returned %d customers, want %d", len(response), tt.expectedCount)
+				}
+			}
+		})
+	}
+}
@@ -4,7 +4,13 @@ import (
 	"cushon/internal/model"
 	"cushon/internal/service"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
 )
 
 // FundHandler handles fund-related HTTP requests
@@ -23,43 +29,297 @@ func NewFundHandler(fundService service.Fund) *FundHandler {
 func (h *FundHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var createRequest model.FundCreate
 	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeDecodeError(w, err)
 		return
 	}
 
-	fund, err := h.fundService.NewFund(createRequest.Name)
+	fund, err := h.fundService.NewFund(createRequest)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeFundError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newFundResponse(fund))
+}
+
+// Get handles retrieving a fund
+func (h *FundHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid fund ID", http.StatusBadRequest)
 		return
 	}
 
-	response := model.FundResponse{
-		ID:   fund.ID,
-		Name: fund.Name,
+	fund, err := h.fundService.GetFund(uint(id))
+	if err != nil {
+		writeFundError(w, err, http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(response)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newFundResponse(fund))
 }
 
-// GetAll handles retrieving all funds
+// GetAll handles listing funds. Funds can be filtered with the status, asset_class, risk_min, risk_max,
+// max_ongoing_charges and currency query parameters and ordered with sort, e.g. ?risk_max=4&sort=-risk_rating
 func (h *FundHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	funds, err := h.fundService.GetAllFunds()
+	filter, order, err := parseFundQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	funds, err := h.fundService.ListFunds(filter, order)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidFundFilter) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	response := make([]model.FundResponse, len(funds))
 	for i, fund := range funds {
-		response[i] = model.FundResponse{
-			ID:   fund.ID,
-			Name: fund.Name,
+		response[i] = newFundResponse(fund)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Update handles partial fund updates, including status changes
+func (h *FundHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid fund ID", http.StatusBadRequest)
+		return
+	}
+
+	var updateRequest model.FundUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updateRequest); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	fund, err := h.fundService.UpdateFund(uint(id), updateRequest)
+	if err != nil {
+		writeFundError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newFundResponse(fund))
+}
+
+// Close handles closing a fund to new investments
+func (h *FundHandler) Close(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid fund ID", http.StatusBadRequest)
+		return
+	}
+
+	fund, err := h.fundService.CloseFund(uint(id))
+	if err != nil {
+		writeFundError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newFundResponse(fund))
+}
+
+// AddPrice handles recording a fund's NAV per unit for a valuation date
+func (h *FundHandler) AddPrice(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid fund ID", http.StatusBadRequest)
+		return
+	}
+
+	var createRequest model.FundPriceCreate
+	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	price, err := h.fundService.AddFundPrice(uint(id), createRequest)
+	if err != nil {
+		writeFundError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newFundPriceResponse(price))
+}
+
+// GetPrices handles listing a fund's price history, optionally limited with the from and to query parameters
+func (h *FundHandler) GetPrices(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid fund ID", http.StatusBadRequest)
+		return
+	}
+
+	var filter model.FundPriceFilter
+	for param, bound := range map[string]**model.Date{"from": &filter.From, "to": &filter.To} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		date, err := model.ParseDate(value)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid %s date: %v", param, err), http.StatusBadRequest)
+			return
 		}
+		*bound = &date
+	}
+
+	prices, err := h.fundService.ListFundPrices(uint(id), filter)
+	if err != nil {
+		writeFundError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]model.FundPriceResponse, len(prices))
+	for i, price := range prices {
+		response[i] = newFundPriceResponse(price)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// GetLatestPrice handles retrieving a fund's most recent price
+func (h *FundHandler) GetLatestPrice(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid fund ID", http.StatusBadRequest)
+		return
+	}
+
+	price, err := h.fundService.GetLatestFundPrice(uint(id))
+	if err != nil {
+		writeFundError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newFundPriceResponse(price))
+}
+
+// writeFundError maps fund errors to HTTP statuses, using fallbackStatus for errors it doesn't know
+func writeFundError(w http.ResponseWriter, err error, fallbackStatus int) {
+	switch {
+	case errors.Is(err, service.ErrFundNotFound), errors.Is(err, service.ErrFundPriceNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidFundTransition):
+		// The fund exists but its current status doesn't allow the change
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrDuplicateISIN), errors.Is(err, service.ErrDuplicateFundPrice):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrInvalidFundStatus), errors.Is(err, service.ErrInvalidFundFilter):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), fallbackStatus)
+	}
+}
+
+// writeDecodeError responds to a request body that couldn't be decoded, explaining invalid percentages,
+// prices and dates
+func writeDecodeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, model.ErrInvalidPercent) || errors.Is(err, model.ErrInvalidPrice) || errors.Is(err, model.ErrInvalidDate) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, "Invalid request body", http.StatusBadRequest)
+}
+
+// parseFundQuery reads the fund list filters and sort order from the query string
+func parseFundQuery(r *http.Request) (model.FundFilter, model.FundOrder, error) {
+	query := r.URL.Query()
+	var filter model.FundFilter
+
+	if status := query.Get("status"); status != "" {
+		fundStatus := model.FundStatus(status)
+		if !fundStatus.Valid() {
+			return filter, model.FundOrder{}, fmt.Errorf("invalid status filter %q", status)
+		}
+		filter.Status = &fundStatus
+	}
+
+	if assetClass := query.Get("asset_class"); assetClass != "" {
+		class := model.AssetClass(assetClass)
+		if !class.Valid() {
+			return filter, model.FundOrder{}, fmt.Errorf("invalid asset_class filter %q", assetClass)
+		}
+		filter.AssetClass = &class
+	}
+
+	for param, bound := range map[string]*int{"risk_min": &filter.MinRisk, "risk_max": &filter.MaxRisk} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		risk, err := strconv.Atoi(value)
+		if err != nil || risk < model.MinRiskRating || risk > model.MaxRiskRating {
+			return filter, model.FundOrder{}, fmt.Errorf("%s must be a risk rating between %d and %d",
+				param, model.MinRiskRating, model.MaxRiskRating)
+		}
+		*bound = risk
+	}
+
+	if maxCharges := query.Get("max_ongoing_charges"); maxCharges != "" {
+		charges, err := model.ParsePercent(maxCharges)
+		if err != nil {
+			return filter, model.FundOrder{}, fmt.Errorf("invalid max_ongoing_charges filter: %w", err)
+		}
+		filter.MaxOngoingCharges = &charges
+	}
+
+	filter.Currency = strings.ToUpper(query.Get("currency"))
+
+	order, err := model.ParseFundOrder(query.Get("sort"))
+	if err != nil {
+		return filter, model.FundOrder{}, err
+	}
+	return filter, order, nil
+}
+
+// newFundResponse converts a fund into its API representation
+func newFundResponse(fund *model.Fund) model.FundResponse {
+	return model.FundResponse{
+		ID:             fund.ID,
+		Name:           fund.Name,
+		ISIN:           fund.ISIN,
+		AssetClass:     fund.AssetClass,
+		RiskRating:     fund.RiskRating,
+		OngoingCharges: fund.OngoingCharges,
+		Currency:       fund.Currency,
+		Description:    fund.Description,
+		Status:         fund.Status,
+		CreatedAt:      fund.CreatedAt,
+		UpdatedAt:      fund.UpdatedAt,
+	}
+}
+
+// newFundPriceResponse converts a fund price into its API representation
+func newFundPriceResponse(price *model.FundPrice) model.FundPriceResponse {
+	return model.FundPriceResponse{
+		FundID:    price.FundID,
+		Date:      price.Date,
+		NAV:       price.NAV,
+		Currency:  price.Currency,
+		CreatedAt: price.CreatedAt,
+	}
+}
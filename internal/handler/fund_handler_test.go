@@ -7,9 +7,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"cushon/internal/mocks"
 	"cushon/internal/model"
+	"cushon/internal/service"
+
+	"github.com/gorilla/mux"
 )
 
 func TestFundHandler_Create(t *testing.T) {
@@ -48,6 +52,15 @@ func TestFundHandler_Create(t *testing.T) {
 			expectedBody:   model.FundResponse{},
 			expectedError:  "Invalid request body",
 		},
+		{
+			name:           "Duplicate ISIN",
+			requestBody:    model.FundCreate{Name: "Test Fund", ISIN: "IE00B4L5Y983"},
+			mockFund:       nil,
+			mockErr:        service.ErrDuplicateISIN,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   model.FundResponse{},
+			expectedError:  service.ErrDuplicateISIN.Error(),
+		},
 		{
 			name:           "Service error",
 			requestBody:    model.FundCreate{},
@@ -152,6 +165,14 @@ func TestFundHandler_GetAll(t *testing.T) {
 			expectedBody:   []model.FundResponse{},
 			expectedError:  "",
 		},
+		{
+			name:           "Invalid filter",
+			mockFunds:      nil,
+			mockErr:        service.ErrInvalidFundFilter,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   nil,
+			expectedError:  service.ErrInvalidFundFilter.Error(),
+		},
 		{
 			name:           "Service error",
 			mockFunds:      nil,
@@ -211,3 +232,479 @@ func TestFundHandler_GetAll(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFundQuery(t *testing.T) {
+	equity := model.AssetClassEquity
+	charges := model.Percent(250)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantFilter model.FundFilter
+		wantOrder  model.FundOrder
+		wantErr    bool
+	}{
+		{
+			name:      "No parameters",
+			query:     "",
+			wantOrder: model.FundOrder{Field: model.FundSortByID},
+		},
+		{
+			name:       "Risk and asset class",
+			query:      "?risk_max=4&asset_class=equity",
+			wantFilter: model.FundFilter{MaxRisk: 4, AssetClass: &equity},
+			wantOrder:  model.FundOrder{Field: model.FundSortByID},
+		},
+		{
+			name:       "Charges, currency and sort",
+			query:      "?risk_min=2&max_ongoing_charges=0.25&currency=gbp&sort=-ongoing_charges",
+			wantFilter: model.FundFilter{MinRisk: 2, MaxOngoingCharges: &charges, Currency: "GBP"},
+			wantOrder:  model.FundOrder{Field: model.FundSortByOngoingCharges, Descending: true},
+		},
+		{name: "Unknown status", query: "?status=suspended", wantErr: true},
+		{name: "Unknown asset class", query: "?asset_class=crypto", wantErr: true},
+		{name: "Risk out of range", query: "?risk_max=8", wantErr: true},
+		{name: "Risk not a number", query: "?risk_min=low", wantErr: true},
+		{name: "Invalid charges", query: "?max_ongoing_charges=cheap", wantErr: true},
+		{name: "Unknown sort field", query: "?sort=isin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/funds"+tt.query, nil)
+			filter, order, err := parseFundQuery(req)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("parseFundQuery() expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFundQuery() unexpected error = %v", err)
+			}
+			if order != tt.wantOrder {
+				t.Errorf("order = %+v, want %+v", order, tt.wantOrder)
+			}
+			if filter.MinRisk != tt.wantFilter.MinRisk || filter.MaxRisk != tt.wantFilter.MaxRisk ||
+				filter.Currency != tt.wantFilter.Currency {
+				t.Errorf("filter = %+v, want %+v", filter, tt.wantFilter)
+			}
+			if (filter.AssetClass == nil) != (tt.wantFilter.AssetClass == nil) ||
+				(filter.AssetClass != nil && *filter.AssetClass != *tt.wantFilter.AssetClass) {
+				t.Errorf("filter.AssetClass = %v, want %v", filter.AssetClass, tt.wantFilter.AssetClass)
+			}
+			if (filter.MaxOngoingCharges == nil) != (tt.wantFilter.MaxOngoingCharges == nil) ||
+				(filter.MaxOngoingCharges != nil && *filter.MaxOngoingCharges != *tt.wantFilter.MaxOngoingCharges) {
+				t.Errorf("filter.MaxOngoingCharges = %v, want %v", filter.MaxOngoingCharges, tt.wantFilter.MaxOngoingCharges)
+			}
+		})
+	}
+}
+
+func TestFundHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		fundID         string
+		mockFund       *model.Fund
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Get existing fund",
+			fundID:         "1",
+			mockFund:       &model.Fund{ID: 1, Name: "Equities Fund", Status: model.FundStatusOpen},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Fund not found",
+			fundID:         "99",
+			mockErr:        service.ErrFundNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid fund ID",
+			fundID:         "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.FundService{
+				MockFund: tt.mockFund,
+				MockErr:  tt.mockErr,
+			}
+			handler := NewFundHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/funds/{id}", handler.Get).Methods("GET")
+
+			req := httptest.NewRequest("GET", "/funds/"+tt.fundID, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response model.FundResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if response.ID != tt.mockFund.ID || response.Status != tt.mockFund.Status {
+					t.Errorf("handler returned wrong fund: got %+v want %+v", response, tt.mockFund)
+				}
+			}
+		})
+	}
+}
+
+func TestFundHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		fundID         string
+		body           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Rename fund",
+			fundID:         "1",
+			body:           `{"name":"Global Equities"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Fund not found",
+			fundID:         "99",
+			body:           `{"status":"closed"}`,
+			mockErr:        service.ErrFundNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Reopen retired fund",
+			fundID:         "1",
+			body:           `{"status":"open"}`,
+			mockErr:        service.ErrInvalidFundTransition,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Unknown status",
+			fundID:         "1",
+			body:           `{"status":"suspended"}`,
+			mockErr:        service.ErrInvalidFundStatus,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid request body",
+			fundID:         "1",
+			body:           "invalid json",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.FundService{
+				MockFund: &model.Fund{ID: 1, Name: "Global Equities", Status: model.FundStatusOpen},
+				MockErr:  tt.mockErr,
+			}
+			handler := NewFundHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/funds/{id}", handler.Update).Methods("PATCH")
+
+			req := httptest.NewRequest("PATCH", "/funds/"+tt.fundID, bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestFundHandler_Close(t *testing.T) {
+	tests := []struct {
+		name           string
+		fundID         string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Close open fund",
+			fundID:         "1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Close retired fund",
+			fundID:         "1",
+			mockErr:        service.ErrInvalidFundTransition,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Fund not found",
+			fundID:         "99",
+			mockErr:        service.ErrFundNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Service error",
+			fundID:         "1",
+			mockErr:        errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.FundService{
+				MockFund: &model.Fund{ID: 1, Name: "Equities Fund", Status: model.FundStatusClosed},
+				MockErr:  tt.mockErr,
+			}
+			handler := NewFundHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/funds/{id}/close", handler.Close).Methods("POST")
+
+			req := httptest.NewRequest("POST", "/funds/"+tt.fundID+"/close", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response model.FundResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if response.Status != model.FundStatusClosed {
+					t.Errorf("handler returned status %v, want %v", response.Status, model.FundStatusClosed)
+				}
+			}
+		})
+	}
+}
+
+func TestFundHandler_AddPrice(t *testing.T) {
+	tests := []struct {
+		name           string
+		fundID         string
+		body           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Record price",
+			fundID:         "1",
+			body:           `{"date": "2026-10-16", "nav": "1.234567"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid fund ID",
+			fundID:         "abc",
+			body:           `{"date": "2026-10-16", "nav": "1.234567"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid date",
+			fundID:         "1",
+			body:           `{"date": "16/10/2026", "nav": "1.234567"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Too many decimal places",
+			fundID:         "1",
+			body:           `{"date": "2026-10-16", "nav": "1.2345678"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Fund not found",
+			fundID:         "99",
+			body:           `{"date": "2026-10-16", "nav": "1.234567"}`,
+			mockErr:        service.ErrFundNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Date already priced",
+			fundID:         "1",
+			body:           `{"date": "2026-10-16", "nav": "1.234567"}`,
+			mockErr:        service.ErrDuplicateFundPrice,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Validation error",
+			fundID:         "1",
+			body:           `{"date": "2026-10-16", "nav": "0"}`,
+			mockErr:        errors.New("price must be greater than 0"),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.FundService{
+				MockPrice: &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 1234567, Currency: "GBP"},
+				MockErr:   tt.mockErr,
+			}
+			handler := NewFundHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/funds/{id}/prices", handler.AddPrice).Methods("POST")
+
+			req := httptest.NewRequest("POST", "/funds/"+tt.fundID+"/prices", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusCreated {
+				var response map[string]any
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if response["date"] != "2026-10-16" || response["nav"] != "1.234567" || response["currency"] != "GBP" {
+					t.Errorf("handler returned %v, want the recorded price", response)
+				}
+			}
+		})
+	}
+}
+
+func TestFundHandler_GetPrices(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockErr        error
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:           "Price history",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "Between dates",
+			query:          "?from=2026-10-01&to=2026-10-31",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "Invalid date",
+			query:          "?from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "From after to",
+			query:          "?from=2026-10-31&to=2026-10-01",
+			mockErr:        service.ErrInvalidFundFilter,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Fund not found",
+			mockErr:        service.ErrFundNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Service error",
+			mockErr:        errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.FundService{
+				MockPrices: []*model.FundPrice{
+					{FundID: 1, Date: model.NewDate(2026, time.October, 15), NAV: 1200000, Currency: "GBP"},
+					{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 1234567, Currency: "GBP"},
+				},
+				MockErr: tt.mockErr,
+			}
+			handler := NewFundHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/funds/{id}/prices", handler.GetPrices).Methods("GET")
+
+			req := httptest.NewRequest("GET", "/funds/1/prices"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response []model.FundPriceResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if len(response) != tt.expectedCount {
+					t.Errorf("handler returned %d prices, want %d", len(response), tt.expectedCount)
+				}
+			}
+		})
+	}
+}
+
+func TestFundHandler_GetLatestPrice(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Latest price",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Fund not priced yet",
+			mockErr:        service.ErrFundPriceNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Fund not found",
+			mockErr:        service.ErrFundNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.FundService{
+				MockPrice: &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 1234567, Currency: "GBP"},
+				MockErr:   tt.mockErr,
+			}
+			handler := NewFundHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/funds/{id}/prices/latest", handler.GetLatestPrice).Methods("GET")
+
+			req := httptest.NewRequest("GET", "/funds/1/prices/latest", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response model.FundPriceResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if response.NAV != 1234567 || response.Date != model.NewDate(2026, time.October, 16) {
+					t.Errorf("handler returned %+v, want the latest price", response)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GlidePathHandler handles HTTP requests for glide paths and moving customers along them
+type GlidePathHandler struct {
+	lifestylingService service.Lifestyling
+}
+
+// NewGlidePathHandler creates a new glide path handler
+func NewGlidePathHandler(lifestylingService service.Lifestyling) *GlidePathHandler {
+	return &GlidePathHandler{
+		lifestylingService: lifestylingService,
+	}
+}
+
+// Create handles glide path creation
+func (h *GlidePathHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var createRequest model.GlidePathCreate
+	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	glidePath, err := h.lifestylingService.CreateGlidePath(createRequest)
+	if err != nil {
+		if errors.Is(err, service.ErrFundNotFound) || errors.Is(err, service.ErrFundNotOpen) {
+			// The request is well formed but names a fund that can't be invested in
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newGlidePathResponse(glidePath))
+}
+
+// Get handles retrieving a glide path
+func (h *GlidePathHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid glide path ID", http.StatusBadRequest)
+		return
+	}
+
+	glidePath, err := h.lifestylingService.GetGlidePath(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrGlidePathNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newGlidePathResponse(glidePath))
+}
+
+// GetAll handles listing glide paths
+func (h *GlidePathHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	glidePaths, err := h.lifestylingService.ListGlidePaths()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]model.GlidePathResponse, len(glidePaths))
+	for i, glidePath := range glidePaths {
+		response[i] = newGlidePathResponse(glidePath)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Run handles moving every customer following a glide path to the stage they have reached
+func (h *GlidePathHandler) Run(w http.ResponseWriter, r *http.Request) {
+	run, err := h.lifestylingService.ApplyGlidePaths()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := model.GlidePathRunResponse{
+		Moved:  make([]model.GlidePathMoveResponse, len(run.Moved)),
+		Failed: run.Failed,
+	}
+	for i, move := range run.Moved {
+		response.Moved[i] = model.GlidePathMoveResponse{
+			CustomerID:        move.CustomerID,
+			GlidePathID:       move.GlidePathID,
+			YearsToRetirement: move.YearsToRetirement,
+			Funds:             move.Allocation.Funds,
+			Rebalance:         newRebalanceResponse(move.Rebalance),
+		}
+	}
+	if response.Failed == nil {
+		response.Failed = []model.GlidePathFailure{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// newGlidePathResponse converts a glide path into its API representation
+func newGlidePathResponse(glidePath *model.GlidePath) model.GlidePathResponse {
+	return model.GlidePathResponse{
+		ID:        glidePath.ID,
+		Name:      glidePath.Name,
+		Stages:    glidePath.Stages,
+		CreatedAt: glidePath.CreatedAt,
+	}
+}
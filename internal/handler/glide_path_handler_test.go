@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+	"cushon/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+func TestGlidePathHandler(t *testing.T) {
+	glidePath := &model.GlidePath{ID: 1, Name: "Balanced", Stages: []model.GlidePathStage{
+		{YearsToRetirement: 10, Funds: []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}}},
+		{YearsToRetirement: 0, Funds: []model.FundAllocation{{FundID: 2, Percentage: model.OneHundredPercent}}},
+	}}
+	validBody := `{"name":"Balanced","stages":[{"years_to_retirement":10,"funds":[{"fund_id":1,"percentage":"100"}]},{"years_to_retirement":0,"funds":[{"fund_id":2,"percentage":"100"}]}]}`
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "Create", method: "POST", path: "/glide-paths", body: validBody, expectedStatus: http.StatusCreated},
+		{name: "Create with an invalid stage", method: "POST", path: "/glide-paths", body: validBody, mockErr: service.ErrInvalidGlidePath, expectedStatus: http.StatusBadRequest},
+		{name: "Create with a closed fund", method: "POST", path: "/glide-paths", body: validBody, mockErr: service.ErrFundNotOpen, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "Create with an invalid percentage", method: "POST", path: "/glide-paths", body: `{"name":"Balanced","stages":[{"funds":[{"fund_id":1,"percentage":"lots"}]}]}`, expectedStatus: http.StatusBadRequest},
+		{name: "Get", method: "GET", path: "/glide-paths/1", expectedStatus: http.StatusOK},
+		{name: "Glide path not found", method: "GET", path: "/glide-paths/99", mockErr: service.ErrGlidePathNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Invalid glide path ID", method: "GET", path: "/glide-paths/abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewGlidePathHandler(&mocks.LifestylingService{MockGlidePath: glidePath, MockErr: tt.mockErr})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/glide-paths", handler.Create).Methods("POST")
+			router.HandleFunc("/glide-paths/{id}", handler.Get).Methods("GET")
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+			if rr.Code >= http.StatusBadRequest {
+				return
+			}
+
+			var response model.GlidePathResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Could not decode response: %v", err)
+			}
+			if response.ID != 1 || response.Name != "Balanced" || len(response.Stages) != 2 || response.Stages[0].YearsToRetirement != 10 {
+				t.Errorf("handler returned wrong glide path: got %+v", response)
+			}
+		})
+	}
+}
+
+func TestGlidePathHandler_GetAll(t *testing.T) {
+	handler := NewGlidePathHandler(&mocks.LifestylingService{MockGlidePaths: []*model.GlidePath{{ID: 1, Name: "Balanced"}, {ID: 2, Name: "Cautious"}}})
+
+	req := httptest.NewRequest("GET", "/glide-paths", nil)
+	rr := httptest.NewRecorder()
+	handler.GetAll(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	var response []model.GlidePathResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Could not decode response: %v", err)
+	}
+	if len(response) != 2 || response[1].Name != "Cautious" {
+		t.Errorf("handler returned wrong glide paths: got %+v", response)
+	}
+}
+
+func TestGlidePathHandler_Run(t *testing.T) {
+	run := &model.GlidePathRun{
+		Moved: []*model.GlidePathMove{{
+			CustomerID:        1,
+			GlidePathID:       1,
+			YearsToRetirement: 9,
+			Allocation:        &model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 2, Percentage: model.OneHundredPercent}}},
+			Rebalance:         &model.Rebalance{CustomerID: 1, Value: model.NewMoney(100000, model.DefaultCurrency), Executed: true},
+		}},
+		Failed: []model.GlidePathFailure{{CustomerID: 2, Error: "fund 2 is closed"}},
+	}
+	handler := NewGlidePathHandler(&mocks.LifestylingService{MockRun: run})
+
+	req := httptest.NewRequest("POST", "/glide-paths/runs", nil)
+	rr := httptest.NewRecorder()
+	handler.Run(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	var response model.GlidePathRunResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Could not decode response: %v", err)
+	}
+	if len(response.Moved) != 1 || response.Moved[0].YearsToRetirement != 9 || response.Moved[0].Funds[0].FundID != 2 ||
+		!response.Moved[0].Rebalance.Executed || len(response.Failed) != 1 || response.Failed[0] != run.Failed[0] {
+		t.Errorf("handler returned wrong run: got %+v", response)
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"cushon/internal/model"
 	"cushon/internal/service"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gorilla/mux"
 )
 
+// maxPayrollFileSize is the largest payroll file accepted, enough for tens of thousands of contributions
+const maxPayrollFileSize = 10 << 20
+
 // InvestmentHandler handles investment-related HTTP requests
 type InvestmentHandler struct {
 	investmentService service.Investment
@@ -26,6 +31,10 @@ func NewInvestmentHandler(investmentService service.Investment) *InvestmentHandl
 func (h *InvestmentHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var createRequest model.InvestmentCreate
 	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
+		if errors.Is(err, model.ErrInvalidMoney) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
@@ -39,22 +48,173 @@ func (h *InvestmentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Fund ID is required", http.StatusBadRequest)
 		return
 	}
-	if createRequest.Amount <= 0 {
+	if !createRequest.Amount.IsPositive() {
 		http.Error(w, "Amount must be greater than 0", http.StatusBadRequest)
 		return
 	}
 
-	investment, err := h.investmentService.NewInvestment(createRequest.ClientID, createRequest.FundID, float32(createRequest.Amount))
+	investment, err := h.investmentService.NewInvestment(createRequest)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		switch {
+		case errors.Is(err, service.ErrCustomerNotFound):
+			// The customer the investment is being made for doesn't exist
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrFundNotFound), errors.Is(err, service.ErrFundNotOpen), errors.Is(err, service.ErrFundNotPriced):
+			// The request is well formed but references a fund that can't be invested in
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, service.ErrContributionSourceNotAllowed):
+			// Retail customers have no employer's payroll to contribute through
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, service.ErrISAAllowanceExceeded):
+			// The customer's ISA can't take this much more in the current tax year
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response := newInvestmentResponse(investment)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// SalaryContribution handles investing a pay period's workplace contributions for an employee, split between
+// them and their employer
+func (h *InvestmentHandler) SalaryContribution(w http.ResponseWriter, r *http.Request) {
+	var createRequest model.SalaryContributionCreate
+	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
+		if errors.Is(err, model.ErrInvalidMoney) || errors.Is(err, model.ErrInvalidPercent) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate request
+	if createRequest.ClientID == 0 {
+		http.Error(w, "Client ID is required", http.StatusBadRequest)
+		return
+	}
+	if createRequest.FundID == 0 {
+		http.Error(w, "Fund ID is required", http.StatusBadRequest)
+		return
+	}
+
+	investments, err := h.investmentService.NewSalaryContribution(createRequest)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCustomerNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrFundNotFound), errors.Is(err, service.ErrFundNotOpen), errors.Is(err, service.ErrFundNotPriced),
+			errors.Is(err, service.ErrContributionSourceNotAllowed), errors.Is(err, service.ErrEmployerInactive),
+			errors.Is(err, service.ErrNoContributionScheme):
+			// The request is well formed but the customer's employer or the fund can't take the contributions
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response := make([]model.InvestmentResponse, len(investments))
+	for i, investment := range investments {
+		response[i] = newInvestmentResponse(investment)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Contribute handles investing an amount across a customer's target allocation
+func (h *InvestmentHandler) Contribute(w http.ResponseWriter, r *http.Request) {
+	var createRequest model.ContributionCreate
+	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
+		if errors.Is(err, model.ErrInvalidMoney) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate request
+	if createRequest.ClientID == 0 {
+		http.Error(w, "Client ID is required", http.StatusBadRequest)
+		return
+	}
+
+	investments, err := h.investmentService.Contribute(createRequest)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCustomerNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrAllocationNotFound), errors.Is(err, service.ErrFundNotFound),
+			errors.Is(err, service.ErrFundNotOpen), errors.Is(err, service.ErrFundNotPriced),
+			errors.Is(err, service.ErrContributionSourceNotAllowed), errors.Is(err, service.ErrISAAllowanceExceeded):
+			// The request is well formed but the customer's allocation or its funds can't take the contribution
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	response := make([]model.InvestmentResponse, len(investments))
+	for i, investment := range investments {
+		response[i] = newInvestmentResponse(investment)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ImportPayroll handles an employer's CSV payroll file, investing every contribution it lists or none of them.
+// Rows that can't be imported are reported one by one.
+func (h *InvestmentHandler) ImportPayroll(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid employer ID", http.StatusBadRequest)
 		return
 	}
 
-	response := model.InvestmentResponse{
-		ID:       investment.ID,
-		ClientID: investment.ClientID,
-		FundID:   investment.FundID,
-		Amount:   float64(investment.Amount),
+	imported, err := h.investmentService.ImportPayroll(uint(id), http.MaxBytesReader(w, r.Body, maxPayrollFileSize))
+	if err != nil {
+		var payrollErr *model.PayrollError
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &payrollErr):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(model.PayrollErrorResponse{Error: err.Error(), Rows: payrollErr.Rows})
+		case errors.As(err, &tooLarge):
+			http.Error(w, fmt.Sprintf("Payroll files can be at most %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+		case errors.Is(err, service.ErrEmployerNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrEmployerInactive), errors.Is(err, service.ErrPayrollTotalsMismatch):
+			// The file can be read but the employer can't contribute or it doesn't add up
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, model.ErrInvalidPayroll):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := model.PayrollImportResponse{
+		EmployerID:    imported.EmployerID,
+		Contributions: len(imported.Investments),
+		Total:         imported.Total,
+		Investments:   make([]model.InvestmentResponse, len(imported.Investments)),
+	}
+	for i, investment := range imported.Investments {
+		response.Investments[i] = newInvestmentResponse(investment)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -62,6 +222,111 @@ func (h *InvestmentHandler) Create(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// Withdraw handles withdrawing an amount or a number of units from a customer's holding in a fund
+func (h *InvestmentHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	var createRequest model.WithdrawalCreate
+	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
+		if errors.Is(err, model.ErrInvalidMoney) || errors.Is(err, model.ErrInvalidUnits) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate request
+	if createRequest.ClientID == 0 {
+		http.Error(w, "Client ID is required", http.StatusBadRequest)
+		return
+	}
+	if createRequest.FundID == 0 {
+		http.Error(w, "Fund ID is required", http.StatusBadRequest)
+		return
+	}
+
+	withdrawal, err := h.investmentService.NewWithdrawal(createRequest)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCustomerNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrFundNotFound), errors.Is(err, service.ErrFundNotPriced), errors.Is(err, service.ErrInsufficientUnits):
+			// The request is well formed but the customer's holding can't cover it
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newInvestmentResponse(withdrawal))
+}
+
+// Switch handles moving money from one of a customer's funds into another
+func (h *InvestmentHandler) Switch(w http.ResponseWriter, r *http.Request) {
+	var createRequest model.SwitchCreate
+	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
+		if errors.Is(err, model.ErrInvalidMoney) || errors.Is(err, model.ErrInvalidUnits) || errors.Is(err, model.ErrInvalidPercent) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate request
+	if createRequest.ClientID == 0 {
+		http.Error(w, "Client ID is required", http.StatusBadRequest)
+		return
+	}
+	if createRequest.FromFundID == 0 || createRequest.ToFundID == 0 {
+		http.Error(w, "From and to fund IDs are required", http.StatusBadRequest)
+		return
+	}
+
+	fundSwitch, err := h.investmentService.NewSwitch(createRequest)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCustomerNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrFundNotFound), errors.Is(err, service.ErrFundNotOpen),
+			errors.Is(err, service.ErrFundNotPriced), errors.Is(err, service.ErrInsufficientUnits):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newSwitchResponse(fundSwitch))
+}
+
+// GetSwitch handles retrieving a switch and both of its legs
+func (h *InvestmentHandler) GetSwitch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid switch ID", http.StatusBadRequest)
+		return
+	}
+
+	fundSwitch, err := h.investmentService.GetSwitch(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrSwitchNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newSwitchResponse(fundSwitch))
+}
+
 // Get handles retrieving an investment
 func (h *InvestmentHandler) Get(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -79,49 +344,178 @@ func (h *InvestmentHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := model.InvestmentResponse{
-		ID:       investment.ID,
-		ClientID: investment.ClientID,
-		FundID:   investment.FundID,
-		Amount:   float64(investment.Amount),
-	}
+	response := newInvestmentResponse(investment)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
 
-// GetAll handles retrieving investments by client ID
+// GetAll handles listing investments by client ID, status or both
 func (h *InvestmentHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	clientIDStr := r.URL.Query().Get("client_id")
-	if clientIDStr == "" {
-		http.Error(w, "client_id query parameter is required", http.StatusBadRequest)
-		return
+	query := r.URL.Query()
+	var filter model.InvestmentFilter
+	if clientIDStr := query.Get("client_id"); clientIDStr != "" {
+		clientID, err := strconv.ParseUint(clientIDStr, 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid client ID", http.StatusBadRequest)
+			return
+		}
+		id := uint(clientID)
+		filter.ClientID = &id
 	}
-
-	clientID, err := strconv.ParseUint(clientIDStr, 10, 32)
-	if err != nil {
-		http.Error(w, "Invalid client ID", http.StatusBadRequest)
+	if statusStr := query.Get("status"); statusStr != "" {
+		status := model.InvestmentStatus(statusStr)
+		filter.Status = &status
+	}
+	if filter.ClientID == nil && filter.Status == nil {
+		http.Error(w, "client_id or status query parameter is required", http.StatusBadRequest)
 		return
 	}
 
-	investments, err := h.investmentService.GetInvestmentsByClientID(uint(clientID))
+	investments, err := h.investmentService.ListInvestments(filter)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidInvestmentStatus) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	response := make([]model.InvestmentResponse, len(investments))
 	for i, investment := range investments {
-		response[i] = model.InvestmentResponse{
-			ID:       investment.ID,
-			ClientID: investment.ClientID,
-			FundID:   investment.FundID,
-			Amount:   float64(investment.Amount),
-		}
+		response[i] = newInvestmentResponse(investment)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// Update handles moving an investment to a new status
+func (h *InvestmentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid investment ID", http.StatusBadRequest)
+		return
+	}
+
+	var updateRequest model.InvestmentUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updateRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if updateRequest.Status == "" {
+		http.Error(w, "Status is required", http.StatusBadRequest)
+		return
+	}
+
+	investment, err := h.investmentService.TransitionInvestment(uint(id), updateRequest.Status)
+	if err != nil {
+		writeInvestmentStatusError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newInvestmentResponse(investment))
+}
+
+// Cancel handles cancelling a pending investment
+func (h *InvestmentHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid investment ID", http.StatusBadRequest)
+		return
+	}
+
+	investment, err := h.investmentService.CancelInvestment(uint(id))
+	if err != nil {
+		writeInvestmentStatusError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newInvestmentResponse(investment))
+}
+
+// GetISAAllowance handles reporting how much of a retail customer's ISA allowance is left for the current tax year
+func (h *InvestmentHandler) GetISAAllowance(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	allowance, err := h.investmentService.GetISAAllowance(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrCustomerNotFound) || errors.Is(err, service.ErrNotISACustomer) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.ISAAllowanceResponse{
+		CustomerID:    allowance.CustomerID,
+		TaxYear:       allowance.TaxYear.String(),
+		TaxYearStarts: allowance.TaxYear.FirstDay(),
+		TaxYearEnds:   allowance.TaxYear.LastDay(),
+		Allowance:     allowance.Allowance,
+		Subscribed:    allowance.Subscribed,
+		Remaining:     allowance.Remaining,
+	})
+}
+
+// writeInvestmentStatusError responds to a failed status change with the status code matching the service error
+func writeInvestmentStatusError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvestmentNotFound), errors.Is(err, service.ErrSwitchNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidInvestmentTransition), errors.Is(err, service.ErrInvestmentStatusChanged),
+		errors.Is(err, service.ErrInsufficientUnits):
+		// The investment exists but its current status, or what has been done with its units, doesn't allow the change
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrInvalidInvestmentStatus):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// newInvestmentResponse converts an investment into its API representation
+func newInvestmentResponse(investment *model.Investment) model.InvestmentResponse {
+	return model.InvestmentResponse{
+		ID:         investment.ID,
+		ClientID:   investment.ClientID,
+		FundID:     investment.FundID,
+		Type:       investment.Type,
+		Status:     investment.Status,
+		SwitchID:   investment.SwitchID,
+		BatchID:    investment.BatchID,
+		Source:     investment.Source,
+		ISA:        investment.ISA,
+		ScheduleID: investment.ScheduleID,
+		RunDate:    investment.RunDate,
+		Amount:     investment.Amount,
+		Units:      investment.Units,
+		Price:      investment.Price,
+		PriceDate:  investment.PriceDate,
+	}
+}
+
+func newSwitchResponse(fundSwitch *model.Switch) model.SwitchResponse {
+	return model.SwitchResponse{
+		ID:         fundSwitch.ID,
+		ClientID:   fundSwitch.ClientID,
+		FromFundID: fundSwitch.FromFundID,
+		ToFundID:   fundSwitch.ToFundID,
+		Sell:       newInvestmentResponse(fundSwitch.Sell),
+		Buy:        newInvestmentResponse(fundSwitch.Buy),
+	}
+}
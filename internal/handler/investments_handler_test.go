@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"cushon/internal/mocks"
 	"cushon/internal/model"
+	"cushon/internal/service"
 
 	"github.com/gorilla/mux"
 )
@@ -29,21 +33,27 @@ func TestInvestmentHandler_Create(t *testing.T) {
 			requestBody: model.InvestmentCreate{
 				ClientID: 1,
 				FundID:   1,
-				Amount:   1000.0,
+				Amount:   model.NewMoney(100000, model.DefaultCurrency),
 			},
 			mockInvestment: &model.Investment{
-				ID:       1,
-				ClientID: 1,
-				FundID:   1,
-				Amount:   1000.0,
+				ID:        1,
+				ClientID:  1,
+				FundID:    1,
+				Amount:    model.NewMoney(100000, model.DefaultCurrency),
+				Units:     400000000,
+				Price:     2500000,
+				PriceDate: model.NewDate(2026, time.October, 16),
 			},
 			mockErr:        nil,
 			expectedStatus: http.StatusCreated,
 			expectedBody: model.InvestmentResponse{
-				ID:       1,
-				ClientID: 1,
-				FundID:   1,
-				Amount:   1000.0,
+				ID:        1,
+				ClientID:  1,
+				FundID:    1,
+				Amount:    model.NewMoney(100000, model.DefaultCurrency),
+				Units:     400000000,
+				Price:     2500000,
+				PriceDate: model.NewDate(2026, time.October, 16),
 			},
 			expectedError: "",
 		},
@@ -51,7 +61,7 @@ func TestInvestmentHandler_Create(t *testing.T) {
 			name: "Empty client ID",
 			requestBody: model.InvestmentCreate{
 				FundID: 1,
-				Amount: 1000.0,
+				Amount: model.NewMoney(100000, model.DefaultCurrency),
 			},
 			mockInvestment: nil,
 			mockErr:        nil,
@@ -63,7 +73,7 @@ func TestInvestmentHandler_Create(t *testing.T) {
 			name: "Empty fund ID",
 			requestBody: model.InvestmentCreate{
 				ClientID: 1,
-				Amount:   1000.0,
+				Amount:   model.NewMoney(100000, model.DefaultCurrency),
 			},
 			mockInvestment: nil,
 			mockErr:        nil,
@@ -76,7 +86,7 @@ func TestInvestmentHandler_Create(t *testing.T) {
 			requestBody: model.InvestmentCreate{
 				ClientID: 1,
 				FundID:   1,
-				Amount:   0,
+				Amount:   model.NewMoney(0, model.DefaultCurrency),
 			},
 			mockInvestment: nil,
 			mockErr:        nil,
@@ -89,7 +99,7 @@ func TestInvestmentHandler_Create(t *testing.T) {
 			requestBody: model.InvestmentCreate{
 				ClientID: 1,
 				FundID:   1,
-				Amount:   -1000.0,
+				Amount:   model.NewMoney(-100000, model.DefaultCurrency),
 			},
 			mockInvestment: nil,
 			mockErr:        nil,
@@ -106,12 +116,77 @@ func TestInvestmentHandler_Create(t *testing.T) {
 			expectedBody:   model.InvestmentResponse{},
 			expectedError:  "Invalid request body",
 		},
+		{
+			name: "Customer not found",
+			requestBody: model.InvestmentCreate{
+				ClientID: 101,
+				FundID:   1,
+				Amount:   model.NewMoney(100000, model.DefaultCurrency),
+			},
+			mockInvestment: nil,
+			mockErr:        service.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   model.InvestmentResponse{},
+			expectedError:  "customer not found",
+		},
+		{
+			name: "Fund not found",
+			requestBody: model.InvestmentCreate{
+				ClientID: 1,
+				FundID:   101,
+				Amount:   model.NewMoney(100000, model.DefaultCurrency),
+			},
+			mockInvestment: nil,
+			mockErr:        service.ErrFundNotFound,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   model.InvestmentResponse{},
+			expectedError:  "fund not found",
+		},
+		{
+			name: "Fund closed to new investments",
+			requestBody: model.InvestmentCreate{
+				ClientID: 1,
+				FundID:   2,
+				Amount:   model.NewMoney(100000, model.DefaultCurrency),
+			},
+			mockInvestment: nil,
+			mockErr:        service.ErrFundNotOpen,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   model.InvestmentResponse{},
+			expectedError:  "fund is not open to new investments",
+		},
+		{
+			name: "Fund not priced yet",
+			requestBody: model.InvestmentCreate{
+				ClientID: 1,
+				FundID:   3,
+				Amount:   model.NewMoney(100000, model.DefaultCurrency),
+			},
+			mockInvestment: nil,
+			mockErr:        service.ErrFundNotPriced,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   model.InvestmentResponse{},
+			expectedError:  "fund has no price",
+		},
+		{
+			name: "ISA allowance exceeded",
+			requestBody: model.InvestmentCreate{
+				ClientID: 1,
+				FundID:   1,
+				Amount:   model.NewMoney(500000, model.DefaultCurrency),
+			},
+			mockInvestment: nil,
+			mockErr:        fmt.Errorf("%w: 1000.00 GBP remaining for the 2026/27 tax year, 5000.00 GBP requested", service.ErrISAAllowanceExceeded),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   model.InvestmentResponse{},
+			expectedError:  "ISA annual allowance exceeded: 1000.00 GBP remaining for the 2026/27 tax year, 5000.00 GBP requested",
+		},
 		{
 			name: "Service error",
 			requestBody: model.InvestmentCreate{
 				ClientID: 1,
 				FundID:   1,
-				Amount:   1000.0,
+				Amount:   model.NewMoney(100000, model.DefaultCurrency),
 			},
 			mockInvestment: nil,
 			mockErr:        errors.New("service error"),
@@ -169,6 +244,12 @@ func TestInvestmentHandler_Create(t *testing.T) {
 					t.Errorf("handler returned wrong Amount: got %v want %v",
 						response.Amount, tt.expectedBody.Amount)
 				}
+				if response.Units != tt.expectedBody.Units || response.Price != tt.expectedBody.Price ||
+					response.PriceDate != tt.expectedBody.PriceDate {
+					t.Errorf("handler returned wrong pricing: got %v units at %v on %v want %v units at %v on %v",
+						response.Units, response.Price, response.PriceDate,
+						tt.expectedBody.Units, tt.expectedBody.Price, tt.expectedBody.PriceDate)
+				}
 			} else if tt.expectedError != "" {
 				if rr.Body.String() != tt.expectedError+"\n" {
 					t.Errorf("handler returned wrong error message: got %v want %v",
@@ -179,6 +260,601 @@ func TestInvestmentHandler_Create(t *testing.T) {
 	}
 }
 
+func TestInvestmentHandler_Create_RejectsInexactAmounts(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "More than two decimal places",
+			body: `{"client_id": 1, "fund_id": 1, "amount": {"amount": 1234567.891, "currency": "GBP"}}`,
+		},
+		{
+			name: "Exponent notation",
+			body: `{"client_id": 1, "fund_id": 1, "amount": {"amount": "1e6", "currency": "GBP"}}`,
+		},
+		{
+			name: "Missing currency",
+			body: `{"client_id": 1, "fund_id": 1, "amount": {"amount": "100.00"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.InvestmentService{}
+			handler := NewInvestmentHandler(mockService)
+
+			req := httptest.NewRequest("POST", "/investments", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+
+			handler.Create(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					rr.Code, http.StatusBadRequest)
+			}
+			if !strings.HasPrefix(rr.Body.String(), model.ErrInvalidMoney.Error()) {
+				t.Errorf("handler returned wrong error message: got %v", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestInvestmentHandler_SalaryContribution(t *testing.T) {
+	contributions := []*model.Investment{
+		{ID: 1, ClientID: 1, FundID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusPending, Source: model.ContributionSourceEmployee, Amount: model.NewMoney(12500, model.DefaultCurrency)},
+		{ID: 2, ClientID: 1, FundID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusPending, Source: model.ContributionSourceEmployer, Amount: model.NewMoney(7500, model.DefaultCurrency)},
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Scheme rates",
+			body:           `{"client_id":1,"fund_id":1,"salary":{"amount":"2500.00","currency":"GBP"}}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Chosen employee rate",
+			body:           `{"client_id":1,"fund_id":1,"salary":{"amount":"2500.00","currency":"GBP"},"employee_rate":"5"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid rate",
+			body:           `{"client_id":1,"fund_id":1,"salary":{"amount":"2500.00","currency":"GBP"},"employee_rate":"five"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing fund ID",
+			body:           `{"client_id":1,"salary":{"amount":"2500.00","currency":"GBP"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Customer not found",
+			body:           `{"client_id":999,"fund_id":1,"salary":{"amount":"2500.00","currency":"GBP"}}`,
+			mockErr:        service.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Retail customer",
+			body:           `{"client_id":2,"fund_id":1,"salary":{"amount":"2500.00","currency":"GBP"}}`,
+			mockErr:        service.ErrContributionSourceNotAllowed,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Employer without a scheme",
+			body:           `{"client_id":1,"fund_id":1,"salary":{"amount":"2500.00","currency":"GBP"}}`,
+			mockErr:        service.ErrNoContributionScheme,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewInvestmentHandler(&mocks.InvestmentService{MockInvestments: contributions, MockErr: tt.mockErr})
+
+			req := httptest.NewRequest("POST", "/salary-contributions", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			handler.SalaryContribution(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v",
+					rr.Code, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+
+			var response []model.InvestmentResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Could not decode response: %v", err)
+			}
+			if len(response) != 2 || response[0].Source != model.ContributionSourceEmployee || response[1].Source != model.ContributionSourceEmployer {
+				t.Errorf("handler returned wrong contributions: got %+v", response)
+			}
+		})
+	}
+}
+
+func TestInvestmentHandler_Contribute(t *testing.T) {
+	contributions := []*model.Investment{
+		{ID: 1, ClientID: 1, FundID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusPending, Source: model.ContributionSourceOneOff, Amount: model.NewMoney(6000, model.DefaultCurrency)},
+		{ID: 2, ClientID: 1, FundID: 3, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusPending, Source: model.ContributionSourceOneOff, Amount: model.NewMoney(4000, model.DefaultCurrency)},
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Contribute across the allocation",
+			body:           `{"client_id":1,"amount":{"amount":"100.00","currency":"GBP"}}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid amount",
+			body:           `{"client_id":1,"amount":{"amount":"100.001","currency":"GBP"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing client ID",
+			body:           `{"amount":{"amount":"100.00","currency":"GBP"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Customer not found",
+			body:           `{"client_id":999,"amount":{"amount":"100.00","currency":"GBP"}}`,
+			mockErr:        service.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "No allocation",
+			body:           `{"client_id":1,"amount":{"amount":"100.00","currency":"GBP"}}`,
+			mockErr:        service.ErrAllocationNotFound,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Allocated fund closed",
+			body:           `{"client_id":1,"amount":{"amount":"100.00","currency":"GBP"}}`,
+			mockErr:        service.ErrFundNotOpen,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "ISA allowance exceeded",
+			body:           `{"client_id":1,"amount":{"amount":"100.00","currency":"GBP"}}`,
+			mockErr:        service.ErrISAAllowanceExceeded,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewInvestmentHandler(&mocks.InvestmentService{MockInvestments: contributions, MockErr: tt.mockErr})
+
+			req := httptest.NewRequest("POST", "/contributions", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			handler.Contribute(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v",
+					rr.Code, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+
+			var response []model.InvestmentResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Could not decode response: %v", err)
+			}
+			if len(response) != 2 || response[0].FundID != 1 || response[1].FundID != 3 {
+				t.Errorf("handler returned wrong contributions: got %+v", response)
+			}
+		})
+	}
+}
+
+func TestInvestmentHandler_ImportPayroll(t *testing.T) {
+	imported := &model.PayrollImport{
+		EmployerID: 1,
+		Total:      model.NewMoney(20000, model.DefaultCurrency),
+		Investments: []*model.Investment{
+			{ID: 1, ClientID: 1, FundID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusPending, Source: model.ContributionSourceEmployee, Amount: model.NewMoney(12500, model.DefaultCurrency)},
+			{ID: 2, ClientID: 1, FundID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusPending, Source: model.ContributionSourceEmployer, Amount: model.NewMoney(7500, model.DefaultCurrency)},
+		},
+	}
+	rowErrors := &model.PayrollError{Rows: []model.PayrollRowError{
+		{Line: 4, Error: "customer not found"},
+		{Line: 6, Error: "invalid contribution source \"bonus\""},
+	}}
+
+	tests := []struct {
+		name           string
+		employerID     string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "Imported", employerID: "1", expectedStatus: http.StatusCreated},
+		{name: "Invalid rows", employerID: "1", mockErr: rowErrors, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "Totals don't match", employerID: "1", mockErr: service.ErrPayrollTotalsMismatch, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "Inactive employer", employerID: "1", mockErr: service.ErrEmployerInactive, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "Missing header", employerID: "1", mockErr: fmt.Errorf("%w: the file ends before its header does", model.ErrInvalidPayroll), expectedStatus: http.StatusBadRequest},
+		{name: "Employer not found", employerID: "99", mockErr: service.ErrEmployerNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Invalid employer ID", employerID: "abc", expectedStatus: http.StatusBadRequest},
+		{name: "Storage error", employerID: "1", mockErr: errors.New("database unavailable"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewInvestmentHandler(&mocks.InvestmentService{MockPayroll: imported, MockErr: tt.mockErr})
+
+			req := httptest.NewRequest("POST", "/employers/"+tt.employerID+"/payroll", strings.NewReader("contributions,total\n"))
+			req = mux.SetURLVars(req, map[string]string{"id": tt.employerID})
+			rr := httptest.NewRecorder()
+
+			handler.ImportPayroll(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v",
+					rr.Code, tt.expectedStatus)
+			}
+
+			switch {
+			case tt.expectedStatus == http.StatusCreated:
+				var response model.PayrollImportResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if response.EmployerID != 1 || response.Contributions != 2 || response.Total != imported.Total || len(response.Investments) != 2 {
+					t.Errorf("handler returned wrong import: got %+v", response)
+				}
+			case tt.mockErr == rowErrors:
+				var response model.PayrollErrorResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if len(response.Rows) != 2 || response.Rows[1].Line != 6 || response.Error == "" {
+					t.Errorf("handler returned wrong row report: got %+v", response)
+				}
+			}
+		})
+	}
+}
+
+func TestInvestmentHandler_Withdraw(t *testing.T) {
+	withdrawal := &model.Investment{
+		ID:        2,
+		ClientID:  1,
+		FundID:    1,
+		Type:      model.TransactionTypeWithdrawal,
+		Amount:    model.NewMoney(-27500, model.DefaultCurrency),
+		Units:     -100000000,
+		Price:     2750000,
+		PriceDate: model.NewDate(2026, time.October, 16),
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockErr        error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "Withdraw an amount",
+			body:           `{"client_id": 1, "fund_id": 1, "amount": {"amount": "275.00", "currency": "GBP"}}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Withdraw units",
+			body:           `{"client_id": 1, "fund_id": 1, "units": "100.000000"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid units",
+			body:           `{"client_id": 1, "fund_id": 1, "units": "1.0000001"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `invalid units: "1.0000001": at most 6 decimal places are allowed`,
+		},
+		{
+			name:           "Invalid request body",
+			body:           `{"client_id": "one"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+		},
+		{
+			name:           "Empty client ID",
+			body:           `{"fund_id": 1, "units": "1"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Client ID is required",
+		},
+		{
+			name:           "Empty fund ID",
+			body:           `{"client_id": 1, "units": "1"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Fund ID is required",
+		},
+		{
+			name:           "Customer not found",
+			body:           `{"client_id": 101, "fund_id": 1, "units": "1"}`,
+			mockErr:        service.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "customer not found",
+		},
+		{
+			name:           "Not enough units held",
+			body:           `{"client_id": 1, "fund_id": 1, "units": "1000"}`,
+			mockErr:        service.ErrInsufficientUnits,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  "not enough units held",
+		},
+		{
+			name:           "Fund has no price",
+			body:           `{"client_id": 1, "fund_id": 1, "units": "1"}`,
+			mockErr:        service.ErrFundNotPriced,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  "fund has no price",
+		},
+		{
+			name:           "Neither amount nor units",
+			body:           `{"client_id": 1, "fund_id": 1}`,
+			mockErr:        errors.New("either an amount or a number of units must be withdrawn"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "either an amount or a number of units must be withdrawn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.InvestmentService{MockInvestment: withdrawal, MockErr: tt.mockErr}
+			handler := NewInvestmentHandler(mockService)
+
+			req := httptest.NewRequest("POST", "/withdrawals", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+
+			handler.Withdraw(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedError != "" {
+				if rr.Body.String() != tt.expectedError+"\n" {
+					t.Errorf("handler returned wrong error message: got %v want %v",
+						rr.Body.String(), tt.expectedError)
+				}
+				return
+			}
+
+			var response model.InvestmentResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Type != model.TransactionTypeWithdrawal || response.Amount != withdrawal.Amount || response.Units != withdrawal.Units {
+				t.Errorf("handler returned unexpected body: got %+v want %+v", response, withdrawal)
+			}
+		})
+	}
+}
+
+func TestInvestmentHandler_Switch(t *testing.T) {
+	fundSwitch := &model.Switch{
+		ID:         1,
+		ClientID:   1,
+		FromFundID: 1,
+		ToFundID:   2,
+		Sell: &model.Investment{
+			ID: 2, ClientID: 1, FundID: 1, Type: model.TransactionTypeSwitchOut, SwitchID: 1,
+			Amount: model.NewMoney(-27500, model.DefaultCurrency), Units: -100000000, Price: 2750000,
+		},
+		Buy: &model.Investment{
+			ID: 3, ClientID: 1, FundID: 2, Type: model.TransactionTypeSwitchIn, SwitchID: 1,
+			Amount: model.NewMoney(27500, model.DefaultCurrency), Units: 220000000, Price: 1250000,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockErr        error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "Switch a percentage",
+			body:           `{"client_id": 1, "from_fund_id": 1, "to_fund_id": 2, "percentage": "25"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid percentage",
+			body:           `{"client_id": 1, "from_fund_id": 1, "to_fund_id": 2, "percentage": "25.0001"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `invalid percentage: "25.0001": at most 3 decimal places are allowed`,
+		},
+		{
+			name:           "Empty client ID",
+			body:           `{"from_fund_id": 1, "to_fund_id": 2, "units": "1"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Client ID is required",
+		},
+		{
+			name:           "Empty target fund ID",
+			body:           `{"client_id": 1, "from_fund_id": 1, "units": "1"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "From and to fund IDs are required",
+		},
+		{
+			name:           "Customer not found",
+			body:           `{"client_id": 101, "from_fund_id": 1, "to_fund_id": 2, "units": "1"}`,
+			mockErr:        service.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "customer not found",
+		},
+		{
+			name:           "Target fund not open",
+			body:           `{"client_id": 1, "from_fund_id": 1, "to_fund_id": 2, "units": "1"}`,
+			mockErr:        service.ErrFundNotOpen,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  "fund is not open to new investments",
+		},
+		{
+			name:           "Not enough units held",
+			body:           `{"client_id": 1, "from_fund_id": 1, "to_fund_id": 2, "units": "1000"}`,
+			mockErr:        service.ErrInsufficientUnits,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  "not enough units held",
+		},
+		{
+			name:           "Same fund",
+			body:           `{"client_id": 1, "from_fund_id": 1, "to_fund_id": 1, "units": "1"}`,
+			mockErr:        errors.New("a switch must be between two different funds"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "a switch must be between two different funds",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.InvestmentService{MockSwitch: fundSwitch, MockErr: tt.mockErr}
+			handler := NewInvestmentHandler(mockService)
+
+			req := httptest.NewRequest("POST", "/switches", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+
+			handler.Switch(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedError != "" {
+				if rr.Body.String() != tt.expectedError+"\n" {
+					t.Errorf("handler returned wrong error message: got %v want %v",
+						rr.Body.String(), tt.expectedError)
+				}
+				return
+			}
+
+			var response model.SwitchResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.ID != 1 || response.Sell.SwitchID != 1 || response.Buy.SwitchID != 1 {
+				t.Errorf("handler returned unexpected switch: %+v", response)
+			}
+			if response.Sell.Units != -100000000 || response.Buy.Units != 220000000 {
+				t.Errorf("handler returned unexpected legs: sell %+v, buy %+v", response.Sell, response.Buy)
+			}
+		})
+	}
+}
+
+func TestInvestmentHandler_GetSwitch(t *testing.T) {
+	fundSwitch := &model.Switch{
+		ID:   1,
+		Sell: &model.Investment{ID: 2, SwitchID: 1, Type: model.TransactionTypeSwitchOut},
+		Buy:  &model.Investment{ID: 3, SwitchID: 1, Type: model.TransactionTypeSwitchIn},
+	}
+
+	tests := []struct {
+		name           string
+		id             string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "Existing switch", id: "1", expectedStatus: http.StatusOK},
+		{name: "Switch not found", id: "999", mockErr: service.ErrSwitchNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Invalid ID", id: "abc", expectedStatus: http.StatusBadRequest},
+		{name: "Service error", id: "1", mockErr: errors.New("database unavailable"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewInvestmentHandler(&mocks.InvestmentService{MockSwitch: fundSwitch, MockErr: tt.mockErr})
+
+			req := httptest.NewRequest("GET", "/switches/"+tt.id, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rr := httptest.NewRecorder()
+
+			handler.GetSwitch(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					rr.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestInvestmentHandler_GetISAAllowance(t *testing.T) {
+	allowance := &model.ISAAllowance{
+		CustomerID: 1,
+		TaxYear:    model.TaxYear{StartYear: 2026},
+		Allowance:  model.ISAAnnualAllowance,
+		Subscribed: model.NewMoney(350000, model.DefaultCurrency),
+		Remaining:  model.NewMoney(1650000, model.DefaultCurrency),
+	}
+
+	tests := []struct {
+		name           string
+		id             string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "Retail customer", id: "1", expectedStatus: http.StatusOK},
+		{name: "Customer not found", id: "999", mockErr: service.ErrCustomerNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Employed customer", id: "2", mockErr: service.ErrNotISACustomer, expectedStatus: http.StatusNotFound},
+		{name: "Invalid ID", id: "abc", expectedStatus: http.StatusBadRequest},
+		{name: "Service error", id: "1", mockErr: errors.New("database unavailable"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewInvestmentHandler(&mocks.InvestmentService{MockAllowance: allowance, MockErr: tt.mockErr})
+
+			req := httptest.NewRequest("GET", "/customers/"+tt.id+"/isa-allowance", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rr := httptest.NewRecorder()
+
+			handler.GetISAAllowance(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v",
+					rr.Code, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response model.ISAAllowanceResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Could not decode response: %v", err)
+			}
+			want := model.ISAAllowanceResponse{
+				CustomerID:    1,
+				TaxYear:       "2026/27",
+				TaxYearStarts: model.NewDate(2026, time.April, 6),
+				TaxYearEnds:   model.NewDate(2027, time.April, 5),
+				Allowance:     model.ISAAnnualAllowance,
+				Subscribed:    allowance.Subscribed,
+				Remaining:     allowance.Remaining,
+			}
+			if response != want {
+				t.Errorf("handler returned wrong allowance: got %+v want %+v", response, want)
+			}
+		})
+	}
+}
+
 func TestInvestmentHandler_Get(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -196,7 +872,7 @@ func TestInvestmentHandler_Get(t *testing.T) {
 				ID:       1,
 				ClientID: 1,
 				FundID:   1,
-				Amount:   1000.0,
+				Amount:   model.NewMoney(100000, model.DefaultCurrency),
 			},
 			mockErr:        nil,
 			expectedStatus: http.StatusOK,
@@ -204,7 +880,7 @@ func TestInvestmentHandler_Get(t *testing.T) {
 				ID:       1,
 				ClientID: 1,
 				FundID:   1,
-				Amount:   1000.0,
+				Amount:   model.NewMoney(100000, model.DefaultCurrency),
 			},
 			expectedError: "",
 		},
@@ -287,7 +963,7 @@ func TestInvestmentHandler_Get(t *testing.T) {
 func TestInvestmentHandler_GetAll(t *testing.T) {
 	tests := []struct {
 		name            string
-		clientID        string
+		query           string
 		mockInvestments []*model.Investment
 		mockErr         error
 		expectedStatus  int
@@ -295,20 +971,20 @@ func TestInvestmentHandler_GetAll(t *testing.T) {
 		expectedError   string
 	}{
 		{
-			name:     "Get investments successfully",
-			clientID: "1",
+			name:  "Get investments successfully",
+			query: "client_id=1",
 			mockInvestments: []*model.Investment{
 				{
 					ID:       1,
 					ClientID: 1,
 					FundID:   1,
-					Amount:   1000.0,
+					Amount:   model.NewMoney(100000, model.DefaultCurrency),
 				},
 				{
 					ID:       2,
 					ClientID: 1,
 					FundID:   2,
-					Amount:   2000.0,
+					Amount:   model.NewMoney(200000, model.DefaultCurrency),
 				},
 			},
 			mockErr:        nil,
@@ -318,20 +994,20 @@ func TestInvestmentHandler_GetAll(t *testing.T) {
 					ID:       1,
 					ClientID: 1,
 					FundID:   1,
-					Amount:   1000.0,
+					Amount:   model.NewMoney(100000, model.DefaultCurrency),
 				},
 				{
 					ID:       2,
 					ClientID: 1,
 					FundID:   2,
-					Amount:   2000.0,
+					Amount:   model.NewMoney(200000, model.DefaultCurrency),
 				},
 			},
 			expectedError: "",
 		},
 		{
 			name:            "No investments found",
-			clientID:        "1",
+			query:           "client_id=1",
 			mockInvestments: []*model.Investment{},
 			mockErr:         nil,
 			expectedStatus:  http.StatusOK,
@@ -340,16 +1016,43 @@ func TestInvestmentHandler_GetAll(t *testing.T) {
 		},
 		{
 			name:            "Invalid client ID",
-			clientID:        "invalid",
+			query:           "client_id=invalid",
 			mockInvestments: nil,
 			mockErr:         nil,
 			expectedStatus:  http.StatusBadRequest,
 			expectedBody:    nil,
 			expectedError:   "Invalid client ID",
 		},
+		{
+			name:            "Filter by status",
+			query:           "status=pending",
+			mockInvestments: []*model.Investment{{ID: 3, ClientID: 2, FundID: 1, Status: model.InvestmentStatusPending, Amount: model.NewMoney(5000, model.DefaultCurrency)}},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    []model.InvestmentResponse{{ID: 3, ClientID: 2, FundID: 1, Status: model.InvestmentStatusPending, Amount: model.NewMoney(5000, model.DefaultCurrency)}},
+		},
+		{
+			name:            "Filter by client and status",
+			query:           "client_id=2&status=pending",
+			mockInvestments: []*model.Investment{{ID: 3, ClientID: 2, FundID: 1, Status: model.InvestmentStatusPending, Amount: model.NewMoney(5000, model.DefaultCurrency)}},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    []model.InvestmentResponse{{ID: 3, ClientID: 2, FundID: 1, Status: model.InvestmentStatusPending, Amount: model.NewMoney(5000, model.DefaultCurrency)}},
+		},
+		{
+			name:           "No filter",
+			query:          "",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "client_id or status query parameter is required",
+		},
+		{
+			name:           "Invalid status",
+			query:          "status=dealt",
+			mockErr:        fmt.Errorf("%w: %q", service.ErrInvalidInvestmentStatus, "dealt"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `invalid investment status: "dealt"`,
+		},
 		{
 			name:            "Service error",
-			clientID:        "1",
+			query:           "client_id=1",
 			mockInvestments: nil,
 			mockErr:         errors.New("service error"),
 			expectedStatus:  http.StatusInternalServerError,
@@ -367,7 +1070,7 @@ func TestInvestmentHandler_GetAll(t *testing.T) {
 
 			handler := NewInvestmentHandler(mockService)
 
-			req := httptest.NewRequest("GET", "/investments?client_id="+tt.clientID, nil)
+			req := httptest.NewRequest("GET", "/investments?"+tt.query, nil)
 			rr := httptest.NewRecorder()
 
 			handler.GetAll(rr, req)
@@ -399,6 +1102,9 @@ func TestInvestmentHandler_GetAll(t *testing.T) {
 					if response[i].FundID != expected.FundID {
 						t.Errorf("investment[%d].FundID = %v, want %v", i, response[i].FundID, expected.FundID)
 					}
+					if response[i].Status != expected.Status {
+						t.Errorf("investment[%d].Status = %v, want %v", i, response[i].Status, expected.Status)
+					}
 					if response[i].Amount != expected.Amount {
 						t.Errorf("investment[%d].Amount = %v, want %v", i, response[i].Amount, expected.Amount)
 					}
@@ -412,3 +1118,144 @@ func TestInvestmentHandler_GetAll(t *testing.T) {
 		})
 	}
 }
+
+func TestInvestmentHandler_Update(t *testing.T) {
+	placed := &model.Investment{ID: 1, ClientID: 1, FundID: 1, Status: model.InvestmentStatusPlaced, Amount: model.NewMoney(100000, model.DefaultCurrency)}
+
+	tests := []struct {
+		name           string
+		id             string
+		body           string
+		mockErr        error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "Place an investment",
+			id:             "1",
+			body:           `{"status": "placed"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing status",
+			id:             "1",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Status is required",
+		},
+		{
+			name:           "Invalid request body",
+			id:             "1",
+			body:           `{"status": 1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+		},
+		{
+			name:           "Invalid ID",
+			id:             "abc",
+			body:           `{"status": "placed"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid investment ID",
+		},
+		{
+			name:           "Unknown status",
+			id:             "1",
+			body:           `{"status": "dealt"}`,
+			mockErr:        fmt.Errorf("%w: %q", service.ErrInvalidInvestmentStatus, "dealt"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `invalid investment status: "dealt"`,
+		},
+		{
+			name:           "Invalid transition",
+			id:             "1",
+			body:           `{"status": "settled"}`,
+			mockErr:        fmt.Errorf("%w: from pending to settled", service.ErrInvalidInvestmentTransition),
+			expectedStatus: http.StatusConflict,
+			expectedError:  "invalid investment status change: from pending to settled",
+		},
+		{
+			name:           "Units already sold",
+			id:             "1",
+			body:           `{"status": "failed"}`,
+			mockErr:        service.ErrInsufficientUnits,
+			expectedStatus: http.StatusConflict,
+			expectedError:  "not enough units held",
+		},
+		{
+			name:           "Investment not found",
+			id:             "999",
+			body:           `{"status": "placed"}`,
+			mockErr:        service.ErrInvestmentNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "investment not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewInvestmentHandler(&mocks.InvestmentService{MockInvestment: placed, MockErr: tt.mockErr})
+
+			req := httptest.NewRequest("PATCH", "/investments/"+tt.id, bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rr := httptest.NewRecorder()
+
+			handler.Update(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedError != "" {
+				if rr.Body.String() != tt.expectedError+"\n" {
+					t.Errorf("handler returned wrong error message: got %v want %v",
+						rr.Body.String(), tt.expectedError)
+				}
+				return
+			}
+
+			var response model.InvestmentResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Status != model.InvestmentStatusPlaced {
+				t.Errorf("Status = %v, want %v", response.Status, model.InvestmentStatusPlaced)
+			}
+		})
+	}
+}
+
+func TestInvestmentHandler_Cancel(t *testing.T) {
+	cancelled := &model.Investment{ID: 1, ClientID: 1, FundID: 1, Status: model.InvestmentStatusCancelled, Amount: model.NewMoney(100000, model.DefaultCurrency)}
+
+	tests := []struct {
+		name           string
+		id             string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "Cancel a pending investment", id: "1", expectedStatus: http.StatusOK},
+		{name: "Investment already placed", id: "1", mockErr: service.ErrInvalidInvestmentTransition, expectedStatus: http.StatusConflict},
+		{name: "Status changed concurrently", id: "1", mockErr: service.ErrInvestmentStatusChanged, expectedStatus: http.StatusConflict},
+		{name: "Investment not found", id: "999", mockErr: service.ErrInvestmentNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Invalid ID", id: "abc", expectedStatus: http.StatusBadRequest},
+		{name: "Service error", id: "1", mockErr: errors.New("database unavailable"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewInvestmentHandler(&mocks.InvestmentService{MockInvestment: cancelled, MockErr: tt.mockErr})
+
+			req := httptest.NewRequest("POST", "/investments/"+tt.id+"/cancel", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rr := httptest.NewRecorder()
+
+			handler.Cancel(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v",
+					rr.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// PortfolioHandler handles requests for customers' portfolio valuations
+type PortfolioHandler struct {
+	portfolioService service.Portfolio
+}
+
+// NewPortfolioHandler creates a new portfolio handler
+func NewPortfolioHandler(portfolioService service.Portfolio) *PortfolioHandler {
+	return &PortfolioHandler{
+		portfolioService: portfolioService,
+	}
+}
+
+// Get handles retrieving a customer's portfolio, valued at the latest fund prices
+func (h *PortfolioHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	portfolio, err := h.portfolioService.GetPortfolio(uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrCustomerNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newPortfolioResponse(portfolio))
+}
+
+// newPortfolioResponse converts a portfolio into its API representation
+func newPortfolioResponse(portfolio *model.Portfolio) model.PortfolioResponse {
+	holdings := make([]model.HoldingResponse, len(portfolio.Holdings))
+	for i, holding := range portfolio.Holdings {
+		holdings[i] = model.HoldingResponse{
+			FundID:      holding.FundID,
+			FundName:    holding.FundName,
+			Units:       holding.Units,
+			Contributed: holding.Contributed,
+			Price:       holding.Price,
+			PriceDate:   holding.PriceDate,
+			Value:       holding.Value,
+			Gain:        holding.Gain,
+			Allocation:  holding.Allocation,
+		}
+	}
+
+	bySource := portfolio.ContributionsBySource
+	if bySource == nil {
+		bySource = make(map[model.ContributionSource]model.Money)
+	}
+
+	return model.PortfolioResponse{
+		CustomerID:            portfolio.CustomerID,
+		Holdings:              holdings,
+		Contributed:           portfolio.Contributed,
+		ContributionsBySource: bySource,
+		Value:                 portfolio.Value,
+		Gain:                  portfolio.Gain,
+	}
+}
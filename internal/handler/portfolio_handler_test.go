@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+	"cushon/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+func TestPortfolioHandler_Get(t *testing.T) {
+	gbp := func(minor int64) model.Money { return model.NewMoney(minor, model.DefaultCurrency) }
+
+	tests := []struct {
+		name           string
+		customerID     string
+		mockPortfolio  *model.Portfolio
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:       "Portfolio with holdings",
+			customerID: "1",
+			mockPortfolio: &model.Portfolio{
+				CustomerID: 1,
+				Holdings: []*model.Holding{
+					{
+						FundID:      1,
+						FundName:    "Global Equities",
+						Units:       650000000,
+						Contributed: gbp(150000),
+						Price:       3000000,
+						PriceDate:   model.NewDate(2026, time.October, 16),
+						Value:       gbp(195000),
+						Gain:        gbp(45000),
+						Allocation:  model.OneHundredPercent,
+					},
+				},
+				Contributed: gbp(150000),
+				ContributionsBySource: map[model.ContributionSource]model.Money{
+					model.ContributionSourceEmployee: gbp(90000),
+					model.ContributionSourceEmployer: gbp(60000),
+				},
+				Value: gbp(195000),
+				Gain:  gbp(45000),
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid customer ID",
+			customerID:     "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Customer not found",
+			customerID:     "99",
+			mockErr:        service.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Service error",
+			customerID:     "1",
+			mockErr:        errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewPortfolioHandler(&mocks.PortfolioService{MockPortfolio: tt.mockPortfolio, MockErr: tt.mockErr})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/customers/{id}/portfolio", handler.Get).Methods("GET")
+
+			req := httptest.NewRequest("GET", "/customers/"+tt.customerID+"/portfolio", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					CustomerID uint `json:"customer_id"`
+					Holdings   []struct {
+						FundName   string      `json:"fund_name"`
+						Units      string      `json:"units"`
+						Value      model.Money `json:"value"`
+						Gain       model.Money `json:"gain"`
+						Allocation string      `json:"allocation"`
+					} `json:"holdings"`
+					ContributionsBySource map[model.ContributionSource]model.Money `json:"contributions_by_source"`
+					Value                 model.Money                              `json:"value"`
+				}
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatalf("Could not decode response: %v", err)
+				}
+				if response.CustomerID != 1 || response.Value != gbp(195000) || len(response.Holdings) != 1 {
+					t.Fatalf("handler returned %+v, want the customer's portfolio", response)
+				}
+				if response.ContributionsBySource[model.ContributionSourceEmployer] != gbp(60000) {
+					t.Errorf("handler returned contributions by source %+v", response.ContributionsBySource)
+				}
+				holding := response.Holdings[0]
+				if holding.FundName != "Global Equities" || holding.Units != "650.000000" || holding.Gain != gbp(45000) ||
+					holding.Allocation != "100.000" {
+					t.Errorf("handler returned holding %+v", holding)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// RebalanceHandler handles requests for bringing customers' portfolios back to their target allocations
+type RebalanceHandler struct {
+	rebalancingService service.Rebalancing
+}
+
+// NewRebalanceHandler creates a new rebalance handler
+func NewRebalanceHandler(rebalancingService service.Rebalancing) *RebalanceHandler {
+	return &RebalanceHandler{
+		rebalancingService: rebalancingService,
+	}
+}
+
+// Preview handles showing how far a customer's portfolio has drifted and the trades rebalancing would make
+func (h *RebalanceHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	rebalance, err := h.rebalancingService.PreviewRebalance(uint(id))
+	if err != nil {
+		writeRebalanceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newRebalanceResponse(rebalance))
+}
+
+// Execute handles rebalancing a customer's portfolio straight away instead of waiting for the scheduler
+func (h *RebalanceHandler) Execute(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	rebalance, err := h.rebalancingService.Rebalance(uint(id))
+	if err != nil {
+		writeRebalanceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if rebalance.Executed {
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	json.NewEncoder(w).Encode(newRebalanceResponse(rebalance))
+}
+
+// Run handles rebalancing every customer who has chosen a target allocation
+func (h *RebalanceHandler) Run(w http.ResponseWriter, r *http.Request) {
+	run, err := h.rebalancingService.RebalanceAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := model.RebalanceRunResponse{
+		Rebalanced: make([]model.RebalanceResponse, len(run.Rebalanced)),
+		Failed:     run.Failed,
+	}
+	for i, rebalance := range run.Rebalanced {
+		response.Rebalanced[i] = newRebalanceResponse(rebalance)
+	}
+	if response.Failed == nil {
+		response.Failed = []model.RebalanceFailure{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeRebalanceError responds with 404 when the customer doesn't exist, 422 when their portfolio can't be
+// rebalanced and 500 otherwise
+func writeRebalanceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrCustomerNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrAllocationNotFound), errors.Is(err, service.ErrFundNotFound),
+		errors.Is(err, service.ErrFundNotOpen), errors.Is(err, service.ErrFundNotPriced),
+		errors.Is(err, service.ErrInsufficientUnits):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// newRebalanceResponse converts a rebalance into its API representation
+func newRebalanceResponse(rebalance *model.Rebalance) model.RebalanceResponse {
+	response := model.RebalanceResponse{
+		CustomerID: rebalance.CustomerID,
+		Value:      rebalance.Value,
+		Funds:      rebalance.Funds,
+		Trades:     rebalance.Trades,
+		Executed:   rebalance.Executed,
+	}
+	if response.Funds == nil {
+		response.Funds = []model.FundDrift{}
+	}
+	if response.Trades == nil {
+		response.Trades = []model.RebalanceTrade{}
+	}
+	for _, fundSwitch := range rebalance.Switches {
+		response.Switches = append(response.Switches, newSwitchResponse(fundSwitch))
+	}
+	return response
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+	"cushon/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+func TestRebalanceHandler(t *testing.T) {
+	gbp := func(minor int64) model.Money { return model.NewMoney(minor, model.DefaultCurrency) }
+	planned := &model.Rebalance{
+		CustomerID: 1,
+		Value:      gbp(100000),
+		Funds: []model.FundDrift{
+			{FundID: 1, Value: gbp(70000), Weight: 70000, Target: 60000, TargetValue: gbp(60000), Drift: 10000},
+			{FundID: 3, Value: gbp(30000), Weight: 30000, Target: 40000, TargetValue: gbp(40000), Drift: -10000},
+		},
+		Trades: []model.RebalanceTrade{{FromFundID: 1, ToFundID: 3, Amount: gbp(10000)}},
+	}
+	executed := *planned
+	executed.Executed = true
+	executed.Switches = []*model.Switch{{
+		ID:         5,
+		ClientID:   1,
+		FromFundID: 1,
+		ToFundID:   3,
+		Sell:       &model.Investment{ID: 10, ClientID: 1, FundID: 1, Amount: gbp(-10000), Units: -40000000},
+		Buy:        &model.Investment{ID: 11, ClientID: 1, FundID: 3, Amount: gbp(10000), Units: 50000000},
+	}}
+	balanced := &model.Rebalance{CustomerID: 1, Value: gbp(100000), Funds: planned.Funds[:1]}
+
+	tests := []struct {
+		name           string
+		method         string
+		customerID     string
+		mockRebalance  *model.Rebalance
+		mockErr        error
+		expectedStatus int
+		wantSwitches   int
+	}{
+		{name: "Preview", method: "GET", customerID: "1", mockRebalance: planned, expectedStatus: http.StatusOK},
+		{name: "Rebalance", method: "POST", customerID: "1", mockRebalance: &executed, expectedStatus: http.StatusCreated, wantSwitches: 1},
+		{name: "Nothing to rebalance", method: "POST", customerID: "1", mockRebalance: balanced, expectedStatus: http.StatusOK},
+		{name: "Customer not found", method: "GET", customerID: "99", mockErr: service.ErrCustomerNotFound, expectedStatus: http.StatusNotFound},
+		{name: "No target allocation", method: "POST", customerID: "1", mockErr: service.ErrAllocationNotFound, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "Target fund closed", method: "POST", customerID: "1", mockErr: service.ErrFundNotOpen, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "Invalid customer ID", method: "GET", customerID: "abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewRebalanceHandler(&mocks.RebalancingService{MockRebalance: tt.mockRebalance, MockErr: tt.mockErr})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/customers/{id}/rebalance", handler.Preview).Methods("GET")
+			router.HandleFunc("/customers/{id}/rebalance", handler.Execute).Methods("POST")
+
+			req := httptest.NewRequest(tt.method, "/customers/"+tt.customerID+"/rebalance", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+			if tt.mockRebalance == nil {
+				return
+			}
+
+			var response model.RebalanceResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Could not decode response: %v", err)
+			}
+			if response.Value != tt.mockRebalance.Value || len(response.Funds) != len(tt.mockRebalance.Funds) || response.Trades == nil {
+				t.Errorf("handler returned wrong rebalance: got %+v", response)
+			}
+			if response.Executed != tt.mockRebalance.Executed || len(response.Switches) != tt.wantSwitches {
+				t.Errorf("handler returned executed = %v with %d switches, want %v with %d", response.Executed, len(response.Switches), tt.mockRebalance.Executed, tt.wantSwitches)
+			}
+		})
+	}
+}
+
+func TestRebalanceHandler_Run(t *testing.T) {
+	run := &model.RebalanceRun{
+		Rebalanced: []*model.Rebalance{{CustomerID: 1, Value: model.NewMoney(100000, model.DefaultCurrency), Executed: true}},
+		Failed:     []model.RebalanceFailure{{CustomerID: 2, Error: "fund 3 is closed"}},
+	}
+	handler := NewRebalanceHandler(&mocks.RebalancingService{MockRun: run})
+
+	req := httptest.NewRequest("POST", "/rebalancing/runs", nil)
+	rr := httptest.NewRecorder()
+	handler.Run(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	var response model.RebalanceRunResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Could not decode response: %v", err)
+	}
+	if len(response.Rebalanced) != 1 || response.Rebalanced[0].CustomerID != 1 || len(response.Failed) != 1 || response.Failed[0] != run.Failed[0] {
+		t.Errorf("handler returned wrong run: got %+v", response)
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ReturnsHandler handles requests for fund performance and customers' returns
+type ReturnsHandler struct {
+	returnsService service.Returns
+}
+
+// NewReturnsHandler creates a new returns handler
+func NewReturnsHandler(returnsService service.Returns) *ReturnsHandler {
+	return &ReturnsHandler{
+		returnsService: returnsService,
+	}
+}
+
+// GetFundReturns handles retrieving a fund's time-weighted returns
+func (h *ReturnsHandler) GetFundReturns(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid fund ID", http.StatusBadRequest)
+		return
+	}
+
+	returns, err := h.returnsService.GetFundReturns(uint(id))
+	if err != nil {
+		writeReturnsError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.FundReturnsResponse{
+		FundID:   returns.FundID,
+		FundName: returns.FundName,
+		Returns:  returns.Returns,
+	})
+}
+
+// GetCustomerReturns handles retrieving a customer's money-weighted returns
+func (h *ReturnsHandler) GetCustomerReturns(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	returns, err := h.returnsService.GetCustomerReturns(uint(id))
+	if err != nil {
+		writeReturnsError(w, err)
+		return
+	}
+
+	response := model.CustomerReturnsResponse{
+		CustomerID: returns.CustomerID,
+		Value:      returns.Value,
+		Returns:    returns.Returns,
+	}
+	if response.Returns == nil {
+		response.Returns = []model.PeriodReturn{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeReturnsError responds with 404 when the fund or customer doesn't exist and with 422 when there is no
+// history to calculate their returns from
+func writeReturnsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrFundNotFound), errors.Is(err, service.ErrCustomerNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrFundNotPriced), errors.Is(err, service.ErrNoReturnHistory):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+	"cushon/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+func TestReturnsHandler(t *testing.T) {
+	annualised := model.Percent(10000)
+	returns := []model.PeriodReturn{
+		{Period: model.ReturnPeriodOneMonth, StartDate: model.NewDate(2027, time.February, 28), EndDate: model.NewDate(2027, time.March, 31), Return: -1234},
+		{Period: model.ReturnPeriodSinceInception, StartDate: model.NewDate(2025, time.March, 31), EndDate: model.NewDate(2027, time.March, 31), Return: 21000, Annualised: &annualised},
+	}
+	returnsService := func(err error) *mocks.ReturnsService {
+		return &mocks.ReturnsService{
+			MockFundReturns:     &model.FundReturns{FundID: 1, FundName: "Fund1", Returns: returns},
+			MockCustomerReturns: &model.CustomerReturns{CustomerID: 1, Value: model.NewMoney(60500, model.DefaultCurrency), Returns: returns},
+			MockErr:             err,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "Fund returns", path: "/funds/1/returns", expectedStatus: http.StatusOK},
+		{name: "Fund not found", path: "/funds/99/returns", mockErr: service.ErrFundNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Fund never priced", path: "/funds/1/returns", mockErr: service.ErrFundNotPriced, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "Invalid fund ID", path: "/funds/abc/returns", expectedStatus: http.StatusBadRequest},
+		{name: "Customer returns", path: "/customers/1/returns", expectedStatus: http.StatusOK},
+		{name: "Customer not found", path: "/customers/99/returns", mockErr: service.ErrCustomerNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Customer not invested yet", path: "/customers/1/returns", mockErr: service.ErrNoReturnHistory, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "Invalid customer ID", path: "/customers/abc/returns", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewReturnsHandler(returnsService(tt.mockErr))
+
+			router := mux.NewRouter()
+			router.HandleFunc("/funds/{id}/returns", handler.GetFundReturns).Methods("GET")
+			router.HandleFunc("/customers/{id}/returns", handler.GetCustomerReturns).Methods("GET")
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+			if rr.Code >= http.StatusBadRequest {
+				return
+			}
+
+			// Both responses carry the same periods
+			var response struct {
+				Returns []model.PeriodReturn `json:"returns"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Could not decode response: %v", err)
+			}
+			if len(response.Returns) != 2 || response.Returns[0].Return != -1234 || response.Returns[0].Annualised != nil ||
+				response.Returns[1].Period != model.ReturnPeriodSinceInception || *response.Returns[1].Annualised != annualised {
+				t.Errorf("handler returned wrong returns: got %+v", response.Returns)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ScheduleHandler handles HTTP requests for customers' contribution schedules
+type ScheduleHandler struct {
+	scheduleService service.Schedule
+}
+
+// NewScheduleHandler creates a new contribution schedule handler
+func NewScheduleHandler(scheduleService service.Schedule) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// Create handles setting up a contribution schedule for a customer
+func (h *ScheduleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var createRequest model.ContributionScheduleCreate
+	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
+		if errors.Is(err, model.ErrInvalidMoney) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeDecodeError(w, err)
+		return
+	}
+
+	schedule, err := h.scheduleService.CreateSchedule(uint(id), createRequest)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCustomerNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrFundNotFound), errors.Is(err, service.ErrFundNotOpen),
+			errors.Is(err, service.ErrAllocationNotFound):
+			// The request is well formed but names a fund that can't be invested in, or relies on an allocation
+			// the customer hasn't chosen
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, service.ErrInvalidSchedule):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newScheduleResponse(schedule))
+}
+
+// GetByCustomer handles listing a customer's contribution schedules
+func (h *ScheduleHandler) GetByCustomer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	schedules, err := h.scheduleService.ListSchedules(uint(id))
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	response := make([]model.ContributionScheduleResponse, len(schedules))
+	for i, schedule := range schedules {
+		response[i] = newScheduleResponse(schedule)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Get handles retrieving a contribution schedule
+func (h *ScheduleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, h.scheduleService.GetSchedule)
+}
+
+// Pause handles stopping an active contribution schedule paying in
+func (h *ScheduleHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, h.scheduleService.PauseSchedule)
+}
+
+// Resume handles restarting a paused contribution schedule
+func (h *ScheduleHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, h.scheduleService.ResumeSchedule)
+}
+
+// Run handles paying in every contribution schedule's runs that have fallen due
+func (h *ScheduleHandler) Run(w http.ResponseWriter, r *http.Request) {
+	run, err := h.scheduleService.RunSchedules()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := model.ScheduleRunResponse{
+		Contributions: make([]model.ScheduledContributionResponse, len(run.Contributions)),
+		Failed:        run.Failed,
+		Skipped:       run.Skipped,
+	}
+	for i, contribution := range run.Contributions {
+		investments := make([]model.InvestmentResponse, len(contribution.Investments))
+		for j, investment := range contribution.Investments {
+			investments[j] = newInvestmentResponse(investment)
+		}
+		response.Contributions[i] = model.ScheduledContributionResponse{
+			ScheduleID:  contribution.ScheduleID,
+			RunDate:     contribution.RunDate,
+			Investments: investments,
+		}
+	}
+	if response.Failed == nil {
+		response.Failed = []model.ScheduleFailure{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// respond reads the schedule ID from the path, applies action to it and responds with the resulting schedule
+func (h *ScheduleHandler) respond(w http.ResponseWriter, r *http.Request, action func(id uint) (*model.ContributionSchedule, error)) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return
+	}
+
+	schedule, err := action(uint(id))
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newScheduleResponse(schedule))
+}
+
+// writeScheduleError maps contribution schedule errors to HTTP statuses
+func writeScheduleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrScheduleNotFound), errors.Is(err, service.ErrCustomerNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidScheduleTransition):
+		// The schedule exists but its current status doesn't allow the change
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// newScheduleResponse converts a contribution schedule into its API representation
+func newScheduleResponse(schedule *model.ContributionSchedule) model.ContributionScheduleResponse {
+	response := model.ContributionScheduleResponse{
+		ID:         schedule.ID,
+		CustomerID: schedule.CustomerID,
+		FundID:     schedule.FundID,
+		Amount:     schedule.Amount,
+		Frequency:  schedule.Frequency,
+		StartDate:  schedule.StartDate,
+		EndDate:    schedule.EndDate,
+		Status:     schedule.Status,
+		CreatedAt:  schedule.CreatedAt,
+		UpdatedAt:  schedule.UpdatedAt,
+	}
+	if schedule.Status != model.ScheduleStatusEnded {
+		nextRunDate := schedule.NextRunDate
+		response.NextRunDate = &nextRunDate
+	}
+	return response
+}
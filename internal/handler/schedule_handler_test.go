@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+	"cushon/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+func TestScheduleHandler(t *testing.T) {
+	fundID := uint(2)
+	schedule := &model.ContributionSchedule{
+		ID:          1,
+		CustomerID:  1,
+		FundID:      &fundID,
+		Amount:      model.NewMoney(10000, model.DefaultCurrency),
+		Frequency:   model.ScheduleFrequencyMonthly,
+		StartDate:   model.NewDate(2026, time.October, 31),
+		Status:      model.ScheduleStatusActive,
+		NextRunDate: model.NewDate(2026, time.November, 30),
+	}
+	validBody := `{"fund_id":2,"amount":{"amount":"100.00","currency":"GBP"},"frequency":"monthly","start_date":"2026-10-31"}`
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "Create", method: "POST", path: "/customers/1/schedules", body: validBody, expectedStatus: http.StatusCreated},
+		{name: "Create for an unknown customer", method: "POST", path: "/customers/99/schedules", body: validBody, mockErr: service.ErrCustomerNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Create with an invalid schedule", method: "POST", path: "/customers/1/schedules", body: validBody, mockErr: service.ErrInvalidSchedule, expectedStatus: http.StatusBadRequest},
+		{name: "Create with a closed fund", method: "POST", path: "/customers/1/schedules", body: validBody, mockErr: service.ErrFundNotOpen, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "Create without an allocation", method: "POST", path: "/customers/1/schedules", body: validBody, mockErr: service.ErrAllocationNotFound, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "Create with an invalid date", method: "POST", path: "/customers/1/schedules", body: `{"amount":{"amount":"100.00","currency":"GBP"},"frequency":"monthly","start_date":"31/10/2026"}`, expectedStatus: http.StatusBadRequest},
+		{name: "Invalid customer ID", method: "POST", path: "/customers/abc/schedules", body: validBody, expectedStatus: http.StatusBadRequest},
+		{name: "Get", method: "GET", path: "/schedules/1", expectedStatus: http.StatusOK},
+		{name: "Schedule not found", method: "GET", path: "/schedules/99", mockErr: service.ErrScheduleNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Invalid schedule ID", method: "GET", path: "/schedules/abc", expectedStatus: http.StatusBadRequest},
+		{name: "Pause", method: "POST", path: "/schedules/1/pause", expectedStatus: http.StatusOK},
+		{name: "Pause a paused schedule", method: "POST", path: "/schedules/1/pause", mockErr: service.ErrInvalidScheduleTransition, expectedStatus: http.StatusConflict},
+		{name: "Resume", method: "POST", path: "/schedules/1/resume", expectedStatus: http.StatusOK},
+		{name: "Resume an unknown schedule", method: "POST", path: "/schedules/99/resume", mockErr: service.ErrScheduleNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewScheduleHandler(&mocks.ScheduleService{MockSchedule: schedule, MockErr: tt.mockErr})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/customers/{id}/schedules", handler.Create).Methods("POST")
+			router.HandleFunc("/schedules/{id}", handler.Get).Methods("GET")
+			router.HandleFunc("/schedules/{id}/pause", handler.Pause).Methods("POST")
+			router.HandleFunc("/schedules/{id}/resume", handler.Resume).Methods("POST")
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+			if rr.Code >= http.StatusBadRequest {
+				return
+			}
+
+			var response model.ContributionScheduleResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Could not decode response: %v", err)
+			}
+			if response.ID != 1 || response.FundID == nil || *response.FundID != 2 || response.Frequency != model.ScheduleFrequencyMonthly ||
+				response.NextRunDate == nil || *response.NextRunDate != schedule.NextRunDate {
+				t.Errorf("handler returned wrong schedule: got %+v", response)
+			}
+		})
+	}
+}
+
+func TestScheduleHandler_GetByCustomer(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "Customer's schedules", expectedStatus: http.StatusOK},
+		{name: "Customer not found", mockErr: service.ErrCustomerNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewScheduleHandler(&mocks.ScheduleService{
+				MockSchedules: []*model.ContributionSchedule{
+					{ID: 1, Amount: model.NewMoney(10000, model.DefaultCurrency), Status: model.ScheduleStatusEnded},
+					{ID: 2, Amount: model.NewMoney(5000, model.DefaultCurrency), Status: model.ScheduleStatusActive},
+				},
+				MockErr: tt.mockErr,
+			})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/customers/{id}/schedules", handler.GetByCustomer).Methods("GET")
+			req := httptest.NewRequest("GET", "/customers/1/schedules", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+			if rr.Code >= http.StatusBadRequest {
+				return
+			}
+			var response []model.ContributionScheduleResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Could not decode response: %v", err)
+			}
+			if len(response) != 2 || response[0].NextRunDate != nil || response[1].NextRunDate == nil {
+				t.Errorf("handler returned wrong schedules: got %+v, want no next run for the ended one", response)
+			}
+		})
+	}
+}
+
+func TestScheduleHandler_Run(t *testing.T) {
+	runDate := model.NewDate(2026, time.October, 16)
+	run := &model.ScheduleRun{
+		Contributions: []*model.ScheduledContribution{{
+			ScheduleID:  1,
+			RunDate:     runDate,
+			Investments: []*model.Investment{{ID: 5, ClientID: 1, FundID: 2, Source: model.ContributionSourceRegular, Amount: model.NewMoney(10000, model.DefaultCurrency)}},
+		}},
+		Failed: []model.ScheduleFailure{{ScheduleID: 2, CustomerID: 2, RunDate: runDate, Error: "fund 3 is closed"}},
+	}
+	handler := NewScheduleHandler(&mocks.ScheduleService{MockRun: run})
+
+	req := httptest.NewRequest("POST", "/schedules/runs", nil)
+	rr := httptest.NewRecorder()
+	handler.Run(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	var response model.ScheduleRunResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Could not decode response: %v", err)
+	}
+	if len(response.Contributions) != 1 || response.Contributions[0].RunDate != runDate || len(response.Contributions[0].Investments) != 1 ||
+		response.Contributions[0].Investments[0].Source != model.ContributionSourceRegular || len(response.Failed) != 1 || response.Failed[0] != run.Failed[0] {
+		t.Errorf("handler returned wrong run: got %+v", response)
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"cushon/internal/clock"
+	"cushon/internal/model"
+	"cushon/internal/repository"
+)
+
+// IdempotencyKeyHeader is the request header clients set to make a POST safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed for a retried request
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength is the longest idempotency key accepted
+const maxIdempotencyKeyLength = 255
+
+// maxIdempotentBodySize is the largest request body read to fingerprint a request, as large as the largest body
+// any endpoint accepts, a payroll file
+const maxIdempotentBodySize = 10 << 20
+
+// IdempotencyMiddleware makes POST requests carrying an Idempotency-Key header safe to retry. The first response
+// for each key and API key is stored until ttl has passed and replayed for every retry, so a request is handled
+// at most once. Reusing a key with a different request is rejected with 422 Unprocessable Entity, and retrying
+// while the first request is still being handled with 409 Conflict. Server errors aren't stored, so the request
+// can be retried. Bodies over 10 MB are rejected with 413 Request Entity Too Large before they are read in full.
+// It must run after AuthMiddleware, which checks the API key.
+func IdempotencyMiddleware(repo repository.IdempotencyRepository, c clock.Clock, ttl time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			http.Error(w, "Idempotency-Key must be at most 255 characters", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := c.Now()
+		apiKey := r.Header.Get("X-API-Key")
+		record, err := repo.ReserveIdempotencyKey(&model.IdempotencyRecord{
+			APIKey:      apiKey,
+			Key:         key,
+			RequestHash: hashRequest(r, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}, now)
+		if errors.Is(err, repository.ErrIdempotencyKeyExists) {
+			replay(w, r, record, body)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.statusCode >= http.StatusInternalServerError {
+			err = repo.ReleaseIdempotencyKey(apiKey, key)
+		} else {
+			err = repo.CompleteIdempotencyKey(apiKey, key, &model.IdempotentResponse{
+				StatusCode:  recorder.statusCode,
+				ContentType: recorder.Header().Get("Content-Type"),
+				Body:        recorder.body.Bytes(),
+			})
+		}
+		if err != nil {
+			// The response has already been sent, so a retry will find the key still being handled until it expires
+			log.Printf("storing response for idempotency key %q: %v", key, err)
+		}
+	})
+}
+
+// NewIdempotencyMiddleware creates a mux.MiddlewareFunc that makes POST requests idempotent
+func NewIdempotencyMiddleware(repo repository.IdempotencyRepository, c clock.Clock, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return IdempotencyMiddleware(repo, c, ttl, next)
+	}
+}
+
+// replay writes the response stored for a key that has already been used by the request r
+func replay(w http.ResponseWriter, r *http.Request, record *model.IdempotencyRecord, body []byte) {
+	if record.RequestHash != hashRequest(r, body) {
+		http.Error(w, "Idempotency-Key has already been used for a different request", http.StatusUnprocessableEntity)
+		return
+	}
+	if record.Response == nil {
+		http.Error(w, "A request with this Idempotency-Key is still being handled", http.StatusConflict)
+		return
+	}
+
+	if record.Response.ContentType != "" {
+		w.Header().Set("Content-Type", record.Response.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.Response.StatusCode)
+	w.Write(record.Response.Body)
+}
+
+// hashRequest fingerprints a request by its method, path and body
+func hashRequest(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder passes a response through to the client while keeping a copy of its status and body
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// WriteHeader records the status code before sending it
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write records the body before sending it
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
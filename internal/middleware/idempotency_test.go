@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/repository"
+)
+
+// countingHandler creates a resource on every call and answers with its sequence number
+type countingHandler struct {
+	mu         sync.Mutex
+	calls      int
+	statusCode int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.calls++
+	calls := h.calls
+	h.mu.Unlock()
+
+	statusCode := h.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusCreated
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	fmt.Fprintf(w, `{"id": %d}`, calls)
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	type request struct {
+		method string
+		path   string
+		apiKey string
+		key    string
+		body   string
+		// after moves the clock on before the request is sent
+		after time.Duration
+	}
+	type response struct {
+		status   int
+		body     string
+		replayed bool
+	}
+	post := func(key, body string) request {
+		return request{method: "POST", path: "/investments", apiKey: "key-1", key: key, body: body}
+	}
+	created := func(id int, replayed bool) response {
+		return response{status: http.StatusCreated, body: fmt.Sprintf(`{"id": %d}`, id), replayed: replayed}
+	}
+
+	tests := []struct {
+		name          string
+		handlerStatus int
+		requests      []request
+		want          []response
+		wantCalls     int
+	}{
+		{
+			name:      "Retry is replayed",
+			requests:  []request{post("abc", `{"amount": 1}`), post("abc", `{"amount": 1}`)},
+			want:      []response{created(1, false), created(1, true)},
+			wantCalls: 1,
+		},
+		{
+			name:      "Different keys are handled separately",
+			requests:  []request{post("abc", `{"amount": 1}`), post("def", `{"amount": 1}`)},
+			want:      []response{created(1, false), created(2, false)},
+			wantCalls: 2,
+		},
+		{
+			name:      "Requests without a key are always handled",
+			requests:  []request{post("", `{"amount": 1}`), post("", `{"amount": 1}`)},
+			want:      []response{created(1, false), created(2, false)},
+			wantCalls: 2,
+		},
+		{
+			name:     "Key reused with a different body",
+			requests: []request{post("abc", `{"amount": 1}`), post("abc", `{"amount": 2}`)},
+			want: []response{
+				created(1, false),
+				{status: http.StatusUnprocessableEntity, body: "Idempotency-Key has already been used for a different request\n"},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "Key reused on a different endpoint",
+			requests: []request{
+				post("abc", `{"name": "x"}`),
+				{method: "POST", path: "/customers", apiKey: "key-1", key: "abc", body: `{"name": "x"}`},
+			},
+			want: []response{
+				created(1, false),
+				{status: http.StatusUnprocessableEntity, body: "Idempotency-Key has already been used for a different request\n"},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "Keys are scoped to the API key",
+			requests: []request{
+				post("abc", `{"amount": 1}`),
+				{method: "POST", path: "/investments", apiKey: "key-2", key: "abc", body: `{"amount": 1}`},
+			},
+			want:      []response{created(1, false), created(2, false)},
+			wantCalls: 2,
+		},
+		{
+			name: "Expired key can be reused",
+			requests: []request{
+				post("abc", `{"amount": 1}`),
+				{method: "POST", path: "/investments", apiKey: "key-1", key: "abc", body: `{"amount": 2}`, after: 24 * time.Hour},
+			},
+			want:      []response{created(1, false), created(2, false)},
+			wantCalls: 2,
+		},
+		{
+			name:          "Client errors are replayed",
+			handlerStatus: http.StatusBadRequest,
+			requests:      []request{post("abc", `{}`), post("abc", `{}`)},
+			want: []response{
+				{status: http.StatusBadRequest, body: `{"id": 1}`},
+				{status: http.StatusBadRequest, body: `{"id": 1}`, replayed: true},
+			},
+			wantCalls: 1,
+		},
+		{
+			name:          "Server errors can be retried",
+			handlerStatus: http.StatusInternalServerError,
+			requests:      []request{post("abc", `{}`), post("abc", `{}`)},
+			want: []response{
+				{status: http.StatusInternalServerError, body: `{"id": 1}`},
+				{status: http.StatusInternalServerError, body: `{"id": 2}`},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "Other methods are passed through",
+			requests: []request{
+				{method: "PATCH", path: "/investments/1", apiKey: "key-1", key: "abc", body: `{}`},
+				{method: "PATCH", path: "/investments/1", apiKey: "key-1", key: "abc", body: `{}`},
+			},
+			want:      []response{created(1, false), created(2, false)},
+			wantCalls: 2,
+		},
+		{
+			name:      "Key too long",
+			requests:  []request{post(strings.Repeat("k", 256), `{}`)},
+			want:      []response{{status: http.StatusBadRequest, body: "Idempotency-Key must be at most 255 characters\n"}},
+			wantCalls: 0,
+		},
+		{
+			name:      "Largest body accepted",
+			requests:  []request{post("abc", strings.Repeat("x", maxIdempotentBodySize))},
+			want:      []response{created(1, false)},
+			wantCalls: 1,
+		},
+		{
+			name:      "Body too large",
+			requests:  []request{post("abc", strings.Repeat("x", maxIdempotentBodySize+1))},
+			want:      []response{{status: http.StatusRequestEntityTooLarge, body: "Request body too large\n"}},
+			wantCalls: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &mocks.Clock{MockNow: time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)}
+			next := &countingHandler{statusCode: tt.handlerStatus}
+			handler := IdempotencyMiddleware(repository.NewInMemoryIdempotencyRepository(), clock, 24*time.Hour, next)
+
+			for i, req := range tt.requests {
+				clock.Set(clock.Now().Add(req.after))
+
+				r := httptest.NewRequest(req.method, req.path, bytes.NewBufferString(req.body))
+				r.Header.Set("X-API-Key", req.apiKey)
+				if req.key != "" {
+					r.Header.Set(IdempotencyKeyHeader, req.key)
+				}
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, r)
+
+				want := tt.want[i]
+				if rr.Code != want.status || rr.Body.String() != want.body {
+					t.Errorf("request %d got %d %q, want %d %q", i+1, rr.Code, rr.Body.String(), want.status, want.body)
+				}
+				if replayed := rr.Header().Get(IdempotentReplayedHeader) == "true"; replayed != want.replayed {
+					t.Errorf("request %d replayed = %v, want %v", i+1, replayed, want.replayed)
+				}
+			}
+			if next.calls != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", next.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_ConcurrentRetries(t *testing.T) {
+	clock := &mocks.Clock{MockNow: time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)}
+	next := &countingHandler{}
+	handler := IdempotencyMiddleware(repository.NewInMemoryIdempotencyRepository(), clock, 24*time.Hour, next)
+
+	// However many retries race each other, the request is only handled once. Retries arriving while it is
+	// being handled are told to try again later, the others get the stored response.
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := httptest.NewRequest("POST", "/investments", bytes.NewBufferString(`{"amount": 1}`))
+			r.Header.Set("X-API-Key", "key-1")
+			r.Header.Set(IdempotencyKeyHeader, "abc")
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, r)
+
+			if rr.Code != http.StatusCreated && rr.Code != http.StatusConflict {
+				t.Errorf("got status %d, want %d or %d", rr.Code, http.StatusCreated, http.StatusConflict)
+			}
+			if rr.Code == http.StatusCreated && rr.Body.String() != `{"id": 1}` {
+				t.Errorf("got body %q, want the first response", rr.Body.String())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if next.calls != 1 {
+		t.Errorf("handler called %d times, want 1", next.calls)
+	}
+}
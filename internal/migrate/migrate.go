@@ -0,0 +1,404 @@
+// Package migrate applies the versioned PostgreSQL schema migrations embedded in the binary.
+//
+// Migrations live in the migrations folder as pairs of files named <version>_<name>.up.sql and
+// <version>_<name>.down.sql. Applied versions are recorded in the schema_migrations table. A version
+// is marked dirty before its SQL runs and clean once it has succeeded, so a failed migration leaves
+// the database dirty and every later run is refused until an operator has fixed it and called Force.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// lockID is the PostgreSQL advisory lock key that stops two migrators running at the same time
+const lockID = 7261756
+
+// ErrDirty is returned when a previous migration failed part way through
+var ErrDirty = errors.New("database is dirty")
+
+// ErrUnknownVersion is returned when asked to migrate to a version that doesn't exist
+var ErrUnknownVersion = errors.New("unknown migration version")
+
+// ErrSchemaMismatch is returned by Verify when the schema isn't at the version the binary expects
+var ErrSchemaMismatch = errors.New("schema is not at the expected version")
+
+// Migration is a single schema change with the SQL to apply and revert it
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status describes the migration state of a database
+type Status struct {
+	// Version is the latest applied migration, 0 when none has been applied
+	Version uint
+	// Dirty is true when the migration at Version failed and needs manual attention
+	Dirty bool
+	// Applied lists the applied versions in ascending order
+	Applied []uint
+	// Pending lists the migrations that have not been applied yet
+	Pending []Migration
+}
+
+// Migrations returns the embedded migrations ordered by version
+func Migrations() ([]Migration, error) {
+	return loadMigrations(migrationFiles, "migrations")
+}
+
+// LatestVersion returns the version the schema is at once every embedded migration has been applied
+func LatestVersion() (uint, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// loadMigrations reads the <version>_<name>.(up|down).sql files in dir
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading migrations: %w", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("migration %s must be named <version>_<name>.up.sql or <version>_<name>.down.sql", name)
+		}
+
+		versionStr, migrationName, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseUint(versionStr, 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migration %s must start with a positive version number", name)
+		}
+
+		contents, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", name, err)
+		}
+
+		migration, exists := byVersion[uint(version)]
+		if !exists {
+			migration = &Migration{Version: uint(version), Name: migrationName}
+			byVersion[uint(version)] = migration
+		}
+		if migration.Name != migrationName {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, migrationName)
+		}
+
+		if direction == "up" {
+			migration.Up = string(contents)
+		} else {
+			migration.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrator applies and reverts migrations on a database
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New creates a migrator for the embedded migrations
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up() error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.To(m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down reverts the latest applied migration
+func (m *Migrator) Down() error {
+	return m.withLock(func() error {
+		status, err := m.status()
+		if err != nil {
+			return err
+		}
+		if status.Dirty {
+			return fmt.Errorf("%w at version %d", ErrDirty, status.Version)
+		}
+		if status.Version == 0 {
+			return nil
+		}
+
+		migration, ok := m.find(status.Version)
+		if !ok {
+			return fmt.Errorf("%w: %d is applied but has no migration file", ErrUnknownVersion, status.Version)
+		}
+		return m.revert(migration)
+	})
+}
+
+// To applies or reverts migrations until the latest applied version is version. Version 0 reverts everything.
+func (m *Migrator) To(version uint) error {
+	if _, ok := m.find(version); !ok && version != 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLock(func() error {
+		status, err := m.status()
+		if err != nil {
+			return err
+		}
+		if status.Dirty {
+			return fmt.Errorf("%w at version %d", ErrDirty, status.Version)
+		}
+
+		applied := make(map[uint]bool, len(status.Applied))
+		for _, v := range status.Applied {
+			applied[v] = true
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version > version && applied[migration.Version] {
+				if err := m.revert(migration); err != nil {
+					return err
+				}
+			}
+		}
+		for _, migration := range m.migrations {
+			if migration.Version <= version && !applied[migration.Version] {
+				if err := m.apply(migration); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Force marks version as applied and clean without running any SQL, so that a database left dirty
+// by a failed migration can be used again once it has been repaired by hand. Versions above it are forgotten.
+func (m *Migrator) Force(version uint) error {
+	if _, ok := m.find(version); !ok && version != 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLock(func() error {
+		return m.inTx(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version >= $1`, version); err != nil {
+				return err
+			}
+			if version == 0 {
+				return nil
+			}
+			_, err := tx.Exec(`INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, version)
+			return err
+		})
+	})
+}
+
+// Status reports the applied and pending migrations
+func (m *Migrator) Status() (*Status, error) {
+	return m.status()
+}
+
+// Verify checks that the schema is exactly at the latest embedded migration and the database isn't dirty. A
+// database migrated by a newer binary has versions this one doesn't know, and fails with ErrSchemaMismatch
+// like one that is behind.
+func (m *Migrator) Verify() error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("%w at version %d", ErrDirty, status.Version)
+	}
+	for _, version := range status.Applied {
+		if _, ok := m.find(version); !ok {
+			return fmt.Errorf("%w: version %d is applied but unknown to this binary, the database was migrated by a newer release",
+				ErrSchemaMismatch, version)
+		}
+	}
+
+	var latest uint
+	if len(m.migrations) > 0 {
+		latest = m.migrations[len(m.migrations)-1].Version
+	}
+	if len(status.Pending) > 0 || status.Version != latest {
+		return fmt.Errorf("%w: schema is at version %d but version %d is expected, run the migrate command",
+			ErrSchemaMismatch, status.Version, latest)
+	}
+	return nil
+}
+
+// apply runs a migration's up SQL and records it as applied
+func (m *Migrator) apply(migration Migration) error {
+	if _, err := m.db.Exec(`INSERT INTO schema_migrations (version, dirty) VALUES ($1, true)`, migration.Version); err != nil {
+		return fmt.Errorf("recording migration %d: %w", migration.Version, err)
+	}
+
+	err := m.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(migration.Up); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE schema_migrations SET dirty = false WHERE version = $1`, migration.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("applying migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// revert runs a migration's down SQL and removes it from the applied versions
+func (m *Migrator) revert(migration Migration) error {
+	if _, err := m.db.Exec(`UPDATE schema_migrations SET dirty = true WHERE version = $1`, migration.Version); err != nil {
+		return fmt.Errorf("recording migration %d: %w", migration.Version, err)
+	}
+
+	err := m.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(migration.Down); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("reverting migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// status reads the migration state. A database without a schema_migrations table is at version 0.
+func (m *Migrator) status() (*Status, error) {
+	var exists bool
+	if err := m.db.QueryRow(`SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("reading applied migrations: %w", err)
+	}
+
+	status := &Status{Applied: make([]uint, 0), Pending: make([]Migration, 0)}
+	if !exists {
+		status.Pending = append(status.Pending, m.migrations...)
+		return status, nil
+	}
+
+	rows, err := m.db.Query(`SELECT version, dirty FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("reading applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[uint]bool)
+	for rows.Next() {
+		var version uint
+		var dirty bool
+		if err := rows.Scan(&version, &dirty); err != nil {
+			return nil, err
+		}
+		status.Applied = append(status.Applied, version)
+		status.Version = version
+		status.Dirty = status.Dirty || dirty
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		if !applied[migration.Version] {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+// find returns the migration with the given version
+func (m *Migrator) find(version uint) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// ensureTable creates the schema_migrations table if it doesn't exist
+func (m *Migrator) ensureTable() error {
+	_, err := m.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		dirty      BOOLEAN NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// withLock runs fn while holding the migration advisory lock on a single connection
+func (m *Migrator) withLock(fn func() error) error {
+	if err := m.ensureTable(); err != nil {
+		return err
+	}
+
+	conn, err := m.db.Conn(context.Background())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("acquiring migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+
+	return fn()
+}
+
+// inTx runs fn in a transaction that is committed if fn succeeds
+func (m *Migrator) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,240 @@
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/lib/pq"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		wantVersions []uint
+		wantErr      bool
+	}{
+		{
+			name: "Ordered by version",
+			files: fstest.MapFS{
+				"m/0010_add_index.up.sql":   {Data: []byte("CREATE INDEX")},
+				"m/0010_add_index.down.sql": {Data: []byte("DROP INDEX")},
+				"m/0002_add_table.up.sql":   {Data: []byte("CREATE TABLE")},
+				"m/0002_add_table.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			wantVersions: []uint{2, 10},
+		},
+		{
+			name: "Missing down migration",
+			files: fstest.MapFS{
+				"m/0001_init.up.sql": {Data: []byte("CREATE TABLE")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Missing version",
+			files: fstest.MapFS{
+				"m/init.up.sql":   {Data: []byte("CREATE TABLE")},
+				"m/init.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Duplicate version",
+			files: fstest.MapFS{
+				"m/0001_init.up.sql":    {Data: []byte("CREATE TABLE")},
+				"m/0001_init.down.sql":  {Data: []byte("DROP TABLE")},
+				"m/0001_other.up.sql":   {Data: []byte("CREATE TABLE")},
+				"m/0001_other.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadMigrations(tt.files, "m")
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("loadMigrations() expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("loadMigrations() unexpected error = %v", err)
+			}
+			if len(got) != len(tt.wantVersions) {
+				t.Fatalf("got %d migrations, want %d", len(got), len(tt.wantVersions))
+			}
+			for i, version := range tt.wantVersions {
+				if got[i].Version != version {
+					t.Errorf("migration[%d].Version = %v, want %v", i, got[i].Version, version)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrations_Embedded(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("Migrations() error = %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != uint(i+1) {
+			t.Errorf("migration[%d].Version = %v, want consecutive version %v", i, migration.Version, i+1)
+		}
+	}
+}
+
+// openTestDB connects to the database named by CUSHON_TEST_DATABASE_URL and drops every table in it,
+// skipping the test when no database is configured
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dataSourceName := os.Getenv("CUSHON_TEST_DATABASE_URL")
+	if dataSourceName == "" {
+		t.Skip("CUSHON_TEST_DATABASE_URL not set, skipping PostgreSQL tests")
+	}
+
+	db, err := sql.Open("postgres", dataSourceName)
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`DROP SCHEMA public CASCADE; CREATE SCHEMA public`); err != nil {
+		t.Fatalf("resetting database: %v", err)
+	}
+	return db
+}
+
+func TestMigrator(t *testing.T) {
+	db := openTestDB(t)
+	migrator, err := New(db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	latest, err := LatestVersion()
+	if err != nil {
+		t.Fatalf("LatestVersion() error = %v", err)
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Version != 0 || len(status.Pending) != int(latest) {
+		t.Errorf("Status() before migrating = %+v, want version 0 with %d pending", status, latest)
+	}
+	if err := migrator.Verify(); !errors.Is(err, ErrSchemaMismatch) {
+		t.Errorf("Verify() on an empty database error = %v, want ErrSchemaMismatch", err)
+	}
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if err := migrator.Verify(); err != nil {
+		t.Errorf("Verify() after Up() error = %v", err)
+	}
+
+	if err := migrator.Down(); err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	status, err = migrator.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Version != latest-1 || len(status.Pending) != 1 {
+		t.Errorf("Status() after Down() = %+v, want version %d with 1 pending", status, latest-1)
+	}
+
+	if err := migrator.To(0); err != nil {
+		t.Fatalf("To(0) error = %v", err)
+	}
+	if err := migrator.To(latest); err != nil {
+		t.Fatalf("To(%d) error = %v", latest, err)
+	}
+	if err := migrator.To(latest + 1); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("To(%d) error = %v, want ErrUnknownVersion", latest+1, err)
+	}
+}
+
+func TestMigrator_RefusesDirtyDatabase(t *testing.T) {
+	db := openTestDB(t)
+	migrator, err := New(db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := migrator.To(1); err != nil {
+		t.Fatalf("To(1) error = %v", err)
+	}
+	if _, err := db.Exec(`UPDATE schema_migrations SET dirty = true WHERE version = 1`); err != nil {
+		t.Fatalf("marking database dirty: %v", err)
+	}
+
+	if err := migrator.Up(); !errors.Is(err, ErrDirty) {
+		t.Errorf("Up() error = %v, want ErrDirty", err)
+	}
+	if err := migrator.Down(); !errors.Is(err, ErrDirty) {
+		t.Errorf("Down() error = %v, want ErrDirty", err)
+	}
+	if err := migrator.Verify(); !errors.Is(err, ErrDirty) {
+		t.Errorf("Verify() error = %v, want ErrDirty", err)
+	}
+
+	if err := migrator.Force(1); err != nil {
+		t.Fatalf("Force(1) error = %v", err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Errorf("Up() after Force() error = %v", err)
+	}
+}
+
+func TestMigrator_FailedMigrationLeavesDatabaseDirty(t *testing.T) {
+	db := openTestDB(t)
+	migrator := &Migrator{db: db, migrations: []Migration{
+		{Version: 1, Name: "broken", Up: "CREATE TABLE broken (", Down: "DROP TABLE broken"},
+	}}
+
+	if err := migrator.Up(); err == nil {
+		t.Fatal("Up() expected an error")
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if !status.Dirty || status.Version != 1 {
+		t.Errorf("Status() = %+v, want dirty at version 1", status)
+	}
+}
+
+func TestMigrator_VerifyRefusesNewerSchema(t *testing.T) {
+	db := openTestDB(t)
+	first := Migration{Version: 1, Name: "first", Up: "CREATE TABLE first (id BIGINT)", Down: "DROP TABLE first"}
+	second := Migration{Version: 2, Name: "second", Up: "CREATE TABLE second (id BIGINT)", Down: "DROP TABLE second"}
+	newer := &Migrator{db: db, migrations: []Migration{first, second}}
+	if err := newer.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	// An older binary only knows the first migration, so nothing is pending for it
+	older := &Migrator{db: db, migrations: []Migration{first}}
+	if err := older.Verify(); !errors.Is(err, ErrSchemaMismatch) {
+		t.Errorf("Verify() by an older binary error = %v, want ErrSchemaMismatch", err)
+	}
+	if err := newer.Verify(); err != nil {
+		t.Errorf("Verify() by the binary that migrated error = %v", err)
+	}
+}
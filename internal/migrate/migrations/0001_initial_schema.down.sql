@@ -0,0 +1,5 @@
+DROP TABLE api_keys;
+DROP TABLE investments;
+DROP TABLE funds;
+DROP TABLE customers;
+DROP TABLE employers;
@@ -0,0 +1,36 @@
+CREATE TABLE employers (
+    id   BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL CHECK (name <> '')
+);
+
+CREATE TABLE customers (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT NOT NULL CHECK (name <> ''),
+    employer_id BIGINT REFERENCES employers (id),
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX customers_employer_id_idx ON customers (employer_id);
+
+CREATE TABLE funds (
+    id   BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL CHECK (name <> '')
+);
+
+CREATE TABLE investments (
+    id           BIGSERIAL PRIMARY KEY,
+    client_id    BIGINT NOT NULL REFERENCES customers (id),
+    fund_id      BIGINT NOT NULL REFERENCES funds (id),
+    amount_minor BIGINT NOT NULL,
+    currency     CHAR(3) NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX investments_client_id_idx ON investments (client_id);
+
+CREATE TABLE api_keys (
+    key_hash   TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,4 @@
+ALTER TABLE employers
+    DROP COLUMN updated_at,
+    DROP COLUMN created_at,
+    DROP COLUMN active;
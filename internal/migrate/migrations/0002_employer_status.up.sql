@@ -0,0 +1,4 @@
+ALTER TABLE employers
+    ADD COLUMN active     BOOLEAN NOT NULL DEFAULT true,
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
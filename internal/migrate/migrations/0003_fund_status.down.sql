@@ -0,0 +1,4 @@
+ALTER TABLE funds
+    DROP COLUMN updated_at,
+    DROP COLUMN created_at,
+    DROP COLUMN status;
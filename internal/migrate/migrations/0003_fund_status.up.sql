@@ -0,0 +1,4 @@
+ALTER TABLE funds
+    ADD COLUMN status     TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed', 'retired')),
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
@@ -0,0 +1,9 @@
+DROP INDEX funds_risk_rating_idx;
+
+ALTER TABLE funds
+    DROP COLUMN description,
+    DROP COLUMN currency,
+    DROP COLUMN ongoing_charges,
+    DROP COLUMN risk_rating,
+    DROP COLUMN asset_class,
+    DROP COLUMN isin;
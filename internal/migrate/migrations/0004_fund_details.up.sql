@@ -0,0 +1,11 @@
+-- Funds created before this migration have no ISIN, asset class or risk rating, so those columns are nullable.
+-- ongoing_charges is stored in thousandths of a percent, e.g. an OCF of 0.075% is stored as 75.
+ALTER TABLE funds
+    ADD COLUMN isin            CHAR(12) UNIQUE,
+    ADD COLUMN asset_class     TEXT CHECK (asset_class IN ('equity', 'bond', 'multi_asset', 'property', 'cash')),
+    ADD COLUMN risk_rating     SMALLINT CHECK (risk_rating BETWEEN 1 AND 7),
+    ADD COLUMN ongoing_charges BIGINT NOT NULL DEFAULT 0 CHECK (ongoing_charges >= 0),
+    ADD COLUMN currency        CHAR(3) NOT NULL DEFAULT 'GBP',
+    ADD COLUMN description     TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX funds_risk_rating_idx ON funds (risk_rating);
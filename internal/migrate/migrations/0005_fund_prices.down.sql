@@ -0,0 +1,6 @@
+ALTER TABLE investments
+    DROP COLUMN price_date,
+    DROP COLUMN price,
+    DROP COLUMN units;
+
+DROP TABLE fund_prices;
@@ -0,0 +1,16 @@
+-- nav is the net asset value per unit in millionths of the major currency unit, e.g. 1.234567 GBP is stored as 1234567.
+CREATE TABLE fund_prices (
+    fund_id    BIGINT NOT NULL REFERENCES funds (id),
+    price_date DATE NOT NULL,
+    nav        BIGINT NOT NULL CHECK (nav > 0),
+    currency   CHAR(3) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (fund_id, price_date)
+);
+
+-- units are stored in millionths of a unit. Investments made before fund prices were recorded have no units
+-- or price, and no price date.
+ALTER TABLE investments
+    ADD COLUMN units      BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN price      BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN price_date DATE;
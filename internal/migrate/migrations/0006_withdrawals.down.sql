@@ -0,0 +1,4 @@
+DELETE FROM investments WHERE type = 'withdrawal';
+
+ALTER TABLE investments
+    DROP COLUMN type;
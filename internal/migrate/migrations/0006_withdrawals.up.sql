@@ -0,0 +1,3 @@
+-- Withdrawals are recorded alongside investments with a negative amount and negative units.
+ALTER TABLE investments
+    ADD COLUMN type TEXT NOT NULL DEFAULT 'investment' CHECK (type IN ('investment', 'withdrawal'));
@@ -0,0 +1,8 @@
+DELETE FROM investments WHERE switch_id IS NOT NULL;
+
+ALTER TABLE investments
+    DROP CONSTRAINT investments_type_check,
+    ADD CONSTRAINT investments_type_check CHECK (type IN ('investment', 'withdrawal')),
+    DROP COLUMN switch_id;
+
+DROP TABLE switches;
@@ -0,0 +1,17 @@
+-- A switch sells units of one fund and invests the proceeds in another. Both legs are stored as investments
+-- pointing at the switch.
+CREATE TABLE switches (
+    id           BIGSERIAL PRIMARY KEY,
+    client_id    BIGINT NOT NULL REFERENCES customers (id),
+    from_fund_id BIGINT NOT NULL REFERENCES funds (id),
+    to_fund_id   BIGINT NOT NULL REFERENCES funds (id),
+    created_at   TIMESTAMPTZ NOT NULL,
+    CHECK (from_fund_id <> to_fund_id)
+);
+
+ALTER TABLE investments
+    ADD COLUMN switch_id BIGINT REFERENCES switches (id),
+    DROP CONSTRAINT investments_type_check,
+    ADD CONSTRAINT investments_type_check CHECK (type IN ('investment', 'withdrawal', 'switch_out', 'switch_in'));
+
+CREATE INDEX investments_switch_id_idx ON investments (switch_id);
@@ -0,0 +1,2 @@
+ALTER TABLE investments
+    DROP COLUMN status;
@@ -0,0 +1,10 @@
+-- Transactions recorded before statuses were introduced were dealt immediately, so they are settled. New
+-- transactions are always inserted as pending.
+ALTER TABLE investments
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'settled'
+        CHECK (status IN ('pending', 'placed', 'settled', 'failed', 'cancelled'));
+
+ALTER TABLE investments
+    ALTER COLUMN status DROP DEFAULT;
+
+CREATE INDEX investments_status_idx ON investments (status);
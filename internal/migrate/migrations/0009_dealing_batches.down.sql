@@ -0,0 +1,4 @@
+ALTER TABLE investments
+    DROP COLUMN batch_id;
+
+DROP TABLE dealing_batches;
@@ -0,0 +1,23 @@
+-- A dealing batch is the net order placed with a fund manager for one fund on one dealing day. Amounts are in
+-- minor units and units and price in millionths, as for investments. Both sides of the order are positive.
+CREATE TABLE dealing_batches (
+    id           BIGSERIAL PRIMARY KEY,
+    fund_id      BIGINT NOT NULL REFERENCES funds (id),
+    dealing_date DATE NOT NULL,
+    price        BIGINT NOT NULL CHECK (price > 0),
+    currency     CHAR(3) NOT NULL,
+    bought_minor BIGINT NOT NULL CHECK (bought_minor >= 0),
+    sold_minor   BIGINT NOT NULL CHECK (sold_minor >= 0),
+    units_bought BIGINT NOT NULL CHECK (units_bought >= 0),
+    units_sold   BIGINT NOT NULL CHECK (units_sold >= 0),
+    created_at   TIMESTAMPTZ NOT NULL,
+    UNIQUE (fund_id, dealing_date)
+);
+
+CREATE INDEX dealing_batches_dealing_date_idx ON dealing_batches (dealing_date);
+
+-- Transactions carry the batch they were dealt in once they have been placed.
+ALTER TABLE investments
+    ADD COLUMN batch_id BIGINT REFERENCES dealing_batches (id);
+
+CREATE INDEX investments_batch_id_idx ON investments (batch_id);
@@ -0,0 +1,15 @@
+-- Responses replayed to retries of requests made with an Idempotency-Key header. Keys are scoped to the API key
+-- that used them, which is stored hashed as in api_keys. A key without a status code is still being handled.
+CREATE TABLE idempotency_keys (
+    api_key_hash TEXT NOT NULL,
+    key          TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code  INTEGER,
+    content_type TEXT,
+    body         BYTEA,
+    created_at   TIMESTAMPTZ NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (api_key_hash, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
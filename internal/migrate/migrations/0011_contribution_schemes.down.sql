@@ -0,0 +1,13 @@
+ALTER TABLE investments
+    DROP CONSTRAINT investments_source_type_check,
+    DROP COLUMN source;
+
+ALTER TABLE employers
+    DROP CONSTRAINT employers_employer_cap_currency_check,
+    DROP CONSTRAINT employers_contribution_scheme_check,
+    DROP COLUMN salary_sacrifice,
+    DROP COLUMN employer_cap_currency,
+    DROP COLUMN employer_cap,
+    DROP COLUMN matching,
+    DROP COLUMN employer_rate,
+    DROP COLUMN employee_rate;
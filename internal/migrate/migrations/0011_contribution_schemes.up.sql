@@ -0,0 +1,24 @@
+-- An employer's contribution scheme is stored on its row and is absent when employee_rate is null. Rates are
+-- in thousandths of a percent and the cap in minor units.
+ALTER TABLE employers
+    ADD COLUMN employee_rate         BIGINT CHECK (employee_rate BETWEEN 0 AND 100000),
+    ADD COLUMN employer_rate         BIGINT CHECK (employer_rate BETWEEN 0 AND 100000),
+    ADD COLUMN matching              BOOLEAN,
+    ADD COLUMN employer_cap          BIGINT CHECK (employer_cap > 0),
+    ADD COLUMN employer_cap_currency CHAR(3),
+    ADD COLUMN salary_sacrifice      BOOLEAN,
+    ADD CONSTRAINT employers_contribution_scheme_check CHECK (
+        (employee_rate IS NULL AND employer_rate IS NULL AND matching IS NULL AND salary_sacrifice IS NULL AND employer_cap IS NULL)
+        OR (employee_rate IS NOT NULL AND employer_rate IS NOT NULL AND matching IS NOT NULL AND salary_sacrifice IS NOT NULL)
+    ),
+    ADD CONSTRAINT employers_employer_cap_currency_check CHECK ((employer_cap IS NULL) = (employer_cap_currency IS NULL));
+
+-- Investments record where the money paid in came from, other transactions only move money already paid in.
+-- Every investment made before sources were introduced was paid in by the customer through the API.
+ALTER TABLE investments
+    ADD COLUMN source TEXT CHECK (source IN ('employee', 'employer', 'salary_sacrifice', 'one_off'));
+
+UPDATE investments SET source = 'one_off' WHERE type = 'investment';
+
+ALTER TABLE investments
+    ADD CONSTRAINT investments_source_type_check CHECK (source IS NULL OR type = 'investment');
@@ -0,0 +1,4 @@
+DROP TABLE target_allocations;
+
+ALTER TABLE employers
+    DROP COLUMN default_fund_id;
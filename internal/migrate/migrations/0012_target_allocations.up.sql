@@ -0,0 +1,15 @@
+-- Employers can choose the fund their employees' contributions go into until they choose their own allocation.
+ALTER TABLE employers
+    ADD COLUMN default_fund_id BIGINT REFERENCES funds (id);
+
+-- A customer's target allocation, one row per fund in the order they chose them. Percentages are in thousandths
+-- of a percent and add up to 100%, which is checked when the allocation is replaced as a whole.
+CREATE TABLE target_allocations (
+    customer_id BIGINT  NOT NULL REFERENCES customers (id) ON DELETE CASCADE,
+    position    INTEGER NOT NULL,
+    fund_id     BIGINT  NOT NULL REFERENCES funds (id),
+    percentage  BIGINT  NOT NULL CHECK (percentage > 0 AND percentage <= 100000),
+    updated_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (customer_id, position),
+    UNIQUE (customer_id, fund_id)
+);
@@ -0,0 +1,7 @@
+ALTER TABLE customers
+    DROP COLUMN glide_path_id,
+    DROP COLUMN retirement_age,
+    DROP COLUMN date_of_birth;
+
+DROP TABLE glide_path_stages;
+DROP TABLE glide_paths;
@@ -0,0 +1,23 @@
+-- A glide path moves its customers' target allocations into lower-risk funds as they approach retirement. Each
+-- stage applies from its number of years to retirement until the next stage starts, one row per fund in order.
+CREATE TABLE glide_paths (
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE glide_path_stages (
+    glide_path_id       BIGINT  NOT NULL REFERENCES glide_paths (id) ON DELETE CASCADE,
+    years_to_retirement INTEGER NOT NULL CHECK (years_to_retirement >= 0),
+    position            INTEGER NOT NULL,
+    fund_id             BIGINT  NOT NULL REFERENCES funds (id),
+    percentage          BIGINT  NOT NULL CHECK (percentage > 0 AND percentage <= 100000),
+    PRIMARY KEY (glide_path_id, years_to_retirement, position),
+    UNIQUE (glide_path_id, years_to_retirement, fund_id)
+);
+
+-- Customers opt into a glide path once they have told us when they were born and when they plan to retire.
+ALTER TABLE customers
+    ADD COLUMN date_of_birth  DATE,
+    ADD COLUMN retirement_age INTEGER,
+    ADD COLUMN glide_path_id  BIGINT REFERENCES glide_paths (id);
@@ -0,0 +1,7 @@
+UPDATE investments SET source = 'one_off' WHERE source = 'regular';
+
+ALTER TABLE investments
+    DROP CONSTRAINT investments_source_check,
+    ADD CONSTRAINT investments_source_check CHECK (source IN ('employee', 'employer', 'salary_sacrifice', 'one_off'));
+
+DROP TABLE contribution_schedules;
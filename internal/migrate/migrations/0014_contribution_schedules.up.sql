@@ -0,0 +1,24 @@
+-- Customers' regular contributions. fund_id is null for schedules that pay in across the customer's target
+-- allocation. next_run_date is the next run that hasn't been paid in or skipped, and is only meaningful while
+-- the schedule hasn't ended.
+CREATE TABLE contribution_schedules (
+    id              BIGSERIAL PRIMARY KEY,
+    customer_id     BIGINT  NOT NULL REFERENCES customers (id) ON DELETE CASCADE,
+    fund_id         BIGINT  REFERENCES funds (id),
+    amount_minor    BIGINT  NOT NULL CHECK (amount_minor > 0),
+    currency        CHAR(3) NOT NULL,
+    frequency       TEXT    NOT NULL CHECK (frequency IN ('weekly', 'monthly', 'quarterly', 'annually')),
+    start_date      DATE    NOT NULL,
+    end_date        DATE    CHECK (end_date >= start_date),
+    status          TEXT    NOT NULL CHECK (status IN ('active', 'paused', 'ended')),
+    next_run_date   DATE    NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX contribution_schedules_customer_id_idx ON contribution_schedules (customer_id);
+
+-- Contributions paid in by a schedule are recorded as regular rather than one-off.
+ALTER TABLE investments
+    DROP CONSTRAINT investments_source_check,
+    ADD CONSTRAINT investments_source_check CHECK (source IN ('employee', 'employer', 'salary_sacrifice', 'one_off', 'regular'));
@@ -0,0 +1,6 @@
+DROP INDEX investments_schedule_run_idx;
+
+ALTER TABLE investments
+    DROP CONSTRAINT investments_schedule_run_check,
+    DROP COLUMN run_date,
+    DROP COLUMN schedule_id;
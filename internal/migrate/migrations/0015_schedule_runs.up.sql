@@ -0,0 +1,8 @@
+-- Contributions paid in by a schedule record the schedule and the date of the run they pay in. Each run can
+-- only be paid into a fund once, so a run retried after it was paid in is never paid in again.
+ALTER TABLE investments
+    ADD COLUMN schedule_id BIGINT REFERENCES contribution_schedules (id) ON DELETE SET NULL,
+    ADD COLUMN run_date    DATE,
+    ADD CONSTRAINT investments_schedule_run_check CHECK ((schedule_id IS NULL) OR (run_date IS NOT NULL));
+
+CREATE UNIQUE INDEX investments_schedule_run_idx ON investments (schedule_id, run_date, fund_id);
@@ -0,0 +1,7 @@
+-- Investments record whether they were paid into the customer's ISA, so only those count towards the ISA
+-- allowance. Money paid into a pension while a customer was employed never counts, even once they become retail.
+ALTER TABLE investments ADD COLUMN isa BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Investments made before now were counted whenever the customer paid them in themselves, and are left counting
+-- rather than giving customers allowance they may already have used
+UPDATE investments SET isa = TRUE WHERE type = 'investment' AND source IN ('one_off', 'regular');
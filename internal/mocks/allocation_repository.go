@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+)
+
+// AllocationRepository is a mock implementation of repository.AllocationRepository
+type AllocationRepository struct {
+	MockAllocation  *model.TargetAllocation
+	MockAllocations []*model.TargetAllocation
+	MockErr         error
+}
+
+// GetAllocation implements repository.AllocationRepository
+func (m *AllocationRepository) GetAllocation(customerID uint) (*model.TargetAllocation, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockAllocation, nil
+}
+
+// ListAllocations implements repository.AllocationRepository
+func (m *AllocationRepository) ListAllocations() ([]*model.TargetAllocation, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockAllocations, nil
+}
+
+// SetAllocation implements repository.AllocationRepository. It returns the allocation it was given.
+func (m *AllocationRepository) SetAllocation(allocation *model.TargetAllocation) (*model.TargetAllocation, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return allocation, nil
+}
+
+// DeleteAllocation implements repository.AllocationRepository
+func (m *AllocationRepository) DeleteAllocation(customerID uint) error {
+	return m.MockErr
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+)
+
+// AllocationService is a mock implementation of service.Allocation
+type AllocationService struct {
+	MockAllocation *model.TargetAllocation
+	MockErr        error
+}
+
+// GetAllocation implements service.Allocation
+func (m *AllocationService) GetAllocation(customerID uint) (*model.TargetAllocation, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockAllocation, nil
+}
+
+// SetAllocation implements service.Allocation
+func (m *AllocationService) SetAllocation(customerID uint, update model.TargetAllocationUpdate) (*model.TargetAllocation, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockAllocation, nil
+}
+
+// DeleteAllocation implements service.Allocation
+func (m *AllocationService) DeleteAllocation(customerID uint) error {
+	return m.MockErr
+}
@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a mock implementation of the clock.Clock interface that always tells MockNow until it is Set
+type Clock struct {
+	mu      sync.Mutex
+	MockNow time.Time
+}
+
+// Now returns the mocked time
+func (m *Clock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.MockNow
+}
+
+// Set moves the clock to now
+func (m *Clock) Set(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.MockNow = now
+}
@@ -6,8 +6,9 @@ import (
 
 // CustomerRepository is a mock implementation of repository.CustomerRepository
 type CustomerRepository struct {
-	MockCustomer *model.Customer
-	MockErr      error
+	MockCustomer  *model.Customer
+	MockCustomers []*model.Customer
+	MockErr       error
 }
 
 // CreateCustomer implements repository.CustomerRepository
@@ -17,3 +18,36 @@ func (m *CustomerRepository) CreateCustomer(customerName string, employerID *uin
 	}
 	return m.MockCustomer, nil
 }
+
+// GetCustomerByID implements repository.CustomerRepository
+func (m *CustomerRepository) GetCustomerByID(id uint) (*model.Customer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockCustomer, nil
+}
+
+// ListCustomers implements repository.CustomerRepository
+func (m *CustomerRepository) ListCustomers(filter model.CustomerFilter) ([]*model.Customer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockCustomers, nil
+}
+
+// UpdateCustomer implements repository.CustomerRepository. It returns a copy of MockCustomer changed by update.
+func (m *CustomerRepository) UpdateCustomer(id uint, update func(customer *model.Customer) error) (*model.Customer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	updated := *m.MockCustomer
+	if err := update(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteCustomerIfNoInvestments implements repository.CustomerRepository
+func (m *CustomerRepository) DeleteCustomerIfNoInvestments(id uint) error {
+	return m.MockErr
+}
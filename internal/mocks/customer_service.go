@@ -6,8 +6,9 @@ import (
 
 // CustomerService is a mock implementation of service.Customer
 type CustomerService struct {
-	MockCustomer *model.Customer
-	MockErr      error
+	MockCustomer  *model.Customer
+	MockCustomers []*model.Customer
+	MockErr       error
 }
 
 // NewRetailCustomer implements service.Customer
@@ -25,3 +26,32 @@ func (m *CustomerService) NewEmployedCustomer(name string, employerID uint) (*mo
 	}
 	return m.MockCustomer, nil
 }
+
+// GetCustomer implements service.Customer
+func (m *CustomerService) GetCustomer(id uint) (*model.Customer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockCustomer, nil
+}
+
+// ListCustomers implements service.Customer
+func (m *CustomerService) ListCustomers(filter model.CustomerFilter) ([]*model.Customer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockCustomers, nil
+}
+
+// UpdateCustomer implements service.Customer
+func (m *CustomerService) UpdateCustomer(id uint, update model.CustomerUpdate) (*model.Customer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockCustomer, nil
+}
+
+// DeleteCustomer implements service.Customer
+func (m *CustomerService) DeleteCustomer(id uint) error {
+	return m.MockErr
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+)
+
+// DealingRepository is a mock implementation of the DealingRepository interface
+type DealingRepository struct {
+	MockBatch   *model.DealingBatch
+	MockBatches []*model.DealingBatch
+	MockErr     error
+}
+
+// CreateDealingBatches returns copies of the batches it is given with IDs assigned in order
+func (m *DealingRepository) CreateDealingBatches(batches []*model.DealingBatch, orders, failed []*model.Investment) ([]*model.DealingBatch, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	created := make([]*model.DealingBatch, len(batches))
+	for i, batch := range batches {
+		stored := *batch
+		stored.ID = uint(i + 1)
+		created[i] = &stored
+	}
+	return created, nil
+}
+
+// GetDealingBatchByID retrieves a dealing batch by ID
+func (m *DealingRepository) GetDealingBatchByID(id uint) (*model.DealingBatch, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockBatch, nil
+}
+
+// ListDealingBatches retrieves the dealing batches
+func (m *DealingRepository) ListDealingBatches(filter model.DealingBatchFilter) ([]*model.DealingBatch, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockBatches, nil
+}
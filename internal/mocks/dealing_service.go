@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+)
+
+// DealingService is a mock implementation of the Dealing service interface
+type DealingService struct {
+	MockBatch   *model.DealingBatch
+	MockBatches []*model.DealingBatch
+	MockErr     error
+}
+
+// RunDealing deals the pending orders for a dealing day
+func (m *DealingService) RunDealing(date model.Date) ([]*model.DealingBatch, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockBatches, nil
+}
+
+// GetBatch retrieves a dealing batch by ID
+func (m *DealingService) GetBatch(id uint) (*model.DealingBatch, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockBatch, nil
+}
+
+// ListBatches retrieves the dealing batches matching a filter
+func (m *DealingService) ListBatches(filter model.DealingBatchFilter) ([]*model.DealingBatch, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockBatches, nil
+}
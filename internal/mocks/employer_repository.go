@@ -6,8 +6,9 @@ import (
 
 // EmployerRepository is a mock implementation of repository.EmployerRepository
 type EmployerRepository struct {
-	MockEmployer *model.Employer
-	MockErr      error
+	MockEmployer  *model.Employer
+	MockEmployers []*model.Employer
+	MockErr       error
 }
 
 // CreateEmployer implements repository.EmployerRepository
@@ -17,3 +18,42 @@ func (m *EmployerRepository) CreateEmployer(name string) (*model.Employer, error
 	}
 	return m.MockEmployer, nil
 }
+
+// GetEmployerByID implements repository.EmployerRepository
+func (m *EmployerRepository) GetEmployerByID(id uint) (*model.Employer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockEmployer, nil
+}
+
+// ListEmployers implements repository.EmployerRepository
+func (m *EmployerRepository) ListEmployers(filter model.EmployerFilter) ([]*model.Employer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockEmployers, nil
+}
+
+// UpdateEmployer implements repository.EmployerRepository. It returns the new name, scheme and default fund on
+// top of MockEmployer.
+func (m *EmployerRepository) UpdateEmployer(id uint, name string, scheme *model.ContributionScheme, defaultFundID *uint) (*model.Employer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	updated := *m.MockEmployer
+	updated.Name = name
+	updated.ContributionScheme = scheme
+	updated.DefaultFundID = defaultFundID
+	return &updated, nil
+}
+
+// DeactivateEmployer implements repository.EmployerRepository. It returns an inactive copy of MockEmployer.
+func (m *EmployerRepository) DeactivateEmployer(id uint) (*model.Employer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	deactivated := *m.MockEmployer
+	deactivated.Active = false
+	return &deactivated, nil
+}
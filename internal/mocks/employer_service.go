@@ -8,6 +8,7 @@ import (
 type EmployerService struct {
 	MockEmployer  *model.Employer
 	MockEmployers []*model.Employer
+	MockEmployees []*model.Customer
 	MockErr       error
 }
 
@@ -19,10 +20,42 @@ func (m *EmployerService) NewEmployer(name string) (*model.Employer, error) {
 	return m.MockEmployer, nil
 }
 
-// GetAllEmployers implements service.Employer
-func (m *EmployerService) GetAllEmployers() ([]*model.Employer, error) {
+// GetEmployer implements service.Employer
+func (m *EmployerService) GetEmployer(id uint) (*model.Employer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockEmployer, nil
+}
+
+// ListEmployers implements service.Employer
+func (m *EmployerService) ListEmployers(filter model.EmployerFilter) ([]*model.Employer, error) {
 	if m.MockErr != nil {
 		return nil, m.MockErr
 	}
 	return m.MockEmployers, nil
 }
+
+// UpdateEmployer implements service.Employer
+func (m *EmployerService) UpdateEmployer(id uint, update model.EmployerUpdate) (*model.Employer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockEmployer, nil
+}
+
+// DeactivateEmployer implements service.Employer
+func (m *EmployerService) DeactivateEmployer(id uint) (*model.Employer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockEmployer, nil
+}
+
+// ListEmployees implements service.Employer
+func (m *EmployerService) ListEmployees(id uint) ([]*model.Customer, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockEmployees, nil
+}
@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+)
+
+// FundPriceRepository is a mock implementation of the FundPriceRepository interface
+type FundPriceRepository struct {
+	MockPrice  *model.FundPrice
+	MockPrices []*model.FundPrice
+	MockErr    error
+}
+
+// CreateFundPrice returns a copy of the price it is given
+func (m *FundPriceRepository) CreateFundPrice(price *model.FundPrice) (*model.FundPrice, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	created := *price
+	return &created, nil
+}
+
+// GetLatestFundPrice retrieves a fund's latest price. Without a MockPrice it looks up the fund's last price on or
+// before the date in MockPrices, which must be ordered by date.
+func (m *FundPriceRepository) GetLatestFundPrice(fundID uint, onOrBefore model.Date) (*model.FundPrice, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	if m.MockPrice != nil {
+		return m.MockPrice, nil
+	}
+	var latest *model.FundPrice
+	for _, price := range m.MockPrices {
+		if price.FundID == fundID && !price.Date.After(onOrBefore.Time) {
+			latest = price
+		}
+	}
+	if latest == nil {
+		return nil, repository.ErrFundPriceNotFound
+	}
+	return latest, nil
+}
+
+// ListFundPrices retrieves a fund's prices
+func (m *FundPriceRepository) ListFundPrices(fundID uint, filter model.FundPriceFilter) ([]*model.FundPrice, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockPrices, nil
+}
@@ -12,17 +12,34 @@ type FundRepository struct {
 }
 
 // CreateFund implements repository.FundRepository
-func (m *FundRepository) CreateFund(name string) (*model.Fund, error) {
+func (m *FundRepository) CreateFund(create model.FundCreate) (*model.Fund, error) {
 	if m.MockErr != nil {
 		return nil, m.MockErr
 	}
 	return m.MockFund, nil
 }
 
-// GetAllFunds implements repository.FundRepository
-func (m *FundRepository) GetAllFunds() ([]*model.Fund, error) {
+// GetFundByID implements repository.FundRepository
+func (m *FundRepository) GetFundByID(id uint) (*model.Fund, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockFund, nil
+}
+
+// ListFunds implements repository.FundRepository
+func (m *FundRepository) ListFunds(filter model.FundFilter) ([]*model.Fund, error) {
 	if m.MockErr != nil {
 		return nil, m.MockErr
 	}
 	return m.MockFunds, nil
 }
+
+// UpdateFund implements repository.FundRepository. It returns a copy of the fund it was given.
+func (m *FundRepository) UpdateFund(fund *model.Fund) (*model.Fund, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	updated := *fund
+	return &updated, nil
+}
@@ -6,23 +6,73 @@ import (
 
 // FundService is a mock implementation of service.Fund
 type FundService struct {
-	MockFund  *model.Fund
-	MockFunds []*model.Fund
-	MockErr   error
+	MockFund   *model.Fund
+	MockFunds  []*model.Fund
+	MockPrice  *model.FundPrice
+	MockPrices []*model.FundPrice
+	MockErr    error
 }
 
 // NewFund implements service.Fund
-func (m *FundService) NewFund(name string) (*model.Fund, error) {
+func (m *FundService) NewFund(create model.FundCreate) (*model.Fund, error) {
 	if m.MockErr != nil {
 		return nil, m.MockErr
 	}
 	return m.MockFund, nil
 }
 
-// GetAllFunds implements service.Fund
-func (m *FundService) GetAllFunds() ([]*model.Fund, error) {
+// ListFunds implements service.Fund
+func (m *FundService) ListFunds(filter model.FundFilter, order model.FundOrder) ([]*model.Fund, error) {
 	if m.MockErr != nil {
 		return nil, m.MockErr
 	}
 	return m.MockFunds, nil
 }
+
+// GetFund implements service.Fund
+func (m *FundService) GetFund(id uint) (*model.Fund, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockFund, nil
+}
+
+// UpdateFund implements service.Fund
+func (m *FundService) UpdateFund(id uint, update model.FundUpdate) (*model.Fund, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockFund, nil
+}
+
+// CloseFund implements service.Fund
+func (m *FundService) CloseFund(id uint) (*model.Fund, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockFund, nil
+}
+
+// AddFundPrice implements service.Fund
+func (m *FundService) AddFundPrice(fundID uint, create model.FundPriceCreate) (*model.FundPrice, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockPrice, nil
+}
+
+// ListFundPrices implements service.Fund
+func (m *FundService) ListFundPrices(fundID uint, filter model.FundPriceFilter) ([]*model.FundPrice, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockPrices, nil
+}
+
+// GetLatestFundPrice implements service.Fund
+func (m *FundService) GetLatestFundPrice(fundID uint) (*model.FundPrice, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockPrice, nil
+}
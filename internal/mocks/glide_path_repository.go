@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+)
+
+// GlidePathRepository is a mock implementation of repository.GlidePathRepository
+type GlidePathRepository struct {
+	MockGlidePath  *model.GlidePath
+	MockGlidePaths []*model.GlidePath
+	MockErr        error
+}
+
+// CreateGlidePath implements repository.GlidePathRepository. It returns the glide path it was given.
+func (m *GlidePathRepository) CreateGlidePath(glidePath *model.GlidePath) (*model.GlidePath, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return glidePath, nil
+}
+
+// GetGlidePathByID implements repository.GlidePathRepository
+func (m *GlidePathRepository) GetGlidePathByID(id uint) (*model.GlidePath, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockGlidePath, nil
+}
+
+// ListGlidePaths implements repository.GlidePathRepository
+func (m *GlidePathRepository) ListGlidePaths() ([]*model.GlidePath, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockGlidePaths, nil
+}
@@ -2,21 +2,130 @@ package mocks
 
 import (
 	"cushon/internal/model"
+	"cushon/internal/repository"
+	"time"
 )
 
 // InvestmentRepository is a mock implementation of the InvestmentRepository interface
 type InvestmentRepository struct {
 	MockInvestment  *model.Investment
 	MockInvestments []*model.Investment
+	MockSwitch      *model.Switch
 	MockErr         error
 }
 
-// CreateInvestment creates a new investment
-func (m *InvestmentRepository) CreateInvestment(clientID, fundID uint, amount float32) (*model.Investment, error) {
+// CreateInvestment returns a copy of the investment it is given, with MockInvestment's ID when it is set
+func (m *InvestmentRepository) CreateInvestment(investment *model.Investment) (*model.Investment, error) {
 	if m.MockErr != nil {
 		return nil, m.MockErr
 	}
-	return m.MockInvestment, nil
+	created := *investment
+	created.Type = model.TransactionTypeInvestment
+	created.Status = model.InvestmentStatusPending
+	if m.MockInvestment != nil {
+		created.ID = m.MockInvestment.ID
+	}
+	return &created, nil
+}
+
+// CreateISAInvestments returns copies of the investments it is given like CreateInvestments, marked as paid into
+// the ISA, or repository.ErrISAAllowanceExceeded when they and the ISA subscriptions in MockInvestments made this
+// tax year come to more than allowance
+func (m *InvestmentRepository) CreateISAInvestments(investments []*model.Investment, allowance model.Money) ([]*model.Investment, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	taxYear := model.TaxYearOf(time.Now())
+	var subscribed int64
+	for _, investment := range investments {
+		subscribed += investment.Amount.Minor
+	}
+	for _, existing := range m.MockInvestments {
+		if len(investments) > 0 && existing.ClientID == investments[0].ClientID && existing.IsISASubscription() && taxYear.Contains(existing.CreatedAt) {
+			subscribed += existing.Amount.Minor
+		}
+	}
+	if subscribed > allowance.Minor {
+		return nil, repository.ErrISAAllowanceExceeded
+	}
+	created, err := m.CreateInvestments(investments)
+	for _, investment := range created {
+		investment.ISA = true
+	}
+	return created, err
+}
+
+// CreateInvestments returns copies of the investments it is given, numbered from MockInvestment's ID when it is set
+func (m *InvestmentRepository) CreateInvestments(investments []*model.Investment) ([]*model.Investment, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	created := make([]*model.Investment, len(investments))
+	for i, investment := range investments {
+		stored := *investment
+		stored.Type = model.TransactionTypeInvestment
+		stored.Status = model.InvestmentStatusPending
+		if m.MockInvestment != nil {
+			stored.ID = m.MockInvestment.ID + uint(i)
+		}
+		created[i] = &stored
+	}
+	return created, nil
+}
+
+// CreateWithdrawal returns a copy of the withdrawal it is given, with MockInvestment's ID when it is set
+func (m *InvestmentRepository) CreateWithdrawal(withdrawal *model.Investment) (*model.Investment, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	created := *withdrawal
+	created.Type = model.TransactionTypeWithdrawal
+	created.Status = model.InvestmentStatusPending
+	if m.MockInvestment != nil {
+		created.ID = m.MockInvestment.ID
+	}
+	return &created, nil
+}
+
+// CreateSwitch returns a copy of the switch it is given with both legs stored, and MockSwitch's ID when it is set
+func (m *InvestmentRepository) CreateSwitch(fundSwitch *model.Switch) (*model.Switch, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	created := *fundSwitch
+	if m.MockSwitch != nil {
+		created.ID = m.MockSwitch.ID
+	}
+	sell, buy := *fundSwitch.Sell, *fundSwitch.Buy
+	sell.Type, buy.Type = model.TransactionTypeSwitchOut, model.TransactionTypeSwitchIn
+	sell.SwitchID, buy.SwitchID = created.ID, created.ID
+	sell.Status, buy.Status = model.InvestmentStatusPending, model.InvestmentStatusPending
+	created.Sell, created.Buy = &sell, &buy
+	return &created, nil
+}
+
+// CreateSwitches returns copies of the switches it is given like CreateSwitch, numbered from MockSwitch's ID
+func (m *InvestmentRepository) CreateSwitches(switches []*model.Switch) ([]*model.Switch, error) {
+	created := make([]*model.Switch, len(switches))
+	for i, fundSwitch := range switches {
+		var err error
+		if created[i], err = m.CreateSwitch(fundSwitch); err != nil {
+			return nil, err
+		}
+		if m.MockSwitch != nil {
+			id := m.MockSwitch.ID + uint(i)
+			created[i].ID, created[i].Sell.SwitchID, created[i].Buy.SwitchID = id, id, id
+		}
+	}
+	return created, nil
+}
+
+// GetSwitchByID retrieves a switch by ID
+func (m *InvestmentRepository) GetSwitchByID(id uint) (*model.Switch, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockSwitch, nil
 }
 
 // GetInvestmentByID retrieves an investment by ID
@@ -34,3 +143,48 @@ func (m *InvestmentRepository) GetInvestmentsByClientID(clientID uint) ([]*model
 	}
 	return m.MockInvestments, nil
 }
+
+// ListInvestments retrieves the transactions matching a filter
+func (m *InvestmentRepository) ListInvestments(filter model.InvestmentFilter) ([]*model.Investment, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockInvestments, nil
+}
+
+// SumDealtUnits totals the units of the MockInvestments that have been dealt in each of the holdings
+func (m *InvestmentRepository) SumDealtUnits(holdings []model.HoldingKey) (map[model.HoldingKey]model.Units, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	wanted := make(map[model.HoldingKey]bool, len(holdings))
+	for _, holding := range holdings {
+		wanted[holding] = true
+	}
+	dealt := make(map[model.HoldingKey]model.Units)
+	for _, investment := range m.MockInvestments {
+		holding := model.HoldingKey{ClientID: investment.ClientID, FundID: investment.FundID}
+		if wanted[holding] && investment.Status != model.InvestmentStatusPending && investment.Status.HoldsUnits() {
+			dealt[holding] += investment.Units
+		}
+	}
+	return dealt, nil
+}
+
+// UpdateInvestmentStatus returns copies of the MockInvestments whose IDs are in ids, moved to status to
+func (m *InvestmentRepository) UpdateInvestmentStatus(ids []uint, from, to model.InvestmentStatus) ([]*model.Investment, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	updated := make([]*model.Investment, 0, len(ids))
+	for _, id := range ids {
+		for _, investment := range m.MockInvestments {
+			if investment.ID == id {
+				moved := *investment
+				moved.Status = to
+				updated = append(updated, &moved)
+			}
+		}
+	}
+	return updated, nil
+}
@@ -2,23 +2,75 @@ package mocks
 
 import (
 	"cushon/internal/model"
+	"io"
 )
 
 // InvestmentService is a mock implementation of the Investment service interface
 type InvestmentService struct {
 	MockInvestment  *model.Investment
 	MockInvestments []*model.Investment
+	MockSwitch      *model.Switch
+	MockAllowance   *model.ISAAllowance
+	MockPayroll     *model.PayrollImport
 	MockErr         error
 }
 
 // NewInvestment creates a new investment
-func (m *InvestmentService) NewInvestment(clientID, fundID uint, amount float32) (*model.Investment, error) {
+func (m *InvestmentService) NewInvestment(create model.InvestmentCreate) (*model.Investment, error) {
 	if m.MockErr != nil {
 		return nil, m.MockErr
 	}
 	return m.MockInvestment, nil
 }
 
+// NewSalaryContribution invests a pay period's workplace contributions
+func (m *InvestmentService) NewSalaryContribution(create model.SalaryContributionCreate) ([]*model.Investment, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockInvestments, nil
+}
+
+// ImportPayroll invests the contributions in an employer's payroll file
+func (m *InvestmentService) ImportPayroll(employerID uint, file io.Reader) (*model.PayrollImport, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockPayroll, nil
+}
+
+// Contribute implements service.Investment
+func (m *InvestmentService) Contribute(create model.ContributionCreate) ([]*model.Investment, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockInvestments, nil
+}
+
+// NewWithdrawal records a withdrawal
+func (m *InvestmentService) NewWithdrawal(create model.WithdrawalCreate) (*model.Investment, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockInvestment, nil
+}
+
+// NewSwitch switches between funds
+func (m *InvestmentService) NewSwitch(create model.SwitchCreate) (*model.Switch, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockSwitch, nil
+}
+
+// GetSwitch retrieves a switch by ID
+func (m *InvestmentService) GetSwitch(id uint) (*model.Switch, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockSwitch, nil
+}
+
 // GetInvestment retrieves an investment by ID
 func (m *InvestmentService) GetInvestment(id uint) (*model.Investment, error) {
 	if m.MockErr != nil {
@@ -27,10 +79,34 @@ func (m *InvestmentService) GetInvestment(id uint) (*model.Investment, error) {
 	return m.MockInvestment, nil
 }
 
-// GetInvestmentsByClientID retrieves all investments for a specific client
-func (m *InvestmentService) GetInvestmentsByClientID(clientID uint) ([]*model.Investment, error) {
+// ListInvestments retrieves the transactions matching a filter
+func (m *InvestmentService) ListInvestments(filter model.InvestmentFilter) ([]*model.Investment, error) {
 	if m.MockErr != nil {
 		return nil, m.MockErr
 	}
 	return m.MockInvestments, nil
 }
+
+// TransitionInvestment moves an investment to a new status
+func (m *InvestmentService) TransitionInvestment(id uint, status model.InvestmentStatus) (*model.Investment, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockInvestment, nil
+}
+
+// CancelInvestment cancels a pending investment
+func (m *InvestmentService) CancelInvestment(id uint) (*model.Investment, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockInvestment, nil
+}
+
+// GetISAAllowance retrieves a customer's ISA allowance
+func (m *InvestmentService) GetISAAllowance(customerID uint) (*model.ISAAllowance, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockAllowance, nil
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+)
+
+// LifestylingService is a mock implementation of service.Lifestyling
+type LifestylingService struct {
+	MockGlidePath  *model.GlidePath
+	MockGlidePaths []*model.GlidePath
+	MockRun        *model.GlidePathRun
+	MockErr        error
+}
+
+// CreateGlidePath implements service.Lifestyling
+func (m *LifestylingService) CreateGlidePath(create model.GlidePathCreate) (*model.GlidePath, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockGlidePath, nil
+}
+
+// GetGlidePath implements service.Lifestyling
+func (m *LifestylingService) GetGlidePath(id uint) (*model.GlidePath, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockGlidePath, nil
+}
+
+// ListGlidePaths implements service.Lifestyling
+func (m *LifestylingService) ListGlidePaths() ([]*model.GlidePath, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockGlidePaths, nil
+}
+
+// ApplyGlidePaths implements service.Lifestyling
+func (m *LifestylingService) ApplyGlidePaths() (*model.GlidePathRun, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockRun, nil
+}
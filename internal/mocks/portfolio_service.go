@@ -0,0 +1,19 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+)
+
+// PortfolioService is a mock implementation of service.Portfolio
+type PortfolioService struct {
+	MockPortfolio *model.Portfolio
+	MockErr       error
+}
+
+// GetPortfolio implements service.Portfolio
+func (m *PortfolioService) GetPortfolio(customerID uint) (*model.Portfolio, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockPortfolio, nil
+}
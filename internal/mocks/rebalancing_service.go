@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+)
+
+// RebalancingService is a mock implementation of service.Rebalancing
+type RebalancingService struct {
+	MockRebalance *model.Rebalance
+	MockRun       *model.RebalanceRun
+	MockErr       error
+}
+
+// PreviewRebalance implements service.Rebalancing
+func (m *RebalancingService) PreviewRebalance(customerID uint) (*model.Rebalance, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockRebalance, nil
+}
+
+// Rebalance implements service.Rebalancing
+func (m *RebalancingService) Rebalance(customerID uint) (*model.Rebalance, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockRebalance, nil
+}
+
+// RebalanceAll implements service.Rebalancing
+func (m *RebalancingService) RebalanceAll() (*model.RebalanceRun, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockRun, nil
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+)
+
+// ReturnsService is a mock implementation of service.Returns
+type ReturnsService struct {
+	MockFundReturns     *model.FundReturns
+	MockCustomerReturns *model.CustomerReturns
+	MockErr             error
+}
+
+// GetFundReturns implements service.Returns
+func (m *ReturnsService) GetFundReturns(fundID uint) (*model.FundReturns, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockFundReturns, nil
+}
+
+// GetCustomerReturns implements service.Returns
+func (m *ReturnsService) GetCustomerReturns(customerID uint) (*model.CustomerReturns, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockCustomerReturns, nil
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+)
+
+// ScheduleRepository is a mock implementation of repository.ScheduleRepository
+type ScheduleRepository struct {
+	MockSchedule  *model.ContributionSchedule
+	MockSchedules []*model.ContributionSchedule
+	MockErr       error
+}
+
+// CreateSchedule implements repository.ScheduleRepository. It returns the schedule it was given.
+func (m *ScheduleRepository) CreateSchedule(schedule *model.ContributionSchedule) (*model.ContributionSchedule, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return schedule, nil
+}
+
+// GetScheduleByID implements repository.ScheduleRepository
+func (m *ScheduleRepository) GetScheduleByID(id uint) (*model.ContributionSchedule, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockSchedule, nil
+}
+
+// ListSchedules implements repository.ScheduleRepository
+func (m *ScheduleRepository) ListSchedules(filter model.ScheduleFilter) ([]*model.ContributionSchedule, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockSchedules, nil
+}
+
+// UpdateSchedule implements repository.ScheduleRepository. It returns the schedule it was given.
+func (m *ScheduleRepository) UpdateSchedule(schedule *model.ContributionSchedule) (*model.ContributionSchedule, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return schedule, nil
+}
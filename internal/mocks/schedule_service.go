@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"cushon/internal/model"
+)
+
+// ScheduleService is a mock implementation of service.Schedule
+type ScheduleService struct {
+	MockSchedule  *model.ContributionSchedule
+	MockSchedules []*model.ContributionSchedule
+	MockRun       *model.ScheduleRun
+	MockErr       error
+}
+
+// CreateSchedule implements service.Schedule
+func (m *ScheduleService) CreateSchedule(customerID uint, create model.ContributionScheduleCreate) (*model.ContributionSchedule, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockSchedule, nil
+}
+
+// GetSchedule implements service.Schedule
+func (m *ScheduleService) GetSchedule(id uint) (*model.ContributionSchedule, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockSchedule, nil
+}
+
+// ListSchedules implements service.Schedule
+func (m *ScheduleService) ListSchedules(customerID uint) ([]*model.ContributionSchedule, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockSchedules, nil
+}
+
+// PauseSchedule implements service.Schedule
+func (m *ScheduleService) PauseSchedule(id uint) (*model.ContributionSchedule, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockSchedule, nil
+}
+
+// ResumeSchedule implements service.Schedule
+func (m *ScheduleService) ResumeSchedule(id uint) (*model.ContributionSchedule, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockSchedule, nil
+}
+
+// RunSchedules implements service.Schedule
+func (m *ScheduleService) RunSchedules() (*model.ScheduleRun, error) {
+	if m.MockErr != nil {
+		return nil, m.MockErr
+	}
+	return m.MockRun, nil
+}
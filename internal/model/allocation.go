@@ -0,0 +1,70 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// FundAllocation is the share of a customer's contributions invested in a fund
+type FundAllocation struct {
+	FundID     uint    `json:"fund_id"`
+	Percentage Percent `json:"percentage"`
+}
+
+// TargetAllocation is how a customer's contributions are split between funds, in the order they chose them.
+// The percentages add up to 100%.
+type TargetAllocation struct {
+	CustomerID uint
+	Funds      []FundAllocation
+	// EmployerDefault is set when the customer hasn't chosen an allocation and their contributions go into
+	// their employer's default fund
+	EmployerDefault bool
+	UpdatedAt       time.Time
+}
+
+// Split divides an amount between the allocation's funds. Each share is rounded down to the minor unit and the
+// pennies left over go to the shares that lost the most to rounding, so the shares always add up to amount.
+func (a *TargetAllocation) Split(amount Money) []Money {
+	shares := make([]Money, len(a.Funds))
+	remainders := make([]int64, len(a.Funds))
+	left := amount.Minor
+	for i, fund := range a.Funds {
+		shares[i] = amount.Share(fund.Percentage)
+		remainders[i] = amount.Minor % int64(OneHundredPercent) * int64(fund.Percentage) % int64(OneHundredPercent)
+		left -= shares[i].Minor
+	}
+
+	order := make([]int, len(a.Funds))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return remainders[order[i]] > remainders[order[j]] })
+	for i := 0; left > 0 && len(order) > 0; i = (i + 1) % len(order) {
+		shares[order[i]].Minor++
+		left--
+	}
+	return shares
+}
+
+// TargetAllocationUpdate represents a request to replace a customer's target allocation
+type TargetAllocationUpdate struct {
+	Funds []FundAllocation `json:"funds"`
+}
+
+// TargetAllocationResponse represents a target allocation as sent in API responses
+type TargetAllocationResponse struct {
+	CustomerID      uint             `json:"customer_id"`
+	Funds           []FundAllocation `json:"funds"`
+	EmployerDefault bool             `json:"employer_default"`
+	UpdatedAt       *time.Time       `json:"updated_at,omitempty"`
+}
+
+// ContributionCreate represents a request to invest an amount across a customer's target allocation
+type ContributionCreate struct {
+	ClientID uint  `json:"client_id"`
+	Amount   Money `json:"amount"`
+	// Source is where the money came from, one-off unless given
+	Source ContributionSource `json:"source"`
+	// Run is the schedule run a regular contribution pays in. It is only set by the scheduler.
+	Run *ScheduleRunKey `json:"-"`
+}
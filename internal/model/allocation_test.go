@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTargetAllocation_Split(t *testing.T) {
+	gbp := func(minor int64) Money { return NewMoney(minor, DefaultCurrency) }
+
+	tests := []struct {
+		name        string
+		percentages []Percent
+		amount      Money
+		want        []int64
+	}{
+		{
+			name:        "Single fund",
+			percentages: []Percent{OneHundredPercent},
+			amount:      gbp(12345),
+			want:        []int64{12345},
+		},
+		{
+			name:        "Exact split",
+			percentages: []Percent{60000, 40000},
+			amount:      gbp(10000),
+			want:        []int64{6000, 4000},
+		},
+		{
+			name:        "Pennies left over go to the largest remainders",
+			percentages: []Percent{33333, 33333, 33334},
+			amount:      gbp(10001),
+			want:        []int64{3334, 3333, 3334},
+		},
+		{
+			name:        "Amount too small for every fund",
+			percentages: []Percent{60000, 40000},
+			amount:      gbp(1),
+			want:        []int64{1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocation := &TargetAllocation{}
+			for i, percentage := range tt.percentages {
+				allocation.Funds = append(allocation.Funds, FundAllocation{FundID: uint(i + 1), Percentage: percentage})
+			}
+
+			got := allocation.Split(tt.amount)
+
+			minors := make([]int64, len(got))
+			var total int64
+			for i, share := range got {
+				if share.Currency != tt.amount.Currency {
+					t.Errorf("shares[%d].Currency = %v, want %v", i, share.Currency, tt.amount.Currency)
+				}
+				minors[i] = share.Minor
+				total += share.Minor
+			}
+			if fmt.Sprint(minors) != fmt.Sprint(tt.want) {
+				t.Errorf("Split() = %v, want %v", minors, tt.want)
+			}
+			if total != tt.amount.Minor {
+				t.Errorf("Split() shares add up to %d, want %d", total, tt.amount.Minor)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package model
+
+// ContributionSource tells where the money for an investment came from, so statements can break contributions down
+type ContributionSource string
+
+const (
+	// ContributionSourceEmployee is paid by an employee out of their net pay through payroll
+	ContributionSourceEmployee ContributionSource = "employee"
+	// ContributionSourceEmployer is paid by the employer on top of the employee's pay
+	ContributionSourceEmployer ContributionSource = "employer"
+	// ContributionSourceSalarySacrifice is paid by the employer out of salary the employee has given up
+	ContributionSourceSalarySacrifice ContributionSource = "salary_sacrifice"
+	// ContributionSourceOneOff is a lump sum paid in by the customer themselves
+	ContributionSourceOneOff ContributionSource = "one_off"
+	// ContributionSourceRegular is paid in by the customer themselves on a contribution schedule
+	ContributionSourceRegular ContributionSource = "regular"
+)
+
+// Valid reports whether s is a known contribution source
+func (s ContributionSource) Valid() bool {
+	switch s {
+	case ContributionSourceEmployee, ContributionSourceEmployer, ContributionSourceSalarySacrifice, ContributionSourceOneOff,
+		ContributionSourceRegular:
+		return true
+	}
+	return false
+}
+
+// Workplace reports whether contributions from s are paid through an employer's payroll, which only
+// employed customers have
+func (s ContributionSource) Workplace() bool {
+	return s == ContributionSourceEmployee || s == ContributionSourceEmployer || s == ContributionSourceSalarySacrifice
+}
+
+// ContributionScheme is how an employer funds its workplace pension, as shares of each employee's salary for
+// a pay period
+type ContributionScheme struct {
+	// EmployeeRate is the share of salary employees pay in unless they choose another rate
+	EmployeeRate Percent `json:"employee_rate"`
+	// EmployerRate is the share of salary the employer pays in
+	EmployerRate Percent `json:"employer_rate"`
+	// Matching makes the employer pay in the same share of salary as the employee instead, up to EmployerRate
+	Matching bool `json:"matching"`
+	// EmployerCap, when set, is the most the employer pays in per pay period
+	EmployerCap *Money `json:"employer_cap,omitempty"`
+	// SalarySacrifice makes employee contributions by salary sacrifice instead of deductions from net pay
+	SalarySacrifice bool `json:"salary_sacrifice"`
+}
+
+// EmployeeSource returns the source employee contributions under the scheme are recorded with
+func (s ContributionScheme) EmployeeSource() ContributionSource {
+	if s.SalarySacrifice {
+		return ContributionSourceSalarySacrifice
+	}
+	return ContributionSourceEmployee
+}
+
+// Contributions splits what is paid in for an employee earning salary in a pay period who contributes
+// employeeRate of it. Both amounts are rounded down to the minor unit.
+func (s ContributionScheme) Contributions(salary Money, employeeRate Percent) (employee, employer Money) {
+	employerRate := s.EmployerRate
+	if s.Matching && employeeRate < employerRate {
+		employerRate = employeeRate
+	}
+
+	employee = salary.Share(employeeRate)
+	employer = salary.Share(employerRate)
+	if s.EmployerCap != nil && employer.Minor > s.EmployerCap.Minor {
+		employer = *s.EmployerCap
+	}
+	return employee, employer
+}
+
+// SalaryContributionCreate represents a pay period's workplace contributions for an employee, split between
+// them and their employer according to the employer's contribution scheme
+type SalaryContributionCreate struct {
+	ClientID uint  `json:"client_id"`
+	FundID   uint  `json:"fund_id"`
+	Salary   Money `json:"salary"`
+	// EmployeeRate overrides the scheme's employee rate when the employee has chosen to pay in more or less
+	EmployeeRate *Percent `json:"employee_rate"`
+}
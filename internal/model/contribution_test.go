@@ -0,0 +1,84 @@
+package model
+
+import "testing"
+
+func TestContributionScheme_Contributions(t *testing.T) {
+	gbp := func(minor int64) Money { return NewMoney(minor, DefaultCurrency) }
+	employerCap := gbp(10000)
+
+	tests := []struct {
+		name         string
+		scheme       ContributionScheme
+		salary       Money
+		employeeRate Percent
+		wantEmployee Money
+		wantEmployer Money
+	}{
+		{
+			name:         "Fixed rates",
+			scheme:       ContributionScheme{EmployeeRate: 5000, EmployerRate: 3000},
+			salary:       gbp(250000),
+			employeeRate: 5000,
+			wantEmployee: gbp(12500),
+			wantEmployer: gbp(7500),
+		},
+		{
+			name:         "Employee paying less doesn't change a fixed employer rate",
+			scheme:       ContributionScheme{EmployeeRate: 5000, EmployerRate: 3000},
+			salary:       gbp(250000),
+			employeeRate: 1000,
+			wantEmployee: gbp(2500),
+			wantEmployer: gbp(7500),
+		},
+		{
+			name:         "Matching below the employer rate",
+			scheme:       ContributionScheme{EmployeeRate: 5000, EmployerRate: 6000, Matching: true},
+			salary:       gbp(250000),
+			employeeRate: 4000,
+			wantEmployee: gbp(10000),
+			wantEmployer: gbp(10000),
+		},
+		{
+			name:         "Matching stops at the employer rate",
+			scheme:       ContributionScheme{EmployeeRate: 5000, EmployerRate: 6000, Matching: true},
+			salary:       gbp(250000),
+			employeeRate: 8000,
+			wantEmployee: gbp(20000),
+			wantEmployer: gbp(15000),
+		},
+		{
+			name:         "Employer contribution capped",
+			scheme:       ContributionScheme{EmployeeRate: 5000, EmployerRate: 5000, EmployerCap: &employerCap},
+			salary:       gbp(500000),
+			employeeRate: 5000,
+			wantEmployee: gbp(25000),
+			wantEmployer: gbp(10000),
+		},
+		{
+			name:         "Amounts are rounded down",
+			scheme:       ContributionScheme{EmployeeRate: 3333, EmployerRate: 2500},
+			salary:       gbp(123457),
+			employeeRate: 3333,
+			wantEmployee: gbp(4114),
+			wantEmployer: gbp(3086),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employee, employer := tt.scheme.Contributions(tt.salary, tt.employeeRate)
+			if employee != tt.wantEmployee || employer != tt.wantEmployer {
+				t.Errorf("Contributions() = %v, %v, want %v, %v", employee, employer, tt.wantEmployee, tt.wantEmployer)
+			}
+		})
+	}
+}
+
+func TestContributionScheme_EmployeeSource(t *testing.T) {
+	if got := (ContributionScheme{}).EmployeeSource(); got != ContributionSourceEmployee {
+		t.Errorf("EmployeeSource() = %v, want %v", got, ContributionSourceEmployee)
+	}
+	if got := (ContributionScheme{SalarySacrifice: true}).EmployeeSource(); got != ContributionSourceSalarySacrifice {
+		t.Errorf("EmployeeSource() = %v, want %v", got, ContributionSourceSalarySacrifice)
+	}
+}
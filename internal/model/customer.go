@@ -1,14 +1,18 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Customer represents a user in the system
 type Customer struct {
-	ID         uint      `json:"id"`
-	Name       string    `json:"name"`
-	EmployerID *uint     `json:"employer_id"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	EmployerID *uint  `json:"employer_id"`
+	RetirementPlan
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // CustomerCreate represents the data needed to create a new customer
@@ -17,9 +21,67 @@ type CustomerCreate struct {
 	EmployerID *uint  `json:"employer_id"`
 }
 
+// CustomerUpdate represents a partial update of a customer. Fields that are absent are left unchanged.
+// Setting employer_id to null turns an employed customer into a retail one, and setting glide_path_id to null
+// opts the customer out of lifestyling.
+type CustomerUpdate struct {
+	Name          *string    `json:"name"`
+	EmployerID    OptionalID `json:"employer_id"`
+	DateOfBirth   *Date      `json:"date_of_birth"`
+	RetirementAge *int       `json:"retirement_age"`
+	GlidePathID   OptionalID `json:"glide_path_id"`
+}
+
+// CustomerFilter restricts which customers are listed. The zero value matches every customer.
+type CustomerFilter struct {
+	// EmployerID only matches customers employed by this employer
+	EmployerID *uint
+	// RetailOnly only matches customers without an employer
+	RetailOnly bool
+	// OnGlidePath only matches customers who have opted into lifestyling
+	OnGlidePath bool
+}
+
+// Matches reports whether a customer satisfies the filter
+func (f CustomerFilter) Matches(customer *Customer) bool {
+	if f.RetailOnly && customer.EmployerID != nil {
+		return false
+	}
+	if f.EmployerID != nil && (customer.EmployerID == nil || *customer.EmployerID != *f.EmployerID) {
+		return false
+	}
+	if f.OnGlidePath && customer.GlidePathID == nil {
+		return false
+	}
+	return true
+}
+
 // CustomerResponse represents the customer data that will be sent in API responses
 type CustomerResponse struct {
-	ID         uint   `json:"id"`
-	Name       string `json:"name"`
-	EmployerID *uint  `json:"employer_id,omitempty"`
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	EmployerID    *uint     `json:"employer_id,omitempty"`
+	DateOfBirth   *Date     `json:"date_of_birth,omitempty"`
+	RetirementAge *int      `json:"retirement_age,omitempty"`
+	GlidePathID   *uint     `json:"glide_path_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// OptionalID is a JSON field that tells apart being absent, being null and holding an ID
+type OptionalID struct {
+	// Set is true when the field was present in the JSON, even if it was null
+	Set bool
+	// Value is the ID, nil when the field was null
+	Value *uint
+}
+
+// UnmarshalJSON records that the field was present and decodes its value
+func (o *OptionalID) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	o.Value = nil
+	if string(data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
 }
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCustomerUpdate_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		wantName       *string
+		wantEmployerID OptionalID
+	}{
+		{
+			name:           "Employer absent",
+			body:           `{"name": "Jane Smith"}`,
+			wantName:       stringPtr("Jane Smith"),
+			wantEmployerID: OptionalID{},
+		},
+		{
+			name:           "Employer null",
+			body:           `{"employer_id": null}`,
+			wantEmployerID: OptionalID{Set: true},
+		},
+		{
+			name:           "Employer set",
+			body:           `{"employer_id": 3}`,
+			wantEmployerID: OptionalID{Set: true, Value: uintPtr(3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got CustomerUpdate
+			if err := json.Unmarshal([]byte(tt.body), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			if (got.Name == nil) != (tt.wantName == nil) || (got.Name != nil && *got.Name != *tt.wantName) {
+				t.Errorf("Name = %v, want %v", got.Name, tt.wantName)
+			}
+			if got.EmployerID.Set != tt.wantEmployerID.Set {
+				t.Errorf("EmployerID.Set = %v, want %v", got.EmployerID.Set, tt.wantEmployerID.Set)
+			}
+			if (got.EmployerID.Value == nil) != (tt.wantEmployerID.Value == nil) ||
+				(got.EmployerID.Value != nil && *got.EmployerID.Value != *tt.wantEmployerID.Value) {
+				t.Errorf("EmployerID.Value = %v, want %v", got.EmployerID.Value, tt.wantEmployerID.Value)
+			}
+		})
+	}
+}
+
+func TestCustomerFilter_Matches(t *testing.T) {
+	retail := &Customer{ID: 1}
+	employed := &Customer{ID: 2, EmployerID: uintPtr(1)}
+
+	tests := []struct {
+		name     string
+		filter   CustomerFilter
+		customer *Customer
+		want     bool
+	}{
+		{name: "No filter matches retail", filter: CustomerFilter{}, customer: retail, want: true},
+		{name: "No filter matches employed", filter: CustomerFilter{}, customer: employed, want: true},
+		{name: "Retail only excludes employed", filter: CustomerFilter{RetailOnly: true}, customer: employed, want: false},
+		{name: "Retail only matches retail", filter: CustomerFilter{RetailOnly: true}, customer: retail, want: true},
+		{name: "Employer matches its employee", filter: CustomerFilter{EmployerID: uintPtr(1)}, customer: employed, want: true},
+		{name: "Employer excludes other employers", filter: CustomerFilter{EmployerID: uintPtr(2)}, customer: employed, want: false},
+		{name: "Employer excludes retail", filter: CustomerFilter{EmployerID: uintPtr(1)}, customer: retail, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.customer); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Helper function to create a pointer to uint
+func uintPtr(n uint) *uint {
+	return &n
+}
+
+// Helper function to create a pointer to string
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// dateLayout is the ISO 8601 layout dates are written in
+const dateLayout = "2006-01-02"
+
+// timeOfDayLayout is the 24 hour layout times of day are written in
+const timeOfDayLayout = "15:04"
+
+// ErrInvalidDate is returned when a date cannot be parsed
+var ErrInvalidDate = errors.New("invalid date")
+
+// Date is a calendar date without a time of day, e.g. a fund valuation date. It is held as midnight UTC
+// so dates can be compared with the time.Time methods.
+type Date struct {
+	time.Time
+}
+
+// NewDate creates a date from its year, month and day
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf returns the calendar date of t in t's location
+func DateOf(t time.Time) Date {
+	return NewDate(t.Date())
+}
+
+// Today returns the current date in UTC
+func Today() Date {
+	return DateOf(time.Now().UTC())
+}
+
+// ParseDate parses a date written as YYYY-MM-DD
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("%w: %q must be written as YYYY-MM-DD", ErrInvalidDate, s)
+	}
+	return Date{t}, nil
+}
+
+// AddDays returns the date days after d, or before it when days is negative
+func (d Date) AddDays(days int) Date {
+	return Date{d.Time.AddDate(0, 0, days)}
+}
+
+// AddMonths returns the date months after d, or before it when months is negative, on the same day of the month
+// or on the month's last day when it is shorter
+func (d Date) AddMonths(months int) Date {
+	first := NewDate(d.Year(), d.Month(), 1).AddDate(0, months, 0)
+	return NewDate(first.Year(), first.Month(), min(d.Day(), daysIn(first.Year(), first.Month())))
+}
+
+// daysIn returns the number of days in a month
+func daysIn(year int, month time.Month) int {
+	return NewDate(year, month+1, 0).Day()
+}
+
+// IsWeekend reports whether d is a Saturday or a Sunday
+func (d Date) IsWeekend() bool {
+	return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+}
+
+// String returns the date as YYYY-MM-DD
+func (d Date) String() string {
+	return d.Format(dateLayout)
+}
+
+// MarshalJSON encodes the date as a "YYYY-MM-DD" string
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a date from a "YYYY-MM-DD" string
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: must be a string written as YYYY-MM-DD", ErrInvalidDate)
+	}
+
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+// TimeOfDay is a time on the clock in UTC, e.g. a dealing cut-off, like dates are held in UTC
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+}
+
+// ParseTimeOfDay parses a time of day written as HH:MM on the 24 hour clock
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	t, err := time.Parse(timeOfDayLayout, s)
+	if err != nil {
+		return TimeOfDay{}, fmt.Errorf("invalid time of day %q: must be written as HH:MM", s)
+	}
+	return TimeOfDay{Hour: t.Hour(), Minute: t.Minute()}, nil
+}
+
+// On returns the instant this time of day falls on date d
+func (t TimeOfDay) On(d Date) time.Time {
+	return d.Time.Add(time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute)
+}
+
+// String returns the time of day as HH:MM
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Date
+		wantErr bool
+	}{
+		{input: "2026-10-16", want: NewDate(2026, time.October, 16)},
+		{input: "2024-02-29", want: NewDate(2024, time.February, 29)},
+		{input: "2025-02-29", wantErr: true},
+		{input: "16/10/2026", wantErr: true},
+		{input: "2026-10-16T00:00:00Z", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDate(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidDate) {
+					t.Errorf("ParseDate() error = %v, want ErrInvalidDate", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDate() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateOf(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	// Just after midnight in London is still the previous day in UTC
+	got := DateOf(time.Date(2026, time.June, 2, 0, 30, 0, 0, london))
+	if want := NewDate(2026, time.June, 2); got != want {
+		t.Errorf("DateOf() = %v, want %v", got, want)
+	}
+}
+
+func TestDate_JSON(t *testing.T) {
+	data, err := json.Marshal(NewDate(2026, time.October, 16))
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error = %v", err)
+	}
+	if string(data) != `"2026-10-16"` {
+		t.Errorf("Marshal() = %s, want \"2026-10-16\"", data)
+	}
+
+	var d Date
+	if err := json.Unmarshal([]byte(`"2026-10-16"`), &d); err != nil || d != NewDate(2026, time.October, 16) {
+		t.Errorf("Unmarshal() = %v, %v", d, err)
+	}
+	if err := json.Unmarshal([]byte(`20261016`), &d); !errors.Is(err, ErrInvalidDate) {
+		t.Errorf("Unmarshal() of a number error = %v, want ErrInvalidDate", err)
+	}
+}
+
+func TestDate_IsWeekend(t *testing.T) {
+	tests := []struct {
+		date Date
+		want bool
+	}{
+		{date: NewDate(2026, time.October, 16), want: false},
+		{date: NewDate(2026, time.October, 17), want: true},
+		{date: NewDate(2026, time.October, 18), want: true},
+		{date: NewDate(2026, time.October, 19), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date.String(), func(t *testing.T) {
+			if got := tt.date.IsWeekend(); got != tt.want {
+				t.Errorf("IsWeekend() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    TimeOfDay
+		wantErr bool
+	}{
+		{input: "12:00", want: TimeOfDay{Hour: 12}},
+		{input: "09:30", want: TimeOfDay{Hour: 9, Minute: 30}},
+		{input: "23:59", want: TimeOfDay{Hour: 23, Minute: 59}},
+		{input: "24:00", wantErr: true},
+		{input: "12", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTimeOfDay(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTimeOfDay() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTimeOfDay() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTimeOfDay() = %v, want %v", got, tt.want)
+			}
+			if got.String() != tt.input {
+				t.Errorf("String() = %v, want %v", got.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestTimeOfDay_On(t *testing.T) {
+	got := TimeOfDay{Hour: 12, Minute: 30}.On(NewDate(2026, time.October, 16))
+	if want := time.Date(2026, time.October, 16, 12, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("On() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// DealingBatch is the single net order placed with a fund manager for one fund on one dealing day. It
+// aggregates every pending order in the fund received before the day's cut-off, all of which are dealt at
+// the fund's price for that day.
+type DealingBatch struct {
+	ID          uint  `json:"id"`
+	FundID      uint  `json:"fund_id"`
+	DealingDate Date  `json:"dealing_date"`
+	Price       Price `json:"price"`
+	// Bought is the amount invested in the fund and Sold the amount taken out of it, both positive
+	Bought Money `json:"bought"`
+	Sold   Money `json:"sold"`
+	// UnitsBought and UnitsSold are the units the batch buys and sells, both positive
+	UnitsBought Units `json:"units_bought"`
+	UnitsSold   Units `json:"units_sold"`
+	// InvestmentIDs are the customer transactions the batch was made of, in ID order
+	InvestmentIDs []uint    `json:"investment_ids"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Net returns the amount the batch invests in the fund, negative when more is sold than bought
+func (b *DealingBatch) Net() (Money, error) {
+	return b.Bought.Sub(b.Sold)
+}
+
+// NetUnits returns the units the batch buys, negative when more are sold than bought
+func (b *DealingBatch) NetUnits() Units {
+	return b.UnitsBought - b.UnitsSold
+}
+
+// DealingBatchFilter restricts which batches are listed. The zero value matches every batch.
+type DealingBatchFilter struct {
+	// DealingDate only matches batches dealt on this date
+	DealingDate *Date
+	// FundID only matches batches for this fund
+	FundID *uint
+}
+
+// Matches reports whether a batch satisfies the filter
+func (f DealingBatchFilter) Matches(batch *DealingBatch) bool {
+	if f.DealingDate != nil && !batch.DealingDate.Equal(f.DealingDate.Time) {
+		return false
+	}
+	if f.FundID != nil && batch.FundID != *f.FundID {
+		return false
+	}
+	return true
+}
+
+// DealingRunCreate represents a request to deal the pending orders for a dealing day
+type DealingRunCreate struct {
+	DealingDate Date `json:"dealing_date"`
+}
+
+// DealingBatchResponse represents the dealing batch data that will be sent in API responses
+type DealingBatchResponse struct {
+	ID            uint      `json:"id"`
+	FundID        uint      `json:"fund_id"`
+	DealingDate   Date      `json:"dealing_date"`
+	Price         Price     `json:"price"`
+	Bought        Money     `json:"bought"`
+	Sold          Money     `json:"sold"`
+	Net           Money     `json:"net"`
+	UnitsBought   Units     `json:"units_bought"`
+	UnitsSold     Units     `json:"units_sold"`
+	NetUnits      Units     `json:"net_units"`
+	InvestmentIDs []uint    `json:"investment_ids"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// errInvalidDecimal is returned when a string is not a plain decimal number
+var errInvalidDecimal = errors.New("invalid decimal number")
+
+// parseDecimal parses a plain decimal string (e.g. "-1234.56") into an integer scaled by 10^scale.
+// Exponents, leading plus signs and more than scale fractional digits are rejected so no precision is lost.
+func parseDecimal(s string, scale int) (int64, error) {
+	negative := false
+	if strings.HasPrefix(s, "-") {
+		negative = true
+		s = s[1:]
+	}
+
+	intPart, fracPart, hasPoint := strings.Cut(s, ".")
+	if intPart == "" || (hasPoint && fracPart == "") {
+		return 0, errInvalidDecimal
+	}
+	if len(fracPart) > scale {
+		return 0, fmt.Errorf("at most %d decimal places are allowed", scale)
+	}
+
+	var value int64
+	for _, digits := range []string{intPart, fracPart + strings.Repeat("0", scale-len(fracPart))} {
+		for _, c := range digits {
+			if c < '0' || c > '9' {
+				return 0, errInvalidDecimal
+			}
+			if value > (math.MaxInt64-int64(c-'0'))/10 {
+				return 0, errors.New("decimal number out of range")
+			}
+			value = value*10 + int64(c-'0')
+		}
+	}
+
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// formatDecimal formats an integer scaled by 10^scale as a plain decimal string with exactly scale fractional digits
+func formatDecimal(value int64, scale int) string {
+	sign := ""
+	magnitude := uint64(value)
+	if value < 0 {
+		sign = "-"
+		magnitude = uint64(-value)
+	}
+
+	digits := fmt.Sprintf("%0*d", scale+1, magnitude)
+	if scale == 0 {
+		return sign + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// unquoteDecimal returns the decimal text of a JSON number or JSON string holding a number
+func unquoteDecimal(data []byte) (string, error) {
+	s := strings.TrimSpace(string(data))
+	if strings.HasPrefix(s, `"`) {
+		if len(s) < 2 || !strings.HasSuffix(s, `"`) {
+			return "", errInvalidDecimal
+		}
+		s = s[1 : len(s)-1]
+	}
+	if s == "" || s == "null" {
+		return "", errInvalidDecimal
+	}
+	return s, nil
+}
@@ -1,9 +1,20 @@
 package model
 
+import "time"
+
 // Employer represents an employer in the system
 type Employer struct {
 	ID   uint   `json:"id"`
 	Name string `json:"name"`
+	// Active is false once the employer has left the scheme. Deactivated employers are kept
+	// so their employees' history stays intact, but no new employees can be enrolled under them.
+	Active bool `json:"active"`
+	// ContributionScheme is how the employer funds its employees' pensions, nil until it has been set up
+	ContributionScheme *ContributionScheme `json:"contribution_scheme"`
+	// DefaultFundID is the fund employees' contributions go into until they choose a target allocation
+	DefaultFundID *uint     `json:"default_fund_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 // EmployerCreate represents the data needed to create a new employer
@@ -11,8 +22,31 @@ type EmployerCreate struct {
 	Name string `json:"name"`
 }
 
+// EmployerUpdate represents a partial update of an employer. Fields that are absent are left unchanged.
+type EmployerUpdate struct {
+	Name               *string             `json:"name"`
+	ContributionScheme *ContributionScheme `json:"contribution_scheme"`
+	DefaultFundID      *uint               `json:"default_fund_id"`
+}
+
+// EmployerFilter restricts which employers are listed. The zero value matches every employer.
+type EmployerFilter struct {
+	// ActiveOnly only matches employers that haven't been deactivated
+	ActiveOnly bool
+}
+
+// Matches reports whether an employer satisfies the filter
+func (f EmployerFilter) Matches(employer *Employer) bool {
+	return !f.ActiveOnly || employer.Active
+}
+
 // EmployerResponse represents the employer data that will be sent in API responses
 type EmployerResponse struct {
-	ID   uint   `json:"id"`
-	Name string `json:"name"`
+	ID                 uint                `json:"id"`
+	Name               string              `json:"name"`
+	Active             bool                `json:"active"`
+	ContributionScheme *ContributionScheme `json:"contribution_scheme,omitempty"`
+	DefaultFundID      *uint               `json:"default_fund_id,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
 }
@@ -1,18 +1,226 @@
 package model
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FundStatus is the lifecycle stage of a fund
+type FundStatus string
+
+const (
+	// FundStatusOpen funds accept new investments
+	FundStatusOpen FundStatus = "open"
+	// FundStatusClosed funds keep their existing holdings but are closed to new money. They can be reopened.
+	FundStatusClosed FundStatus = "closed"
+	// FundStatusRetired funds have been wound up. Retirement is permanent.
+	FundStatusRetired FundStatus = "retired"
+)
+
+// Valid reports whether s is a known fund status
+func (s FundStatus) Valid() bool {
+	switch s {
+	case FundStatusOpen, FundStatusClosed, FundStatusRetired:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a fund in status s can be moved to status next.
+// Open and closed funds can move between each other or be retired, retired funds can't change.
+func (s FundStatus) CanTransitionTo(next FundStatus) bool {
+	if !next.Valid() {
+		return false
+	}
+	if s == next {
+		return true
+	}
+	return s == FundStatusOpen || s == FundStatusClosed
+}
+
+// AssetClass is the broad type of asset a fund invests in
+type AssetClass string
+
+const (
+	AssetClassEquity     AssetClass = "equity"
+	AssetClassBond       AssetClass = "bond"
+	AssetClassMultiAsset AssetClass = "multi_asset"
+	AssetClassProperty   AssetClass = "property"
+	AssetClassCash       AssetClass = "cash"
+)
+
+// Valid reports whether a is a known asset class
+func (a AssetClass) Valid() bool {
+	switch a {
+	case AssetClassEquity, AssetClassBond, AssetClassMultiAsset, AssetClassProperty, AssetClassCash:
+		return true
+	}
+	return false
+}
+
+// Risk ratings follow the synthetic risk and reward indicator (SRRI) scale from 1 (lowest) to 7 (highest)
+const (
+	MinRiskRating = 1
+	MaxRiskRating = 7
+)
+
 // Fund represents an investment fund
 type Fund struct {
 	ID   uint   `json:"id"`
 	Name string `json:"name"`
+	// ISIN is the International Securities Identification Number of the fund's share class
+	ISIN       string     `json:"isin"`
+	AssetClass AssetClass `json:"asset_class"`
+	// RiskRating is the fund's SRRI risk rating, from 1 to 7
+	RiskRating int `json:"risk_rating"`
+	// OngoingCharges is the ongoing charges figure (OCF), the yearly cost of the fund as a percentage of the amount invested
+	OngoingCharges Percent `json:"ongoing_charges"`
+	// Currency is the ISO 4217 currency the fund is priced in
+	Currency    string     `json:"currency"`
+	Description string     `json:"description"`
+	Status      FundStatus `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 // FundCreate represents the data needed to create a new fund
 type FundCreate struct {
-	Name string `json:"name"`
+	Name           string     `json:"name"`
+	ISIN           string     `json:"isin"`
+	AssetClass     AssetClass `json:"asset_class"`
+	RiskRating     int        `json:"risk_rating"`
+	OngoingCharges Percent    `json:"ongoing_charges"`
+	Currency       string     `json:"currency"`
+	Description    string     `json:"description"`
+}
+
+// FundUpdate represents a partial update of a fund. Fields that are absent are left unchanged.
+// The ISIN and currency identify the share class and can't be changed.
+type FundUpdate struct {
+	Name           *string     `json:"name"`
+	Status         *FundStatus `json:"status"`
+	AssetClass     *AssetClass `json:"asset_class"`
+	RiskRating     *int        `json:"risk_rating"`
+	OngoingCharges *Percent    `json:"ongoing_charges"`
+	Description    *string     `json:"description"`
+}
+
+// FundFilter restricts which funds are listed. The zero value matches every fund.
+type FundFilter struct {
+	Status     *FundStatus
+	AssetClass *AssetClass
+	// MinRisk and MaxRisk bound the risk rating, 0 means unbounded
+	MinRisk int
+	MaxRisk int
+	// MaxOngoingCharges only matches funds at most this expensive
+	MaxOngoingCharges *Percent
+	Currency          string
+}
+
+// Matches reports whether a fund satisfies the filter
+func (f FundFilter) Matches(fund *Fund) bool {
+	if f.Status != nil && fund.Status != *f.Status {
+		return false
+	}
+	if f.AssetClass != nil && fund.AssetClass != *f.AssetClass {
+		return false
+	}
+	if f.MinRisk != 0 && fund.RiskRating < f.MinRisk {
+		return false
+	}
+	if f.MaxRisk != 0 && fund.RiskRating > f.MaxRisk {
+		return false
+	}
+	if f.MaxOngoingCharges != nil && fund.OngoingCharges > *f.MaxOngoingCharges {
+		return false
+	}
+	if f.Currency != "" && fund.Currency != f.Currency {
+		return false
+	}
+	return true
+}
+
+// FundSortField is a field funds can be sorted by
+type FundSortField string
+
+const (
+	FundSortByID             FundSortField = "id"
+	FundSortByName           FundSortField = "name"
+	FundSortByRiskRating     FundSortField = "risk_rating"
+	FundSortByOngoingCharges FundSortField = "ongoing_charges"
+)
+
+// FundOrder is the order funds are listed in
+type FundOrder struct {
+	Field      FundSortField
+	Descending bool
+}
+
+// ParseFundOrder parses a sort field name, optionally prefixed with "-" for descending order, e.g. "-risk_rating".
+// An empty string sorts by ID.
+func ParseFundOrder(s string) (FundOrder, error) {
+	order := FundOrder{Field: FundSortByID}
+	if s == "" {
+		return order, nil
+	}
+
+	if strings.HasPrefix(s, "-") {
+		order.Descending = true
+		s = s[1:]
+	}
+	order.Field = FundSortField(s)
+
+	switch order.Field {
+	case FundSortByID, FundSortByName, FundSortByRiskRating, FundSortByOngoingCharges:
+		return order, nil
+	}
+	return FundOrder{}, fmt.Errorf("funds can't be sorted by %q", s)
+}
+
+// SortFunds sorts funds in place. Funds that compare equal are kept in ID order.
+func SortFunds(funds []*Fund, order FundOrder) {
+	compare := func(a, b *Fund) int {
+		switch order.Field {
+		case FundSortByName:
+			return strings.Compare(a.Name, b.Name)
+		case FundSortByRiskRating:
+			return a.RiskRating - b.RiskRating
+		case FundSortByOngoingCharges:
+			switch {
+			case a.OngoingCharges < b.OngoingCharges:
+				return -1
+			case a.OngoingCharges > b.OngoingCharges:
+				return 1
+			}
+		}
+		return 0
+	}
+
+	sort.SliceStable(funds, func(i, j int) bool {
+		cmp := compare(funds[i], funds[j])
+		if order.Descending {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+		return funds[i].ID < funds[j].ID
+	})
 }
 
 // FundResponse represents the fund data that will be sent in API responses
 type FundResponse struct {
-	ID   uint   `json:"id"`
-	Name string `json:"name"`
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	ISIN           string     `json:"isin"`
+	AssetClass     AssetClass `json:"asset_class"`
+	RiskRating     int        `json:"risk_rating"`
+	OngoingCharges Percent    `json:"ongoing_charges"`
+	Currency       string     `json:"currency"`
+	Description    string     `json:"description"`
+	Status         FundStatus `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// FundPrice is a fund's net asset value (NAV) per unit on a valuation date. A fund has at most one price per date.
+type FundPrice struct {
+	FundID    uint      `json:"fund_id"`
+	Date      Date      `json:"date"`
+	NAV       Price     `json:"nav"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FundPriceCreate represents the data needed to record a fund price. The currency is the fund's currency.
+type FundPriceCreate struct {
+	Date Date  `json:"date"`
+	NAV  Price `json:"nav"`
+}
+
+// FundPriceFilter restricts which prices are listed. The zero value matches every price.
+type FundPriceFilter struct {
+	// From only matches prices on or after this date
+	From *Date
+	// To only matches prices on or before this date
+	To *Date
+}
+
+// Matches reports whether a price satisfies the filter
+func (f FundPriceFilter) Matches(price *FundPrice) bool {
+	if f.From != nil && price.Date.Before(f.From.Time) {
+		return false
+	}
+	if f.To != nil && price.Date.After(f.To.Time) {
+		return false
+	}
+	return true
+}
+
+// FundPriceResponse represents the fund price data that will be sent in API responses
+type FundPriceResponse struct {
+	FundID    uint      `json:"fund_id"`
+	Date      Date      `json:"date"`
+	NAV       Price     `json:"nav"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,96 @@
+package model
+
+import "testing"
+
+func TestFundStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from FundStatus
+		to   FundStatus
+		want bool
+	}{
+		{from: FundStatusOpen, to: FundStatusClosed, want: true},
+		{from: FundStatusOpen, to: FundStatusRetired, want: true},
+		{from: FundStatusOpen, to: FundStatusOpen, want: true},
+		{from: FundStatusClosed, to: FundStatusOpen, want: true},
+		{from: FundStatusClosed, to: FundStatusRetired, want: true},
+		{from: FundStatusRetired, to: FundStatusRetired, want: true},
+		{from: FundStatusRetired, to: FundStatusOpen, want: false},
+		{from: FundStatusRetired, to: FundStatusClosed, want: false},
+		{from: FundStatusOpen, to: "suspended", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+				t.Errorf("CanTransitionTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFundFilter_Matches(t *testing.T) {
+	fund := &Fund{
+		ID:             1,
+		AssetClass:     AssetClassEquity,
+		RiskRating:     5,
+		OngoingCharges: 200,
+		Currency:       "GBP",
+		Status:         FundStatusOpen,
+	}
+	open, closed := FundStatusOpen, FundStatusClosed
+	equity, bond := AssetClassEquity, AssetClassBond
+	cheap, expensive := Percent(100), Percent(200)
+
+	tests := []struct {
+		name   string
+		filter FundFilter
+		want   bool
+	}{
+		{name: "Empty filter", filter: FundFilter{}, want: true},
+		{name: "Matching status", filter: FundFilter{Status: &open}, want: true},
+		{name: "Other status", filter: FundFilter{Status: &closed}, want: false},
+		{name: "Matching asset class", filter: FundFilter{AssetClass: &equity}, want: true},
+		{name: "Other asset class", filter: FundFilter{AssetClass: &bond}, want: false},
+		{name: "Risk within range", filter: FundFilter{MinRisk: 5, MaxRisk: 5}, want: true},
+		{name: "Risk above maximum", filter: FundFilter{MaxRisk: 4}, want: false},
+		{name: "Risk below minimum", filter: FundFilter{MinRisk: 6}, want: false},
+		{name: "Charges at maximum", filter: FundFilter{MaxOngoingCharges: &expensive}, want: true},
+		{name: "Charges above maximum", filter: FundFilter{MaxOngoingCharges: &cheap}, want: false},
+		{name: "Other currency", filter: FundFilter{Currency: "USD"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(fund); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFundOrder(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    FundOrder
+		wantErr bool
+	}{
+		{input: "", want: FundOrder{Field: FundSortByID}},
+		{input: "name", want: FundOrder{Field: FundSortByName}},
+		{input: "-risk_rating", want: FundOrder{Field: FundSortByRiskRating, Descending: true}},
+		{input: "ongoing_charges", want: FundOrder{Field: FundSortByOngoingCharges}},
+		{input: "isin", wantErr: true},
+		{input: "-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFundOrder(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFundOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFundOrder() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// The earliest and latest ages customers can plan to retire at
+const (
+	MinRetirementAge = 55
+	MaxRetirementAge = 75
+)
+
+// RetirementPlan is when a customer plans to retire and, once they have opted into lifestyling, the glide path
+// their target allocation follows as retirement approaches
+type RetirementPlan struct {
+	DateOfBirth   *Date `json:"date_of_birth"`
+	RetirementAge *int  `json:"retirement_age"`
+	// GlidePathID is the glide path the customer follows, nil when they haven't opted in
+	GlidePathID *uint `json:"glide_path_id"`
+}
+
+// YearsToRetirement returns the number of whole years from today until the customer reaches their retirement
+// age, negative once they have passed it. It returns false when their date of birth or retirement age is missing.
+func (p RetirementPlan) YearsToRetirement(today Date) (int, bool) {
+	if p.DateOfBirth == nil || p.RetirementAge == nil {
+		return 0, false
+	}
+	retirement := p.DateOfBirth.AddDate(*p.RetirementAge, 0, 0)
+	years := retirement.Year() - today.Year()
+	if today.Month() > retirement.Month() || today.Month() == retirement.Month() && today.Day() > retirement.Day() {
+		// This year's anniversary has passed
+		years--
+	}
+	return years, true
+}
+
+// GlidePathStage is the allocation a glide path moves customers into a number of years before their retirement
+type GlidePathStage struct {
+	YearsToRetirement int              `json:"years_to_retirement"`
+	Funds             []FundAllocation `json:"funds"`
+}
+
+// GlidePath moves customers' target allocations from growth funds into lower-risk funds as their retirement
+// approaches. Its stages are ordered from the furthest from retirement to the nearest.
+type GlidePath struct {
+	ID        uint
+	Name      string
+	Stages    []GlidePathStage
+	CreatedAt time.Time
+}
+
+// SortStages orders the stages from the furthest from retirement to the nearest
+func (g *GlidePath) SortStages() {
+	sort.SliceStable(g.Stages, func(i, j int) bool { return g.Stages[i].YearsToRetirement > g.Stages[j].YearsToRetirement })
+}
+
+// StageFor returns the stage customers are in yearsToRetirement years before retirement: the nearest stage that
+// has started. Customers further from retirement than every stage are in the first one, and customers past
+// their retirement age stay in the last.
+func (g *GlidePath) StageFor(yearsToRetirement int) GlidePathStage {
+	stage := g.Stages[0]
+	for _, next := range g.Stages[1:] {
+		if yearsToRetirement > next.YearsToRetirement {
+			break
+		}
+		stage = next
+	}
+	return stage
+}
+
+// GlidePathCreate represents the data needed to create a glide path
+type GlidePathCreate struct {
+	Name   string           `json:"name"`
+	Stages []GlidePathStage `json:"stages"`
+}
+
+// GlidePathResponse represents a glide path as sent in API responses
+type GlidePathResponse struct {
+	ID        uint             `json:"id"`
+	Name      string           `json:"name"`
+	Stages    []GlidePathStage `json:"stages"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// GlidePathMove is a customer's target allocation moving to the next stage of their glide path
+type GlidePathMove struct {
+	CustomerID        uint
+	GlidePathID       uint
+	YearsToRetirement int
+	Allocation        *TargetAllocation
+	// Rebalance is the rebalance made into the new allocation
+	Rebalance *Rebalance
+}
+
+// GlidePathFailure reports why a customer couldn't be moved along their glide path
+type GlidePathFailure struct {
+	CustomerID uint   `json:"customer_id"`
+	Error      string `json:"error"`
+}
+
+// GlidePathRun is the outcome of moving every customer who has opted into lifestyling along their glide path
+type GlidePathRun struct {
+	// Moved are the customers whose target allocation changed
+	Moved  []*GlidePathMove
+	Failed []GlidePathFailure
+}
+
+// GlidePathMoveResponse represents a glide path move as sent in API responses
+type GlidePathMoveResponse struct {
+	CustomerID        uint              `json:"customer_id"`
+	GlidePathID       uint              `json:"glide_path_id"`
+	YearsToRetirement int               `json:"years_to_retirement"`
+	Funds             []FundAllocation  `json:"funds"`
+	Rebalance         RebalanceResponse `json:"rebalance"`
+}
+
+// GlidePathRunResponse represents a glide path run as sent in API responses
+type GlidePathRunResponse struct {
+	Moved  []GlidePathMoveResponse `json:"moved"`
+	Failed []GlidePathFailure      `json:"failed"`
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetirementPlan_YearsToRetirement(t *testing.T) {
+	dateOfBirth := NewDate(1970, time.June, 15)
+	retirementAge := 67
+
+	tests := []struct {
+		name      string
+		plan      RetirementPlan
+		today     Date
+		wantYears int
+		wantOK    bool
+	}{
+		{
+			name:      "Before this year's birthday",
+			plan:      RetirementPlan{DateOfBirth: &dateOfBirth, RetirementAge: &retirementAge},
+			today:     NewDate(2026, time.June, 14),
+			wantYears: 11,
+			wantOK:    true,
+		},
+		{
+			name:      "On this year's birthday",
+			plan:      RetirementPlan{DateOfBirth: &dateOfBirth, RetirementAge: &retirementAge},
+			today:     NewDate(2026, time.June, 15),
+			wantYears: 11,
+			wantOK:    true,
+		},
+		{
+			name:      "After this year's birthday",
+			plan:      RetirementPlan{DateOfBirth: &dateOfBirth, RetirementAge: &retirementAge},
+			today:     NewDate(2026, time.June, 16),
+			wantYears: 10,
+			wantOK:    true,
+		},
+		{
+			name:      "Past retirement",
+			plan:      RetirementPlan{DateOfBirth: &dateOfBirth, RetirementAge: &retirementAge},
+			today:     NewDate(2038, time.January, 1),
+			wantYears: -1,
+			wantOK:    true,
+		},
+		{
+			name:  "No retirement age",
+			plan:  RetirementPlan{DateOfBirth: &dateOfBirth},
+			today: NewDate(2026, time.June, 15),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			years, ok := tt.plan.YearsToRetirement(tt.today)
+			if years != tt.wantYears || ok != tt.wantOK {
+				t.Errorf("YearsToRetirement() = %d, %v, want %d, %v", years, ok, tt.wantYears, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGlidePath_StageFor(t *testing.T) {
+	glidePath := &GlidePath{Stages: []GlidePathStage{
+		{YearsToRetirement: 0, Funds: []FundAllocation{{FundID: 3, Percentage: OneHundredPercent}}},
+		{YearsToRetirement: 10, Funds: []FundAllocation{{FundID: 1, Percentage: OneHundredPercent}}},
+		{YearsToRetirement: 5, Funds: []FundAllocation{{FundID: 2, Percentage: OneHundredPercent}}},
+	}}
+	glidePath.SortStages()
+
+	tests := []struct {
+		name              string
+		yearsToRetirement int
+		wantFundID        uint
+	}{
+		{name: "Further out than every stage", yearsToRetirement: 30, wantFundID: 1},
+		{name: "First stage starts", yearsToRetirement: 10, wantFundID: 1},
+		{name: "Between stages", yearsToRetirement: 7, wantFundID: 1},
+		{name: "Second stage starts", yearsToRetirement: 5, wantFundID: 2},
+		{name: "At retirement", yearsToRetirement: 0, wantFundID: 3},
+		{name: "Past retirement", yearsToRetirement: -2, wantFundID: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := glidePath.StageFor(tt.yearsToRetirement); got.Funds[0].FundID != tt.wantFundID {
+				t.Errorf("StageFor(%d) = %+v, want the stage in fund %d", tt.yearsToRetirement, got, tt.wantFundID)
+			}
+		})
+	}
+}
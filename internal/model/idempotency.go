@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// IdempotencyRecord is the first request an API key made with an idempotency key and, once it has been handled,
+// the response replayed to retries of that request. Records expire at ExpiresAt, after which the key can be reused.
+type IdempotencyRecord struct {
+	APIKey string
+	Key    string
+	// RequestHash fingerprints the method, path and body of the first request so reuse with a different request is spotted
+	RequestHash string
+	// Response is nil while the first request is still being handled
+	Response  *IdempotentResponse
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// IdempotentResponse is the response stored for an idempotency key
+type IdempotentResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
@@ -2,27 +2,155 @@ package model
 
 import "time"
 
-// Investment represents an investment in the system
+// TransactionType tells apart money going into a fund from money coming out of it
+type TransactionType string
+
+const (
+	// TransactionTypeInvestment buys units with the amount invested
+	TransactionTypeInvestment TransactionType = "investment"
+	// TransactionTypeWithdrawal sells units. Its amount and units are negative.
+	TransactionTypeWithdrawal TransactionType = "withdrawal"
+	// TransactionTypeSwitchOut sells units of the fund a switch moves out of. Its amount and units are negative.
+	TransactionTypeSwitchOut TransactionType = "switch_out"
+	// TransactionTypeSwitchIn buys units of the fund a switch moves into with the proceeds of its switch out
+	TransactionTypeSwitchIn TransactionType = "switch_in"
+)
+
+// InvestmentStatus is the settlement stage of a transaction
+type InvestmentStatus string
+
+const (
+	// InvestmentStatusPending transactions are waiting to be dealt and can still be cancelled
+	InvestmentStatusPending InvestmentStatus = "pending"
+	// InvestmentStatusPlaced transactions have been sent to the fund manager to be dealt
+	InvestmentStatusPlaced InvestmentStatus = "placed"
+	// InvestmentStatusSettled transactions have been dealt and their units are owned by the customer
+	InvestmentStatusSettled InvestmentStatus = "settled"
+	// InvestmentStatusFailed transactions were placed but couldn't be dealt
+	InvestmentStatusFailed InvestmentStatus = "failed"
+	// InvestmentStatusCancelled transactions were withdrawn before being placed
+	InvestmentStatusCancelled InvestmentStatus = "cancelled"
+)
+
+// Valid reports whether s is a known investment status
+func (s InvestmentStatus) Valid() bool {
+	switch s {
+	case InvestmentStatusPending, InvestmentStatusPlaced, InvestmentStatusSettled, InvestmentStatusFailed, InvestmentStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a transaction in status s can be moved to status next.
+// Pending transactions are placed or cancelled, placed ones settle or fail. Settled, failed and cancelled are final.
+func (s InvestmentStatus) CanTransitionTo(next InvestmentStatus) bool {
+	switch s {
+	case InvestmentStatusPending:
+		return next == InvestmentStatusPlaced || next == InvestmentStatusCancelled
+	case InvestmentStatusPlaced:
+		return next == InvestmentStatusSettled || next == InvestmentStatusFailed
+	}
+	return false
+}
+
+// HoldsUnits reports whether transactions in status s count towards a customer's holdings.
+// Failed and cancelled transactions never moved any units.
+func (s InvestmentStatus) HoldsUnits() bool {
+	return s != InvestmentStatusFailed && s != InvestmentStatusCancelled
+}
+
+// Investment represents a transaction in a customer's fund history: an investment, or a withdrawal recorded
+// with a negative amount and negative units. The amount is converted into fund units at the fund's price on PriceDate.
+// Both legs of a switch between funds carry the switch's ID, which is 0 for any other transaction.
+// Investments record the source of the money paid in, which is empty for every other transaction, and whether
+// they were paid into the customer's ISA. Regular contributions also carry the ID of the contribution schedule and
+// the date of the run they pay in.
+// Transactions are created pending and move through the statuses described by InvestmentStatus. Once dealt,
+// a transaction carries the ID of the dealing batch it was placed in and is repriced at the dealing day's price.
 type Investment struct {
-	ID        uint      `json:"id"`
-	ClientID  uint      `json:"client_id"`
-	FundID    uint      `json:"fund_id"`
-	Amount    float32   `json:"amount"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uint               `json:"id"`
+	ClientID   uint               `json:"client_id"`
+	FundID     uint               `json:"fund_id"`
+	Type       TransactionType    `json:"type"`
+	Status     InvestmentStatus   `json:"status"`
+	SwitchID   uint               `json:"switch_id,omitempty"`
+	BatchID    uint               `json:"batch_id,omitempty"`
+	Source     ContributionSource `json:"source,omitempty"`
+	ISA        bool               `json:"isa,omitempty"`
+	ScheduleID uint               `json:"schedule_id,omitempty"`
+	RunDate    *Date              `json:"run_date,omitempty"`
+	Amount     Money              `json:"amount"`
+	Units      Units              `json:"units"`
+	Price      Price              `json:"price"`
+	PriceDate  Date               `json:"price_date"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
 }
 
-// InvestmentCreate represents the data needed to create a new investment
+// InvestmentCreate represents the data needed to create a new investment. The source defaults to a one-off
+// contribution when it is empty.
 type InvestmentCreate struct {
-	ClientID uint    `json:"client_id" validate:"required"`
-	FundID   uint    `json:"fund_id" validate:"required"`
-	Amount   float64 `json:"amount"`
+	ClientID uint               `json:"client_id" validate:"required"`
+	FundID   uint               `json:"fund_id" validate:"required"`
+	Amount   Money              `json:"amount"`
+	Source   ContributionSource `json:"source"`
+	// Run is the schedule run a regular contribution pays in. It is only set by the scheduler.
+	Run *ScheduleRunKey `json:"-"`
+}
+
+// InvestmentUpdate represents a status change of a transaction
+type InvestmentUpdate struct {
+	Status InvestmentStatus `json:"status"`
+}
+
+// InvestmentFilter restricts which transactions are listed. The zero value matches every transaction.
+type InvestmentFilter struct {
+	// ClientID only matches transactions of this customer
+	ClientID *uint
+	// Status only matches transactions in this status
+	Status *InvestmentStatus
+	// Run only matches the contributions paying in this schedule run
+	Run *ScheduleRunKey
+}
+
+// Matches reports whether a transaction satisfies the filter
+func (f InvestmentFilter) Matches(investment *Investment) bool {
+	if f.ClientID != nil && investment.ClientID != *f.ClientID {
+		return false
+	}
+	if f.Status != nil && investment.Status != *f.Status {
+		return false
+	}
+	if f.Run != nil && !f.Run.Pays(investment) {
+		return false
+	}
+	return true
+}
+
+// WithdrawalCreate represents the data needed to withdraw from a customer's holding in a fund.
+// Exactly one of Amount and Units must be set.
+type WithdrawalCreate struct {
+	ClientID uint   `json:"client_id"`
+	FundID   uint   `json:"fund_id"`
+	Amount   *Money `json:"amount"`
+	Units    *Units `json:"units"`
 }
 
 // InvestmentResponse represents the investment data that will be sent in API responses
 type InvestmentResponse struct {
-	ID       uint    `json:"id"`
-	ClientID uint    `json:"client_id"`
-	FundID   uint    `json:"fund_id"`
-	Amount   float64 `json:"amount"`
+	ID         uint               `json:"id"`
+	ClientID   uint               `json:"client_id"`
+	FundID     uint               `json:"fund_id"`
+	Type       TransactionType    `json:"type"`
+	Status     InvestmentStatus   `json:"status"`
+	SwitchID   uint               `json:"switch_id,omitempty"`
+	BatchID    uint               `json:"batch_id,omitempty"`
+	Source     ContributionSource `json:"source,omitempty"`
+	ISA        bool               `json:"isa,omitempty"`
+	ScheduleID uint               `json:"schedule_id,omitempty"`
+	RunDate    *Date              `json:"run_date,omitempty"`
+	Amount     Money              `json:"amount"`
+	Units      Units              `json:"units"`
+	Price      Price              `json:"price"`
+	PriceDate  Date               `json:"price_date"`
 }
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInvestmentStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from InvestmentStatus
+		to   InvestmentStatus
+		want bool
+	}{
+		{from: InvestmentStatusPending, to: InvestmentStatusPlaced, want: true},
+		{from: InvestmentStatusPending, to: InvestmentStatusCancelled, want: true},
+		{from: InvestmentStatusPending, to: InvestmentStatusSettled, want: false},
+		{from: InvestmentStatusPending, to: InvestmentStatusFailed, want: false},
+		{from: InvestmentStatusPending, to: InvestmentStatusPending, want: false},
+		{from: InvestmentStatusPlaced, to: InvestmentStatusSettled, want: true},
+		{from: InvestmentStatusPlaced, to: InvestmentStatusFailed, want: true},
+		{from: InvestmentStatusPlaced, to: InvestmentStatusCancelled, want: false},
+		{from: InvestmentStatusPlaced, to: InvestmentStatusPending, want: false},
+		{from: InvestmentStatusSettled, to: InvestmentStatusFailed, want: false},
+		{from: InvestmentStatusFailed, to: InvestmentStatusPlaced, want: false},
+		{from: InvestmentStatusCancelled, to: InvestmentStatusPending, want: false},
+		{from: InvestmentStatusPending, to: "dealt", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+				t.Errorf("CanTransitionTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvestmentFilter_Matches(t *testing.T) {
+	runDate := NewDate(2026, time.October, 16)
+	investment := &Investment{ID: 1, ClientID: 1, Status: InvestmentStatusPending, ScheduleID: 1, RunDate: &runDate}
+	client1, client2 := uint(1), uint(2)
+	pending, settled := InvestmentStatusPending, InvestmentStatusSettled
+
+	tests := []struct {
+		name   string
+		filter InvestmentFilter
+		want   bool
+	}{
+		{name: "No filter", filter: InvestmentFilter{}, want: true},
+		{name: "Same client", filter: InvestmentFilter{ClientID: &client1}, want: true},
+		{name: "Other client", filter: InvestmentFilter{ClientID: &client2}, want: false},
+		{name: "Same status", filter: InvestmentFilter{Status: &pending}, want: true},
+		{name: "Other status", filter: InvestmentFilter{Status: &settled}, want: false},
+		{name: "Same client, other status", filter: InvestmentFilter{ClientID: &client1, Status: &settled}, want: false},
+		{name: "Same schedule run", filter: InvestmentFilter{Run: &ScheduleRunKey{ScheduleID: 1, RunDate: runDate}}, want: true},
+		{name: "Other run of the schedule", filter: InvestmentFilter{Run: &ScheduleRunKey{ScheduleID: 1, RunDate: runDate.AddDays(7)}}, want: false},
+		{name: "Other schedule", filter: InvestmentFilter{Run: &ScheduleRunKey{ScheduleID: 2, RunDate: runDate}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(investment); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata"
+)
+
+// ISAAnnualAllowance is the most a retail customer can subscribe to their ISA in one tax year
+var ISAAnnualAllowance = NewMoney(2000000, DefaultCurrency)
+
+// ukTime is the time zone tax years start in
+var ukTime = mustLoadLocation("Europe/London")
+
+// TaxYear is a UK tax year, running from 6 April to 5 April the following year. StartYear is the calendar year
+// it starts in, e.g. 2026 for the 2026/27 tax year.
+type TaxYear struct {
+	StartYear int
+}
+
+// TaxYearOf returns the tax year t falls in, in UK time
+func TaxYearOf(t time.Time) TaxYear {
+	year := t.In(ukTime).Year()
+	if t.Before(TaxYear{StartYear: year}.Start()) {
+		year--
+	}
+	return TaxYear{StartYear: year}
+}
+
+// Start returns the instant the tax year starts, midnight on 6 April in UK time
+func (y TaxYear) Start() time.Time {
+	return time.Date(y.StartYear, time.April, 6, 0, 0, 0, 0, ukTime)
+}
+
+// End returns the instant the tax year ends, which is the start of the next one
+func (y TaxYear) End() time.Time {
+	return TaxYear{StartYear: y.StartYear + 1}.Start()
+}
+
+// Contains reports whether t falls in the tax year
+func (y TaxYear) Contains(t time.Time) bool {
+	return !t.Before(y.Start()) && t.Before(y.End())
+}
+
+// FirstDay returns 6 April, the first day of the tax year
+func (y TaxYear) FirstDay() Date {
+	return NewDate(y.StartYear, time.April, 6)
+}
+
+// LastDay returns 5 April, the last day of the tax year
+func (y TaxYear) LastDay() Date {
+	return NewDate(y.StartYear+1, time.April, 5)
+}
+
+// String returns the tax year as it is usually written, e.g. 2026/27
+func (y TaxYear) String() string {
+	return fmt.Sprintf("%d/%02d", y.StartYear, (y.StartYear+1)%100)
+}
+
+// IsISASubscription reports whether the transaction is money paid into a customer's ISA. Only investments made
+// into the ISA count, not those paid into a pension while the customer was employed, switches move money already
+// in the ISA, and failed or cancelled investments never paid anything in. Withdrawals don't give back any allowance.
+func (i *Investment) IsISASubscription() bool {
+	return i.Type == TransactionTypeInvestment && i.ISA && i.Status.HoldsUnits()
+}
+
+// ISAAllowance is how much of a retail customer's annual ISA allowance they have used in a tax year
+type ISAAllowance struct {
+	CustomerID uint
+	TaxYear    TaxYear
+	Allowance  Money
+	// Subscribed is the total of the customer's ISA subscriptions made in the tax year
+	Subscribed Money
+	// Remaining is what can still be subscribed in the tax year, never less than zero
+	Remaining Money
+}
+
+// NewISAAllowance works out the allowance remaining after subscribing subscribed in a tax year
+func NewISAAllowance(customerID uint, taxYear TaxYear, allowance, subscribed Money) (*ISAAllowance, error) {
+	remaining, err := allowance.Sub(subscribed)
+	if err != nil {
+		return nil, err
+	}
+	if remaining.Minor < 0 {
+		remaining = NewMoney(0, allowance.Currency)
+	}
+	return &ISAAllowance{
+		CustomerID: customerID,
+		TaxYear:    taxYear,
+		Allowance:  allowance,
+		Subscribed: subscribed,
+		Remaining:  remaining,
+	}, nil
+}
+
+// ISAAllowanceResponse represents an ISA allowance as sent in API responses
+type ISAAllowanceResponse struct {
+	CustomerID    uint   `json:"customer_id"`
+	TaxYear       string `json:"tax_year"`
+	TaxYearStarts Date   `json:"tax_year_starts"`
+	TaxYearEnds   Date   `json:"tax_year_ends"`
+	Allowance     Money  `json:"allowance"`
+	Subscribed    Money  `json:"subscribed"`
+	Remaining     Money  `json:"remaining"`
+}
+
+// mustLoadLocation loads a time zone from the embedded time zone database
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
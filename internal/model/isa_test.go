@@ -0,0 +1,103 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaxYearOf(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		want TaxYear
+	}{
+		{name: "first instant of the tax year", at: time.Date(2026, time.April, 5, 23, 0, 0, 0, time.UTC), want: TaxYear{StartYear: 2026}},
+		{name: "last instant of the previous tax year", at: time.Date(2026, time.April, 5, 22, 59, 59, 0, time.UTC), want: TaxYear{StartYear: 2025}},
+		{name: "new year's day", at: time.Date(2027, time.January, 1, 12, 0, 0, 0, time.UTC), want: TaxYear{StartYear: 2026}},
+		{name: "winter, when UK time is UTC", at: time.Date(2026, time.December, 31, 23, 59, 0, 0, time.UTC), want: TaxYear{StartYear: 2026}},
+		{name: "before 6 April", at: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), want: TaxYear{StartYear: 2025}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TaxYearOf(tt.at)
+			if got != tt.want {
+				t.Errorf("TaxYearOf() = %v, want %v", got, tt.want)
+			}
+			if !got.Contains(tt.at) {
+				t.Errorf("%v.Contains(%v) = false, want true", got, tt.at)
+			}
+		})
+	}
+}
+
+func TestTaxYear(t *testing.T) {
+	year := TaxYear{StartYear: 2026}
+
+	if got := year.String(); got != "2026/27" {
+		t.Errorf("String() = %q, want %q", got, "2026/27")
+	}
+	if got := (TaxYear{StartYear: 2099}).String(); got != "2099/00" {
+		t.Errorf("String() = %q, want %q", got, "2099/00")
+	}
+	if got, want := year.FirstDay(), NewDate(2026, time.April, 6); got != want {
+		t.Errorf("FirstDay() = %v, want %v", got, want)
+	}
+	if got, want := year.LastDay(), NewDate(2027, time.April, 5); got != want {
+		t.Errorf("LastDay() = %v, want %v", got, want)
+	}
+	if !year.End().Equal(TaxYear{StartYear: 2027}.Start()) {
+		t.Errorf("End() = %v, want the start of the next tax year", year.End())
+	}
+	if year.Contains(year.End()) {
+		t.Error("Contains(End()) = true, want false")
+	}
+}
+
+func TestNewISAAllowance(t *testing.T) {
+	tests := []struct {
+		name          string
+		subscribed    int64
+		wantRemaining int64
+	}{
+		{name: "nothing subscribed", subscribed: 0, wantRemaining: 2000000},
+		{name: "part subscribed", subscribed: 1250050, wantRemaining: 749950},
+		{name: "fully subscribed", subscribed: 2000000, wantRemaining: 0},
+		{name: "over subscribed", subscribed: 2100000, wantRemaining: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewISAAllowance(1, TaxYear{StartYear: 2026}, ISAAnnualAllowance, NewMoney(tt.subscribed, DefaultCurrency))
+			if err != nil {
+				t.Fatalf("NewISAAllowance() unexpected error = %v", err)
+			}
+			if want := NewMoney(tt.wantRemaining, DefaultCurrency); got.Remaining != want {
+				t.Errorf("NewISAAllowance().Remaining = %v, want %v", got.Remaining, want)
+			}
+		})
+	}
+}
+
+func TestInvestment_IsISASubscription(t *testing.T) {
+	tests := []struct {
+		name       string
+		investment Investment
+		want       bool
+	}{
+		{name: "ISA investment", investment: Investment{Type: TransactionTypeInvestment, ISA: true, Status: InvestmentStatusPending}, want: true},
+		{name: "settled ISA investment", investment: Investment{Type: TransactionTypeInvestment, ISA: true, Status: InvestmentStatusSettled}, want: true},
+		{name: "investment paid into a pension while employed", investment: Investment{Type: TransactionTypeInvestment, Status: InvestmentStatusSettled}},
+		{name: "cancelled ISA investment", investment: Investment{Type: TransactionTypeInvestment, ISA: true, Status: InvestmentStatusCancelled}},
+		{name: "failed ISA investment", investment: Investment{Type: TransactionTypeInvestment, ISA: true, Status: InvestmentStatusFailed}},
+		{name: "switch in", investment: Investment{Type: TransactionTypeSwitchIn, Status: InvestmentStatusSettled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.investment.IsISASubscription(); got != tt.want {
+				t.Errorf("IsISASubscription() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidISIN is returned when a string is not a valid International Securities Identification Number
+var ErrInvalidISIN = errors.New("invalid ISIN")
+
+// ValidateISIN checks that isin is a well formed ISIN (ISO 6166): a two letter country code,
+// nine alphanumeric characters and a Luhn check digit, e.g. "GB00B3X7QG63"
+func ValidateISIN(isin string) error {
+	if len(isin) != 12 {
+		return fmt.Errorf("%w: %q must be 12 characters long", ErrInvalidISIN, isin)
+	}
+	for i := 0; i < 2; i++ {
+		if isin[i] < 'A' || isin[i] > 'Z' {
+			return fmt.Errorf("%w: %q must start with a two letter country code", ErrInvalidISIN, isin)
+		}
+	}
+	if isin[11] < '0' || isin[11] > '9' {
+		return fmt.Errorf("%w: %q must end with a check digit", ErrInvalidISIN, isin)
+	}
+
+	// Letters are expanded to two digits (A=10 ... Z=35) before applying the Luhn algorithm
+	digits := make([]int, 0, 24)
+	for i := 0; i < len(isin); i++ {
+		c := isin[i]
+		switch {
+		case c >= '0' && c <= '9':
+			digits = append(digits, int(c-'0'))
+		case c >= 'A' && c <= 'Z':
+			value := int(c-'A') + 10
+			digits = append(digits, value/10, value%10)
+		default:
+			return fmt.Errorf("%w: %q must only contain upper case letters and digits", ErrInvalidISIN, isin)
+		}
+	}
+
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	if sum%10 != 0 {
+		return fmt.Errorf("%w: %q has an incorrect check digit", ErrInvalidISIN, isin)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateISIN(t *testing.T) {
+	tests := []struct {
+		isin    string
+		wantErr bool
+	}{
+		{isin: "US0378331005"},
+		{isin: "GB0002634946"},
+		{isin: "IE00B4L5Y983"},
+		{isin: "GB00B3X7QG63"},
+		{isin: "US0378331006", wantErr: true},
+		{isin: "IE00B4L5Y984", wantErr: true},
+		{isin: "US037833100", wantErr: true},
+		{isin: "US03783310055", wantErr: true},
+		{isin: "120378331005", wantErr: true},
+		{isin: "US037833100X", wantErr: true},
+		{isin: "us0378331005", wantErr: true},
+		{isin: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.isin, func(t *testing.T) {
+			err := ValidateISIN(tt.isin)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidISIN) {
+					t.Errorf("ValidateISIN() error = %v, want ErrInvalidISIN", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ValidateISIN() unexpected error = %v", err)
+			}
+		})
+	}
+}
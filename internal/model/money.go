@@ -0,0 +1,139 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// DefaultCurrency is the currency customers invest in
+const DefaultCurrency = "GBP"
+
+// moneyScale is the number of decimal places (minor unit digits) supported for every currency
+const moneyScale = 2
+
+// ErrInvalidMoney is returned when a monetary amount or currency cannot be parsed
+var ErrInvalidMoney = errors.New("invalid money")
+
+// ErrCurrencyMismatch is returned when combining amounts in different currencies
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money represents an exact monetary amount as integer minor units (e.g. pence) of an ISO 4217 currency
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// moneyJSON is the wire representation of Money
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// NewMoney creates a Money value from minor units
+func NewMoney(minor int64, currency string) Money {
+	return Money{Minor: minor, Currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "1234567.89" in the given currency.
+// More than two decimal places are rejected rather than rounded.
+func ParseMoney(amount, currency string) (Money, error) {
+	if !IsValidCurrency(currency) {
+		return Money{}, fmt.Errorf("%w: currency must be a 3 letter ISO 4217 code", ErrInvalidMoney)
+	}
+
+	minor, err := parseDecimal(amount, moneyScale)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: amount %q: %v", ErrInvalidMoney, amount, err)
+	}
+
+	return Money{Minor: minor, Currency: currency}, nil
+}
+
+// IsValidCurrency reports whether code looks like an ISO 4217 currency code
+func IsValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.Minor > 0
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+// Add returns the sum of two amounts in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Minor: m.Minor + other.Minor, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of two amounts in the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Minor: m.Minor - other.Minor, Currency: m.Currency}, nil
+}
+
+// Share returns percent of the amount, rounded towards zero to the minor unit
+func (m Money) Share(percent Percent) Money {
+	share := new(big.Int).Mul(big.NewInt(m.Minor), big.NewInt(int64(percent)))
+	share.Quo(share, big.NewInt(int64(OneHundredPercent)))
+	return Money{Minor: share.Int64(), Currency: m.Currency}
+}
+
+// Decimal returns the amount as a decimal string in major units, e.g. "1234567.89"
+func (m Money) Decimal() string {
+	return formatDecimal(m.Minor, moneyScale)
+}
+
+// String returns the amount followed by its currency, e.g. "1234567.89 GBP"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// MarshalJSON encodes the amount as a decimal string so clients never see float rounding
+func (m Money) MarshalJSON() ([]byte, error) {
+	amount, err := json.Marshal(m.Decimal())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(moneyJSON{Amount: amount, Currency: m.Currency})
+}
+
+// UnmarshalJSON decodes {"amount": "1234.56", "currency": "GBP"}. The amount may be a JSON string or number,
+// but it is parsed from its literal text and must have at most two decimal places.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMoney, err)
+	}
+
+	amount, err := unquoteDecimal(raw.Amount)
+	if err != nil {
+		return fmt.Errorf("%w: amount is required and must be a decimal number", ErrInvalidMoney)
+	}
+
+	parsed, err := ParseMoney(amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    string
+		currency  string
+		wantMinor int64
+		wantErr   bool
+	}{
+		{name: "Whole amount", amount: "2000", currency: "GBP", wantMinor: 200000},
+		{name: "Two decimal places", amount: "1234567.89", currency: "GBP", wantMinor: 123456789},
+		{name: "One decimal place", amount: "0.5", currency: "GBP", wantMinor: 50},
+		{name: "Negative amount", amount: "-10.01", currency: "GBP", wantMinor: -1001},
+		{name: "Three decimal places", amount: "10.001", currency: "GBP", wantErr: true},
+		{name: "Exponent", amount: "1e3", currency: "GBP", wantErr: true},
+		{name: "Trailing point", amount: "10.", currency: "GBP", wantErr: true},
+		{name: "Empty amount", amount: "", currency: "GBP", wantErr: true},
+		{name: "Out of range", amount: "92233720368547758.08", currency: "GBP", wantErr: true},
+		{name: "Lowercase currency", amount: "10", currency: "gbp", wantErr: true},
+		{name: "Missing currency", amount: "10", currency: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.amount, tt.currency)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMoney) {
+					t.Errorf("ParseMoney() error = %v, want ErrInvalidMoney", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMoney() unexpected error = %v", err)
+			}
+			if got.Minor != tt.wantMinor {
+				t.Errorf("Minor = %v, want %v", got.Minor, tt.wantMinor)
+			}
+			if got.Currency != tt.currency {
+				t.Errorf("Currency = %v, want %v", got.Currency, tt.currency)
+			}
+		})
+	}
+}
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    Money
+		wantErr bool
+	}{
+		{name: "String amount", body: `{"amount": "1234567.89", "currency": "GBP"}`, want: NewMoney(123456789, "GBP")},
+		{name: "Number amount", body: `{"amount": 1234567.89, "currency": "GBP"}`, want: NewMoney(123456789, "GBP")},
+		{name: "Number with too many decimals", body: `{"amount": 0.105, "currency": "GBP"}`, wantErr: true},
+		{name: "String with too many decimals", body: `{"amount": "0.105", "currency": "GBP"}`, wantErr: true},
+		{name: "Missing amount", body: `{"currency": "GBP"}`, wantErr: true},
+		{name: "Missing currency", body: `{"amount": "10.00"}`, wantErr: true},
+		{name: "Not an object", body: `10.00`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.body), &got)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMoney) {
+					t.Errorf("Unmarshal() error = %v, want ErrInvalidMoney", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		money Money
+		want  string
+	}{
+		{name: "Pennies preserved", money: NewMoney(123456789, "GBP"), want: `{"amount":"1234567.89","currency":"GBP"}`},
+		{name: "Less than one pound", money: NewMoney(5, "GBP"), want: `{"amount":"0.05","currency":"GBP"}`},
+		{name: "Negative amount", money: NewMoney(-150, "GBP"), want: `{"amount":"-1.50","currency":"GBP"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.money)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Add(t *testing.T) {
+	got, err := NewMoney(150, "GBP").Add(NewMoney(250, "GBP"))
+	if err != nil {
+		t.Fatalf("Add() unexpected error = %v", err)
+	}
+	if got != NewMoney(400, "GBP") {
+		t.Errorf("Add() = %v, want 4.00 GBP", got)
+	}
+
+	if _, err := NewMoney(150, "GBP").Add(NewMoney(250, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add() error = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestMoney_Share(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		percent Percent
+		want    int64
+	}{
+		{name: "whole share", amount: 250000, percent: 5000, want: 12500},
+		{name: "rounded down", amount: 999, percent: 3333, want: 33},
+		{name: "nothing", amount: 250000, percent: 0, want: 0},
+		{name: "everything", amount: 250000, percent: OneHundredPercent, want: 250000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewMoney(tt.amount, "GBP").Share(tt.percent); got != NewMoney(tt.want, "GBP") {
+				t.Errorf("Share() = %v, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPayroll is returned for a payroll file that can't be read, e.g. one without its header
+var ErrInvalidPayroll = errors.New("invalid payroll file")
+
+// Columns named by the header lines that start a payroll file
+var (
+	payrollControlColumns      = []string{"contributions", "total"}
+	payrollContributionColumns = []string{"client_id", "fund_id", "source", "amount"}
+)
+
+// Payroll is an employer's monthly payroll file, listing the workplace contributions of each employee. It
+// starts with control totals the contributions must add up to, so a truncated or altered file is caught:
+//
+//	contributions,total
+//	2,200.00
+//	client_id,fund_id,source,amount
+//	1,1,employee,125.00
+//	1,1,employer,75.00
+//
+// Amounts are in GBP with at most two decimal places.
+type Payroll struct {
+	// Count is the number of contributions the header says the file lists
+	Count int
+	// Total is what the header says the contributions add up to
+	Total Money
+	// Contributions are the rows that could be read
+	Contributions []PayrollContribution
+	// Errors describe the rows that couldn't be read
+	Errors []PayrollRowError
+}
+
+// PayrollContribution is one row of a payroll file
+type PayrollContribution struct {
+	// Line is the line of the file the contribution is on, for reporting errors
+	Line     int
+	ClientID uint
+	FundID   uint
+	Source   ContributionSource
+	Amount   Money
+}
+
+// PayrollRowError describes why a row of a payroll file can't be imported
+type PayrollRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// PayrollError is returned when some rows of a payroll file can't be imported, so none of them are
+type PayrollError struct {
+	Rows []PayrollRowError
+}
+
+// Error implements the error interface
+func (e *PayrollError) Error() string {
+	if len(e.Rows) == 1 {
+		return fmt.Sprintf("payroll file has an invalid row: line %d: %s", e.Rows[0].Line, e.Rows[0].Error)
+	}
+	return fmt.Sprintf("payroll file has %d invalid rows", len(e.Rows))
+}
+
+// ParsePayroll reads a payroll file. A file without a valid header is rejected with ErrInvalidPayroll, while
+// rows that can't be read are reported in the payroll's Errors so every problem can be fixed at once.
+func ParsePayroll(r io.Reader) (*Payroll, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var header [3][]string
+	for i := range header {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: the file ends before its header does", ErrInvalidPayroll)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPayroll, err)
+		}
+		header[i] = record
+	}
+	if !sameColumns(header[0], payrollControlColumns) {
+		return nil, fmt.Errorf("%w: the first line must name the columns %s", ErrInvalidPayroll, strings.Join(payrollControlColumns, ","))
+	}
+	if len(header[1]) != len(payrollControlColumns) {
+		return nil, fmt.Errorf("%w: line 2 must hold the number of contributions and their total", ErrInvalidPayroll)
+	}
+	if !sameColumns(header[2], payrollContributionColumns) {
+		return nil, fmt.Errorf("%w: line 3 must name the columns %s", ErrInvalidPayroll, strings.Join(payrollContributionColumns, ","))
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(header[1][0]))
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("%w: the number of contributions must be a whole number", ErrInvalidPayroll)
+	}
+	total, err := ParseMoney(strings.TrimSpace(header[1][1]), DefaultCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: total: %v", ErrInvalidPayroll, err)
+	}
+
+	payroll := &Payroll{Count: count, Total: total}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidPayroll, err)
+			}
+			payroll.Errors = append(payroll.Errors, PayrollRowError{Line: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		contribution, err := parsePayrollContribution(record)
+		if err != nil {
+			payroll.Errors = append(payroll.Errors, PayrollRowError{Line: line, Error: err.Error()})
+			continue
+		}
+		contribution.Line = line
+		payroll.Contributions = append(payroll.Contributions, *contribution)
+	}
+	return payroll, nil
+}
+
+// parsePayrollContribution reads a contribution from the columns of a row
+func parsePayrollContribution(record []string) (*PayrollContribution, error) {
+	if len(record) != len(payrollContributionColumns) {
+		return nil, fmt.Errorf("expected %d columns, found %d", len(payrollContributionColumns), len(record))
+	}
+
+	clientID, err := strconv.ParseUint(strings.TrimSpace(record[0]), 10, 32)
+	if err != nil || clientID == 0 {
+		return nil, fmt.Errorf("invalid client ID %q", record[0])
+	}
+	fundID, err := strconv.ParseUint(strings.TrimSpace(record[1]), 10, 32)
+	if err != nil || fundID == 0 {
+		return nil, fmt.Errorf("invalid fund ID %q", record[1])
+	}
+	source := ContributionSource(strings.TrimSpace(record[2]))
+	if !source.Valid() {
+		return nil, fmt.Errorf("invalid contribution source %q", record[2])
+	}
+	amount, err := ParseMoney(strings.TrimSpace(record[3]), DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PayrollContribution{ClientID: uint(clientID), FundID: uint(fundID), Source: source, Amount: amount}, nil
+}
+
+// sameColumns reports whether a header line names the expected columns, ignoring case and spaces
+func sameColumns(record, columns []string) bool {
+	if len(record) != len(columns) {
+		return false
+	}
+	for i, column := range columns {
+		if !strings.EqualFold(strings.TrimSpace(record[i]), column) {
+			return false
+		}
+	}
+	return true
+}
+
+// PayrollImport is the result of importing a payroll file
+type PayrollImport struct {
+	EmployerID  uint
+	Total       Money
+	Investments []*Investment
+}
+
+// PayrollImportResponse represents an imported payroll file as sent in API responses
+type PayrollImportResponse struct {
+	EmployerID    uint                 `json:"employer_id"`
+	Contributions int                  `json:"contributions"`
+	Total         Money                `json:"total"`
+	Investments   []InvestmentResponse `json:"investments"`
+}
+
+// PayrollErrorResponse reports why a payroll file wasn't imported, row by row
+type PayrollErrorResponse struct {
+	Error string            `json:"error"`
+	Rows  []PayrollRowError `json:"rows"`
+}
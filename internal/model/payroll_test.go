@@ -0,0 +1,93 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParsePayroll(t *testing.T) {
+	const header = "contributions,total\n2,200.00\nclient_id,fund_id,source,amount\n"
+
+	tests := []struct {
+		name      string
+		file      string
+		wantRows  []PayrollContribution
+		wantLines []int
+		wantErr   error
+	}{
+		{
+			name: "Valid file",
+			file: header + "1,2,employee,125.00\n\n3, 4, employer, 75\n",
+			wantRows: []PayrollContribution{
+				{Line: 4, ClientID: 1, FundID: 2, Source: ContributionSourceEmployee, Amount: NewMoney(12500, DefaultCurrency)},
+				{Line: 6, ClientID: 3, FundID: 4, Source: ContributionSourceEmployer, Amount: NewMoney(7500, DefaultCurrency)},
+			},
+		},
+		{
+			name: "Header names in another case",
+			file: "Contributions,Total\n1,1.00\nCLIENT_ID,FUND_ID,SOURCE,AMOUNT\n1,1,employee,1.00\n",
+			wantRows: []PayrollContribution{
+				{Line: 4, ClientID: 1, FundID: 1, Source: ContributionSourceEmployee, Amount: NewMoney(100, DefaultCurrency)},
+			},
+		},
+		{
+			name:      "Invalid rows are reported",
+			file:      header + "0,1,employee,1.00\n1,1,bonus,1.00\n1,1,employee,1.001\n1,1,employee\n1,1,employee,1.00\n",
+			wantRows:  []PayrollContribution{{Line: 8, ClientID: 1, FundID: 1, Source: ContributionSourceEmployee, Amount: NewMoney(100, DefaultCurrency)}},
+			wantLines: []int{4, 5, 6, 7},
+		},
+		{
+			name:    "Empty file",
+			file:    "",
+			wantErr: ErrInvalidPayroll,
+		},
+		{
+			name:    "Missing control totals",
+			file:    "client_id,fund_id,source,amount\n1,1,employee,1.00\n",
+			wantErr: ErrInvalidPayroll,
+		},
+		{
+			name:    "Invalid total",
+			file:    "contributions,total\n1,lots\nclient_id,fund_id,source,amount\n",
+			wantErr: ErrInvalidPayroll,
+		},
+		{
+			name:    "Missing column names",
+			file:    "contributions,total\n1,1.00\n1,1,employee,1.00\n",
+			wantErr: ErrInvalidPayroll,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePayroll(strings.NewReader(tt.file))
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ParsePayroll() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePayroll() unexpected error = %v", err)
+			}
+			if len(got.Contributions) != len(tt.wantRows) {
+				t.Fatalf("ParsePayroll() read %+v, want %+v", got.Contributions, tt.wantRows)
+			}
+			for i, want := range tt.wantRows {
+				if got.Contributions[i] != want {
+					t.Errorf("contribution %d = %+v, want %+v", i, got.Contributions[i], want)
+				}
+			}
+			if len(got.Errors) != len(tt.wantLines) {
+				t.Fatalf("ParsePayroll() errors = %+v, want errors on lines %v", got.Errors, tt.wantLines)
+			}
+			for i, line := range tt.wantLines {
+				if got.Errors[i].Line != line {
+					t.Errorf("error %d on line %d, want line %d", i, got.Errors[i].Line, line)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// percentScale is the number of decimal places kept for percentages
+const percentScale = 3
+
+// ErrInvalidPercent is returned when a percentage cannot be parsed
+var ErrInvalidPercent = errors.New("invalid percentage")
+
+// Percent is an exact percentage stored as thousandths of a percent, e.g. 0.075% is 75 and 100% is 100000
+type Percent int64
+
+// OneHundredPercent is 100%
+const OneHundredPercent Percent = 100 * 1000
+
+// ParsePercent parses a decimal percentage such as "0.075". More than three decimal places are rejected.
+func ParsePercent(s string) (Percent, error) {
+	value, err := parseDecimal(s, percentScale)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q: %v", ErrInvalidPercent, s, err)
+	}
+	return Percent(value), nil
+}
+
+// PercentOf returns part as a percentage of whole, rounded half away from zero to the nearest thousandth
+// of a percent. It returns 0 when whole is 0.
+func PercentOf(part, whole int64) Percent {
+	if whole == 0 {
+		return 0
+	}
+
+	// Adding or subtracting half of whole before dividing rounds the truncated quotient: (2*part*100% ± |whole|) / (2*whole)
+	numerator := new(big.Int).Mul(big.NewInt(part), big.NewInt(2*int64(OneHundredPercent)))
+	half := new(big.Int).Abs(big.NewInt(whole))
+	if part < 0 {
+		numerator.Sub(numerator, half)
+	} else {
+		numerator.Add(numerator, half)
+	}
+	denominator := new(big.Int).Mul(big.NewInt(whole), big.NewInt(2))
+	return Percent(numerator.Quo(numerator, denominator).Int64())
+}
+
+// String returns the percentage as a decimal string without the % sign, e.g. "0.075"
+func (p Percent) String() string {
+	return formatDecimal(int64(p), percentScale)
+}
+
+// MarshalJSON encodes the percentage as a decimal string
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes a percentage from a JSON string or number such as "0.075" or 0.075
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	s, err := unquoteDecimal(data)
+	if err != nil {
+		return fmt.Errorf("%w: must be a decimal number", ErrInvalidPercent)
+	}
+
+	parsed, err := ParsePercent(s)
+	if err != nil {
+		return err
+	}
+
+	*p = parsed
+	return nil
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParsePercent(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Percent
+		wantErr bool
+	}{
+		{input: "0", want: 0},
+		{input: "0.075", want: 75},
+		{input: "1.5", want: 1500},
+		{input: "100", want: OneHundredPercent},
+		{input: "-0.5", want: -500},
+		{input: "0.0755", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePercent(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPercent) {
+					t.Errorf("ParsePercent() error = %v, want ErrInvalidPercent", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePercent() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePercent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercent_JSON(t *testing.T) {
+	data, err := json.Marshal(Percent(75))
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error = %v", err)
+	}
+	if string(data) != `"0.075"` {
+		t.Errorf("Marshal() = %s, want \"0.075\"", data)
+	}
+
+	for _, input := range []string{`"0.22"`, `0.22`} {
+		var p Percent
+		if err := json.Unmarshal([]byte(input), &p); err != nil {
+			t.Fatalf("Unmarshal(%s) unexpected error = %v", input, err)
+		}
+		if p != 220 {
+			t.Errorf("Unmarshal(%s) = %v, want 220", input, p)
+		}
+	}
+
+	var p Percent
+	if err := json.Unmarshal([]byte(`true`), &p); !errors.Is(err, ErrInvalidPercent) {
+		t.Errorf("Unmarshal(true) error = %v, want ErrInvalidPercent", err)
+	}
+}
+
+func TestPercentOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		part  int64
+		whole int64
+		want  Percent
+	}{
+		{name: "Whole", part: 250, whole: 250, want: OneHundredPercent},
+		{name: "Quarter", part: 25, whole: 100, want: 25000},
+		{name: "Third rounds down", part: 1, whole: 3, want: 33333},
+		{name: "Two thirds rounds up", part: 2, whole: 3, want: 66667},
+		{name: "Half a thousandth rounds away from zero", part: 1, whole: 200000, want: 1},
+		{name: "Negative", part: -2, whole: 3, want: -66667},
+		{name: "Zero whole", part: 5, whole: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PercentOf(tt.part, tt.whole); got != tt.want {
+				t.Errorf("PercentOf(%d, %d) = %v, want %v", tt.part, tt.whole, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package model
+
+// Holding is a customer's position in one fund: the units bought by their investments valued at the fund's latest price
+type Holding struct {
+	FundID   uint
+	FundName string
+	Units    Units
+	// Contributed is the total amount invested in the fund
+	Contributed Money
+	// Price is the fund's latest price and PriceDate its valuation date. Both are zero when the fund hasn't been priced.
+	Price     Price
+	PriceDate Date
+	// Value is what the units are worth at Price
+	Value Money
+	// Gain is Value minus Contributed, negative for a loss
+	Gain Money
+	// Allocation is the share of the portfolio's value held in this fund
+	Allocation Percent
+}
+
+// HoldingKey identifies a customer's holding in a fund
+type HoldingKey struct {
+	ClientID uint
+	FundID   uint
+}
+
+// Portfolio is a customer's holdings across every fund they have invested in
+type Portfolio struct {
+	CustomerID  uint
+	Holdings    []*Holding
+	Contributed Money
+	// ContributionsBySource is the total paid in from each source, before anything was withdrawn
+	ContributionsBySource map[ContributionSource]Money
+	Value                 Money
+	Gain                  Money
+}
+
+// HoldingResponse represents a holding as sent in API responses
+type HoldingResponse struct {
+	FundID      uint    `json:"fund_id"`
+	FundName    string  `json:"fund_name"`
+	Units       Units   `json:"units"`
+	Contributed Money   `json:"contributed"`
+	Price       Price   `json:"price"`
+	PriceDate   Date    `json:"price_date"`
+	Value       Money   `json:"value"`
+	Gain        Money   `json:"gain"`
+	Allocation  Percent `json:"allocation"`
+}
+
+// PortfolioResponse represents the portfolio data that will be sent in API responses
+type PortfolioResponse struct {
+	CustomerID            uint                         `json:"customer_id"`
+	Holdings              []HoldingResponse            `json:"holdings"`
+	Contributed           Money                        `json:"contributed"`
+	ContributionsBySource map[ContributionSource]Money `json:"contributions_by_source"`
+	Value                 Money                        `json:"value"`
+	Gain                  Money                        `json:"gain"`
+}
@@ -0,0 +1,172 @@
+package model
+
+import "sort"
+
+// RebalancePolicy decides when a portfolio has drifted far enough from its target allocation to be rebalanced,
+// and which trades are worth making to restore it
+type RebalancePolicy struct {
+	// Tolerance is how many percentage points a fund's share of the portfolio can drift from its target before
+	// the portfolio is rebalanced
+	Tolerance Percent
+	// MinimumTrade is the smallest amount worth switching between two funds
+	MinimumTrade Money
+}
+
+// FundDrift compares a customer's holding in a fund with the fund's target in their allocation
+type FundDrift struct {
+	FundID uint  `json:"fund_id"`
+	Value  Money `json:"value"`
+	// Weight is the fund's share of the portfolio's value
+	Weight      Percent `json:"weight"`
+	Target      Percent `json:"target"`
+	TargetValue Money   `json:"target_value"`
+	// Drift is Weight minus Target, negative when the fund is under its target
+	Drift Percent `json:"drift"`
+}
+
+// RebalanceTrade moves an amount from a fund over its target into a fund under it
+type RebalanceTrade struct {
+	FromFundID uint  `json:"from_fund_id"`
+	ToFundID   uint  `json:"to_fund_id"`
+	Amount     Money `json:"amount"`
+}
+
+// Rebalance is the plan for bringing a customer's portfolio back to their target allocation
+type Rebalance struct {
+	CustomerID uint
+	// Value is the portfolio's value at the funds' latest prices
+	Value Money
+	// Funds are the funds in the allocation, in its order, followed by any other fund held
+	Funds []FundDrift
+	// Trades are empty when every fund is within the tolerance band
+	Trades []RebalanceTrade
+	// Switches are the switches made for the trades once the rebalance has been executed
+	Switches []*Switch
+	Executed bool
+}
+
+// PlanRebalance compares the value of a customer's holdings in each fund with their target allocation. When any
+// fund has drifted further from its target than the policy's tolerance, it plans the trades that restore every
+// fund to its target: each fund over its target is only sold from and each fund under it only bought into, the
+// largest excess going to the largest shortfall first, and trades smaller than the policy's minimum are left out.
+func PlanRebalance(allocation *TargetAllocation, values map[uint]Money, policy RebalancePolicy) (*Rebalance, error) {
+	total := NewMoney(0, DefaultCurrency)
+	heldIDs := make([]uint, 0, len(values))
+	for fundID, value := range values {
+		var err error
+		if total, err = total.Add(value); err != nil {
+			return nil, err
+		}
+		heldIDs = append(heldIDs, fundID)
+	}
+	sort.Slice(heldIDs, func(i, j int) bool { return heldIDs[i] < heldIDs[j] })
+
+	rebalance := &Rebalance{CustomerID: allocation.CustomerID, Value: total}
+	targets := allocation.Split(total)
+	inAllocation := make(map[uint]bool, len(allocation.Funds))
+	for i, fund := range allocation.Funds {
+		inAllocation[fund.FundID] = true
+		rebalance.Funds = append(rebalance.Funds, fundDrift(fund.FundID, values[fund.FundID], fund.Percentage, targets[i], total))
+	}
+	for _, fundID := range heldIDs {
+		if !inAllocation[fundID] && !values[fundID].IsZero() {
+			rebalance.Funds = append(rebalance.Funds, fundDrift(fundID, values[fundID], 0, NewMoney(0, total.Currency), total))
+		}
+	}
+
+	drifted := false
+	for _, fund := range rebalance.Funds {
+		if fund.Drift > policy.Tolerance || -fund.Drift > policy.Tolerance {
+			drifted = true
+		}
+	}
+	if !drifted {
+		return rebalance, nil
+	}
+
+	// Each fund's excess over its target, positive for funds to sell from and negative for funds to buy into
+	type excess struct {
+		fundID uint
+		minor  int64
+	}
+	var sells, buys []*excess
+	for _, fund := range rebalance.Funds {
+		minor := fund.Value.Minor - fund.TargetValue.Minor
+		switch {
+		case minor >= policy.MinimumTrade.Minor && minor > 0:
+			sells = append(sells, &excess{fundID: fund.FundID, minor: minor})
+		case -minor >= policy.MinimumTrade.Minor && minor < 0:
+			buys = append(buys, &excess{fundID: fund.FundID, minor: -minor})
+		}
+	}
+	sort.SliceStable(sells, func(i, j int) bool { return sells[i].minor > sells[j].minor })
+	sort.SliceStable(buys, func(i, j int) bool { return buys[i].minor > buys[j].minor })
+
+	for s, b := 0, 0; s < len(sells) && b < len(buys); {
+		amount := min(sells[s].minor, buys[b].minor)
+		if amount >= policy.MinimumTrade.Minor {
+			rebalance.Trades = append(rebalance.Trades, RebalanceTrade{
+				FromFundID: sells[s].fundID,
+				ToFundID:   buys[b].fundID,
+				Amount:     NewMoney(amount, total.Currency),
+			})
+		}
+		sells[s].minor -= amount
+		buys[b].minor -= amount
+		if sells[s].minor == 0 {
+			s++
+		}
+		if buys[b].minor == 0 {
+			b++
+		}
+	}
+	return rebalance, nil
+}
+
+// fundDrift compares the value held in a fund with its target share of the portfolio's total value
+func fundDrift(fundID uint, value Money, target Percent, targetValue Money, total Money) FundDrift {
+	if value.Currency == "" {
+		value = NewMoney(0, total.Currency)
+	}
+	drift := FundDrift{
+		FundID:      fundID,
+		Value:       value,
+		Weight:      PercentOf(value.Minor, total.Minor),
+		Target:      target,
+		TargetValue: targetValue,
+	}
+	if !total.IsZero() {
+		// An empty portfolio can't drift from its target
+		drift.Drift = drift.Weight - target
+	}
+	return drift
+}
+
+// RebalanceFailure reports why a customer's portfolio couldn't be rebalanced
+type RebalanceFailure struct {
+	CustomerID uint   `json:"customer_id"`
+	Error      string `json:"error"`
+}
+
+// RebalanceRun is the outcome of rebalancing every customer who has chosen a target allocation
+type RebalanceRun struct {
+	// Rebalanced are the customers whose portfolios had drifted and were rebalanced
+	Rebalanced []*Rebalance
+	Failed     []RebalanceFailure
+}
+
+// RebalanceResponse represents a rebalance as sent in API responses
+type RebalanceResponse struct {
+	CustomerID uint             `json:"customer_id"`
+	Value      Money            `json:"value"`
+	Funds      []FundDrift      `json:"funds"`
+	Trades     []RebalanceTrade `json:"trades"`
+	Executed   bool             `json:"executed"`
+	Switches   []SwitchResponse `json:"switches,omitempty"`
+}
+
+// RebalanceRunResponse represents a rebalancing run as sent in API responses
+type RebalanceRunResponse struct {
+	Rebalanced []RebalanceResponse `json:"rebalanced"`
+	Failed     []RebalanceFailure  `json:"failed"`
+}
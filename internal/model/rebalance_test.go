@@ -0,0 +1,98 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPlanRebalance(t *testing.T) {
+	gbp := func(minor int64) Money { return NewMoney(minor, DefaultCurrency) }
+	policy := RebalancePolicy{Tolerance: 5000, MinimumTrade: gbp(2500)}
+	allocation := &TargetAllocation{
+		CustomerID: 1,
+		Funds:      []FundAllocation{{FundID: 1, Percentage: 60000}, {FundID: 2, Percentage: 40000}},
+	}
+
+	tests := []struct {
+		name       string
+		allocation *TargetAllocation
+		values     map[uint]Money
+		policy     RebalancePolicy
+		wantDrift  []Percent
+		wantTrades []RebalanceTrade
+	}{
+		{
+			name:       "Within tolerance",
+			allocation: allocation,
+			values:     map[uint]Money{1: gbp(640000), 2: gbp(360000)},
+			wantDrift:  []Percent{4000, -4000},
+		},
+		{
+			name:       "Drifted beyond tolerance",
+			allocation: allocation,
+			values:     map[uint]Money{1: gbp(700000), 2: gbp(300000)},
+			wantDrift:  []Percent{10000, -10000},
+			wantTrades: []RebalanceTrade{{FromFundID: 1, ToFundID: 2, Amount: gbp(100000)}},
+		},
+		{
+			name:       "Fund not in the allocation is sold",
+			allocation: allocation,
+			values:     map[uint]Money{1: gbp(600000), 2: gbp(200000), 3: gbp(200000)},
+			wantDrift:  []Percent{0, -20000, 20000},
+			wantTrades: []RebalanceTrade{{FromFundID: 3, ToFundID: 2, Amount: gbp(200000)}},
+		},
+		{
+			name: "Largest excess goes to the largest shortfall",
+			allocation: &TargetAllocation{
+				CustomerID: 1,
+				Funds: []FundAllocation{
+					{FundID: 1, Percentage: 25000}, {FundID: 2, Percentage: 25000},
+					{FundID: 3, Percentage: 25000}, {FundID: 4, Percentage: 25000},
+				},
+			},
+			values:    map[uint]Money{1: gbp(4000), 2: gbp(3000), 3: gbp(2000), 4: gbp(1000)},
+			policy:    RebalancePolicy{Tolerance: 5000, MinimumTrade: gbp(100)},
+			wantDrift: []Percent{15000, 5000, -5000, -15000},
+			wantTrades: []RebalanceTrade{
+				{FromFundID: 1, ToFundID: 4, Amount: gbp(1500)},
+				{FromFundID: 2, ToFundID: 3, Amount: gbp(500)},
+			},
+		},
+		{
+			name:       "Trades below the minimum are left out",
+			allocation: allocation,
+			values:     map[uint]Money{1: gbp(4000), 2: gbp(1000)},
+			wantDrift:  []Percent{20000, -20000},
+		},
+		{
+			name:       "Nothing held",
+			allocation: allocation,
+			values:     map[uint]Money{},
+			wantDrift:  []Percent{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.policy == (RebalancePolicy{}) {
+				tt.policy = policy
+			}
+
+			got, err := PlanRebalance(tt.allocation, tt.values, tt.policy)
+			if err != nil {
+				t.Fatalf("PlanRebalance() unexpected error = %v", err)
+			}
+
+			drift := make([]Percent, len(got.Funds))
+			for i, fund := range got.Funds {
+				drift[i] = fund.Drift
+			}
+			if fmt.Sprint(drift) != fmt.Sprint(tt.wantDrift) {
+				t.Errorf("PlanRebalance() drift = %v, want %v", drift, tt.wantDrift)
+			}
+			if fmt.Sprint(got.Trades) != fmt.Sprint(tt.wantTrades) {
+				t.Errorf("PlanRebalance() trades = %v, want %v", got.Trades, tt.wantTrades)
+			}
+		})
+	}
+}
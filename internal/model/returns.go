@@ -0,0 +1,203 @@
+package model
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoReturn is returned when no rate of return explains a portfolio's cash flows and values
+var ErrNoReturn = errors.New("return can't be calculated")
+
+// daysPerYear is the length of a year when annualising returns, counting days on an actual/365 basis
+const daysPerYear = 365
+
+// Bounds on the yearly growth the internal rate of return is searched between: losing 99.99% or multiplying a
+// hundredfold in a year
+const (
+	minYearlyGrowth = 0.0001
+	maxYearlyGrowth = 100
+)
+
+// ReturnPeriod is a standard period returns are reported over, ending today
+type ReturnPeriod string
+
+const (
+	ReturnPeriodOneMonth    ReturnPeriod = "1M"
+	ReturnPeriodThreeMonths ReturnPeriod = "3M"
+	ReturnPeriodYearToDate  ReturnPeriod = "YTD"
+	ReturnPeriodOneYear     ReturnPeriod = "1Y"
+	// ReturnPeriodSinceInception starts at a fund's first price or a customer's first investment
+	ReturnPeriodSinceInception ReturnPeriod = "since_inception"
+)
+
+// ReturnPeriods are the periods returns are reported over, in order
+var ReturnPeriods = []ReturnPeriod{
+	ReturnPeriodOneMonth,
+	ReturnPeriodThreeMonths,
+	ReturnPeriodYearToDate,
+	ReturnPeriodOneYear,
+	ReturnPeriodSinceInception,
+}
+
+// Start returns the valuation date a period ending on end is measured from, or inception for the period since
+// inception. Months are counted back to the same day of the month, or its last day when it is shorter, and the
+// year to date is measured from the last day of the previous year. A period that would start before inception
+// is cut short to start there and reported as partial.
+func (p ReturnPeriod) Start(end, inception Date) (start Date, partial bool) {
+	switch p {
+	case ReturnPeriodOneMonth:
+		start = end.AddMonths(-1)
+	case ReturnPeriodThreeMonths:
+		start = end.AddMonths(-3)
+	case ReturnPeriodYearToDate:
+		start = NewDate(end.Year()-1, 12, 31)
+	case ReturnPeriodOneYear:
+		start = end.AddMonths(-12)
+	default:
+		return inception, false
+	}
+	if start.Before(inception.Time) {
+		return inception, true
+	}
+	return start, false
+}
+
+// PeriodReturn is the return over one period
+type PeriodReturn struct {
+	Period    ReturnPeriod `json:"period"`
+	StartDate Date         `json:"start_date"`
+	EndDate   Date         `json:"end_date"`
+	// Partial is true when the history starts after the period does, so the return covers less than the period
+	Partial bool `json:"partial"`
+	// Return is the cumulative return from StartDate to EndDate
+	Return Percent `json:"return"`
+	// Annualised is Return as a yearly rate, only given for periods of a year or more
+	Annualised *Percent `json:"annualised,omitempty"`
+}
+
+// NewPeriodReturn reports growth, the value at the end of a period for each unit at its start, as a return
+func NewPeriodReturn(period ReturnPeriod, start, end Date, partial bool, growth float64) PeriodReturn {
+	periodReturn := PeriodReturn{
+		Period:    period,
+		StartDate: start,
+		EndDate:   end,
+		Partial:   partial,
+		Return:    percentFromRate(growth - 1),
+	}
+	if days := daysBetween(start, end); days >= daysPerYear {
+		annualised := percentFromRate(math.Pow(growth, daysPerYear/days) - 1)
+		periodReturn.Annualised = &annualised
+	}
+	return periodReturn
+}
+
+// TimeWeightedReturn returns the growth of a fund from its price at the start of a period to its price at the
+// end. It is the product of the growth between each pair of consecutive prices in between, which cancel out, so
+// it doesn't depend on when money was paid in or taken out of the fund.
+func TimeWeightedReturn(start, end *FundPrice) float64 {
+	return float64(end.NAV) / float64(start.NAV)
+}
+
+// CashFlow is money paid into a portfolio on a date, negative when it is taken out
+type CashFlow struct {
+	Date   Date
+	Amount Money
+}
+
+// MoneyWeightedReturn returns the growth of a portfolio over a period at its internal rate of return: the
+// constant yearly rate at which its value at the start and the cash flows paid in during the period, each
+// grown from its date to the end, would be worth its value at the end. Unlike the time-weighted return, it
+// weights the return by how much money was invested when. A period that starts and ends on the same day
+// returns the value at the end over the money put in.
+func MoneyWeightedReturn(startValue Money, start Date, flows []CashFlow, endValue Money, end Date) (float64, error) {
+	if daysBetween(start, end) == 0 {
+		invested := float64(startValue.Minor)
+		for _, flow := range flows {
+			invested += float64(flow.Amount.Minor)
+		}
+		if invested <= 0 {
+			return 0, ErrNoReturn
+		}
+		return float64(endValue.Minor) / invested, nil
+	}
+
+	rate, err := internalRateOfReturn(startValue, start, flows, endValue, end)
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(1+rate, daysBetween(start, end)/daysPerYear), nil
+}
+
+// internalRateOfReturn finds the yearly rate that grows the start value and cash flows into the end value. It
+// bisects the growth between its bounds on a log scale, so it finds a rate whenever their future values fall
+// strictly either side of the end value at the bounds.
+func internalRateOfReturn(startValue Money, start Date, flows []CashFlow, endValue Money, end Date) (float64, error) {
+	// excess is how much more the money paid in would be worth at the end than the end value, growing at growth
+	excess := func(growth float64) float64 {
+		total := float64(startValue.Minor)*math.Pow(growth, daysBetween(start, end)/daysPerYear) - float64(endValue.Minor)
+		for _, flow := range flows {
+			total += float64(flow.Amount.Minor) * math.Pow(growth, daysBetween(flow.Date, end)/daysPerYear)
+		}
+		return total
+	}
+
+	lo, hi := math.Log(minYearlyGrowth), math.Log(maxYearlyGrowth)
+	excessLo, excessHi := excess(minYearlyGrowth), excess(maxYearlyGrowth)
+	if excessLo == 0 || excessHi == 0 || (excessLo < 0) == (excessHi < 0) {
+		// Nothing was invested, or the rate is outside the bounds
+		return 0, ErrNoReturn
+	}
+	for i := 0; i < 200 && hi-lo > 1e-15; i++ {
+		mid := (lo + hi) / 2
+		excessMid := excess(math.Exp(mid))
+		if excessMid == 0 {
+			return math.Exp(mid) - 1, nil
+		}
+		if (excessMid < 0) == (excessLo < 0) {
+			lo, excessLo = mid, excessMid
+		} else {
+			hi = mid
+		}
+	}
+	return math.Exp((lo+hi)/2) - 1, nil
+}
+
+// daysBetween returns the number of days from one date to a later one
+func daysBetween(from, to Date) float64 {
+	return math.Round(to.Sub(from.Time).Hours() / 24)
+}
+
+// percentFromRate converts a rate such as 0.05 into a percentage, rounded half away from zero to the nearest
+// thousandth of a percent
+func percentFromRate(rate float64) Percent {
+	return Percent(math.Round(rate * float64(OneHundredPercent)))
+}
+
+// FundReturns is a fund's time-weighted return over each standard period
+type FundReturns struct {
+	FundID   uint
+	FundName string
+	Returns  []PeriodReturn
+}
+
+// CustomerReturns is a customer's money-weighted return over each standard period
+type CustomerReturns struct {
+	CustomerID uint
+	// Value is the portfolio's value at the funds' latest prices
+	Value   Money
+	Returns []PeriodReturn
+}
+
+// FundReturnsResponse represents a fund's returns as sent in API responses
+type FundReturnsResponse struct {
+	FundID   uint           `json:"fund_id"`
+	FundName string         `json:"fund_name"`
+	Returns  []PeriodReturn `json:"returns"`
+}
+
+// CustomerReturnsResponse represents a customer's returns as sent in API responses
+type CustomerReturnsResponse struct {
+	CustomerID uint           `json:"customer_id"`
+	Value      Money          `json:"value"`
+	Returns    []PeriodReturn `json:"returns"`
+}
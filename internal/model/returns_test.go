@@ -0,0 +1,182 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestReturnPeriod_Start(t *testing.T) {
+	end := NewDate(2027, time.March, 31)
+	inception := NewDate(2026, time.June, 15)
+
+	tests := []struct {
+		period      ReturnPeriod
+		inception   Date
+		wantStart   Date
+		wantPartial bool
+	}{
+		{period: ReturnPeriodOneMonth, inception: inception, wantStart: NewDate(2027, time.February, 28)},
+		{period: ReturnPeriodThreeMonths, inception: inception, wantStart: NewDate(2026, time.December, 31)},
+		{period: ReturnPeriodYearToDate, inception: inception, wantStart: NewDate(2026, time.December, 31)},
+		{period: ReturnPeriodOneYear, inception: inception, wantStart: inception, wantPartial: true},
+		{period: ReturnPeriodOneYear, inception: NewDate(2020, time.January, 1), wantStart: NewDate(2026, time.March, 31)},
+		{period: ReturnPeriodSinceInception, inception: inception, wantStart: inception},
+		{period: ReturnPeriodYearToDate, inception: NewDate(2027, time.January, 4), wantStart: NewDate(2027, time.January, 4), wantPartial: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			start, partial := tt.period.Start(end, tt.inception)
+			if start != tt.wantStart || partial != tt.wantPartial {
+				t.Errorf("Start() = %s, %v, want %s, %v", start, partial, tt.wantStart, tt.wantPartial)
+			}
+		})
+	}
+}
+
+func TestNewPeriodReturn(t *testing.T) {
+	start := NewDate(2024, time.March, 31)
+	tenPercent := Percent(10000)
+
+	tests := []struct {
+		name           string
+		end            Date
+		growth         float64
+		wantReturn     Percent
+		wantAnnualised *Percent
+	}{
+		{name: "Under a year isn't annualised", end: NewDate(2024, time.September, 30), growth: 1.05, wantReturn: 5000},
+		{name: "Loss", end: NewDate(2024, time.April, 30), growth: 0.987654, wantReturn: -1235},
+		{name: "Two years", end: start.AddDays(2 * 365), growth: 1.21, wantReturn: 21000, wantAnnualised: &tenPercent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPeriodReturn(ReturnPeriodSinceInception, start, tt.end, false, tt.growth)
+			if got.Return != tt.wantReturn {
+				t.Errorf("Return = %s, want %s", got.Return, tt.wantReturn)
+			}
+			if (got.Annualised == nil) != (tt.wantAnnualised == nil) || (got.Annualised != nil && *got.Annualised != *tt.wantAnnualised) {
+				t.Errorf("Annualised = %v, want %v", got.Annualised, tt.wantAnnualised)
+			}
+		})
+	}
+}
+
+func TestTimeWeightedReturn(t *testing.T) {
+	// The fund rises 10% and then falls 10%, whatever was invested in between: 1.1 * 0.9 = 0.99
+	start := &FundPrice{Date: NewDate(2026, time.January, 2), NAV: 1000000}
+	end := &FundPrice{Date: NewDate(2026, time.March, 2), NAV: 990000}
+	if got := TimeWeightedReturn(start, end); math.Abs(got-0.99) > 1e-12 {
+		t.Errorf("TimeWeightedReturn() = %v, want 0.99", got)
+	}
+}
+
+func TestMoneyWeightedReturn(t *testing.T) {
+	gbp := func(pounds float64) Money { return NewMoney(int64(math.Round(pounds*100)), DefaultCurrency) }
+	start := NewDate(2024, time.January, 1)
+
+	tests := []struct {
+		name       string
+		startValue Money
+		start      Date
+		flows      []CashFlow
+		endValue   Money
+		end        Date
+		wantRate   float64
+		wantGrowth float64
+		wantErr    error
+	}{
+		{
+			// The example for Excel's XIRR: paid in once and taken out four times, returning 37.3362535% a year
+			name:       "Withdrawals",
+			startValue: gbp(10000),
+			start:      NewDate(2008, time.January, 1),
+			flows: []CashFlow{
+				{Date: NewDate(2008, time.March, 1), Amount: gbp(-2750)},
+				{Date: NewDate(2008, time.October, 30), Amount: gbp(-4250)},
+				{Date: NewDate(2009, time.February, 15), Amount: gbp(-3250)},
+			},
+			endValue:   gbp(2750),
+			end:        NewDate(2009, time.April, 1),
+			wantRate:   0.373362535,
+			wantGrowth: math.Pow(1.373362535, 456.0/365),
+		},
+		{
+			// Half the units are sold after a year of 10% growth and the rest grow 10% more: 1000g² - 550g = 605
+			name:       "Partial withdrawal at a constant rate",
+			startValue: gbp(1000),
+			start:      start,
+			flows:      []CashFlow{{Date: start.AddDays(365), Amount: gbp(-550)}},
+			endValue:   gbp(605),
+			end:        start.AddDays(730),
+			wantRate:   0.1,
+			wantGrowth: 1.21,
+		},
+		{
+			// The price doubles and halves again, so the time-weighted return is 0, but twice as much money was
+			// invested for the fall as for the rise: 1000g² + 1000g = 1500, g = (√7 - 1) / 2
+			name:       "Paid in before a fall",
+			startValue: gbp(1000),
+			start:      start,
+			flows:      []CashFlow{{Date: start.AddDays(365), Amount: gbp(1000)}},
+			endValue:   gbp(1500),
+			end:        start.AddDays(730),
+			wantRate:   (math.Sqrt(7)-1)/2 - 1,
+			wantGrowth: math.Pow((math.Sqrt(7)-1)/2, 2),
+		},
+		{
+			name:       "Invested during the period",
+			startValue: gbp(0),
+			start:      start,
+			flows:      []CashFlow{{Date: start, Amount: gbp(1000)}},
+			endValue:   gbp(1050),
+			end:        start.AddDays(365),
+			wantRate:   0.05,
+			wantGrowth: 1.05,
+		},
+		{
+			name:       "Same day",
+			startValue: gbp(1000),
+			start:      start,
+			endValue:   gbp(999.99),
+			end:        start,
+			wantGrowth: 0.99999,
+		},
+		{
+			name:       "Nothing invested",
+			startValue: gbp(0),
+			start:      start,
+			endValue:   gbp(0),
+			end:        start.AddDays(30),
+			wantErr:    ErrNoReturn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			growth, err := MoneyWeightedReturn(tt.startValue, tt.start, tt.flows, tt.endValue, tt.end)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("MoneyWeightedReturn() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MoneyWeightedReturn() unexpected error = %v", err)
+			}
+			if math.Abs(growth-tt.wantGrowth) > 1e-8 {
+				t.Errorf("MoneyWeightedReturn() = %.10f, want %.10f", growth, tt.wantGrowth)
+			}
+			if tt.start == tt.end {
+				return
+			}
+			rate, err := internalRateOfReturn(tt.startValue, tt.start, tt.flows, tt.endValue, tt.end)
+			if err != nil || math.Abs(rate-tt.wantRate) > 1e-8 {
+				t.Errorf("internalRateOfReturn() = %.10f, %v, want %.10f", rate, err, tt.wantRate)
+			}
+		})
+	}
+}
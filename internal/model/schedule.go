@@ -0,0 +1,198 @@
+package model
+
+import "time"
+
+// ScheduleFrequency is how often a contribution schedule pays in
+type ScheduleFrequency string
+
+const (
+	ScheduleFrequencyWeekly    ScheduleFrequency = "weekly"
+	ScheduleFrequencyMonthly   ScheduleFrequency = "monthly"
+	ScheduleFrequencyQuarterly ScheduleFrequency = "quarterly"
+	ScheduleFrequencyAnnually  ScheduleFrequency = "annually"
+)
+
+// Valid reports whether f is a known frequency
+func (f ScheduleFrequency) Valid() bool {
+	switch f {
+	case ScheduleFrequencyWeekly, ScheduleFrequencyMonthly, ScheduleFrequencyQuarterly, ScheduleFrequencyAnnually:
+		return true
+	}
+	return false
+}
+
+// months returns the number of months between runs, 0 for weekly schedules
+func (f ScheduleFrequency) months() int {
+	switch f {
+	case ScheduleFrequencyMonthly:
+		return 1
+	case ScheduleFrequencyQuarterly:
+		return 3
+	case ScheduleFrequencyAnnually:
+		return 12
+	}
+	return 0
+}
+
+// ScheduleStatus is the lifecycle stage of a contribution schedule
+type ScheduleStatus string
+
+const (
+	// ScheduleStatusActive schedules pay in on each run date
+	ScheduleStatusActive ScheduleStatus = "active"
+	// ScheduleStatusPaused schedules skip their run dates until they are resumed
+	ScheduleStatusPaused ScheduleStatus = "paused"
+	// ScheduleStatusEnded schedules have passed their end date. Ending is permanent.
+	ScheduleStatusEnded ScheduleStatus = "ended"
+)
+
+// ContributionSchedule is a customer's regular contribution, paid into a fund or, when FundID is nil, across
+// their target allocation. Runs fall on the start date and every period after it; monthly runs that start on a
+// day the month doesn't have fall on its last day instead.
+type ContributionSchedule struct {
+	ID         uint
+	CustomerID uint
+	FundID     *uint
+	Amount     Money
+	Frequency  ScheduleFrequency
+	StartDate  Date
+	// EndDate is the last date the schedule can run on, nil when it runs until it is stopped
+	EndDate *Date
+	Status  ScheduleStatus
+	// NextRunDate is the date of the next run that hasn't been paid in or skipped
+	NextRunDate Date
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ScheduleRunKey identifies one run of a contribution schedule. The contributions paying in a run record it, so
+// a run is never paid in twice.
+type ScheduleRunKey struct {
+	ScheduleID uint
+	RunDate    Date
+}
+
+// Pays reports whether an investment pays in the run
+func (k ScheduleRunKey) Pays(investment *Investment) bool {
+	return investment.ScheduleID == k.ScheduleID && investment.RunDate != nil && investment.RunDate.Equal(k.RunDate.Time)
+}
+
+// RunDate returns the date of the schedule's nth run, counting the start date as run 0
+func (s *ContributionSchedule) RunDate(n int) Date {
+	months := s.Frequency.months()
+	if months == 0 {
+		return s.StartDate.AddDays(7 * n)
+	}
+	return s.StartDate.AddMonths(months * n)
+}
+
+// Due reports whether the schedule has a run to pay in on or before today
+func (s *ContributionSchedule) Due(today Date) bool {
+	return s.Status == ScheduleStatusActive && !s.NextRunDate.After(today.Time)
+}
+
+// Advance moves the schedule on to the run after NextRunDate, ending it when that is past its end date
+func (s *ContributionSchedule) Advance() {
+	s.NextRunDate = s.RunDate(s.run(s.NextRunDate) + 1)
+	if s.EndDate != nil && s.NextRunDate.After(s.EndDate.Time) {
+		s.Status = ScheduleStatusEnded
+	}
+}
+
+// Resume reactivates a paused schedule, skipping the runs that fell while it was paused
+func (s *ContributionSchedule) Resume(today Date) {
+	s.Status = ScheduleStatusActive
+	for s.Status == ScheduleStatusActive && s.NextRunDate.Before(today.Time) {
+		s.Advance()
+	}
+}
+
+// run returns the number of the run that falls on date
+func (s *ContributionSchedule) run(date Date) int {
+	months := s.Frequency.months()
+	if months == 0 {
+		return int(date.Sub(s.StartDate.Time).Hours()/24) / 7
+	}
+	elapsed := (date.Year()-s.StartDate.Year())*12 + int(date.Month()-s.StartDate.Month())
+	return elapsed / months
+}
+
+// ContributionScheduleCreate represents the data needed to set up a contribution schedule. Contributions go
+// across the customer's target allocation when fund_id is absent.
+type ContributionScheduleCreate struct {
+	FundID    *uint             `json:"fund_id"`
+	Amount    Money             `json:"amount"`
+	Frequency ScheduleFrequency `json:"frequency"`
+	StartDate Date              `json:"start_date"`
+	EndDate   *Date             `json:"end_date"`
+}
+
+// ContributionScheduleResponse represents a contribution schedule as sent in API responses
+type ContributionScheduleResponse struct {
+	ID         uint              `json:"id"`
+	CustomerID uint              `json:"customer_id"`
+	FundID     *uint             `json:"fund_id,omitempty"`
+	Amount     Money             `json:"amount"`
+	Frequency  ScheduleFrequency `json:"frequency"`
+	StartDate  Date              `json:"start_date"`
+	EndDate    *Date             `json:"end_date,omitempty"`
+	Status     ScheduleStatus    `json:"status"`
+	// NextRunDate is absent once the schedule has ended
+	NextRunDate *Date     `json:"next_run_date,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ScheduleFilter restricts which contribution schedules are listed. The zero value matches every schedule.
+type ScheduleFilter struct {
+	CustomerID *uint
+	Status     *ScheduleStatus
+}
+
+// Matches reports whether a schedule passes the filter
+func (f ScheduleFilter) Matches(schedule *ContributionSchedule) bool {
+	if f.CustomerID != nil && schedule.CustomerID != *f.CustomerID {
+		return false
+	}
+	if f.Status != nil && schedule.Status != *f.Status {
+		return false
+	}
+	return true
+}
+
+// ScheduledContribution is the investments paid in for one run of a contribution schedule
+type ScheduledContribution struct {
+	ScheduleID  uint
+	RunDate     Date
+	Investments []*Investment
+}
+
+// ScheduleFailure reports why a contribution schedule's run couldn't be paid in, or was skipped
+type ScheduleFailure struct {
+	ScheduleID uint   `json:"schedule_id"`
+	CustomerID uint   `json:"customer_id"`
+	RunDate    Date   `json:"run_date"`
+	Error      string `json:"error"`
+}
+
+// ScheduleRun is the outcome of paying in every contribution schedule's runs that have fallen due
+type ScheduleRun struct {
+	Contributions []*ScheduledContribution
+	// Failed runs are held back to be retried, and Skipped runs were rejected for good and moved on from
+	Failed  []ScheduleFailure
+	Skipped []ScheduleFailure
+}
+
+// ScheduledContributionResponse represents a scheduled contribution as sent in API responses
+type ScheduledContributionResponse struct {
+	ScheduleID  uint                 `json:"schedule_id"`
+	RunDate     Date                 `json:"run_date"`
+	Investments []InvestmentResponse `json:"investments"`
+}
+
+// ScheduleRunResponse represents a schedule run as sent in API responses
+type ScheduleRunResponse struct {
+	Contributions []ScheduledContributionResponse `json:"contributions"`
+	Failed        []ScheduleFailure               `json:"failed"`
+	Skipped       []ScheduleFailure               `json:"skipped"`
+}
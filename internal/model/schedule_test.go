@@ -0,0 +1,133 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContributionSchedule_RunDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		frequency ScheduleFrequency
+		start     Date
+		want      []Date
+	}{
+		{
+			name:      "Weekly",
+			frequency: ScheduleFrequencyWeekly,
+			start:     NewDate(2026, time.December, 24),
+			want:      []Date{NewDate(2026, time.December, 24), NewDate(2026, time.December, 31), NewDate(2027, time.January, 7)},
+		},
+		{
+			name:      "Monthly from the last day of a long month",
+			frequency: ScheduleFrequencyMonthly,
+			start:     NewDate(2027, time.January, 31),
+			want:      []Date{NewDate(2027, time.January, 31), NewDate(2027, time.February, 28), NewDate(2027, time.March, 31), NewDate(2027, time.April, 30)},
+		},
+		{
+			name:      "Quarterly",
+			frequency: ScheduleFrequencyQuarterly,
+			start:     NewDate(2026, time.November, 30),
+			want:      []Date{NewDate(2026, time.November, 30), NewDate(2027, time.February, 28), NewDate(2027, time.May, 30)},
+		},
+		{
+			name:      "Annually from a leap day",
+			frequency: ScheduleFrequencyAnnually,
+			start:     NewDate(2028, time.February, 29),
+			want:      []Date{NewDate(2028, time.February, 29), NewDate(2029, time.February, 28), NewDate(2032, time.February, 29)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &ContributionSchedule{Frequency: tt.frequency, StartDate: tt.start}
+			runs := []int{0, 1, 2, 3}
+			if tt.frequency == ScheduleFrequencyAnnually {
+				runs = []int{0, 1, 4}
+			}
+			for i, want := range tt.want {
+				if got := schedule.RunDate(runs[i]); got != want {
+					t.Errorf("RunDate(%d) = %s, want %s", runs[i], got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestContributionSchedule_Advance(t *testing.T) {
+	endDate := NewDate(2027, time.March, 31)
+	schedule := &ContributionSchedule{
+		Frequency:   ScheduleFrequencyMonthly,
+		StartDate:   NewDate(2027, time.January, 31),
+		EndDate:     &endDate,
+		Status:      ScheduleStatusActive,
+		NextRunDate: NewDate(2027, time.January, 31),
+	}
+
+	// The February run falls on the 28th but March's goes back to the 31st
+	want := []Date{NewDate(2027, time.February, 28), NewDate(2027, time.March, 31)}
+	for _, next := range want {
+		schedule.Advance()
+		if schedule.NextRunDate != next || schedule.Status != ScheduleStatusActive {
+			t.Fatalf("Advance() = %s %s, want %s active", schedule.NextRunDate, schedule.Status, next)
+		}
+	}
+	schedule.Advance()
+	if schedule.Status != ScheduleStatusEnded {
+		t.Errorf("Advance() status = %s, want ended after the last run before the end date", schedule.Status)
+	}
+}
+
+func TestContributionSchedule_Resume(t *testing.T) {
+	endDate := NewDate(2027, time.February, 1)
+
+	tests := []struct {
+		name       string
+		endDate    *Date
+		today      Date
+		wantNext   Date
+		wantStatus ScheduleStatus
+	}{
+		{
+			name:       "Before the next run",
+			today:      NewDate(2027, time.January, 5),
+			wantNext:   NewDate(2027, time.January, 8),
+			wantStatus: ScheduleStatusActive,
+		},
+		{
+			name:       "On a run date",
+			today:      NewDate(2027, time.January, 15),
+			wantNext:   NewDate(2027, time.January, 15),
+			wantStatus: ScheduleStatusActive,
+		},
+		{
+			name:       "Runs missed while paused are skipped",
+			today:      NewDate(2027, time.January, 20),
+			wantNext:   NewDate(2027, time.January, 22),
+			wantStatus: ScheduleStatusActive,
+		},
+		{
+			name:       "Past the end date",
+			endDate:    &endDate,
+			today:      NewDate(2027, time.February, 3),
+			wantNext:   NewDate(2027, time.February, 5),
+			wantStatus: ScheduleStatusEnded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &ContributionSchedule{
+				Frequency:   ScheduleFrequencyWeekly,
+				StartDate:   NewDate(2027, time.January, 1),
+				EndDate:     tt.endDate,
+				Status:      ScheduleStatusPaused,
+				NextRunDate: NewDate(2027, time.January, 8),
+			}
+			schedule.Resume(tt.today)
+			if schedule.NextRunDate != tt.wantNext || schedule.Status != tt.wantStatus {
+				t.Errorf("Resume(%s) = %s %s, want %s %s", tt.today, schedule.NextRunDate, schedule.Status, tt.wantNext, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Switch moves money from one of a customer's funds to another. It is made of two transactions that are
+// stored together or not at all: Sell takes units out of the source fund and Buy invests the proceeds in
+// the target fund.
+type Switch struct {
+	ID         uint        `json:"id"`
+	ClientID   uint        `json:"client_id"`
+	FromFundID uint        `json:"from_fund_id"`
+	ToFundID   uint        `json:"to_fund_id"`
+	Sell       *Investment `json:"sell"`
+	Buy        *Investment `json:"buy"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// SwitchCreate represents the data needed to switch between funds. Exactly one of Amount, Units and
+// Percentage must be set; Percentage is a share of the units held in the source fund.
+type SwitchCreate struct {
+	ClientID   uint     `json:"client_id"`
+	FromFundID uint     `json:"from_fund_id"`
+	ToFundID   uint     `json:"to_fund_id"`
+	Amount     *Money   `json:"amount"`
+	Units      *Units   `json:"units"`
+	Percentage *Percent `json:"percentage"`
+}
+
+// SwitchResponse represents the switch data that will be sent in API responses
+type SwitchResponse struct {
+	ID         uint               `json:"id"`
+	ClientID   uint               `json:"client_id"`
+	FromFundID uint               `json:"from_fund_id"`
+	ToFundID   uint               `json:"to_fund_id"`
+	Sell       InvestmentResponse `json:"sell"`
+	Buy        InvestmentResponse `json:"buy"`
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// unitsScale is the number of decimal places kept for fund units
+const unitsScale = 6
+
+// priceScale is the number of decimal places kept for unit prices, in major currency units
+const priceScale = 6
+
+// ErrInvalidUnits is returned when a number of units cannot be parsed
+var ErrInvalidUnits = errors.New("invalid units")
+
+// ErrInvalidPrice is returned when a unit price cannot be parsed
+var ErrInvalidPrice = errors.New("invalid price")
+
+// Units is an exact number of fund units stored in millionths of a unit, e.g. 12.5 units is 12500000
+type Units int64
+
+// ParseUnits parses a decimal number of units such as "12.345678". More than six decimal places are rejected.
+func ParseUnits(s string) (Units, error) {
+	value, err := parseDecimal(s, unitsScale)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q: %v", ErrInvalidUnits, s, err)
+	}
+	return Units(value), nil
+}
+
+// String returns the units as a decimal string, e.g. "12.500000"
+func (u Units) String() string {
+	return formatDecimal(int64(u), unitsScale)
+}
+
+// MarshalJSON encodes the units as a decimal string
+func (u Units) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON decodes units from a JSON string or number such as "12.5" or 12.5
+func (u *Units) UnmarshalJSON(data []byte) error {
+	s, err := unquoteDecimal(data)
+	if err != nil {
+		return fmt.Errorf("%w: must be a decimal number", ErrInvalidUnits)
+	}
+
+	parsed, err := ParseUnits(s)
+	if err != nil {
+		return err
+	}
+
+	*u = parsed
+	return nil
+}
+
+// Price is an exact price of one fund unit stored in millionths of the major currency unit,
+// e.g. a NAV of 1.234567 GBP per unit is 1234567. The currency is held alongside the price.
+type Price int64
+
+// ParsePrice parses a decimal unit price such as "1.234567". More than six decimal places are rejected.
+func ParsePrice(s string) (Price, error) {
+	value, err := parseDecimal(s, priceScale)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q: %v", ErrInvalidPrice, s, err)
+	}
+	return Price(value), nil
+}
+
+// String returns the price as a decimal string, e.g. "1.234567"
+func (p Price) String() string {
+	return formatDecimal(int64(p), priceScale)
+}
+
+// MarshalJSON encodes the price as a decimal string
+func (p Price) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes a price from a JSON string or number such as "1.234567" or 1.234567
+func (p *Price) UnmarshalJSON(data []byte) error {
+	s, err := unquoteDecimal(data)
+	if err != nil {
+		return fmt.Errorf("%w: must be a decimal number", ErrInvalidPrice)
+	}
+
+	parsed, err := ParsePrice(s)
+	if err != nil {
+		return err
+	}
+
+	*p = parsed
+	return nil
+}
+
+// conversionFactor scales minor currency units times units per price into the stored representations:
+// 10^(unitsScale + priceScale - moneyScale)
+var conversionFactor = new(big.Int).Exp(big.NewInt(10), big.NewInt(unitsScale+priceScale-moneyScale), nil)
+
+// UnitsFor returns the number of units amount buys at this price, rounded towards zero so a customer
+// is never allocated more units than they paid for. The price must be positive.
+func (p Price) UnitsFor(amount Money) Units {
+	units := new(big.Int).Mul(big.NewInt(amount.Minor), conversionFactor)
+	units.Quo(units, big.NewInt(int64(p)))
+	return Units(units.Int64())
+}
+
+// UnitsToSell returns the number of units that must be sold at this price to raise amount, rounded up
+// so the units sold are always worth at least the amount paid out. The price must be positive.
+func (p Price) UnitsToSell(amount Money) Units {
+	units := new(big.Int).Mul(big.NewInt(amount.Minor), conversionFactor)
+	price := big.NewInt(int64(p))
+	units.Add(units, price)
+	units.Sub(units, big.NewInt(1))
+	units.Quo(units, price)
+	return Units(units.Int64())
+}
+
+// ValueOf returns what units are worth at this price in currency, rounded towards zero to the minor unit
+func (p Price) ValueOf(units Units, currency string) Money {
+	value := new(big.Int).Mul(big.NewInt(int64(units)), big.NewInt(int64(p)))
+	value.Quo(value, conversionFactor)
+	return Money{Minor: value.Int64(), Currency: currency}
+}
+
+// Share returns percent of these units, rounded towards zero so no more than percent is ever sold
+func (u Units) Share(percent Percent) Units {
+	share := new(big.Int).Mul(big.NewInt(int64(u)), big.NewInt(int64(percent)))
+	share.Quo(share, big.NewInt(int64(OneHundredPercent)))
+	return Units(share.Int64())
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestPrice_UnitsFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		price  Price
+		amount Money
+		want   Units
+	}{
+		{name: "Whole units", price: 2500000, amount: NewMoney(100000, "GBP"), want: 400000000},
+		{name: "Rounded down", price: 3000000, amount: NewMoney(100000, "GBP"), want: 333333333},
+		{name: "Price with six decimal places", price: 1234567, amount: NewMoney(123456789, "GBP"), want: 1000000720900},
+		{name: "Large amount doesn't overflow", price: 1000000, amount: NewMoney(100000000000000, "GBP"), want: 1000000000000000000},
+		{name: "Too small for a unit fraction", price: 200000000000, amount: NewMoney(1, "GBP"), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.price.UnitsFor(tt.amount); got != tt.want {
+				t.Errorf("UnitsFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrice_ValueOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		price Price
+		units Units
+		want  Money
+	}{
+		{name: "Whole units", price: 2500000, units: 400000000, want: NewMoney(100000, "GBP")},
+		{name: "Rounded down to the penny", price: 3000000, units: 333333333, want: NewMoney(99999, "GBP")},
+		{name: "Price rise", price: 2750000, units: 400000000, want: NewMoney(110000, "GBP")},
+		{name: "No units", price: 2500000, units: 0, want: NewMoney(0, "GBP")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.price.ValueOf(tt.units, "GBP"); got != tt.want {
+				t.Errorf("ValueOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitsAndPrice_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Units Units `json:"units"`
+		Price Price `json:"price"`
+	}{Units: 12500000, Price: 1234567})
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error = %v", err)
+	}
+	if string(data) != `{"units":"12.500000","price":"1.234567"}` {
+		t.Errorf("Marshal() = %s", data)
+	}
+
+	var price Price
+	if err := json.Unmarshal([]byte(`"1.5"`), &price); err != nil || price != 1500000 {
+		t.Errorf("Unmarshal() = %v, %v, want 1500000", price, err)
+	}
+	if err := json.Unmarshal([]byte(`"1.2345678"`), &price); !errors.Is(err, ErrInvalidPrice) {
+		t.Errorf("Unmarshal() with seven decimal places error = %v, want ErrInvalidPrice", err)
+	}
+
+	var units Units
+	if err := json.Unmarshal([]byte(`12.5`), &units); err != nil || units != 12500000 {
+		t.Errorf("Unmarshal() = %v, %v, want 12500000", units, err)
+	}
+	if err := json.Unmarshal([]byte(`"lots"`), &units); !errors.Is(err, ErrInvalidUnits) {
+		t.Errorf("Unmarshal() error = %v, want ErrInvalidUnits", err)
+	}
+}
+
+func TestPrice_UnitsToSell(t *testing.T) {
+	tests := []struct {
+		name   string
+		price  Price
+		amount Money
+		want   Units
+	}{
+		{name: "Whole units", price: 2500000, amount: NewMoney(100000, "GBP"), want: 400000000},
+		{name: "Rounded up", price: 3000000, amount: NewMoney(100000, "GBP"), want: 333333334},
+		{name: "Tiny amount still sells a unit fraction", price: 200000000000, amount: NewMoney(1, "GBP"), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.price.UnitsToSell(tt.amount)
+			if got != tt.want {
+				t.Errorf("UnitsToSell() = %v, want %v", got, tt.want)
+			}
+			if value := tt.price.ValueOf(got, "GBP"); value.Minor < tt.amount.Minor {
+				t.Errorf("%v units are worth %v, less than %v", got, value, tt.amount)
+			}
+		})
+	}
+}
+
+func TestUnits_Share(t *testing.T) {
+	tests := []struct {
+		name    string
+		units   Units
+		percent Percent
+		want    Units
+	}{
+		{name: "All units", units: 400000000, percent: OneHundredPercent, want: 400000000},
+		{name: "Half", units: 400000000, percent: 50000, want: 200000000},
+		{name: "Rounded down", units: 1000001, percent: 50000, want: 500000},
+		{name: "Fraction of a percent", units: 400000000, percent: 125, want: 500000},
+		{name: "Nothing", units: 400000000, percent: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.units.Share(tt.percent); got != tt.want {
+				t.Errorf("Share() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"cushon/internal/model"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrAllocationNotFound is returned when a customer hasn't chosen a target allocation
+var ErrAllocationNotFound = errors.New("target allocation not found")
+
+// AllocationRepository defines the contract for storing customers' target allocations. Implementations don't
+// check that the percentages add up or that the funds are open, that is up to the caller.
+type AllocationRepository interface {
+	GetAllocation(customerID uint) (*model.TargetAllocation, error)
+	ListAllocations() ([]*model.TargetAllocation, error)
+	SetAllocation(allocation *model.TargetAllocation) (*model.TargetAllocation, error)
+	DeleteAllocation(customerID uint) error
+}
+
+// InMemoryAllocationRepository is a simple in-memory implementation of AllocationRepository.
+// It is safe for concurrent use.
+type InMemoryAllocationRepository struct {
+	mu          sync.RWMutex
+	allocations map[uint]*model.TargetAllocation
+}
+
+// NewInMemoryAllocationRepository creates a new in-memory allocation repository
+func NewInMemoryAllocationRepository() *InMemoryAllocationRepository {
+	return &InMemoryAllocationRepository{
+		allocations: make(map[uint]*model.TargetAllocation),
+	}
+}
+
+// GetAllocation retrieves a customer's target allocation
+func (r *InMemoryAllocationRepository) GetAllocation(customerID uint) (*model.TargetAllocation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	allocation, exists := r.allocations[customerID]
+	if !exists {
+		return nil, ErrAllocationNotFound
+	}
+	return copyAllocation(allocation), nil
+}
+
+// ListAllocations retrieves every customer's target allocation, ordered by customer ID
+func (r *InMemoryAllocationRepository) ListAllocations() ([]*model.TargetAllocation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	allocations := make([]*model.TargetAllocation, 0, len(r.allocations))
+	for _, allocation := range r.allocations {
+		allocations = append(allocations, copyAllocation(allocation))
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		return allocations[i].CustomerID < allocations[j].CustomerID
+	})
+	return allocations, nil
+}
+
+// SetAllocation replaces a customer's target allocation
+func (r *InMemoryAllocationRepository) SetAllocation(allocation *model.TargetAllocation) (*model.TargetAllocation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyAllocation(allocation)
+	stored.EmployerDefault = false
+	stored.UpdatedAt = time.Now()
+	r.allocations[allocation.CustomerID] = stored
+
+	return copyAllocation(stored), nil
+}
+
+// DeleteAllocation removes a customer's target allocation
+func (r *InMemoryAllocationRepository) DeleteAllocation(customerID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.allocations[customerID]; !exists {
+		return ErrAllocationNotFound
+	}
+	delete(r.allocations, customerID)
+	return nil
+}
+
+// copyAllocation returns a copy of an allocation that shares nothing with it
+func copyAllocation(allocation *model.TargetAllocation) *model.TargetAllocation {
+	stored := *allocation
+	stored.Funds = append([]model.FundAllocation(nil), allocation.Funds...)
+	return &stored
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"cushon/internal/model"
+)
+
+func TestInMemoryAllocationRepository(t *testing.T) {
+	repo := NewInMemoryAllocationRepository()
+
+	if _, err := repo.GetAllocation(1); !errors.Is(err, ErrAllocationNotFound) {
+		t.Fatalf("GetAllocation() error = %v, want ErrAllocationNotFound", err)
+	}
+
+	funds := []model.FundAllocation{{FundID: 1, Percentage: 60000}, {FundID: 3, Percentage: 40000}}
+	set, err := repo.SetAllocation(&model.TargetAllocation{CustomerID: 1, Funds: funds, EmployerDefault: true})
+	if err != nil {
+		t.Fatalf("SetAllocation() error = %v", err)
+	}
+	if set.EmployerDefault || set.UpdatedAt.IsZero() {
+		t.Errorf("SetAllocation() = %+v, want a chosen allocation with UpdatedAt set", set)
+	}
+	funds[0].Percentage = 1
+	set.Funds[1].FundID = 99
+
+	got, err := repo.GetAllocation(1)
+	if err != nil {
+		t.Fatalf("GetAllocation() error = %v", err)
+	}
+	if len(got.Funds) != 2 || got.Funds[0] != (model.FundAllocation{FundID: 1, Percentage: 60000}) || got.Funds[1].FundID != 3 {
+		t.Errorf("GetAllocation() = %+v, want the allocation as it was stored", got.Funds)
+	}
+
+	if _, err := repo.SetAllocation(&model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 2, Percentage: model.OneHundredPercent}}}); err != nil {
+		t.Fatalf("SetAllocation() error = %v", err)
+	}
+	if replaced, _ := repo.GetAllocation(1); len(replaced.Funds) != 1 || replaced.Funds[0].FundID != 2 {
+		t.Errorf("GetAllocation() = %+v, want the replacement allocation", replaced.Funds)
+	}
+
+	if err := repo.DeleteAllocation(1); err != nil {
+		t.Fatalf("DeleteAllocation() error = %v", err)
+	}
+	if _, err := repo.GetAllocation(1); !errors.Is(err, ErrAllocationNotFound) {
+		t.Errorf("GetAllocation() error = %v, want ErrAllocationNotFound after deleting it", err)
+	}
+	if err := repo.DeleteAllocation(1); !errors.Is(err, ErrAllocationNotFound) {
+		t.Errorf("DeleteAllocation() error = %v, want ErrAllocationNotFound", err)
+	}
+
+	repo.SetAllocation(&model.TargetAllocation{CustomerID: 3, Funds: []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}}})
+	repo.SetAllocation(&model.TargetAllocation{CustomerID: 2, Funds: funds})
+	all, err := repo.ListAllocations()
+	if err != nil {
+		t.Fatalf("ListAllocations() error = %v", err)
+	}
+	if len(all) != 2 || all[0].CustomerID != 2 || all[1].CustomerID != 3 || len(all[0].Funds) != 2 {
+		t.Errorf("ListAllocations() = %+v, want customers 2 and 3's allocations in order", all)
+	}
+}
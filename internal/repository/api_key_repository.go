@@ -1,12 +1,16 @@
 package repository
 
+import "sync"
+
 // APIKeyRepository defines the interface for API key operations
 type APIKeyRepository interface {
 	ValidateKey(key string) bool
 }
 
-// InMemoryAPIKeyRepository implements APIKeyRepository using an in-memory store
+// InMemoryAPIKeyRepository implements APIKeyRepository using an in-memory store.
+// It is safe for concurrent use.
 type InMemoryAPIKeyRepository struct {
+	mu   sync.RWMutex
 	keys map[string]bool
 }
 
@@ -19,10 +23,16 @@ func NewInMemoryAPIKeyRepository() *InMemoryAPIKeyRepository {
 
 // ValidateKey implements the APIKeyRepository interface
 func (r *InMemoryAPIKeyRepository) ValidateKey(key string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	return r.keys[key]
 }
 
 // AddKey adds a new API key to the repository
 func (r *InMemoryAPIKeyRepository) AddKey(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	r.keys[key] = true
 }
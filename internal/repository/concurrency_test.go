@@ -0,0 +1,472 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"cushon/internal/model"
+)
+
+// These tests hammer every in-memory repository method from many goroutines at once.
+// Run them with `go test -race` so the race detector can spot unsynchronised access.
+
+const (
+	stressWorkers    = 16
+	stressIterations = 200
+)
+
+// runConcurrently calls fn from stressWorkers goroutines, stressIterations times each, and waits for them to finish
+func runConcurrently(fn func(worker, iteration int)) {
+	var wg sync.WaitGroup
+	for w := 0; w < stressWorkers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < stressIterations; i++ {
+				fn(worker, i)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// assertUniqueIDs fails the test if ids contains duplicates or doesn't hold want entries
+func assertUniqueIDs(t *testing.T, ids *sync.Map, want int) {
+	t.Helper()
+
+	count := 0
+	ids.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	if count != want {
+		t.Errorf("got %d unique IDs, want %d", count, want)
+	}
+}
+
+func TestInMemoryCustomerRepository_Concurrent(t *testing.T) {
+	repo := NewInMemoryCustomerRepository(NewInMemoryInvestmentRepository())
+	var ids sync.Map
+
+	runConcurrently(func(worker, iteration int) {
+		customer, err := repo.CreateCustomer(fmt.Sprintf("Customer %d-%d", worker, iteration), uintPtr(uint(worker)))
+		if err != nil {
+			t.Errorf("CreateCustomer() error = %v", err)
+			return
+		}
+		if _, loaded := ids.LoadOrStore(customer.ID, true); loaded {
+			t.Errorf("CreateCustomer() reused ID %d", customer.ID)
+		}
+
+		got, err := repo.GetCustomerByID(customer.ID)
+		if err != nil || got.Name != customer.Name {
+			t.Errorf("GetCustomerByID() = %v, %v, want %v", got, err, customer)
+		}
+
+		_, err = repo.UpdateCustomer(customer.ID, func(customer *model.Customer) error {
+			customer.Name += " (updated)"
+			return nil
+		})
+		if err != nil {
+			t.Errorf("UpdateCustomer() error = %v", err)
+		}
+		if _, err := repo.ListCustomers(model.CustomerFilter{RetailOnly: true}); err != nil {
+			t.Errorf("ListCustomers() error = %v", err)
+		}
+		if iteration%2 == 0 {
+			if err := repo.DeleteCustomerIfNoInvestments(customer.ID); err != nil {
+				t.Errorf("DeleteCustomerIfNoInvestments() error = %v", err)
+			}
+		}
+	})
+
+	assertUniqueIDs(t, &ids, stressWorkers*stressIterations)
+
+	customers, err := repo.ListCustomers(model.CustomerFilter{})
+	if err != nil {
+		t.Fatalf("ListCustomers() error = %v", err)
+	}
+	if len(customers) != stressWorkers*stressIterations/2 {
+		t.Errorf("got %d customers, want %d", len(customers), stressWorkers*stressIterations/2)
+	}
+}
+
+func TestInMemoryCustomerRepository_ConcurrentUpdates(t *testing.T) {
+	repo := NewInMemoryCustomerRepository(NewInMemoryInvestmentRepository())
+	customer, err := repo.CreateCustomer("Customer", nil)
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+
+	// Every update is made to what the last one stored, so none of them is lost
+	runConcurrently(func(worker, iteration int) {
+		_, err := repo.UpdateCustomer(customer.ID, func(customer *model.Customer) error {
+			customer.Name += "."
+			return nil
+		})
+		if err != nil {
+			t.Errorf("UpdateCustomer() error = %v", err)
+		}
+	})
+
+	got, err := repo.GetCustomerByID(customer.ID)
+	if err != nil {
+		t.Fatalf("GetCustomerByID() error = %v", err)
+	}
+	if want := len(customer.Name) + stressWorkers*stressIterations; len(got.Name) != want {
+		t.Errorf("name is %d characters long, want %d", len(got.Name), want)
+	}
+}
+
+func TestInMemoryEmployerRepository_Concurrent(t *testing.T) {
+	repo := NewInMemoryEmployerRepository()
+	var ids sync.Map
+
+	runConcurrently(func(worker, iteration int) {
+		employer, err := repo.CreateEmployer(fmt.Sprintf("Employer %d-%d", worker, iteration))
+		if err != nil {
+			t.Errorf("CreateEmployer() error = %v", err)
+			return
+		}
+		if _, loaded := ids.LoadOrStore(employer.ID, true); loaded {
+			t.Errorf("CreateEmployer() reused ID %d", employer.ID)
+		}
+
+		got, err := repo.GetEmployerByID(employer.ID)
+		if err != nil || got.Name != employer.Name {
+			t.Errorf("GetEmployerByID() = %v, %v, want %v", got, err, employer)
+		}
+
+		if _, err := repo.UpdateEmployer(employer.ID, employer.Name+" (updated)", nil, nil); err != nil {
+			t.Errorf("UpdateEmployer() error = %v", err)
+		}
+		if _, err := repo.ListEmployers(model.EmployerFilter{ActiveOnly: true}); err != nil {
+			t.Errorf("ListEmployers() error = %v", err)
+		}
+		if iteration%2 == 0 {
+			if _, err := repo.DeactivateEmployer(employer.ID); err != nil {
+				t.Errorf("DeactivateEmployer() error = %v", err)
+			}
+		}
+	})
+
+	assertUniqueIDs(t, &ids, stressWorkers*stressIterations)
+}
+
+func TestInMemoryFundRepository_Concurrent(t *testing.T) {
+	repo := NewInMemoryFundRepository()
+	var ids sync.Map
+
+	runConcurrently(func(worker, iteration int) {
+		if iteration%2 == 0 {
+			fund, err := repo.CreateFund(model.FundCreate{Name: fmt.Sprintf("Fund %d-%d", worker, iteration)})
+			if err != nil {
+				t.Errorf("CreateFund() error = %v", err)
+				return
+			}
+			if _, loaded := ids.LoadOrStore(fund.ID, true); loaded {
+				t.Errorf("CreateFund() reused ID %d", fund.ID)
+			}
+			return
+		}
+
+		if _, err := repo.ListFunds(model.FundFilter{}); err != nil {
+			t.Errorf("ListFunds(model.FundFilter{}) error = %v", err)
+		}
+		if _, err := repo.GetFundByID(uint(iteration)); err != nil && !errors.Is(err, ErrFundNotFound) {
+			t.Errorf("GetFundByID() error = %v", err)
+		}
+		if _, err := repo.UpdateFund(&model.Fund{ID: uint(iteration), Name: "Updated", Status: model.FundStatusClosed}); err != nil && !errors.Is(err, ErrFundNotFound) {
+			t.Errorf("UpdateFund() error = %v", err)
+		}
+	})
+
+	assertUniqueIDs(t, &ids, stressWorkers*stressIterations/2)
+
+	funds, err := repo.ListFunds(model.FundFilter{})
+	if err != nil {
+		t.Fatalf("ListFunds(model.FundFilter{}) error = %v", err)
+	}
+	if len(funds) != stressWorkers*stressIterations/2 {
+		t.Errorf("got %d funds, want %d", len(funds), stressWorkers*stressIterations/2)
+	}
+}
+
+func TestInMemoryInvestmentRepository_Concurrent(t *testing.T) {
+	repo := NewInMemoryInvestmentRepository()
+	var ids sync.Map
+	amount := model.NewMoney(100, model.DefaultCurrency)
+
+	runConcurrently(func(worker, iteration int) {
+		clientID := uint(worker + 1)
+
+		switch iteration % 3 {
+		case 0:
+			investment, err := repo.CreateInvestment(&model.Investment{ClientID: clientID, FundID: 1, Amount: amount})
+			if err != nil {
+				t.Errorf("CreateInvestment() error = %v", err)
+				return
+			}
+			if _, loaded := ids.LoadOrStore(investment.ID, true); loaded {
+				t.Errorf("CreateInvestment() reused ID %d", investment.ID)
+			}
+		case 1:
+			if _, err := repo.GetInvestmentByID(uint(iteration)); err != nil && !errors.Is(err, ErrInvestmentNotFound) {
+				t.Errorf("GetInvestmentByID() error = %v", err)
+			}
+		case 2:
+			if _, err := repo.GetInvestmentsByClientID(clientID); err != nil {
+				t.Errorf("GetInvestmentsByClientID() error = %v", err)
+			}
+		}
+	})
+
+	created := 0
+	for i := 0; i < stressIterations; i += 3 {
+		created++
+	}
+	assertUniqueIDs(t, &ids, stressWorkers*created)
+
+	for worker := 0; worker < stressWorkers; worker++ {
+		investments, err := repo.GetInvestmentsByClientID(uint(worker + 1))
+		if err != nil {
+			t.Fatalf("GetInvestmentsByClientID() error = %v", err)
+		}
+		if len(investments) != created {
+			t.Errorf("client %d has %d investments, want %d", worker+1, len(investments), created)
+		}
+	}
+}
+
+func TestInMemoryInvestmentRepository_ConcurrentWithdrawals(t *testing.T) {
+	repo := NewInMemoryInvestmentRepository()
+	if _, err := repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000}); err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+
+	var withdrawn sync.Map
+	runConcurrently(func(worker, iteration int) {
+		withdrawal, err := repo.CreateWithdrawal(&model.Investment{ClientID: 1, FundID: 1, Units: -1})
+		if err != nil {
+			if !errors.Is(err, ErrInsufficientUnits) {
+				t.Errorf("CreateWithdrawal() error = %v", err)
+			}
+			return
+		}
+		withdrawn.Store(withdrawal.ID, true)
+	})
+
+	// Only the 1000 units held can be withdrawn, however the withdrawals interleave
+	assertUniqueIDs(t, &withdrawn, 1000)
+}
+
+func TestInMemoryInvestmentRepository_ConcurrentISAInvestments(t *testing.T) {
+	repo := NewInMemoryInvestmentRepository()
+
+	var subscribed sync.Map
+	runConcurrently(func(worker, iteration int) {
+		investments, err := repo.CreateISAInvestments([]*model.Investment{{ClientID: 1, FundID: 1, Amount: model.NewMoney(1000, model.DefaultCurrency)}}, model.ISAAnnualAllowance)
+		if err != nil {
+			if !errors.Is(err, ErrISAAllowanceExceeded) {
+				t.Errorf("CreateISAInvestments() error = %v", err)
+			}
+			return
+		}
+		subscribed.Store(investments[0].ID, true)
+	})
+
+	// Only 2000 investments of 10.00 GBP fit in the allowance, however the investments interleave
+	assertUniqueIDs(t, &subscribed, 2000)
+}
+
+func TestInMemoryInvestmentRepository_ConcurrentSwitches(t *testing.T) {
+	repo := NewInMemoryInvestmentRepository()
+	if _, err := repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000}); err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+
+	// Every worker switches one unit back and forth between funds 1 and 2
+	runConcurrently(func(worker, iteration int) {
+		from, to := uint(1), uint(2)
+		if iteration%2 == 1 {
+			from, to = to, from
+		}
+		fundSwitch, err := repo.CreateSwitch(&model.Switch{
+			ClientID:   1,
+			FromFundID: from,
+			ToFundID:   to,
+			Sell:       &model.Investment{ClientID: 1, FundID: from, Units: -1},
+			Buy:        &model.Investment{ClientID: 1, FundID: to, Units: 1},
+		})
+		if err != nil {
+			if !errors.Is(err, ErrInsufficientUnits) {
+				t.Errorf("CreateSwitch() error = %v", err)
+			}
+			return
+		}
+		if _, err := repo.GetSwitchByID(fundSwitch.ID); err != nil {
+			t.Errorf("GetSwitchByID() error = %v", err)
+		}
+	})
+
+	// Units only ever move between the funds, so the total held never changes and no holding goes negative
+	investments, err := repo.GetInvestmentsByClientID(1)
+	if err != nil {
+		t.Fatalf("GetInvestmentsByClientID() error = %v", err)
+	}
+	held := map[uint]model.Units{}
+	for _, investment := range investments {
+		held[investment.FundID] += investment.Units
+	}
+	if held[1] < 0 || held[2] < 0 || held[1]+held[2] != 1000 {
+		t.Errorf("holdings = %v, want 1000 units split between funds 1 and 2", held)
+	}
+}
+
+func TestInMemoryInvestmentRepository_ConcurrentStatusUpdates(t *testing.T) {
+	repo := NewInMemoryInvestmentRepository()
+	investment, err := repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000})
+	if err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+
+	// Every worker races to either place or cancel the same pending investment, so exactly one of them wins
+	var won sync.Map
+	runConcurrently(func(worker, iteration int) {
+		to := model.InvestmentStatusPlaced
+		if worker%2 == 0 {
+			to = model.InvestmentStatusCancelled
+		}
+		if _, err := repo.UpdateInvestmentStatus([]uint{investment.ID}, model.InvestmentStatusPending, to); err != nil {
+			if !errors.Is(err, ErrInvestmentStatusChanged) {
+				t.Errorf("UpdateInvestmentStatus() error = %v", err)
+			}
+			return
+		}
+		won.Store(worker, true)
+		if _, err := repo.ListInvestments(model.InvestmentFilter{Status: &to}); err != nil {
+			t.Errorf("ListInvestments() error = %v", err)
+		}
+	})
+
+	assertUniqueIDs(t, &won, 1)
+}
+
+func TestInMemoryFundPriceRepository_Concurrent(t *testing.T) {
+	repo := NewInMemoryFundPriceRepository()
+	firstDate := model.NewDate(2026, time.January, 1)
+
+	runConcurrently(func(worker, iteration int) {
+		fundID := uint(worker + 1)
+
+		switch iteration % 3 {
+		case 0:
+			price := &model.FundPrice{FundID: fundID, Date: firstDate.AddDays(iteration), NAV: 1000000, Currency: "GBP"}
+			if _, err := repo.CreateFundPrice(price); err != nil {
+				t.Errorf("CreateFundPrice() error = %v", err)
+			}
+		case 1:
+			if _, err := repo.GetLatestFundPrice(fundID, firstDate.AddDays(iteration)); err != nil && !errors.Is(err, ErrFundPriceNotFound) {
+				t.Errorf("GetLatestFundPrice() error = %v", err)
+			}
+		case 2:
+			if _, err := repo.ListFundPrices(fundID, model.FundPriceFilter{}); err != nil {
+				t.Errorf("ListFundPrices() error = %v", err)
+			}
+		}
+	})
+
+	created := 0
+	for i := 0; i < stressIterations; i += 3 {
+		created++
+	}
+	for worker := 0; worker < stressWorkers; worker++ {
+		prices, err := repo.ListFundPrices(uint(worker+1), model.FundPriceFilter{})
+		if err != nil {
+			t.Fatalf("ListFundPrices() error = %v", err)
+		}
+		if len(prices) != created {
+			t.Errorf("fund %d has %d prices, want %d", worker+1, len(prices), created)
+		}
+	}
+}
+
+func TestInMemoryAPIKeyRepository_Concurrent(t *testing.T) {
+	repo := NewInMemoryAPIKeyRepository()
+
+	runConcurrently(func(worker, iteration int) {
+		key := fmt.Sprintf("key-%d-%d", worker, iteration)
+		repo.AddKey(key)
+		if !repo.ValidateKey(key) {
+			t.Errorf("ValidateKey(%q) = false after AddKey()", key)
+		}
+		repo.ValidateKey("unknown-key")
+	})
+}
+
+func TestInMemoryDealingRepository_ConcurrentRuns(t *testing.T) {
+	investments := NewInMemoryInvestmentRepository()
+	repo := NewInMemoryDealingRepository(investments)
+	order, err := investments.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000})
+	if err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+	date := model.NewDate(2026, time.October, 16)
+
+	// Every worker races to deal the same pending order, or to cancel it, so exactly one of them wins
+	var won sync.Map
+	runConcurrently(func(worker, iteration int) {
+		if worker%4 == 0 {
+			if _, err := investments.UpdateInvestmentStatus([]uint{order.ID}, model.InvestmentStatusPending, model.InvestmentStatusCancelled); err == nil {
+				won.Store(worker, true)
+			}
+			return
+		}
+
+		batch := &model.DealingBatch{FundID: 1, DealingDate: date, InvestmentIDs: []uint{order.ID}}
+		if _, err := repo.CreateDealingBatches([]*model.DealingBatch{batch}, []*model.Investment{order}, nil); err != nil {
+			if !errors.Is(err, ErrInvestmentStatusChanged) && !errors.Is(err, ErrDuplicateDealingBatch) {
+				t.Errorf("CreateDealingBatches() error = %v", err)
+			}
+			return
+		}
+		won.Store(worker, true)
+		if _, err := repo.ListDealingBatches(model.DealingBatchFilter{DealingDate: &date}); err != nil {
+			t.Errorf("ListDealingBatches() error = %v", err)
+		}
+	})
+
+	assertUniqueIDs(t, &won, 1)
+}
+
+func TestInMemoryIdempotencyRepository_Concurrent(t *testing.T) {
+	repo := NewInMemoryIdempotencyRepository()
+	now := time.Now()
+
+	// Every worker races to reserve the same key on each iteration, so each key is reserved exactly once
+	var reserved sync.Map
+	runConcurrently(func(worker, iteration int) {
+		key := fmt.Sprintf("key-%d", iteration)
+		record := &model.IdempotencyRecord{APIKey: "api-key", Key: key, RequestHash: "hash", ExpiresAt: now.Add(time.Hour)}
+		if _, err := repo.ReserveIdempotencyKey(record, now); err != nil {
+			if !errors.Is(err, ErrIdempotencyKeyExists) {
+				t.Errorf("ReserveIdempotencyKey() error = %v", err)
+			}
+			return
+		}
+		if _, loaded := reserved.LoadOrStore(key, worker); loaded {
+			t.Errorf("key %s reserved twice", key)
+		}
+		if err := repo.CompleteIdempotencyKey("api-key", key, &model.IdempotentResponse{StatusCode: 201}); err != nil {
+			t.Errorf("CompleteIdempotencyKey() error = %v", err)
+		}
+	})
+
+	assertUniqueIDs(t, &reserved, stressIterations)
+	if _, err := repo.DeleteExpiredIdempotencyKeys(now.Add(time.Hour)); err != nil {
+		t.Errorf("DeleteExpiredIdempotencyKeys() error = %v", err)
+	}
+}
@@ -3,25 +3,42 @@ package repository
 import (
 	"cushon/internal/model"
 	"errors"
+	"sort"
+	"sync"
 	"time"
 )
 
+// ErrCustomerNotFound is returned when a customer doesn't exist
+var ErrCustomerNotFound = errors.New("customer not found")
+
+// ErrCustomerHasInvestments is returned when deleting a customer that still has investments
+var ErrCustomerHasInvestments = errors.New("customer has investments")
+
 // CustomerRepository defines the contract for storing and retrieving user data.
 type CustomerRepository interface {
 	CreateCustomer(customerName string, employerID *uint) (*model.Customer, error)
+	GetCustomerByID(id uint) (*model.Customer, error)
+	ListCustomers(filter model.CustomerFilter) ([]*model.Customer, error)
+	UpdateCustomer(id uint, update func(customer *model.Customer) error) (*model.Customer, error)
+	DeleteCustomerIfNoInvestments(id uint) error
 }
 
 // InMemoryCustomerRepository is a simple in-memory implementation of CustomerRepository for demonstration.
+// It is safe for concurrent use.
 type InMemoryCustomerRepository struct {
-	customers map[uint]*model.Customer
-	nextID    uint
+	mu          sync.RWMutex
+	investments *InMemoryInvestmentRepository
+	customers   map[uint]*model.Customer
+	nextID      uint
 }
 
-// NewInMemoryCustomerRepository creates a new in-memory customer repository.
-func NewInMemoryCustomerRepository() *InMemoryCustomerRepository {
+// NewInMemoryCustomerRepository creates a new in-memory customer repository for the customers whose
+// transactions are kept in investments.
+func NewInMemoryCustomerRepository(investments *InMemoryInvestmentRepository) *InMemoryCustomerRepository {
 	return &InMemoryCustomerRepository{
-		customers: make(map[uint]*model.Customer),
-		nextID:    1,
+		investments: investments,
+		customers:   make(map[uint]*model.Customer),
+		nextID:      1,
 	}
 }
 
@@ -31,6 +48,9 @@ func (r *InMemoryCustomerRepository) CreateCustomer(customerName string, employe
 		return nil, errors.New("customer name cannot be empty")
 	}
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	now := time.Now()
 	customer := &model.Customer{
 		ID:         r.nextID,
@@ -43,5 +63,114 @@ func (r *InMemoryCustomerRepository) CreateCustomer(customerName string, employe
 	r.customers[customer.ID] = customer
 	r.nextID++
 
-	return customer, nil
+	return copyCustomer(customer), nil
+}
+
+// GetCustomerByID retrieves a customer by its ID
+func (r *InMemoryCustomerRepository) GetCustomerByID(id uint) (*model.Customer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	customer, exists := r.customers[id]
+	if !exists {
+		return nil, ErrCustomerNotFound
+	}
+	return copyCustomer(customer), nil
+}
+
+// ListCustomers retrieves the customers matching filter ordered by ID
+func (r *InMemoryCustomerRepository) ListCustomers(filter model.CustomerFilter) ([]*model.Customer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	customers := make([]*model.Customer, 0)
+	for _, customer := range r.customers {
+		if filter.Matches(customer) {
+			customers = append(customers, copyCustomer(customer))
+		}
+	}
+	sort.Slice(customers, func(i, j int) bool {
+		return customers[i].ID < customers[j].ID
+	})
+	return customers, nil
+}
+
+// UpdateCustomer passes a copy of a customer to update and stores the name, employer and retirement plan it
+// leaves, or nothing if update returns an error. The customer is locked until it is stored, so concurrent
+// updates can't overwrite each other's changes.
+func (r *InMemoryCustomerRepository) UpdateCustomer(id uint, update func(customer *model.Customer) error) (*model.Customer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	customer, exists := r.customers[id]
+	if !exists {
+		return nil, ErrCustomerNotFound
+	}
+
+	updated := copyCustomer(customer)
+	if err := update(updated); err != nil {
+		return nil, err
+	}
+	if updated.Name == "" {
+		return nil, errors.New("customer name cannot be empty")
+	}
+
+	// Copy again so update can't hold on to the stored fields
+	updated = copyCustomer(updated)
+	customer.Name = updated.Name
+	customer.EmployerID = updated.EmployerID
+	customer.RetirementPlan = updated.RetirementPlan
+	customer.UpdatedAt = time.Now()
+
+	return copyCustomer(customer), nil
+}
+
+// DeleteCustomerIfNoInvestments removes a customer, or fails with ErrCustomerHasInvestments if they have any
+// transactions. No transactions can be stored while it checks.
+func (r *InMemoryCustomerRepository) DeleteCustomerIfNoInvestments(id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.customers[id]; !exists {
+		return ErrCustomerNotFound
+	}
+
+	r.investments.mu.RLock()
+	defer r.investments.mu.RUnlock()
+	for _, investment := range r.investments.investments {
+		if investment.ClientID == id {
+			return ErrCustomerHasInvestments
+		}
+	}
+	delete(r.customers, id)
+	return nil
+}
+
+// copyCustomer returns a copy of a stored customer so callers can't modify the repository's data
+func copyCustomer(customer *model.Customer) *model.Customer {
+	c := *customer
+	if customer.EmployerID != nil {
+		employerID := *customer.EmployerID
+		c.EmployerID = &employerID
+	}
+	c.RetirementPlan = copyRetirementPlan(customer.RetirementPlan)
+	return &c
+}
+
+// copyRetirementPlan returns a copy of a retirement plan that shares none of its fields
+func copyRetirementPlan(plan model.RetirementPlan) model.RetirementPlan {
+	var c model.RetirementPlan
+	if plan.DateOfBirth != nil {
+		dateOfBirth := *plan.DateOfBirth
+		c.DateOfBirth = &dateOfBirth
+	}
+	if plan.RetirementAge != nil {
+		retirementAge := *plan.RetirementAge
+		c.RetirementAge = &retirementAge
+	}
+	if plan.GlidePathID != nil {
+		glidePathID := *plan.GlidePathID
+		c.GlidePathID = &glidePathID
+	}
+	return c
 }
@@ -3,6 +3,9 @@ package repository
 import (
 	"errors"
 	"testing"
+	"time"
+
+	"cushon/internal/model"
 )
 
 func TestInMemoryCustomerRepository_CreateCustomer(t *testing.T) {
@@ -34,7 +37,7 @@ func TestInMemoryCustomerRepository_CreateCustomer(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			repo := NewInMemoryCustomerRepository()
+			repo := NewInMemoryCustomerRepository(NewInMemoryInvestmentRepository())
 			got, err := repo.CreateCustomer(tt.customerName, tt.employerID)
 
 			if tt.wantErr != nil {
@@ -95,3 +98,250 @@ func TestInMemoryCustomerRepository_CreateCustomer(t *testing.T) {
 func uintPtr(n uint) *uint {
 	return &n
 }
+
+func TestInMemoryCustomerRepository_GetCustomerByID(t *testing.T) {
+	repo := NewInMemoryCustomerRepository(NewInMemoryInvestmentRepository())
+	created, err := repo.CreateCustomer("Jane Smith", uintPtr(1))
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      uint
+		wantErr error
+	}{
+		{
+			name:    "Existing customer",
+			id:      created.ID,
+			wantErr: nil,
+		},
+		{
+			name:    "Non-existent customer",
+			id:      999,
+			wantErr: ErrCustomerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetCustomerByID(tt.id)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetCustomerByID() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetCustomerByID() unexpected error = %v", err)
+			}
+			if got.ID != created.ID || got.Name != created.Name {
+				t.Errorf("GetCustomerByID() = %+v, want %+v", got, created)
+			}
+
+			// Modifying the returned customer must not change the stored one
+			*got.EmployerID = 2
+			stored, _ := repo.GetCustomerByID(tt.id)
+			if *stored.EmployerID != 1 {
+				t.Error("GetCustomerByID() returned a reference to the stored customer")
+			}
+		})
+	}
+}
+
+func TestInMemoryCustomerRepository_ListCustomers(t *testing.T) {
+	repo := NewInMemoryCustomerRepository(NewInMemoryInvestmentRepository())
+	for _, employerID := range []*uint{nil, uintPtr(1), uintPtr(2), uintPtr(1), nil} {
+		if _, err := repo.CreateCustomer("Customer", employerID); err != nil {
+			t.Fatalf("CreateCustomer() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		filter  model.CustomerFilter
+		wantIDs []uint
+	}{
+		{
+			name:    "All customers",
+			filter:  model.CustomerFilter{},
+			wantIDs: []uint{1, 2, 3, 4, 5},
+		},
+		{
+			name:    "Customers of an employer",
+			filter:  model.CustomerFilter{EmployerID: uintPtr(1)},
+			wantIDs: []uint{2, 4},
+		},
+		{
+			name:    "Retail customers",
+			filter:  model.CustomerFilter{RetailOnly: true},
+			wantIDs: []uint{1, 5},
+		},
+		{
+			name:    "Employer without customers",
+			filter:  model.CustomerFilter{EmployerID: uintPtr(99)},
+			wantIDs: []uint{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ListCustomers(tt.filter)
+			if err != nil {
+				t.Fatalf("ListCustomers() error = %v", err)
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("ListCustomers() returned %d customers, want %d", len(got), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if got[i].ID != id {
+					t.Errorf("customers[%d].ID = %v, want %v", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestInMemoryCustomerRepository_UpdateCustomer(t *testing.T) {
+	repo := NewInMemoryCustomerRepository(NewInMemoryInvestmentRepository())
+	created, err := repo.CreateCustomer("Jane Smith", uintPtr(1))
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+	dateOfBirth := model.NewDate(1970, time.June, 15)
+	retirementAge := 67
+
+	tests := []struct {
+		name         string
+		id           uint
+		customerName string
+		employerID   *uint
+		plan         model.RetirementPlan
+		wantErr      error
+	}{
+		{
+			name:         "Move to another employer",
+			id:           created.ID,
+			customerName: "Jane Doe",
+			employerID:   uintPtr(2),
+		},
+		{
+			name:         "Opt into a glide path",
+			id:           created.ID,
+			customerName: "Jane Doe",
+			employerID:   uintPtr(2),
+			plan:         model.RetirementPlan{DateOfBirth: &dateOfBirth, RetirementAge: &retirementAge, GlidePathID: uintPtr(1)},
+		},
+		{
+			name:         "Become a retail customer",
+			id:           created.ID,
+			customerName: "Jane Doe",
+			employerID:   nil,
+		},
+		{
+			name:         "Empty customer name",
+			id:           created.ID,
+			customerName: "",
+			wantErr:      errors.New("customer name cannot be empty"),
+		},
+		{
+			name:         "Non-existent customer",
+			id:           999,
+			customerName: "Jane Doe",
+			wantErr:      ErrCustomerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.UpdateCustomer(tt.id, func(customer *model.Customer) error {
+				customer.Name, customer.EmployerID, customer.RetirementPlan = tt.customerName, tt.employerID, tt.plan
+				return nil
+			})
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Errorf("UpdateCustomer() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UpdateCustomer() unexpected error = %v", err)
+			}
+			stored, _ := repo.GetCustomerByID(tt.id)
+			for _, customer := range []*model.Customer{got, stored} {
+				if customer.Name != tt.customerName {
+					t.Errorf("Name = %v, want %v", customer.Name, tt.customerName)
+				}
+				if (customer.EmployerID == nil) != (tt.employerID == nil) ||
+					(customer.EmployerID != nil && *customer.EmployerID != *tt.employerID) {
+					t.Errorf("EmployerID = %v, want %v", customer.EmployerID, tt.employerID)
+				}
+				if (customer.GlidePathID == nil) != (tt.plan.GlidePathID == nil) ||
+					(customer.DateOfBirth == nil) != (tt.plan.DateOfBirth == nil) ||
+					(customer.RetirementAge == nil) != (tt.plan.RetirementAge == nil) {
+					t.Errorf("RetirementPlan = %+v, want %+v", customer.RetirementPlan, tt.plan)
+				}
+				if !customer.CreatedAt.Equal(created.CreatedAt) || customer.UpdatedAt.Before(created.UpdatedAt) {
+					t.Errorf("UpdateCustomer() timestamps = %v, %v", customer.CreatedAt, customer.UpdatedAt)
+				}
+			}
+		})
+	}
+}
+
+func TestInMemoryCustomerRepository_UpdateCustomer_Rejected(t *testing.T) {
+	repo := NewInMemoryCustomerRepository(NewInMemoryInvestmentRepository())
+	created, err := repo.CreateCustomer("Jane Smith", uintPtr(1))
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+
+	rejected := errors.New("employer inactive")
+	_, err = repo.UpdateCustomer(created.ID, func(customer *model.Customer) error {
+		customer.Name = "Jane Doe"
+		return rejected
+	})
+	if !errors.Is(err, rejected) {
+		t.Fatalf("UpdateCustomer() error = %v, want %v", err, rejected)
+	}
+	if stored, _ := repo.GetCustomerByID(created.ID); stored.Name != created.Name {
+		t.Errorf("Name = %v after a rejected update, want %v", stored.Name, created.Name)
+	}
+}
+
+func TestInMemoryCustomerRepository_DeleteCustomerIfNoInvestments(t *testing.T) {
+	investments := NewInMemoryInvestmentRepository()
+	repo := NewInMemoryCustomerRepository(investments)
+	invested, err := repo.CreateCustomer("Jane Smith", nil)
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+	created, err := repo.CreateCustomer("John Doe", nil)
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+	if _, err := investments.CreateInvestment(&model.Investment{ClientID: invested.ID, FundID: 1, Amount: model.NewMoney(10000, "GBP")}); err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+
+	if err := repo.DeleteCustomerIfNoInvestments(invested.ID); !errors.Is(err, ErrCustomerHasInvestments) {
+		t.Errorf("DeleteCustomerIfNoInvestments() error = %v, want ErrCustomerHasInvestments", err)
+	}
+	if _, err := repo.GetCustomerByID(invested.ID); err != nil {
+		t.Errorf("GetCustomerByID() error = %v, want the customer with investments kept", err)
+	}
+
+	if err := repo.DeleteCustomerIfNoInvestments(created.ID); err != nil {
+		t.Fatalf("DeleteCustomerIfNoInvestments() error = %v", err)
+	}
+	if _, err := repo.GetCustomerByID(created.ID); !errors.Is(err, ErrCustomerNotFound) {
+		t.Errorf("GetCustomerByID() after delete error = %v, want ErrCustomerNotFound", err)
+	}
+	if err := repo.DeleteCustomerIfNoInvestments(created.ID); !errors.Is(err, ErrCustomerNotFound) {
+		t.Errorf("DeleteCustomerIfNoInvestments() twice error = %v, want ErrCustomerNotFound", err)
+	}
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"cushon/internal/model"
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrDealingBatchNotFound is returned when a dealing batch doesn't exist
+var ErrDealingBatchNotFound = errors.New("dealing batch not found")
+
+// ErrDuplicateDealingBatch is returned when a fund has already been dealt on the dealing date of a new batch
+var ErrDuplicateDealingBatch = errors.New("fund has already been dealt on this date")
+
+// DealingRepository defines the contract for storing and retrieving the batches pending orders are dealt in
+type DealingRepository interface {
+	CreateDealingBatches(batches []*model.DealingBatch, orders, failed []*model.Investment) ([]*model.DealingBatch, error)
+	GetDealingBatchByID(id uint) (*model.DealingBatch, error)
+	ListDealingBatches(filter model.DealingBatchFilter) ([]*model.DealingBatch, error)
+}
+
+// InMemoryDealingRepository is a simple in-memory implementation of DealingRepository that deals the orders
+// held by an InMemoryInvestmentRepository. It is safe for concurrent use.
+type InMemoryDealingRepository struct {
+	// investments' lock also guards the batches so orders and their batches are written together
+	investments *InMemoryInvestmentRepository
+	batches     map[uint]*model.DealingBatch
+	nextID      uint
+}
+
+// NewInMemoryDealingRepository creates a new in-memory dealing repository for the orders in investments
+func NewInMemoryDealingRepository(investments *InMemoryInvestmentRepository) *InMemoryDealingRepository {
+	return &InMemoryDealingRepository{
+		investments: investments,
+		batches:     make(map[uint]*model.DealingBatch),
+		nextID:      1,
+	}
+}
+
+// CreateDealingBatches stores the batches, places the orders dealt in them and fails the orders that couldn't be
+// dealt, or does none of these when any order is no longer pending, any fund has already been dealt on the
+// batch's date or a customer would be left holding fewer dealt units than they have sold, which fails with
+// ErrInsufficientUnits. Each order is placed in the batch for its fund with the amount, units and price it was
+// dealt at. The batches' IDs are assigned by the repository.
+func (r *InMemoryDealingRepository) CreateDealingBatches(batches []*model.DealingBatch, orders, failed []*model.Investment) ([]*model.DealingBatch, error) {
+	r.investments.mu.Lock()
+	defer r.investments.mu.Unlock()
+
+	for _, batch := range batches {
+		for _, existing := range r.batches {
+			if existing.FundID == batch.FundID && existing.DealingDate.Equal(batch.DealingDate.Time) {
+				return nil, ErrDuplicateDealingBatch
+			}
+		}
+	}
+	for _, group := range [][]*model.Investment{orders, failed} {
+		for _, order := range group {
+			investment, exists := r.investments.investments[order.ID]
+			if !exists {
+				return nil, ErrInvestmentNotFound
+			}
+			if investment.Status != model.InvestmentStatusPending {
+				return nil, ErrInvestmentStatusChanged
+			}
+		}
+	}
+	type holding struct{ clientID, fundID uint }
+	dealt := make(map[holding]model.Units)
+	for _, order := range orders {
+		investment := r.investments.investments[order.ID]
+		dealt[holding{investment.ClientID, investment.FundID}] += order.Units
+	}
+	for h, units := range dealt {
+		if r.investments.unitsDealt(h.clientID, h.fundID)+units < 0 {
+			return nil, ErrInsufficientUnits
+		}
+	}
+
+	now := time.Now()
+	batchIDs := make(map[uint]uint, len(batches))
+	created := make([]*model.DealingBatch, len(batches))
+	for i, batch := range batches {
+		stored := *batch
+		stored.ID = r.nextID
+		stored.InvestmentIDs = append([]uint(nil), batch.InvestmentIDs...)
+		stored.CreatedAt = now
+		r.batches[stored.ID] = &stored
+		r.nextID++
+
+		batchIDs[stored.FundID] = stored.ID
+		created[i] = copyDealingBatch(&stored)
+	}
+	for _, order := range orders {
+		investment := r.investments.investments[order.ID]
+		investment.Amount = order.Amount
+		investment.Units = order.Units
+		investment.Price = order.Price
+		investment.PriceDate = order.PriceDate
+		investment.Status = model.InvestmentStatusPlaced
+		investment.BatchID = batchIDs[investment.FundID]
+		investment.UpdatedAt = now
+	}
+	for _, order := range failed {
+		investment := r.investments.investments[order.ID]
+		investment.Status = model.InvestmentStatusFailed
+		investment.UpdatedAt = now
+	}
+	return created, nil
+}
+
+// GetDealingBatchByID retrieves a dealing batch by its ID
+func (r *InMemoryDealingRepository) GetDealingBatchByID(id uint) (*model.DealingBatch, error) {
+	r.investments.mu.RLock()
+	defer r.investments.mu.RUnlock()
+
+	batch, exists := r.batches[id]
+	if !exists {
+		return nil, ErrDealingBatchNotFound
+	}
+	return copyDealingBatch(batch), nil
+}
+
+// ListDealingBatches retrieves the batches matching filter ordered by ID
+func (r *InMemoryDealingRepository) ListDealingBatches(filter model.DealingBatchFilter) ([]*model.DealingBatch, error) {
+	r.investments.mu.RLock()
+	defer r.investments.mu.RUnlock()
+
+	batches := make([]*model.DealingBatch, 0)
+	for _, batch := range r.batches {
+		if filter.Matches(batch) {
+			batches = append(batches, copyDealingBatch(batch))
+		}
+	}
+	sort.Slice(batches, func(i, j int) bool {
+		return batches[i].ID < batches[j].ID
+	})
+	return batches, nil
+}
+
+// copyDealingBatch returns a copy of batch that doesn't share its investment IDs
+func copyDealingBatch(batch *model.DealingBatch) *model.DealingBatch {
+	stored := *batch
+	stored.InvestmentIDs = append([]uint(nil), batch.InvestmentIDs...)
+	return &stored
+}
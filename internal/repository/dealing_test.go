@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"cushon/internal/model"
+)
+
+// newTestBatch returns a batch dealing fund 1 on a Friday at 2.00 a unit
+func newTestBatch(investmentIDs ...uint) *model.DealingBatch {
+	return &model.DealingBatch{
+		FundID:        1,
+		DealingDate:   model.NewDate(2026, time.October, 16),
+		Price:         2000000,
+		Bought:        model.NewMoney(10000, model.DefaultCurrency),
+		Sold:          model.NewMoney(0, model.DefaultCurrency),
+		UnitsBought:   50000000,
+		InvestmentIDs: investmentIDs,
+	}
+}
+
+func TestInMemoryDealingRepository_CreateDealingBatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(*InMemoryInvestmentRepository, *InMemoryDealingRepository) uint
+		wantErr error
+	}{
+		{
+			name: "Place a pending order",
+			setup: func(investments *InMemoryInvestmentRepository, _ *InMemoryDealingRepository) uint {
+				inv, _ := investments.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000})
+				return inv.ID
+			},
+		},
+		{
+			name: "Order no longer pending",
+			setup: func(investments *InMemoryInvestmentRepository, _ *InMemoryDealingRepository) uint {
+				inv, _ := investments.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000})
+				investments.UpdateInvestmentStatus([]uint{inv.ID}, model.InvestmentStatusPending, model.InvestmentStatusCancelled)
+				return inv.ID
+			},
+			wantErr: ErrInvestmentStatusChanged,
+		},
+		{
+			name: "Unknown order",
+			setup: func(*InMemoryInvestmentRepository, *InMemoryDealingRepository) uint {
+				return 999
+			},
+			wantErr: ErrInvestmentNotFound,
+		},
+		{
+			name: "Fund already dealt on the day",
+			setup: func(investments *InMemoryInvestmentRepository, dealing *InMemoryDealingRepository) uint {
+				first, _ := investments.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000})
+				dealing.CreateDealingBatches([]*model.DealingBatch{newTestBatch(first.ID)}, []*model.Investment{first}, nil)
+				inv, _ := investments.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000})
+				return inv.ID
+			},
+			wantErr: ErrDuplicateDealingBatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			investments := NewInMemoryInvestmentRepository()
+			repo := NewInMemoryDealingRepository(investments)
+			id := tt.setup(investments, repo)
+			batchesBefore, _ := repo.ListDealingBatches(model.DealingBatchFilter{})
+
+			order := &model.Investment{
+				ID:        id,
+				FundID:    1,
+				Amount:    model.NewMoney(10000, model.DefaultCurrency),
+				Units:     50000000,
+				Price:     2000000,
+				PriceDate: model.NewDate(2026, time.October, 16),
+			}
+			got, err := repo.CreateDealingBatches([]*model.DealingBatch{newTestBatch(id)}, []*model.Investment{order}, nil)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateDealingBatches() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				// Nothing is written when any order can't be placed
+				batchesAfter, _ := repo.ListDealingBatches(model.DealingBatchFilter{})
+				if len(batchesAfter) != len(batchesBefore) {
+					t.Errorf("got %d batches after a failed run, want %d", len(batchesAfter), len(batchesBefore))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateDealingBatches() unexpected error = %v", err)
+			}
+			if len(got) != 1 || got[0].ID == 0 || !reflect.DeepEqual(got[0].InvestmentIDs, []uint{id}) {
+				t.Fatalf("CreateDealingBatches() = %+v, want one batch holding investment %d", got, id)
+			}
+
+			placed, _ := investments.GetInvestmentByID(id)
+			if placed.Status != model.InvestmentStatusPlaced || placed.BatchID != got[0].ID || placed.Units != order.Units ||
+				placed.Amount != order.Amount || placed.Price != order.Price || !placed.PriceDate.Equal(order.PriceDate.Time) {
+				t.Errorf("investment after dealing = %+v, want it placed in batch %d at %v", placed, got[0].ID, order.Price)
+			}
+
+			stored, err := repo.GetDealingBatchByID(got[0].ID)
+			if err != nil {
+				t.Fatalf("GetDealingBatchByID() error = %v", err)
+			}
+			if !reflect.DeepEqual(stored, got[0]) {
+				t.Errorf("GetDealingBatchByID() = %+v, want %+v", stored, got[0])
+			}
+		})
+	}
+}
+
+func TestInMemoryDealingRepository_CreateDealingBatches_Holdings(t *testing.T) {
+	// £100 is invested at 1.00 a unit and all 100 units withdrawn, then the investment is dealt at 2.00 a unit
+	tests := []struct {
+		name       string
+		sellFailed bool
+		wantErr    error
+		wantStatus [2]model.InvestmentStatus
+	}{
+		{
+			name:       "Placing a sell of more units than were dealt",
+			wantErr:    ErrInsufficientUnits,
+			wantStatus: [2]model.InvestmentStatus{model.InvestmentStatusPending, model.InvestmentStatusPending},
+		},
+		{
+			name:       "Failing the sell instead",
+			sellFailed: true,
+			wantStatus: [2]model.InvestmentStatus{model.InvestmentStatusPlaced, model.InvestmentStatusFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			investments := NewInMemoryInvestmentRepository()
+			repo := NewInMemoryDealingRepository(investments)
+			buy, _ := investments.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(10000, model.DefaultCurrency), Units: 100000000})
+			sell, err := investments.CreateWithdrawal(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(-10000, model.DefaultCurrency), Units: -100000000})
+			if err != nil {
+				t.Fatalf("CreateWithdrawal() error = %v", err)
+			}
+
+			bought, sold := *buy, *sell
+			bought.Units, bought.Price = 50000000, 2000000
+			sold.Amount, sold.Price = model.NewMoney(-20000, model.DefaultCurrency), 2000000
+			batch := newTestBatch(buy.ID, sell.ID)
+			orders, failed := []*model.Investment{&bought, &sold}, []*model.Investment(nil)
+			if tt.sellFailed {
+				batch = newTestBatch(buy.ID)
+				orders, failed = []*model.Investment{&bought}, []*model.Investment{&sold}
+			}
+
+			_, err = repo.CreateDealingBatches([]*model.DealingBatch{batch}, orders, failed)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateDealingBatches() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for i, id := range []uint{buy.ID, sell.ID} {
+				investment, _ := investments.GetInvestmentByID(id)
+				if investment.Status != tt.wantStatus[i] {
+					t.Errorf("investment %d status = %v, want %v", id, investment.Status, tt.wantStatus[i])
+				}
+			}
+			if held := investments.unitsDealt(1, 1); held < 0 {
+				t.Errorf("customer holds %v dealt units, want none sold that weren't dealt", held)
+			}
+		})
+	}
+}
+
+func TestInMemoryDealingRepository_ListDealingBatches(t *testing.T) {
+	investments := NewInMemoryInvestmentRepository()
+	repo := NewInMemoryDealingRepository(investments)
+
+	friday := model.NewDate(2026, time.October, 16)
+	for day := 0; day < 2; day++ {
+		for fundID := uint(1); fundID <= 2; fundID++ {
+			batch := newTestBatch()
+			batch.FundID = fundID
+			batch.DealingDate = friday.AddDays(day * 3)
+			if _, err := repo.CreateDealingBatches([]*model.DealingBatch{batch}, nil, nil); err != nil {
+				t.Fatalf("CreateDealingBatches() error = %v", err)
+			}
+		}
+	}
+
+	fundID := uint(2)
+	monday := friday.AddDays(3)
+	tests := []struct {
+		name    string
+		filter  model.DealingBatchFilter
+		wantIDs []uint
+	}{
+		{name: "All batches", filter: model.DealingBatchFilter{}, wantIDs: []uint{1, 2, 3, 4}},
+		{name: "By dealing date", filter: model.DealingBatchFilter{DealingDate: &monday}, wantIDs: []uint{3, 4}},
+		{name: "By fund", filter: model.DealingBatchFilter{FundID: &fundID}, wantIDs: []uint{2, 4}},
+		{name: "By dealing date and fund", filter: model.DealingBatchFilter{DealingDate: &friday, FundID: &fundID}, wantIDs: []uint{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ListDealingBatches(tt.filter)
+			if err != nil {
+				t.Fatalf("ListDealingBatches() error = %v", err)
+			}
+			ids := make([]uint, len(got))
+			for i, batch := range got {
+				ids[i] = batch.ID
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ListDealingBatches() IDs = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestInMemoryDealingRepository_GetDealingBatchByID_NotFound(t *testing.T) {
+	repo := NewInMemoryDealingRepository(NewInMemoryInvestmentRepository())
+	if _, err := repo.GetDealingBatchByID(1); !errors.Is(err, ErrDealingBatchNotFound) {
+		t.Errorf("GetDealingBatchByID() error = %v, want %v", err, ErrDealingBatchNotFound)
+	}
+}
@@ -3,15 +3,27 @@ package repository
 import (
 	"cushon/internal/model"
 	"errors"
+	"sort"
+	"sync"
+	"time"
 )
 
+// ErrEmployerNotFound is returned when an employer doesn't exist
+var ErrEmployerNotFound = errors.New("employer not found")
+
 // EmployerRepository defines the contract for storing and retrieving employer data
 type EmployerRepository interface {
 	CreateEmployer(name string) (*model.Employer, error)
+	GetEmployerByID(id uint) (*model.Employer, error)
+	ListEmployers(filter model.EmployerFilter) ([]*model.Employer, error)
+	UpdateEmployer(id uint, name string, scheme *model.ContributionScheme, defaultFundID *uint) (*model.Employer, error)
+	DeactivateEmployer(id uint) (*model.Employer, error)
 }
 
-// InMemoryEmployerRepository is a simple in-memory implementation of EmployerRepository
+// InMemoryEmployerRepository is a simple in-memory implementation of EmployerRepository.
+// It is safe for concurrent use.
 type InMemoryEmployerRepository struct {
+	mu        sync.RWMutex
 	employers map[uint]*model.Employer
 	nextID    uint
 }
@@ -30,13 +42,118 @@ func (r *InMemoryEmployerRepository) CreateEmployer(name string) (*model.Employe
 		return nil, errors.New("employer name cannot be empty")
 	}
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
 	employer := &model.Employer{
-		ID:   r.nextID,
-		Name: name,
+		ID:        r.nextID,
+		Name:      name,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 
 	r.employers[employer.ID] = employer
 	r.nextID++
 
-	return employer, nil
+	return copyEmployer(employer), nil
+}
+
+// GetEmployerByID retrieves an employer by its ID
+func (r *InMemoryEmployerRepository) GetEmployerByID(id uint) (*model.Employer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	employer, exists := r.employers[id]
+	if !exists {
+		return nil, ErrEmployerNotFound
+	}
+	return copyEmployer(employer), nil
+}
+
+// ListEmployers retrieves the employers matching filter ordered by ID
+func (r *InMemoryEmployerRepository) ListEmployers(filter model.EmployerFilter) ([]*model.Employer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	employers := make([]*model.Employer, 0)
+	for _, employer := range r.employers {
+		if filter.Matches(employer) {
+			employers = append(employers, copyEmployer(employer))
+		}
+	}
+	sort.Slice(employers, func(i, j int) bool {
+		return employers[i].ID < employers[j].ID
+	})
+	return employers, nil
+}
+
+// UpdateEmployer renames an employer and replaces its contribution scheme and default fund. A nil scheme or
+// default fund removes it.
+func (r *InMemoryEmployerRepository) UpdateEmployer(id uint, name string, scheme *model.ContributionScheme, defaultFundID *uint) (*model.Employer, error) {
+	if name == "" {
+		return nil, errors.New("employer name cannot be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	employer, exists := r.employers[id]
+	if !exists {
+		return nil, ErrEmployerNotFound
+	}
+	employer.Name = name
+	employer.ContributionScheme = copyContributionScheme(scheme)
+	employer.DefaultFundID = copyID(defaultFundID)
+	employer.UpdatedAt = time.Now()
+
+	return copyEmployer(employer), nil
+}
+
+// DeactivateEmployer marks an employer as no longer active. Deactivating an inactive employer has no effect.
+func (r *InMemoryEmployerRepository) DeactivateEmployer(id uint) (*model.Employer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	employer, exists := r.employers[id]
+	if !exists {
+		return nil, ErrEmployerNotFound
+	}
+	if employer.Active {
+		employer.Active = false
+		employer.UpdatedAt = time.Now()
+	}
+
+	return copyEmployer(employer), nil
+}
+
+// copyEmployer returns a copy of an employer that shares nothing with it
+func copyEmployer(employer *model.Employer) *model.Employer {
+	stored := *employer
+	stored.ContributionScheme = copyContributionScheme(employer.ContributionScheme)
+	stored.DefaultFundID = copyID(employer.DefaultFundID)
+	return &stored
+}
+
+// copyID returns a copy of an optional ID
+func copyID(id *uint) *uint {
+	if id == nil {
+		return nil
+	}
+	stored := *id
+	return &stored
+}
+
+// copyContributionScheme returns a copy of a contribution scheme that shares nothing with it, or nil for no scheme
+func copyContributionScheme(scheme *model.ContributionScheme) *model.ContributionScheme {
+	if scheme == nil {
+		return nil
+	}
+	stored := *scheme
+	if scheme.EmployerCap != nil {
+		employerCap := *scheme.EmployerCap
+		stored.EmployerCap = &employerCap
+	}
+	return &stored
 }
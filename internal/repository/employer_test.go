@@ -59,6 +59,9 @@ func TestInMemoryEmployerRepository_Create(t *testing.T) {
 			if got.Name != tt.wantEmp.Name {
 				t.Errorf("Name = %v, want %v", got.Name, tt.wantEmp.Name)
 			}
+			if !got.Active {
+				t.Error("Create() returned an inactive employer")
+			}
 
 			// Verify the employer was stored
 			stored, exists := repo.employers[got.ID]
@@ -72,3 +75,212 @@ func TestInMemoryEmployerRepository_Create(t *testing.T) {
 		})
 	}
 }
+
+func TestInMemoryEmployerRepository_GetEmployerByID(t *testing.T) {
+	repo := NewInMemoryEmployerRepository()
+	created, err := repo.CreateEmployer("Test Employer")
+	if err != nil {
+		t.Fatalf("CreateEmployer() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      uint
+		wantErr error
+	}{
+		{
+			name:    "Existing employer",
+			id:      created.ID,
+			wantErr: nil,
+		},
+		{
+			name:    "Non-existent employer",
+			id:      999,
+			wantErr: ErrEmployerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetEmployerByID(tt.id)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetEmployerByID() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetEmployerByID() unexpected error = %v", err)
+			}
+			if got.ID != created.ID || got.Name != created.Name {
+				t.Errorf("GetEmployerByID() = %+v, want %+v", got, created)
+			}
+		})
+	}
+}
+
+func TestInMemoryEmployerRepository_ListEmployers(t *testing.T) {
+	repo := NewInMemoryEmployerRepository()
+	for _, name := range []string{"First", "Second", "Third"} {
+		if _, err := repo.CreateEmployer(name); err != nil {
+			t.Fatalf("CreateEmployer() error = %v", err)
+		}
+	}
+	if _, err := repo.DeactivateEmployer(2); err != nil {
+		t.Fatalf("DeactivateEmployer() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		filter  model.EmployerFilter
+		wantIDs []uint
+	}{
+		{
+			name:    "All employers",
+			filter:  model.EmployerFilter{},
+			wantIDs: []uint{1, 2, 3},
+		},
+		{
+			name:    "Active employers",
+			filter:  model.EmployerFilter{ActiveOnly: true},
+			wantIDs: []uint{1, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ListEmployers(tt.filter)
+			if err != nil {
+				t.Fatalf("ListEmployers() error = %v", err)
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("ListEmployers() returned %d employers, want %d", len(got), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if got[i].ID != id {
+					t.Errorf("employers[%d].ID = %v, want %v", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestInMemoryEmployerRepository_UpdateEmployer(t *testing.T) {
+	repo := NewInMemoryEmployerRepository()
+	created, err := repo.CreateEmployer("Test Employer")
+	if err != nil {
+		t.Fatalf("CreateEmployer() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      uint
+		empName string
+		wantErr error
+	}{
+		{
+			name:    "Rename employer",
+			id:      created.ID,
+			empName: "Renamed Employer",
+		},
+		{
+			name:    "Empty employer name",
+			id:      created.ID,
+			empName: "",
+			wantErr: errors.New("employer name cannot be empty"),
+		},
+		{
+			name:    "Non-existent employer",
+			id:      999,
+			empName: "Renamed Employer",
+			wantErr: ErrEmployerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.UpdateEmployer(tt.id, tt.empName, nil, nil)
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Errorf("UpdateEmployer() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UpdateEmployer() unexpected error = %v", err)
+			}
+			stored, _ := repo.GetEmployerByID(tt.id)
+			if got.Name != tt.empName || stored.Name != tt.empName {
+				t.Errorf("Name = %v (stored %v), want %v", got.Name, stored.Name, tt.empName)
+			}
+			if got.UpdatedAt.Before(created.UpdatedAt) {
+				t.Errorf("UpdatedAt = %v, want after %v", got.UpdatedAt, created.UpdatedAt)
+			}
+		})
+	}
+}
+
+func TestInMemoryEmployerRepository_UpdateEmployer_ContributionScheme(t *testing.T) {
+	repo := NewInMemoryEmployerRepository()
+	created, err := repo.CreateEmployer("Test Employer")
+	if err != nil {
+		t.Fatalf("CreateEmployer() error = %v", err)
+	}
+
+	employerCap := model.NewMoney(50000, model.DefaultCurrency)
+	scheme := &model.ContributionScheme{EmployeeRate: 5000, EmployerRate: 3000, EmployerCap: &employerCap}
+	if _, err := repo.UpdateEmployer(created.ID, created.Name, scheme, nil); err != nil {
+		t.Fatalf("UpdateEmployer() unexpected error = %v", err)
+	}
+	scheme.EmployerRate = 9000
+	employerCap.Minor = 1
+
+	stored, _ := repo.GetEmployerByID(created.ID)
+	if stored.ContributionScheme == nil || stored.ContributionScheme.EmployerRate != 3000 || stored.ContributionScheme.EmployerCap.Minor != 50000 {
+		t.Fatalf("ContributionScheme = %+v, want the scheme as it was stored", stored.ContributionScheme)
+	}
+
+	stored.ContributionScheme.EmployeeRate = 1
+	if again, _ := repo.GetEmployerByID(created.ID); again.ContributionScheme.EmployeeRate != 5000 {
+		t.Errorf("ContributionScheme.EmployeeRate = %v, want 5000 after changing a returned copy", again.ContributionScheme.EmployeeRate)
+	}
+
+	if _, err := repo.UpdateEmployer(created.ID, created.Name, nil, nil); err != nil {
+		t.Fatalf("UpdateEmployer() unexpected error = %v", err)
+	}
+	if removed, _ := repo.GetEmployerByID(created.ID); removed.ContributionScheme != nil {
+		t.Errorf("ContributionScheme = %+v, want nil after removing it", removed.ContributionScheme)
+	}
+}
+
+func TestInMemoryEmployerRepository_DeactivateEmployer(t *testing.T) {
+	repo := NewInMemoryEmployerRepository()
+	created, err := repo.CreateEmployer("Test Employer")
+	if err != nil {
+		t.Fatalf("CreateEmployer() error = %v", err)
+	}
+
+	got, err := repo.DeactivateEmployer(created.ID)
+	if err != nil {
+		t.Fatalf("DeactivateEmployer() error = %v", err)
+	}
+	if got.Active {
+		t.Error("DeactivateEmployer() returned an active employer")
+	}
+
+	again, err := repo.DeactivateEmployer(created.ID)
+	if err != nil {
+		t.Fatalf("DeactivateEmployer() twice error = %v", err)
+	}
+	if !again.UpdatedAt.Equal(got.UpdatedAt) {
+		t.Error("DeactivateEmployer() on an inactive employer changed UpdatedAt")
+	}
+
+	if _, err := repo.DeactivateEmployer(999); !errors.Is(err, ErrEmployerNotFound) {
+		t.Errorf("DeactivateEmployer() error = %v, want ErrEmployerNotFound", err)
+	}
+}
@@ -3,16 +3,29 @@ package repository
 import (
 	"cushon/internal/model"
 	"errors"
+	"sort"
+	"sync"
+	"time"
 )
 
+// ErrFundNotFound is returned when a fund doesn't exist
+var ErrFundNotFound = errors.New("fund not found")
+
+// ErrDuplicateISIN is returned when a fund is created with the ISIN of another fund
+var ErrDuplicateISIN = errors.New("a fund with this ISIN already exists")
+
 // FundRepository defines the contract for storing and retrieving fund data.
 type FundRepository interface {
-	CreateFund(name string) (*model.Fund, error)
-	GetAllFunds() ([]*model.Fund, error)
+	CreateFund(create model.FundCreate) (*model.Fund, error)
+	GetFundByID(id uint) (*model.Fund, error)
+	ListFunds(filter model.FundFilter) ([]*model.Fund, error)
+	UpdateFund(fund *model.Fund) (*model.Fund, error)
 }
 
 // InMemoryFundRepository is a simple in-memory implementation of FundRepository for demonstration.
+// It is safe for concurrent use.
 type InMemoryFundRepository struct {
+	mu     sync.RWMutex
 	funds  map[uint]*model.Fund
 	nextID uint
 }
@@ -25,28 +38,101 @@ func NewInMemoryFundRepository() *InMemoryFundRepository {
 	}
 }
 
-// GetAllFunds retrieves all funds
-func (r *InMemoryFundRepository) GetAllFunds() ([]*model.Fund, error) {
+// GetFundByID retrieves a fund by its ID
+func (r *InMemoryFundRepository) GetFundByID(id uint) (*model.Fund, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fund, exists := r.funds[id]
+	if !exists {
+		return nil, ErrFundNotFound
+	}
+	stored := *fund
+	return &stored, nil
+}
+
+// ListFunds retrieves the funds matching filter ordered by ID
+func (r *InMemoryFundRepository) ListFunds(filter model.FundFilter) ([]*model.Fund, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	funds := make([]*model.Fund, 0, len(r.funds))
 	for _, fund := range r.funds {
-		funds = append(funds, fund)
+		if filter.Matches(fund) {
+			stored := *fund
+			funds = append(funds, &stored)
+		}
 	}
+	sort.Slice(funds, func(i, j int) bool {
+		return funds[i].ID < funds[j].ID
+	})
 	return funds, nil
 }
 
-// CreateFund creates a new fund
-func (r *InMemoryFundRepository) CreateFund(name string) (*model.Fund, error) {
-	if name == "" {
+// CreateFund creates a new fund. New funds are open.
+func (r *InMemoryFundRepository) CreateFund(create model.FundCreate) (*model.Fund, error) {
+	if create.Name == "" {
 		return nil, errors.New("fund name cannot be empty")
 	}
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if create.ISIN != "" {
+		for _, fund := range r.funds {
+			if fund.ISIN == create.ISIN {
+				return nil, ErrDuplicateISIN
+			}
+		}
+	}
+
+	now := time.Now()
 	fund := &model.Fund{
-		ID:   r.nextID,
-		Name: name,
+		ID:             r.nextID,
+		Name:           create.Name,
+		ISIN:           create.ISIN,
+		AssetClass:     create.AssetClass,
+		RiskRating:     create.RiskRating,
+		OngoingCharges: create.OngoingCharges,
+		Currency:       create.Currency,
+		Description:    create.Description,
+		Status:         model.FundStatusOpen,
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
 	r.funds[fund.ID] = fund
 	r.nextID++
 
-	return fund, nil
+	stored := *fund
+	return &stored, nil
+}
+
+// UpdateFund replaces the name, status and descriptive details of the fund with fund.ID.
+// The ISIN and currency are fixed when the fund is created.
+func (r *InMemoryFundRepository) UpdateFund(fund *model.Fund) (*model.Fund, error) {
+	if fund.Name == "" {
+		return nil, errors.New("fund name cannot be empty")
+	}
+	if !fund.Status.Valid() {
+		return nil, errors.New("invalid fund status")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.funds[fund.ID]
+	if !exists {
+		return nil, ErrFundNotFound
+	}
+	existing.Name = fund.Name
+	existing.Status = fund.Status
+	existing.AssetClass = fund.AssetClass
+	existing.RiskRating = fund.RiskRating
+	existing.OngoingCharges = fund.OngoingCharges
+	existing.Description = fund.Description
+	existing.UpdatedAt = time.Now()
+
+	stored := *existing
+	return &stored, nil
 }
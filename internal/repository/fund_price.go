@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"cushon/internal/model"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrFundPriceNotFound is returned when a fund has no price for the requested date
+var ErrFundPriceNotFound = errors.New("fund price not found")
+
+// ErrDuplicateFundPrice is returned when a fund already has a price for the date being recorded
+var ErrDuplicateFundPrice = errors.New("fund already has a price for this date")
+
+// FundPriceRepository defines the contract for storing and retrieving fund price history.
+// Implementations don't check that the fund exists, that is up to the caller.
+type FundPriceRepository interface {
+	CreateFundPrice(price *model.FundPrice) (*model.FundPrice, error)
+	GetLatestFundPrice(fundID uint, onOrBefore model.Date) (*model.FundPrice, error)
+	ListFundPrices(fundID uint, filter model.FundPriceFilter) ([]*model.FundPrice, error)
+}
+
+// InMemoryFundPriceRepository is a simple in-memory implementation of FundPriceRepository.
+// It is safe for concurrent use.
+type InMemoryFundPriceRepository struct {
+	mu sync.RWMutex
+	// prices holds each fund's prices ordered by date
+	prices map[uint][]*model.FundPrice
+}
+
+// NewInMemoryFundPriceRepository creates a new in-memory fund price repository
+func NewInMemoryFundPriceRepository() *InMemoryFundPriceRepository {
+	return &InMemoryFundPriceRepository{
+		prices: make(map[uint][]*model.FundPrice),
+	}
+}
+
+// CreateFundPrice records a fund's price for a date
+func (r *InMemoryFundPriceRepository) CreateFundPrice(price *model.FundPrice) (*model.FundPrice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prices := r.prices[price.FundID]
+	i := sort.Search(len(prices), func(i int) bool {
+		return !prices[i].Date.Before(price.Date.Time)
+	})
+	if i < len(prices) && prices[i].Date.Equal(price.Date.Time) {
+		return nil, ErrDuplicateFundPrice
+	}
+
+	stored := *price
+	stored.CreatedAt = time.Now()
+
+	prices = append(prices, nil)
+	copy(prices[i+1:], prices[i:])
+	prices[i] = &stored
+	r.prices[price.FundID] = prices
+
+	result := stored
+	return &result, nil
+}
+
+// GetLatestFundPrice retrieves a fund's most recent price on or before a date
+func (r *InMemoryFundPriceRepository) GetLatestFundPrice(fundID uint, onOrBefore model.Date) (*model.FundPrice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prices := r.prices[fundID]
+	i := sort.Search(len(prices), func(i int) bool {
+		return prices[i].Date.After(onOrBefore.Time)
+	})
+	if i == 0 {
+		return nil, ErrFundPriceNotFound
+	}
+	stored := *prices[i-1]
+	return &stored, nil
+}
+
+// ListFundPrices retrieves a fund's prices matching filter ordered by date
+func (r *InMemoryFundPriceRepository) ListFundPrices(fundID uint, filter model.FundPriceFilter) ([]*model.FundPrice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prices := make([]*model.FundPrice, 0)
+	for _, price := range r.prices[fundID] {
+		if filter.Matches(price) {
+			stored := *price
+			prices = append(prices, &stored)
+		}
+	}
+	return prices, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/model"
+)
+
+func TestInMemoryFundPriceRepository_CreateFundPrice(t *testing.T) {
+	repo := NewInMemoryFundPriceRepository()
+	price := &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, 15), NAV: 1234567, Currency: "GBP"}
+
+	got, err := repo.CreateFundPrice(price)
+	if err != nil {
+		t.Fatalf("CreateFundPrice() error = %v", err)
+	}
+	if got.FundID != 1 || got.Date != price.Date || got.NAV != price.NAV || got.Currency != "GBP" || got.CreatedAt.IsZero() {
+		t.Errorf("CreateFundPrice() = %+v, want %+v with a creation time", got, price)
+	}
+
+	// Another fund can be priced on the same date
+	if _, err := repo.CreateFundPrice(&model.FundPrice{FundID: 2, Date: price.Date, NAV: 1000000, Currency: "GBP"}); err != nil {
+		t.Errorf("CreateFundPrice() for another fund error = %v", err)
+	}
+
+	if _, err := repo.CreateFundPrice(price); !errors.Is(err, ErrDuplicateFundPrice) {
+		t.Errorf("CreateFundPrice() twice error = %v, want ErrDuplicateFundPrice", err)
+	}
+}
+
+func TestInMemoryFundPriceRepository_GetLatestFundPrice(t *testing.T) {
+	repo := NewInMemoryFundPriceRepository()
+	// Prices are recorded out of order to check they are kept sorted by date
+	for _, price := range []*model.FundPrice{
+		{FundID: 1, Date: model.NewDate(2026, time.October, 14), NAV: 1100000},
+		{FundID: 1, Date: model.NewDate(2026, time.October, 12), NAV: 1000000},
+		{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 1200000},
+	} {
+		if _, err := repo.CreateFundPrice(price); err != nil {
+			t.Fatalf("CreateFundPrice() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		fundID     uint
+		onOrBefore model.Date
+		wantNAV    model.Price
+		wantErr    error
+	}{
+		{name: "Priced on the date", fundID: 1, onOrBefore: model.NewDate(2026, time.October, 14), wantNAV: 1100000},
+		{name: "Previous price", fundID: 1, onOrBefore: model.NewDate(2026, time.October, 15), wantNAV: 1100000},
+		{name: "After the last price", fundID: 1, onOrBefore: model.NewDate(2026, time.October, 20), wantNAV: 1200000},
+		{name: "Before the first price", fundID: 1, onOrBefore: model.NewDate(2026, time.October, 11), wantErr: ErrFundPriceNotFound},
+		{name: "Fund without prices", fundID: 2, onOrBefore: model.NewDate(2026, time.October, 20), wantErr: ErrFundPriceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetLatestFundPrice(tt.fundID, tt.onOrBefore)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetLatestFundPrice() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetLatestFundPrice() unexpected error = %v", err)
+			}
+			if got.NAV != tt.wantNAV {
+				t.Errorf("GetLatestFundPrice() NAV = %v, want %v", got.NAV, tt.wantNAV)
+			}
+		})
+	}
+}
+
+func TestInMemoryFundPriceRepository_ListFundPrices(t *testing.T) {
+	repo := NewInMemoryFundPriceRepository()
+	for _, day := range []int{16, 12, 14} {
+		if _, err := repo.CreateFundPrice(&model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, day), NAV: 1000000}); err != nil {
+			t.Fatalf("CreateFundPrice() error = %v", err)
+		}
+	}
+
+	from, to := model.NewDate(2026, time.October, 13), model.NewDate(2026, time.October, 16)
+	tests := []struct {
+		name     string
+		filter   model.FundPriceFilter
+		wantDays []int
+	}{
+		{name: "All prices", filter: model.FundPriceFilter{}, wantDays: []int{12, 14, 16}},
+		{name: "From a date", filter: model.FundPriceFilter{From: &from}, wantDays: []int{14, 16}},
+		{name: "Between dates", filter: model.FundPriceFilter{From: &from, To: &to}, wantDays: []int{14, 16}},
+		{name: "Up to a date", filter: model.FundPriceFilter{To: &from}, wantDays: []int{12}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ListFundPrices(1, tt.filter)
+			if err != nil {
+				t.Fatalf("ListFundPrices() error = %v", err)
+			}
+			if len(got) != len(tt.wantDays) {
+				t.Fatalf("got %d prices, want %d", len(got), len(tt.wantDays))
+			}
+			for i, day := range tt.wantDays {
+				if got[i].Date.Day() != day {
+					t.Errorf("price[%d].Date = %v, want day %d", i, got[i].Date, day)
+				}
+			}
+		})
+	}
+}
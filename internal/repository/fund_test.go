@@ -7,7 +7,7 @@ import (
 	"cushon/internal/model"
 )
 
-func TestInMemoryFundRepository_GetAllFunds(t *testing.T) {
+func TestInMemoryFundRepository_ListFunds(t *testing.T) {
 	tests := []struct {
 		name      string
 		setup     func(*InMemoryFundRepository)
@@ -21,7 +21,7 @@ func TestInMemoryFundRepository_GetAllFunds(t *testing.T) {
 		{
 			name: "Single fund",
 			setup: func(r *InMemoryFundRepository) {
-				r.CreateFund("Test Fund 1")
+				r.CreateFund(model.FundCreate{Name: "Test Fund 1"})
 			},
 			wantFunds: []*model.Fund{
 				{
@@ -33,8 +33,8 @@ func TestInMemoryFundRepository_GetAllFunds(t *testing.T) {
 		{
 			name: "Multiple funds",
 			setup: func(r *InMemoryFundRepository) {
-				r.CreateFund("Test Fund 1")
-				r.CreateFund("Test Fund 2")
+				r.CreateFund(model.FundCreate{Name: "Test Fund 1"})
+				r.CreateFund(model.FundCreate{Name: "Test Fund 2"})
 			},
 			wantFunds: []*model.Fund{
 				{
@@ -56,9 +56,9 @@ func TestInMemoryFundRepository_GetAllFunds(t *testing.T) {
 				tt.setup(repo)
 			}
 
-			got, err := repo.GetAllFunds()
+			got, err := repo.ListFunds(model.FundFilter{})
 			if err != nil {
-				t.Errorf("GetAllFunds() error = %v", err)
+				t.Errorf("ListFunds() error = %v", err)
 				return
 			}
 
@@ -100,7 +100,7 @@ func TestInMemoryFundRepository_CreateFund(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := NewInMemoryFundRepository()
-			got, err := repo.CreateFund(tt.fundName)
+			got, err := repo.CreateFund(model.FundCreate{Name: tt.fundName})
 
 			if tt.wantErr != nil {
 				if err == nil || err.Error() != tt.wantErr.Error() {
@@ -120,9 +120,9 @@ func TestInMemoryFundRepository_CreateFund(t *testing.T) {
 			}
 
 			// Verify the fund was stored
-			funds, err := repo.GetAllFunds()
+			funds, err := repo.ListFunds(model.FundFilter{})
 			if err != nil {
-				t.Errorf("GetAllFunds() error = %v", err)
+				t.Errorf("ListFunds() error = %v", err)
 				return
 			}
 
@@ -139,3 +139,156 @@ func TestInMemoryFundRepository_CreateFund(t *testing.T) {
 		})
 	}
 }
+
+func TestInMemoryFundRepository_GetFundByID(t *testing.T) {
+	repo := NewInMemoryFundRepository()
+	created, err := repo.CreateFund(model.FundCreate{Name: "Test Fund"})
+	if err != nil {
+		t.Fatalf("CreateFund() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      uint
+		wantErr error
+	}{
+		{
+			name:    "Existing fund",
+			id:      created.ID,
+			wantErr: nil,
+		},
+		{
+			name:    "Non-existent fund",
+			id:      999,
+			wantErr: ErrFundNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetFundByID(tt.id)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetFundByID() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetFundByID() unexpected error = %v", err)
+			}
+			if got.ID != created.ID || got.Name != created.Name {
+				t.Errorf("GetFundByID() = %+v, want %+v", got, created)
+			}
+		})
+	}
+}
+
+func TestInMemoryFundRepository_UpdateFund(t *testing.T) {
+	repo := NewInMemoryFundRepository()
+	created, err := repo.CreateFund(model.FundCreate{Name: "Equities Fund"})
+	if err != nil {
+		t.Fatalf("CreateFund() error = %v", err)
+	}
+	if created.Status != model.FundStatusOpen {
+		t.Errorf("CreateFund() Status = %v, want %v", created.Status, model.FundStatusOpen)
+	}
+
+	tests := []struct {
+		name     string
+		id       uint
+		fundName string
+		status   model.FundStatus
+		wantErr  error
+	}{
+		{
+			name:     "Close fund",
+			id:       created.ID,
+			fundName: "Equities Fund",
+			status:   model.FundStatusClosed,
+		},
+		{
+			name:     "Empty fund name",
+			id:       created.ID,
+			fundName: "",
+			status:   model.FundStatusOpen,
+			wantErr:  errors.New("fund name cannot be empty"),
+		},
+		{
+			name:     "Unknown status",
+			id:       created.ID,
+			fundName: "Equities Fund",
+			status:   "suspended",
+			wantErr:  errors.New("invalid fund status"),
+		},
+		{
+			name:     "Non-existent fund",
+			id:       999,
+			fundName: "Equities Fund",
+			status:   model.FundStatusOpen,
+			wantErr:  ErrFundNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.UpdateFund(&model.Fund{ID: tt.id, Name: tt.fundName, Status: tt.status})
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Errorf("UpdateFund() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UpdateFund() unexpected error = %v", err)
+			}
+			stored, _ := repo.GetFundByID(tt.id)
+			if got.Status != tt.status || stored.Status != tt.status {
+				t.Errorf("Status = %v (stored %v), want %v", got.Status, stored.Status, tt.status)
+			}
+		})
+	}
+}
+
+func TestInMemoryFundRepository_ListFunds_Filter(t *testing.T) {
+	repo := NewInMemoryFundRepository()
+	for _, create := range []model.FundCreate{
+		{Name: "Global Equities", ISIN: "IE00B4L5Y983", AssetClass: model.AssetClassEquity, RiskRating: 6, Currency: "GBP"},
+		{Name: "UK Equity Income", ISIN: "GB0002634946", AssetClass: model.AssetClassEquity, RiskRating: 4, Currency: "GBP"},
+		{Name: "Gilts", ISIN: "GB00B3X7QG63", AssetClass: model.AssetClassBond, RiskRating: 3, Currency: "GBP"},
+	} {
+		if _, err := repo.CreateFund(create); err != nil {
+			t.Fatalf("CreateFund() error = %v", err)
+		}
+	}
+
+	equity := model.AssetClassEquity
+	got, err := repo.ListFunds(model.FundFilter{AssetClass: &equity, MaxRisk: 4})
+	if err != nil {
+		t.Fatalf("ListFunds() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "UK Equity Income" {
+		t.Errorf("ListFunds() = %+v, want only UK Equity Income", got)
+	}
+}
+
+func TestInMemoryFundRepository_CreateFund_DuplicateISIN(t *testing.T) {
+	repo := NewInMemoryFundRepository()
+	if _, err := repo.CreateFund(model.FundCreate{Name: "Global Equities", ISIN: "IE00B4L5Y983"}); err != nil {
+		t.Fatalf("CreateFund() error = %v", err)
+	}
+
+	// Funds without an ISIN don't clash with each other
+	for i := 0; i < 2; i++ {
+		if _, err := repo.CreateFund(model.FundCreate{Name: "Legacy Fund"}); err != nil {
+			t.Fatalf("CreateFund() without ISIN error = %v", err)
+		}
+	}
+
+	if _, err := repo.CreateFund(model.FundCreate{Name: "Copy", ISIN: "IE00B4L5Y983"}); !errors.Is(err, ErrDuplicateISIN) {
+		t.Errorf("CreateFund() error = %v, want ErrDuplicateISIN", err)
+	}
+}
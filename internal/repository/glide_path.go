@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"cushon/internal/model"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrGlidePathNotFound is returned when a glide path doesn't exist
+var ErrGlidePathNotFound = errors.New("glide path not found")
+
+// GlidePathRepository defines the contract for storing glide paths. Implementations don't check that each
+// stage's percentages add up or that the funds are open, that is up to the caller.
+type GlidePathRepository interface {
+	CreateGlidePath(glidePath *model.GlidePath) (*model.GlidePath, error)
+	GetGlidePathByID(id uint) (*model.GlidePath, error)
+	ListGlidePaths() ([]*model.GlidePath, error)
+}
+
+// InMemoryGlidePathRepository is a simple in-memory implementation of GlidePathRepository.
+// It is safe for concurrent use.
+type InMemoryGlidePathRepository struct {
+	mu         sync.RWMutex
+	glidePaths []*model.GlidePath
+}
+
+// NewInMemoryGlidePathRepository creates a new in-memory glide path repository
+func NewInMemoryGlidePathRepository() *InMemoryGlidePathRepository {
+	return &InMemoryGlidePathRepository{}
+}
+
+// CreateGlidePath stores a new glide path with its stages ordered from the furthest from retirement to the nearest
+func (r *InMemoryGlidePathRepository) CreateGlidePath(glidePath *model.GlidePath) (*model.GlidePath, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyGlidePath(glidePath)
+	stored.ID = uint(len(r.glidePaths) + 1)
+	stored.CreatedAt = time.Now()
+	stored.SortStages()
+	r.glidePaths = append(r.glidePaths, stored)
+	return copyGlidePath(stored), nil
+}
+
+// GetGlidePathByID retrieves a glide path by its ID
+func (r *InMemoryGlidePathRepository) GetGlidePathByID(id uint) (*model.GlidePath, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if id == 0 || id > uint(len(r.glidePaths)) {
+		return nil, ErrGlidePathNotFound
+	}
+	return copyGlidePath(r.glidePaths[id-1]), nil
+}
+
+// ListGlidePaths retrieves every glide path ordered by ID
+func (r *InMemoryGlidePathRepository) ListGlidePaths() ([]*model.GlidePath, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	glidePaths := make([]*model.GlidePath, len(r.glidePaths))
+	for i, glidePath := range r.glidePaths {
+		glidePaths[i] = copyGlidePath(glidePath)
+	}
+	return glidePaths, nil
+}
+
+// copyGlidePath returns a copy of a glide path that shares none of its stages or funds
+func copyGlidePath(glidePath *model.GlidePath) *model.GlidePath {
+	c := *glidePath
+	c.Stages = make([]model.GlidePathStage, len(glidePath.Stages))
+	for i, stage := range glidePath.Stages {
+		c.Stages[i] = model.GlidePathStage{
+			YearsToRetirement: stage.YearsToRetirement,
+			Funds:             append([]model.FundAllocation(nil), stage.Funds...),
+		}
+	}
+	return &c
+}
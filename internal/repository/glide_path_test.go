@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"cushon/internal/model"
+)
+
+func TestInMemoryGlidePathRepository(t *testing.T) {
+	repo := NewInMemoryGlidePathRepository()
+
+	if _, err := repo.GetGlidePathByID(1); !errors.Is(err, ErrGlidePathNotFound) {
+		t.Fatalf("GetGlidePathByID() error = %v, want ErrGlidePathNotFound", err)
+	}
+
+	stages := []model.GlidePathStage{
+		{YearsToRetirement: 0, Funds: []model.FundAllocation{{FundID: 2, Percentage: model.OneHundredPercent}}},
+		{YearsToRetirement: 10, Funds: []model.FundAllocation{{FundID: 1, Percentage: 80000}, {FundID: 2, Percentage: 20000}}},
+	}
+	created, err := repo.CreateGlidePath(&model.GlidePath{Name: "Balanced", Stages: stages})
+	if err != nil {
+		t.Fatalf("CreateGlidePath() error = %v", err)
+	}
+	if created.ID != 1 || created.CreatedAt.IsZero() || created.Stages[0].YearsToRetirement != 10 {
+		t.Errorf("CreateGlidePath() = %+v, want ID 1 with its stages furthest from retirement first", created)
+	}
+	stages[1].Funds[0].FundID = 99
+	created.Stages[1].Funds[0].FundID = 99
+
+	got, err := repo.GetGlidePathByID(created.ID)
+	if err != nil {
+		t.Fatalf("GetGlidePathByID() error = %v", err)
+	}
+	if got.Name != "Balanced" || len(got.Stages) != 2 || got.Stages[0].Funds[0].FundID != 1 || got.Stages[1].Funds[0].FundID != 2 {
+		t.Errorf("GetGlidePathByID() = %+v, want the glide path as it was stored", got)
+	}
+
+	if _, err := repo.CreateGlidePath(&model.GlidePath{Name: "Cautious", Stages: stages[:1]}); err != nil {
+		t.Fatalf("CreateGlidePath() error = %v", err)
+	}
+	all, err := repo.ListGlidePaths()
+	if err != nil {
+		t.Fatalf("ListGlidePaths() error = %v", err)
+	}
+	if len(all) != 2 || all[0].Name != "Balanced" || all[1].Name != "Cautious" {
+		t.Errorf("ListGlidePaths() = %+v, want both glide paths in order", all)
+	}
+}
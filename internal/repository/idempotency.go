@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"cushon/internal/model"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrIdempotencyKeyExists is returned when reserving an idempotency key that has already been used and hasn't expired
+var ErrIdempotencyKeyExists = errors.New("idempotency key has already been used")
+
+// ErrIdempotencyKeyNotFound is returned when completing or releasing an idempotency key that isn't reserved
+var ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+
+// IdempotencyRepository defines the contract for storing the responses replayed for idempotency keys. Keys are
+// scoped to the API key that used them.
+type IdempotencyRepository interface {
+	ReserveIdempotencyKey(record *model.IdempotencyRecord, now time.Time) (*model.IdempotencyRecord, error)
+	CompleteIdempotencyKey(apiKey, key string, response *model.IdempotentResponse) error
+	ReleaseIdempotencyKey(apiKey, key string) error
+	DeleteExpiredIdempotencyKeys(now time.Time) (int, error)
+}
+
+// InMemoryIdempotencyRepository is a simple in-memory implementation of IdempotencyRepository.
+// It is safe for concurrent use.
+type InMemoryIdempotencyRepository struct {
+	mu      sync.RWMutex
+	records map[idempotencyKey]*model.IdempotencyRecord
+}
+
+// idempotencyKey identifies an idempotency key used by an API key
+type idempotencyKey struct {
+	apiKey string
+	key    string
+}
+
+// NewInMemoryIdempotencyRepository creates a new in-memory idempotency repository
+func NewInMemoryIdempotencyRepository() *InMemoryIdempotencyRepository {
+	return &InMemoryIdempotencyRepository{
+		records: make(map[idempotencyKey]*model.IdempotencyRecord),
+	}
+}
+
+// ReserveIdempotencyKey stores record, without a response, unless its key is already held by a record that
+// hasn't expired at now. In that case the existing record is returned with ErrIdempotencyKeyExists, so only one
+// of several concurrent requests with the same key is handled.
+func (r *InMemoryIdempotencyRepository) ReserveIdempotencyKey(record *model.IdempotencyRecord, now time.Time) (*model.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := idempotencyKey{record.APIKey, record.Key}
+	if existing, exists := r.records[id]; exists && existing.ExpiresAt.After(now) {
+		return copyIdempotencyRecord(existing), ErrIdempotencyKeyExists
+	}
+
+	stored := *record
+	stored.Response = nil
+	r.records[id] = &stored
+	return copyIdempotencyRecord(&stored), nil
+}
+
+// CompleteIdempotencyKey stores the response to replay for a reserved key
+func (r *InMemoryIdempotencyRepository) CompleteIdempotencyKey(apiKey, key string, response *model.IdempotentResponse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, exists := r.records[idempotencyKey{apiKey, key}]
+	if !exists {
+		return ErrIdempotencyKeyNotFound
+	}
+	stored := *response
+	stored.Body = append([]byte(nil), response.Body...)
+	record.Response = &stored
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets a reserved key so the request can be retried
+func (r *InMemoryIdempotencyRepository) ReleaseIdempotencyKey(apiKey, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := idempotencyKey{apiKey, key}
+	if _, exists := r.records[id]; !exists {
+		return ErrIdempotencyKeyNotFound
+	}
+	delete(r.records, id)
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys forgets every key that has expired at now and returns how many there were
+func (r *InMemoryIdempotencyRepository) DeleteExpiredIdempotencyKeys(now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for id, record := range r.records {
+		if !record.ExpiresAt.After(now) {
+			delete(r.records, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// copyIdempotencyRecord returns a copy of record that doesn't share its response
+func copyIdempotencyRecord(record *model.IdempotencyRecord) *model.IdempotencyRecord {
+	stored := *record
+	if record.Response != nil {
+		response := *record.Response
+		response.Body = append([]byte(nil), record.Response.Body...)
+		stored.Response = &response
+	}
+	return &stored
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/model"
+)
+
+func TestInMemoryIdempotencyRepository(t *testing.T) {
+	repo := NewInMemoryIdempotencyRepository()
+	now := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+	record := func(apiKey, requestHash string, at time.Time) *model.IdempotencyRecord {
+		return &model.IdempotencyRecord{APIKey: apiKey, Key: "abc", RequestHash: requestHash, CreatedAt: at, ExpiresAt: at.Add(time.Hour)}
+	}
+
+	reserved, err := repo.ReserveIdempotencyKey(record("key-1", "first", now), now)
+	if err != nil || reserved.Response != nil {
+		t.Fatalf("ReserveIdempotencyKey() = %+v, %v, want the key reserved without a response", reserved, err)
+	}
+
+	existing, err := repo.ReserveIdempotencyKey(record("key-1", "second", now), now)
+	if !errors.Is(err, ErrIdempotencyKeyExists) || existing.RequestHash != "first" || existing.Response != nil {
+		t.Errorf("ReserveIdempotencyKey() = %+v, %v, want the first request still being handled", existing, err)
+	}
+	if _, err := repo.ReserveIdempotencyKey(record("key-2", "second", now), now); err != nil {
+		t.Errorf("ReserveIdempotencyKey() for another API key error = %v", err)
+	}
+
+	response := &model.IdempotentResponse{StatusCode: 201, ContentType: "application/json", Body: []byte(`{"id": 1}`)}
+	if err := repo.CompleteIdempotencyKey("key-1", "abc", response); err != nil {
+		t.Fatalf("CompleteIdempotencyKey() error = %v", err)
+	}
+	response.Body[0] = 'x'
+	existing, err = repo.ReserveIdempotencyKey(record("key-1", "first", now), now)
+	if !errors.Is(err, ErrIdempotencyKeyExists) || existing.Response == nil || string(existing.Response.Body) != `{"id": 1}` {
+		t.Errorf("ReserveIdempotencyKey() = %+v, %v, want the stored response", existing, err)
+	}
+
+	if err := repo.ReleaseIdempotencyKey("key-2", "abc"); err != nil {
+		t.Errorf("ReleaseIdempotencyKey() error = %v", err)
+	}
+	if err := repo.ReleaseIdempotencyKey("key-2", "abc"); !errors.Is(err, ErrIdempotencyKeyNotFound) {
+		t.Errorf("ReleaseIdempotencyKey() error = %v, want ErrIdempotencyKeyNotFound", err)
+	}
+	if err := repo.CompleteIdempotencyKey("key-2", "abc", response); !errors.Is(err, ErrIdempotencyKeyNotFound) {
+		t.Errorf("CompleteIdempotencyKey() error = %v, want ErrIdempotencyKeyNotFound", err)
+	}
+
+	// Once expired the key can be reserved again, and is cleaned up
+	later := now.Add(time.Hour)
+	if reserved, err := repo.ReserveIdempotencyKey(record("key-1", "third", later), later); err != nil || reserved.RequestHash != "third" {
+		t.Errorf("ReserveIdempotencyKey() after expiry = %+v, %v, want the key reserved again", reserved, err)
+	}
+	repo.ReserveIdempotencyKey(record("key-3", "first", now), now)
+	if deleted, err := repo.DeleteExpiredIdempotencyKeys(later); err != nil || deleted != 1 {
+		t.Errorf("DeleteExpiredIdempotencyKeys() = %d, %v, want 1", deleted, err)
+	}
+}
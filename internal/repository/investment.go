@@ -3,73 +3,384 @@ package repository
 import (
 	"cushon/internal/model"
 	"errors"
+	"sort"
+	"sync"
 	"time"
 )
 
-// InvestmentRepository defines the contract for storing and retrieving investment data
+// ErrInvestmentNotFound is returned when an investment doesn't exist
+var ErrInvestmentNotFound = errors.New("investment not found")
+
+// ErrInsufficientUnits is returned when withdrawing more units than a customer holds in a fund
+var ErrInsufficientUnits = errors.New("not enough units held")
+
+// ErrSwitchNotFound is returned when a switch doesn't exist
+var ErrSwitchNotFound = errors.New("switch not found")
+
+// ErrInvestmentStatusChanged is returned when a transaction's status was changed by someone else before it could be updated
+var ErrInvestmentStatusChanged = errors.New("investment status has changed")
+
+// ErrISAAllowanceExceeded is returned when an investment would take a customer's ISA subscriptions for the tax
+// year over their allowance
+var ErrISAAllowanceExceeded = errors.New("ISA annual allowance exceeded")
+
+// ErrScheduleRunPaid is returned when storing a contribution for a schedule run that has already been paid into
+// the same fund
+var ErrScheduleRunPaid = errors.New("schedule run has already been paid in")
+
+// InvestmentRepository defines the contract for storing and retrieving investment data.
+// Implementations don't check that the client and fund exist, that is up to the caller.
 type InvestmentRepository interface {
-	CreateInvestment(clientID, fundID uint, amount float32) (*model.Investment, error)
+	CreateInvestment(investment *model.Investment) (*model.Investment, error)
+	CreateISAInvestments(investments []*model.Investment, allowance model.Money) ([]*model.Investment, error)
+	CreateInvestments(investments []*model.Investment) ([]*model.Investment, error)
+	CreateWithdrawal(withdrawal *model.Investment) (*model.Investment, error)
+	CreateSwitch(fundSwitch *model.Switch) (*model.Switch, error)
+	CreateSwitches(switches []*model.Switch) ([]*model.Switch, error)
+	GetSwitchByID(id uint) (*model.Switch, error)
 	GetInvestmentByID(id uint) (*model.Investment, error)
 	GetInvestmentsByClientID(clientID uint) ([]*model.Investment, error)
+	ListInvestments(filter model.InvestmentFilter) ([]*model.Investment, error)
+	SumDealtUnits(holdings []model.HoldingKey) (map[model.HoldingKey]model.Units, error)
+	UpdateInvestmentStatus(ids []uint, from, to model.InvestmentStatus) ([]*model.Investment, error)
 }
 
-// InMemoryInvestmentRepository is a simple in-memory implementation of InvestmentRepository
+// InMemoryInvestmentRepository is a simple in-memory implementation of InvestmentRepository.
+// It is safe for concurrent use.
 type InMemoryInvestmentRepository struct {
-	investments map[uint]*model.Investment
-	nextID      uint
+	mu           sync.RWMutex
+	investments  map[uint]*model.Investment
+	nextID       uint
+	switches     map[uint]*model.Switch
+	nextSwitchID uint
 }
 
 // NewInMemoryInvestmentRepository creates a new in-memory investment repository
 func NewInMemoryInvestmentRepository() *InMemoryInvestmentRepository {
 	return &InMemoryInvestmentRepository{
-		investments: make(map[uint]*model.Investment),
-		nextID:      1,
+		investments:  make(map[uint]*model.Investment),
+		nextID:       1,
+		switches:     make(map[uint]*model.Switch),
+		nextSwitchID: 1,
+	}
+}
+
+// CreateInvestment stores a new pending investment. The ID and timestamps are assigned by the repository.
+func (r *InMemoryInvestmentRepository) CreateInvestment(investment *model.Investment) (*model.Investment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkRunsUnpaid([]*model.Investment{investment}); err != nil {
+		return nil, err
+	}
+	return r.create(investment, model.TransactionTypeInvestment), nil
+}
+
+// CreateISAInvestments stores new pending investments into one customer's ISA, or none of them with
+// ErrISAAllowanceExceeded when together they would take the customer's subscriptions for the current tax year over
+// allowance. The investments are marked as paid into the ISA, and the subscriptions are totalled and the
+// investments stored under the same lock so concurrent investments can't breach the allowance.
+func (r *InMemoryInvestmentRepository) CreateISAInvestments(investments []*model.Investment, allowance model.Money) ([]*model.Investment, error) {
+	if len(investments) == 0 {
+		return []*model.Investment{}, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clientID := investments[0].ClientID
+	taxYear := model.TaxYearOf(time.Now())
+	subscribed := model.NewMoney(0, allowance.Currency)
+	for _, investment := range investments {
+		var err error
+		if subscribed, err = subscribed.Add(investment.Amount); err != nil {
+			return nil, ErrISAAllowanceExceeded
+		}
+	}
+	for _, existing := range r.investments {
+		if existing.ClientID == clientID && existing.IsISASubscription() && taxYear.Contains(existing.CreatedAt) {
+			var err error
+			if subscribed, err = subscribed.Add(existing.Amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if subscribed.Minor > allowance.Minor {
+		return nil, ErrISAAllowanceExceeded
+	}
+	if err := r.checkRunsUnpaid(investments); err != nil {
+		return nil, err
+	}
+
+	created := make([]*model.Investment, len(investments))
+	for i, investment := range investments {
+		subscription := *investment
+		subscription.ISA = true
+		created[i] = r.create(&subscription, model.TransactionTypeInvestment)
+	}
+	return created, nil
+}
+
+// CreateInvestments stores several new pending investments at once, so either all of them are stored or none is
+func (r *InMemoryInvestmentRepository) CreateInvestments(investments []*model.Investment) ([]*model.Investment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkRunsUnpaid(investments); err != nil {
+		return nil, err
+	}
+
+	created := make([]*model.Investment, len(investments))
+	for i, investment := range investments {
+		created[i] = r.create(investment, model.TransactionTypeInvestment)
+	}
+	return created, nil
+}
+
+// CreateWithdrawal stores a withdrawal, whose amount and units are negative. The holding is checked and the
+// withdrawal stored under the same lock so concurrent withdrawals can't overdraw it.
+func (r *InMemoryInvestmentRepository) CreateWithdrawal(withdrawal *model.Investment) (*model.Investment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.unitsHeld(withdrawal.ClientID, withdrawal.FundID)+withdrawal.Units < 0 {
+		return nil, ErrInsufficientUnits
+	}
+
+	return r.create(withdrawal, model.TransactionTypeWithdrawal), nil
+}
+
+// CreateSwitch stores both legs of a switch, or neither when the customer doesn't hold the units sold. The
+// switch's ID and the IDs of its legs are assigned by the repository.
+func (r *InMemoryInvestmentRepository) CreateSwitch(fundSwitch *model.Switch) (*model.Switch, error) {
+	created, err := r.CreateSwitches([]*model.Switch{fundSwitch})
+	if err != nil {
+		return nil, err
 	}
+	return created[0], nil
 }
 
-// Create creates a new investment
-func (r *InMemoryInvestmentRepository) CreateInvestment(clientID, fundID uint, amount float32) (*model.Investment, error) {
-	// check user/fund are valid, this could go to a real DB and check if user/fund exist
-	// here I will assume only clients/funds with ID greater than 100 are not valid
-	if clientID > 100 {
-		return nil, errors.New("invalid user ID")
+// CreateSwitches stores several switches together, or none of them when the customer doesn't hold the units
+// they sell between them
+func (r *InMemoryInvestmentRepository) CreateSwitches(switches []*model.Switch) ([]*model.Switch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type holding struct{ clientID, fundID uint }
+	sold := make(map[holding]model.Units)
+	for _, fundSwitch := range switches {
+		sold[holding{fundSwitch.ClientID, fundSwitch.FromFundID}] -= fundSwitch.Sell.Units
+	}
+	for held, units := range sold {
+		if r.unitsHeld(held.clientID, held.fundID) < units {
+			return nil, ErrInsufficientUnits
+		}
 	}
-	if fundID > 100 {
-		return nil, errors.New("invalid fund ID")
+
+	created := make([]*model.Switch, len(switches))
+	for i, fundSwitch := range switches {
+		created[i] = r.createSwitch(fundSwitch)
 	}
+	return created, nil
+}
 
+// createSwitch stores a switch and both of its legs. The caller must hold the lock.
+func (r *InMemoryInvestmentRepository) createSwitch(fundSwitch *model.Switch) *model.Switch {
+	created := *fundSwitch
+	created.ID = r.nextSwitchID
+	created.CreatedAt = time.Now()
+	r.nextSwitchID++
+
+	sell, buy := *fundSwitch.Sell, *fundSwitch.Buy
+	sell.SwitchID, buy.SwitchID = created.ID, created.ID
+	created.Sell = r.create(&sell, model.TransactionTypeSwitchOut)
+	created.Buy = r.create(&buy, model.TransactionTypeSwitchIn)
+
+	stored := created
+	r.switches[stored.ID] = &stored
+	return &created
+}
+
+// GetSwitchByID retrieves a switch and both of its legs by the switch's ID
+func (r *InMemoryInvestmentRepository) GetSwitchByID(id uint) (*model.Switch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fundSwitch, exists := r.switches[id]
+	if !exists {
+		return nil, ErrSwitchNotFound
+	}
+	stored := *fundSwitch
+	sell, buy := *r.investments[fundSwitch.Sell.ID], *r.investments[fundSwitch.Buy.ID]
+	stored.Sell, stored.Buy = &sell, &buy
+	return &stored, nil
+}
+
+// unitsHeld returns the number of units of a fund a customer holds. The caller must hold the lock.
+func (r *InMemoryInvestmentRepository) unitsHeld(clientID, fundID uint) model.Units {
+	var held model.Units
+	for _, investment := range r.investments {
+		if investment.ClientID == clientID && investment.FundID == fundID && investment.Status.HoldsUnits() {
+			held += investment.Units
+		}
+	}
+	return held
+}
+
+// SumDealtUnits returns the number of units of each of the holdings that the customer holds through transactions
+// that have been dealt, leaving out pending ones. Holdings without any are left out.
+func (r *InMemoryInvestmentRepository) SumDealtUnits(holdings []model.HoldingKey) (map[model.HoldingKey]model.Units, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[model.HoldingKey]bool, len(holdings))
+	for _, holding := range holdings {
+		wanted[holding] = true
+	}
+	dealt := make(map[model.HoldingKey]model.Units)
+	for _, investment := range r.investments {
+		holding := model.HoldingKey{ClientID: investment.ClientID, FundID: investment.FundID}
+		if wanted[holding] && investment.Status != model.InvestmentStatusPending && investment.Status.HoldsUnits() {
+			dealt[holding] += investment.Units
+		}
+	}
+	return dealt, nil
+}
+
+// unitsDealt returns the number of units of a fund a customer holds through transactions that have been dealt,
+// leaving out pending ones. The caller must hold the lock.
+func (r *InMemoryInvestmentRepository) unitsDealt(clientID, fundID uint) model.Units {
+	var dealt model.Units
+	for _, investment := range r.investments {
+		if investment.ClientID == clientID && investment.FundID == fundID && investment.Status != model.InvestmentStatusPending && investment.Status.HoldsUnits() {
+			dealt += investment.Units
+		}
+	}
+	return dealt
+}
+
+// checkRunsUnpaid fails with ErrScheduleRunPaid when any of the investments pays in a schedule run that has
+// already been paid into its fund. The caller must hold the lock.
+func (r *InMemoryInvestmentRepository) checkRunsUnpaid(investments []*model.Investment) error {
+	for _, investment := range investments {
+		if investment.RunDate == nil {
+			continue
+		}
+		run := model.ScheduleRunKey{ScheduleID: investment.ScheduleID, RunDate: *investment.RunDate}
+		for _, existing := range r.investments {
+			if existing.FundID == investment.FundID && run.Pays(existing) {
+				return ErrScheduleRunPaid
+			}
+		}
+	}
+	return nil
+}
+
+// create stores a pending transaction of the given type and returns a copy of it. The caller must hold the write lock.
+func (r *InMemoryInvestmentRepository) create(investment *model.Investment, transactionType model.TransactionType) *model.Investment {
 	now := time.Now()
-	investment := &model.Investment{
-		ID:        r.nextID,
-		ClientID:  clientID,
-		FundID:    fundID,
-		Amount:    amount,
-		CreatedAt: now,
-		UpdatedAt: now,
+	created := *investment
+	created.ID = r.nextID
+	created.Type = transactionType
+	created.Status = model.InvestmentStatusPending
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	if investment.RunDate != nil {
+		runDate := *investment.RunDate
+		created.RunDate = &runDate
 	}
 
-	r.investments[investment.ID] = investment
+	r.investments[created.ID] = &created
 	r.nextID++
 
-	return investment, nil
+	stored := created
+	return &stored
 }
 
 // GetByID retrieves an investment by its ID
 func (r *InMemoryInvestmentRepository) GetInvestmentByID(id uint) (*model.Investment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	investment, exists := r.investments[id]
 	if !exists {
-		return nil, errors.New("investment not found")
+		return nil, ErrInvestmentNotFound
 	}
-	return investment, nil
+	stored := *investment
+	return &stored, nil
 }
 
-// GetInvestmentsByClientID retrieves all investments for a specific client
+// GetInvestmentsByClientID retrieves all investments for a specific client ordered by ID
 func (r *InMemoryInvestmentRepository) GetInvestmentsByClientID(clientID uint) ([]*model.Investment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	investments := make([]*model.Investment, 0)
 	for _, investment := range r.investments {
 		if investment.ClientID == clientID {
-			investments = append(investments, investment)
+			stored := *investment
+			investments = append(investments, &stored)
+		}
+	}
+	sort.Slice(investments, func(i, j int) bool {
+		return investments[i].ID < investments[j].ID
+	})
+	return investments, nil
+}
+
+// ListInvestments retrieves the transactions matching filter ordered by ID
+func (r *InMemoryInvestmentRepository) ListInvestments(filter model.InvestmentFilter) ([]*model.Investment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	investments := make([]*model.Investment, 0)
+	for _, investment := range r.investments {
+		if filter.Matches(investment) {
+			stored := *investment
+			investments = append(investments, &stored)
 		}
 	}
+	sort.Slice(investments, func(i, j int) bool {
+		return investments[i].ID < investments[j].ID
+	})
 	return investments, nil
 }
+
+// UpdateInvestmentStatus moves every transaction in ids from status from to status to, or none of them when any
+// is no longer in status from. Voiding transactions that bought units fails with ErrInsufficientUnits when the
+// units have already been sold.
+func (r *InMemoryInvestmentRepository) UpdateInvestmentStatus(ids []uint, from, to model.InvestmentStatus) ([]*model.Investment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type holding struct{ clientID, fundID uint }
+	released := make(map[holding]model.Units)
+	for _, id := range ids {
+		investment, exists := r.investments[id]
+		if !exists {
+			return nil, ErrInvestmentNotFound
+		}
+		if investment.Status != from {
+			return nil, ErrInvestmentStatusChanged
+		}
+		if from.HoldsUnits() && !to.HoldsUnits() {
+			released[holding{investment.ClientID, investment.FundID}] += investment.Units
+		}
+	}
+	for h, units := range released {
+		if r.unitsHeld(h.clientID, h.fundID) < units {
+			return nil, ErrInsufficientUnits
+		}
+	}
+
+	now := time.Now()
+	updated := make([]*model.Investment, len(ids))
+	for i, id := range ids {
+		investment := r.investments[id]
+		investment.Status = to
+		investment.UpdatedAt = now
+		stored := *investment
+		updated[i] = &stored
+	}
+	return updated, nil
+}
@@ -2,7 +2,11 @@ package repository
 
 import (
 	"errors"
+	"reflect"
 	"testing"
+	"time"
+
+	"cushon/internal/model"
 )
 
 func TestInMemoryInvestmentRepository_CreateInvestment(t *testing.T) {
@@ -10,35 +14,28 @@ func TestInMemoryInvestmentRepository_CreateInvestment(t *testing.T) {
 		name     string
 		clientID uint
 		fundID   uint
-		amount   float32
+		amount   model.Money
 		wantErr  error
 	}{
 		{
 			name:     "Valid investment",
 			clientID: 1,
 			fundID:   1,
-			amount:   1000.0,
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
 			wantErr:  nil,
 		},
 		{
-			name:     "Invalid client ID",
+			name:     "Client and fund IDs above 100",
 			clientID: 101,
-			fundID:   1,
-			amount:   1000.0,
-			wantErr:  errors.New("invalid user ID"),
-		},
-		{
-			name:     "Invalid fund ID",
-			clientID: 1,
 			fundID:   101,
-			amount:   1000.0,
-			wantErr:  errors.New("invalid fund ID"),
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
+			wantErr:  nil,
 		},
 		{
 			name:     "Zero amount",
 			clientID: 1,
 			fundID:   1,
-			amount:   0,
+			amount:   model.NewMoney(0, model.DefaultCurrency),
 			wantErr:  nil,
 		},
 	}
@@ -46,7 +43,7 @@ func TestInMemoryInvestmentRepository_CreateInvestment(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := NewInMemoryInvestmentRepository()
-			got, err := repo.CreateInvestment(tt.clientID, tt.fundID, tt.amount)
+			got, err := repo.CreateInvestment(&model.Investment{ClientID: tt.clientID, FundID: tt.fundID, Amount: tt.amount, Units: 400000000, Price: 2500000})
 
 			if tt.wantErr != nil {
 				if err == nil || err.Error() != tt.wantErr.Error() {
@@ -74,6 +71,12 @@ func TestInMemoryInvestmentRepository_CreateInvestment(t *testing.T) {
 			if got.Amount != tt.amount {
 				t.Errorf("Amount = %v, want %v", got.Amount, tt.amount)
 			}
+			if got.Units != 400000000 || got.Price != 2500000 {
+				t.Errorf("Units = %v at %v, want 400.000000 at 2.500000", got.Units, got.Price)
+			}
+			if got.ID == 0 || got.CreatedAt.IsZero() {
+				t.Errorf("CreateInvestment() = %+v, want an ID and creation time", got)
+			}
 		})
 	}
 }
@@ -88,7 +91,7 @@ func TestInMemoryInvestmentRepository_GetInvestmentByID(t *testing.T) {
 		{
 			name: "Existing investment",
 			setup: func(r *InMemoryInvestmentRepository) uint {
-				inv, _ := r.CreateInvestment(1, 1, 1000.0)
+				inv, _ := r.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(100000, model.DefaultCurrency)})
 				return inv.ID
 			},
 			wantErr: nil,
@@ -143,7 +146,7 @@ func TestInMemoryInvestmentRepository_GetInvestmentsByClientID(t *testing.T) {
 		{
 			name: "Single investment for client",
 			setup: func(r *InMemoryInvestmentRepository) {
-				r.CreateInvestment(1, 1, 1000.0)
+				r.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(100000, model.DefaultCurrency)})
 			},
 			clientID:  1,
 			wantCount: 1,
@@ -151,9 +154,9 @@ func TestInMemoryInvestmentRepository_GetInvestmentsByClientID(t *testing.T) {
 		{
 			name: "Multiple investments for client",
 			setup: func(r *InMemoryInvestmentRepository) {
-				r.CreateInvestment(1, 1, 1000.0)
-				r.CreateInvestment(1, 2, 2000.0)
-				r.CreateInvestment(1, 3, 3000.0)
+				r.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(100000, model.DefaultCurrency)})
+				r.CreateInvestment(&model.Investment{ClientID: 1, FundID: 2, Amount: model.NewMoney(200000, model.DefaultCurrency)})
+				r.CreateInvestment(&model.Investment{ClientID: 1, FundID: 3, Amount: model.NewMoney(300000, model.DefaultCurrency)})
 			},
 			clientID:  1,
 			wantCount: 3,
@@ -161,8 +164,8 @@ func TestInMemoryInvestmentRepository_GetInvestmentsByClientID(t *testing.T) {
 		{
 			name: "No investments for client",
 			setup: func(r *InMemoryInvestmentRepository) {
-				r.CreateInvestment(2, 1, 1000.0)
-				r.CreateInvestment(2, 2, 2000.0)
+				r.CreateInvestment(&model.Investment{ClientID: 2, FundID: 1, Amount: model.NewMoney(100000, model.DefaultCurrency)})
+				r.CreateInvestment(&model.Investment{ClientID: 2, FundID: 2, Amount: model.NewMoney(200000, model.DefaultCurrency)})
 			},
 			clientID:  1,
 			wantCount: 0,
@@ -204,3 +207,430 @@ func TestInMemoryInvestmentRepository_GetInvestmentsByClientID(t *testing.T) {
 		})
 	}
 }
+
+func TestInMemoryInvestmentRepository_CreateISAInvestments(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientID uint
+		amounts  []int64
+		wantErr  error
+	}{
+		{
+			name:     "Within the allowance",
+			clientID: 1,
+			amounts:  []int64{100000},
+		},
+		{
+			name:     "Up to the allowance",
+			clientID: 1,
+			amounts:  []int64{300000, 200000},
+		},
+		{
+			name:     "Over the allowance",
+			clientID: 1,
+			amounts:  []int64{500001},
+			wantErr:  ErrISAAllowanceExceeded,
+		},
+		{
+			name:     "Over the allowance together",
+			clientID: 1,
+			amounts:  []int64{300000, 200001},
+			wantErr:  ErrISAAllowanceExceeded,
+		},
+		{
+			name:     "Other customers' subscriptions don't count",
+			clientID: 2,
+			amounts:  []int64{2000000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewInMemoryInvestmentRepository()
+			repo.CreateISAInvestments([]*model.Investment{{ClientID: 1, FundID: 1, Amount: model.NewMoney(1500000, model.DefaultCurrency)}}, model.ISAAnnualAllowance)
+			cancelled, _ := repo.CreateISAInvestments([]*model.Investment{{ClientID: 1, FundID: 1, Amount: model.NewMoney(400000, model.DefaultCurrency)}}, model.ISAAnnualAllowance)
+			repo.UpdateInvestmentStatus([]uint{cancelled[0].ID}, model.InvestmentStatusPending, model.InvestmentStatusCancelled)
+			lastYear, _ := repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(2000000, model.DefaultCurrency)})
+			repo.investments[lastYear.ID].ISA = true
+			repo.investments[lastYear.ID].CreatedAt = model.TaxYearOf(time.Now()).Start().Add(-time.Second)
+			// Paid into a pension while the customer was employed, before they became retail
+			repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(2000000, model.DefaultCurrency)})
+
+			investments := make([]*model.Investment, len(tt.amounts))
+			for i, amount := range tt.amounts {
+				investments[i] = &model.Investment{ClientID: tt.clientID, FundID: 2, Amount: model.NewMoney(amount, model.DefaultCurrency)}
+			}
+			stored := len(repo.investments)
+			got, err := repo.CreateISAInvestments(investments, model.ISAAnnualAllowance)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateISAInvestments() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if len(repo.investments) != stored {
+					t.Errorf("CreateISAInvestments() stored %d investments, want none", len(repo.investments)-stored)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateISAInvestments() unexpected error = %v", err)
+			}
+			if len(got) != len(tt.amounts) {
+				t.Fatalf("CreateISAInvestments() returned %d investments, want %d", len(got), len(tt.amounts))
+			}
+			for i, investment := range got {
+				if investment.Type != model.TransactionTypeInvestment || investment.Status != model.InvestmentStatusPending || !investment.ISA || investment.Amount.Minor != tt.amounts[i] {
+					t.Errorf("investments[%d] = %+v, want a pending ISA investment of %d", i, investment, tt.amounts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestInMemoryInvestmentRepository_CreateInvestments(t *testing.T) {
+	repo := NewInMemoryInvestmentRepository()
+
+	got, err := repo.CreateInvestments([]*model.Investment{
+		{ClientID: 1, FundID: 1, Amount: model.NewMoney(12500, model.DefaultCurrency), Source: model.ContributionSourceEmployee},
+		{ClientID: 1, FundID: 1, Amount: model.NewMoney(7500, model.DefaultCurrency), Source: model.ContributionSourceEmployer},
+	})
+	if err != nil {
+		t.Fatalf("CreateInvestments() unexpected error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID == got[1].ID {
+		t.Fatalf("CreateInvestments() = %+v, want two investments with their own IDs", got)
+	}
+	for i, want := range []model.ContributionSource{model.ContributionSourceEmployee, model.ContributionSourceEmployer} {
+		stored, err := repo.GetInvestmentByID(got[i].ID)
+		if err != nil {
+			t.Fatalf("GetInvestmentByID() unexpected error = %v", err)
+		}
+		if stored.Type != model.TransactionTypeInvestment || stored.Status != model.InvestmentStatusPending || stored.Source != want {
+			t.Errorf("stored investment = %+v, want a pending %s investment", stored, want)
+		}
+	}
+}
+
+func TestInMemoryInvestmentRepository_CreateInvestments_ScheduleRuns(t *testing.T) {
+	repo := NewInMemoryInvestmentRepository()
+	runDate := model.NewDate(2026, time.October, 16)
+	regular := func(fundID uint, runDate model.Date) *model.Investment {
+		return &model.Investment{ClientID: 1, FundID: fundID, Amount: model.NewMoney(5000, model.DefaultCurrency), Source: model.ContributionSourceRegular, ScheduleID: 1, RunDate: &runDate}
+	}
+
+	got, err := repo.CreateInvestments([]*model.Investment{regular(1, runDate), regular(2, runDate)})
+	if err != nil {
+		t.Fatalf("CreateInvestments() unexpected error = %v", err)
+	}
+	run := model.ScheduleRunKey{ScheduleID: 1, RunDate: runDate}
+	if paid, _ := repo.ListInvestments(model.InvestmentFilter{Run: &run}); len(paid) != 2 || paid[0].ID != got[0].ID {
+		t.Errorf("ListInvestments() = %+v, want the run's two investments", paid)
+	}
+
+	// A run is paid into each fund at most once, however it is stored
+	if _, err := repo.CreateInvestments([]*model.Investment{regular(3, runDate), regular(2, runDate)}); !errors.Is(err, ErrScheduleRunPaid) {
+		t.Errorf("CreateInvestments() error = %v, want ErrScheduleRunPaid", err)
+	}
+	if _, err := repo.CreateInvestment(regular(1, runDate)); !errors.Is(err, ErrScheduleRunPaid) {
+		t.Errorf("CreateInvestment() error = %v, want ErrScheduleRunPaid", err)
+	}
+	if _, err := repo.CreateISAInvestments([]*model.Investment{regular(1, runDate)}, model.ISAAnnualAllowance); !errors.Is(err, ErrScheduleRunPaid) {
+		t.Errorf("CreateISAInvestments() error = %v, want ErrScheduleRunPaid", err)
+	}
+	if _, err := repo.CreateInvestment(regular(1, runDate.AddDays(7))); err != nil {
+		t.Errorf("CreateInvestment() for the next run unexpected error = %v", err)
+	}
+	if stored, _ := repo.GetInvestmentsByClientID(1); len(stored) != 3 {
+		t.Errorf("stored %d investments, want 3", len(stored))
+	}
+}
+
+func TestInMemoryInvestmentRepository_CreateWithdrawal(t *testing.T) {
+	tests := []struct {
+		name    string
+		fundID  uint
+		units   model.Units
+		wantErr error
+	}{
+		{
+			name:   "Part of the holding",
+			fundID: 1,
+			units:  -100000000,
+		},
+		{
+			name:   "Whole holding",
+			fundID: 1,
+			units:  -400000000,
+		},
+		{
+			name:    "More than the holding",
+			fundID:  1,
+			units:   -400000001,
+			wantErr: ErrInsufficientUnits,
+		},
+		{
+			name:    "Fund not held",
+			fundID:  2,
+			units:   -1,
+			wantErr: ErrInsufficientUnits,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewInMemoryInvestmentRepository()
+			repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(100000, model.DefaultCurrency), Units: 400000000})
+			repo.CreateInvestment(&model.Investment{ClientID: 2, FundID: 2, Amount: model.NewMoney(100000, model.DefaultCurrency), Units: 400000000})
+
+			got, err := repo.CreateWithdrawal(&model.Investment{ClientID: 1, FundID: tt.fundID, Amount: model.NewMoney(-100, model.DefaultCurrency), Units: tt.units})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateWithdrawal() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateWithdrawal() unexpected error = %v", err)
+			}
+			if got.Type != model.TransactionTypeWithdrawal || got.Units != tt.units {
+				t.Errorf("CreateWithdrawal() = %+v, want a withdrawal of %v units", got, tt.units)
+			}
+		})
+	}
+}
+
+func TestInMemoryInvestmentRepository_CreateSwitch(t *testing.T) {
+	newSwitch := func(units model.Units) *model.Switch {
+		return &model.Switch{
+			ClientID:   1,
+			FromFundID: 1,
+			ToFundID:   2,
+			Sell:       &model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(-10000, model.DefaultCurrency), Units: -units},
+			Buy:        &model.Investment{ClientID: 1, FundID: 2, Amount: model.NewMoney(10000, model.DefaultCurrency), Units: units},
+		}
+	}
+
+	repo := NewInMemoryInvestmentRepository()
+	repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(100000, model.DefaultCurrency), Units: 400000000})
+
+	got, err := repo.CreateSwitch(newSwitch(400000000))
+	if err != nil {
+		t.Fatalf("CreateSwitch() error = %v", err)
+	}
+	if got.ID == 0 || got.Sell.SwitchID != got.ID || got.Buy.SwitchID != got.ID {
+		t.Errorf("CreateSwitch() = %+v, want both legs to carry the switch's ID", got)
+	}
+	if got.Sell.Type != model.TransactionTypeSwitchOut || got.Buy.Type != model.TransactionTypeSwitchIn {
+		t.Errorf("CreateSwitch() legs have types %v and %v", got.Sell.Type, got.Buy.Type)
+	}
+
+	stored, err := repo.GetSwitchByID(got.ID)
+	if err != nil || stored.Sell.ID != got.Sell.ID || stored.Buy.ID != got.Buy.ID {
+		t.Errorf("GetSwitchByID() = %+v, %v, want %+v", stored, err, got)
+	}
+
+	// Fund 1 has been switched out entirely, so switching again must store neither leg
+	if _, err := repo.CreateSwitch(newSwitch(1)); !errors.Is(err, ErrInsufficientUnits) {
+		t.Errorf("CreateSwitch() error = %v, want ErrInsufficientUnits", err)
+	}
+	investments, _ := repo.GetInvestmentsByClientID(1)
+	if len(investments) != 3 {
+		t.Errorf("got %d transactions after a failed switch, want 3", len(investments))
+	}
+
+	if _, err := repo.GetSwitchByID(999); !errors.Is(err, ErrSwitchNotFound) {
+		t.Errorf("GetSwitchByID() error = %v, want ErrSwitchNotFound", err)
+	}
+}
+
+func TestInMemoryInvestmentRepository_CreateSwitches(t *testing.T) {
+	newSwitch := func(fromFundID, toFundID uint, units model.Units) *model.Switch {
+		return &model.Switch{
+			ClientID:   1,
+			FromFundID: fromFundID,
+			ToFundID:   toFundID,
+			Sell:       &model.Investment{ClientID: 1, FundID: fromFundID, Amount: model.NewMoney(-10000, model.DefaultCurrency), Units: -units},
+			Buy:        &model.Investment{ClientID: 1, FundID: toFundID, Amount: model.NewMoney(10000, model.DefaultCurrency), Units: units},
+		}
+	}
+
+	repo := NewInMemoryInvestmentRepository()
+	repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(100000, model.DefaultCurrency), Units: 400000000})
+
+	// Together the switches sell more of fund 1 than is held, so neither is stored
+	_, err := repo.CreateSwitches([]*model.Switch{newSwitch(1, 2, 300000000), newSwitch(1, 3, 200000000)})
+	if !errors.Is(err, ErrInsufficientUnits) {
+		t.Fatalf("CreateSwitches() error = %v, want ErrInsufficientUnits", err)
+	}
+	if investments, _ := repo.GetInvestmentsByClientID(1); len(investments) != 1 {
+		t.Fatalf("got %d transactions after failed switches, want 1", len(investments))
+	}
+
+	got, err := repo.CreateSwitches([]*model.Switch{newSwitch(1, 2, 300000000), newSwitch(1, 3, 100000000)})
+	if err != nil {
+		t.Fatalf("CreateSwitches() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID == got[1].ID || got[1].Buy.FundID != 3 || got[1].Buy.SwitchID != got[1].ID {
+		t.Errorf("CreateSwitches() = %+v, want both switches stored in order", got)
+	}
+	if investments, _ := repo.GetInvestmentsByClientID(1); len(investments) != 5 {
+		t.Errorf("got %d transactions after the switches, want 5", len(investments))
+	}
+}
+
+func TestInMemoryInvestmentRepository_UpdateInvestmentStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(*InMemoryInvestmentRepository) []uint
+		from    model.InvestmentStatus
+		to      model.InvestmentStatus
+		wantErr error
+	}{
+		{
+			name: "Place a pending investment",
+			setup: func(r *InMemoryInvestmentRepository) []uint {
+				inv, _ := r.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000})
+				return []uint{inv.ID}
+			},
+			from: model.InvestmentStatusPending,
+			to:   model.InvestmentStatusPlaced,
+		},
+		{
+			name: "Cancel both legs of a switch",
+			setup: func(r *InMemoryInvestmentRepository) []uint {
+				r.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000})
+				fundSwitch, _ := r.CreateSwitch(&model.Switch{
+					ClientID: 1, FromFundID: 1, ToFundID: 2,
+					Sell: &model.Investment{ClientID: 1, FundID: 1, Units: -1000},
+					Buy:  &model.Investment{ClientID: 1, FundID: 2, Units: 500},
+				})
+				return []uint{fundSwitch.Sell.ID, fundSwitch.Buy.ID}
+			},
+			from: model.InvestmentStatusPending,
+			to:   model.InvestmentStatusCancelled,
+		},
+		{
+			name: "Status already changed",
+			setup: func(r *InMemoryInvestmentRepository) []uint {
+				inv, _ := r.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000})
+				return []uint{inv.ID}
+			},
+			from:    model.InvestmentStatusPlaced,
+			to:      model.InvestmentStatusSettled,
+			wantErr: ErrInvestmentStatusChanged,
+		},
+		{
+			name: "Cancel an investment whose units were withdrawn",
+			setup: func(r *InMemoryInvestmentRepository) []uint {
+				inv, _ := r.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000})
+				r.CreateWithdrawal(&model.Investment{ClientID: 1, FundID: 1, Units: -600})
+				return []uint{inv.ID}
+			},
+			from:    model.InvestmentStatusPending,
+			to:      model.InvestmentStatusCancelled,
+			wantErr: ErrInsufficientUnits,
+		},
+		{
+			name: "Unknown investment",
+			setup: func(r *InMemoryInvestmentRepository) []uint {
+				inv, _ := r.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 1000})
+				return []uint{inv.ID, 999}
+			},
+			from:    model.InvestmentStatusPending,
+			to:      model.InvestmentStatusPlaced,
+			wantErr: ErrInvestmentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewInMemoryInvestmentRepository()
+			ids := tt.setup(repo)
+
+			got, err := repo.UpdateInvestmentStatus(ids, tt.from, tt.to)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("UpdateInvestmentStatus() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				// Nothing is updated when the update fails
+				for _, id := range ids {
+					if stored, err := repo.GetInvestmentByID(id); err == nil && stored.Status != model.InvestmentStatusPending {
+						t.Errorf("investment %d is %s after a failed update", id, stored.Status)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateInvestmentStatus() unexpected error = %v", err)
+			}
+			for i, id := range ids {
+				stored, _ := repo.GetInvestmentByID(id)
+				if got[i].ID != id || got[i].Status != tt.to || stored.Status != tt.to {
+					t.Errorf("investment %d = %+v, stored %+v, want status %s", id, got[i], stored, tt.to)
+				}
+			}
+		})
+	}
+}
+
+func TestInMemoryInvestmentRepository_ListInvestments(t *testing.T) {
+	repo := NewInMemoryInvestmentRepository()
+	for _, clientID := range []uint{1, 1, 2} {
+		repo.CreateInvestment(&model.Investment{ClientID: clientID, FundID: 1, Units: 1000})
+	}
+	if _, err := repo.UpdateInvestmentStatus([]uint{2}, model.InvestmentStatusPending, model.InvestmentStatusCancelled); err != nil {
+		t.Fatalf("UpdateInvestmentStatus() error = %v", err)
+	}
+
+	client1 := uint(1)
+	pending, cancelled := model.InvestmentStatusPending, model.InvestmentStatusCancelled
+	tests := []struct {
+		name    string
+		filter  model.InvestmentFilter
+		wantIDs []uint
+	}{
+		{name: "No filter", filter: model.InvestmentFilter{}, wantIDs: []uint{1, 2, 3}},
+		{name: "By client", filter: model.InvestmentFilter{ClientID: &client1}, wantIDs: []uint{1, 2}},
+		{name: "By status", filter: model.InvestmentFilter{Status: &pending}, wantIDs: []uint{1, 3}},
+		{name: "By client and status", filter: model.InvestmentFilter{ClientID: &client1, Status: &cancelled}, wantIDs: []uint{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ListInvestments(tt.filter)
+			if err != nil {
+				t.Fatalf("ListInvestments() error = %v", err)
+			}
+			gotIDs := make([]uint, len(got))
+			for i, investment := range got {
+				gotIDs[i] = investment.ID
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("ListInvestments() IDs = %v, want %v", gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestInMemoryInvestmentRepository_SumDealtUnits(t *testing.T) {
+	repo := NewInMemoryInvestmentRepository()
+	settled, _ := repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 100000000})
+	placed, _ := repo.CreateWithdrawal(&model.Investment{ClientID: 1, FundID: 1, Units: -30000000})
+	failed, _ := repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 500000000})
+	repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Units: 70000000})
+	other, _ := repo.CreateInvestment(&model.Investment{ClientID: 2, FundID: 1, Units: 40000000})
+	repo.UpdateInvestmentStatus([]uint{settled.ID, placed.ID, failed.ID, other.ID}, model.InvestmentStatusPending, model.InvestmentStatusPlaced)
+	repo.UpdateInvestmentStatus([]uint{settled.ID}, model.InvestmentStatusPlaced, model.InvestmentStatusSettled)
+	repo.UpdateInvestmentStatus([]uint{failed.ID}, model.InvestmentStatusPlaced, model.InvestmentStatusFailed)
+
+	// Pending and failed transactions haven't been dealt, and holdings that weren't asked for aren't totalled
+	got, err := repo.SumDealtUnits([]model.HoldingKey{{ClientID: 1, FundID: 1}, {ClientID: 1, FundID: 2}})
+	if err != nil {
+		t.Fatalf("SumDealtUnits() unexpected error = %v", err)
+	}
+	if want := map[model.HoldingKey]model.Units{{ClientID: 1, FundID: 1}: 70000000}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SumDealtUnits() = %v, want %v", got, want)
+	}
+}
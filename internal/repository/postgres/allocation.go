@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"database/sql"
+	"time"
+)
+
+// AllocationRepository is a PostgreSQL implementation of repository.AllocationRepository
+type AllocationRepository struct {
+	db *sql.DB
+}
+
+// NewAllocationRepository creates a new PostgreSQL allocation repository
+func NewAllocationRepository(db *sql.DB) *AllocationRepository {
+	return &AllocationRepository{db: db}
+}
+
+// GetAllocation retrieves a customer's target allocation
+func (r *AllocationRepository) GetAllocation(customerID uint) (*model.TargetAllocation, error) {
+	rows, err := r.db.Query(
+		`SELECT fund_id, percentage, updated_at FROM target_allocations
+		 WHERE customer_id = $1
+		 ORDER BY position`,
+		customerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	allocation := &model.TargetAllocation{CustomerID: customerID}
+	for rows.Next() {
+		var fund model.FundAllocation
+		if err := rows.Scan(&fund.FundID, &fund.Percentage, &allocation.UpdatedAt); err != nil {
+			return nil, err
+		}
+		allocation.Funds = append(allocation.Funds, fund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(allocation.Funds) == 0 {
+		return nil, repository.ErrAllocationNotFound
+	}
+	return allocation, nil
+}
+
+// ListAllocations retrieves every customer's target allocation, ordered by customer ID
+func (r *AllocationRepository) ListAllocations() ([]*model.TargetAllocation, error) {
+	rows, err := r.db.Query(
+		`SELECT customer_id, fund_id, percentage, updated_at FROM target_allocations
+		 ORDER BY customer_id, position`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	allocations := make([]*model.TargetAllocation, 0)
+	for rows.Next() {
+		var customerID uint
+		var fund model.FundAllocation
+		var updatedAt time.Time
+		if err := rows.Scan(&customerID, &fund.FundID, &fund.Percentage, &updatedAt); err != nil {
+			return nil, err
+		}
+		if len(allocations) == 0 || allocations[len(allocations)-1].CustomerID != customerID {
+			allocations = append(allocations, &model.TargetAllocation{CustomerID: customerID, UpdatedAt: updatedAt})
+		}
+		last := allocations[len(allocations)-1]
+		last.Funds = append(last.Funds, fund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return allocations, nil
+}
+
+// SetAllocation replaces a customer's target allocation in a single transaction. The foreign keys guarantee
+// that the customer and the funds exist.
+func (r *AllocationRepository) SetAllocation(allocation *model.TargetAllocation) (*model.TargetAllocation, error) {
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM target_allocations WHERE customer_id = $1`, allocation.CustomerID); err != nil {
+			return err
+		}
+		for position, fund := range allocation.Funds {
+			_, err := tx.Exec(
+				`INSERT INTO target_allocations (customer_id, position, fund_id, percentage, updated_at)
+				 VALUES ($1, $2, $3, $4, now())`,
+				allocation.CustomerID, position, fund.FundID, fund.Percentage,
+			)
+			if constraint, ok := violatedForeignKey(err); ok {
+				if constraint == "target_allocations_fund_id_fkey" {
+					return repository.ErrFundNotFound
+				}
+				return repository.ErrCustomerNotFound
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetAllocation(allocation.CustomerID)
+}
+
+// DeleteAllocation removes a customer's target allocation
+func (r *AllocationRepository) DeleteAllocation(customerID uint) error {
+	result, err := r.db.Exec(`DELETE FROM target_allocations WHERE customer_id = $1`, customerID)
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return repository.ErrAllocationNotFound
+	}
+	return nil
+}
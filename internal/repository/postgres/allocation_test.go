@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"cushon/internal/model"
+	"cushon/internal/repository"
+)
+
+func TestAllocationRepository(t *testing.T) {
+	db := openTestDB(t)
+	seedInvestmentFixtures(t, NewInvestmentRepository(db))
+	repo := NewAllocationRepository(db)
+
+	if _, err := repo.GetAllocation(1); !errors.Is(err, repository.ErrAllocationNotFound) {
+		t.Fatalf("GetAllocation() error = %v, want ErrAllocationNotFound", err)
+	}
+
+	set, err := repo.SetAllocation(&model.TargetAllocation{
+		CustomerID: 1,
+		Funds:      []model.FundAllocation{{FundID: 2, Percentage: 60000}, {FundID: 1, Percentage: 40000}},
+	})
+	if err != nil {
+		t.Fatalf("SetAllocation() error = %v", err)
+	}
+	if len(set.Funds) != 2 || set.Funds[0] != (model.FundAllocation{FundID: 2, Percentage: 60000}) || set.UpdatedAt.IsZero() {
+		t.Errorf("SetAllocation() = %+v, want the funds in the order they were given", set)
+	}
+
+	replaced, err := repo.SetAllocation(&model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}}})
+	if err != nil {
+		t.Fatalf("SetAllocation() error = %v", err)
+	}
+	if len(replaced.Funds) != 1 || replaced.Funds[0].FundID != 1 {
+		t.Errorf("SetAllocation() = %+v, want the replacement allocation", replaced.Funds)
+	}
+
+	if _, err := repo.SetAllocation(&model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 99, Percentage: model.OneHundredPercent}}}); !errors.Is(err, repository.ErrFundNotFound) {
+		t.Errorf("SetAllocation() error = %v, want ErrFundNotFound", err)
+	}
+	if got, _ := repo.GetAllocation(1); got == nil || got.Funds[0].FundID != 1 {
+		t.Errorf("GetAllocation() = %+v, want the allocation kept after a failed replacement", got)
+	}
+	if _, err := repo.SetAllocation(&model.TargetAllocation{CustomerID: 99, Funds: []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}}}); !errors.Is(err, repository.ErrCustomerNotFound) {
+		t.Errorf("SetAllocation() error = %v, want ErrCustomerNotFound", err)
+	}
+
+	if err := repo.DeleteAllocation(1); err != nil {
+		t.Fatalf("DeleteAllocation() error = %v", err)
+	}
+	if err := repo.DeleteAllocation(1); !errors.Is(err, repository.ErrAllocationNotFound) {
+		t.Errorf("DeleteAllocation() error = %v, want ErrAllocationNotFound", err)
+	}
+
+	for _, customerID := range []uint{2, 1} {
+		if _, err := repo.SetAllocation(&model.TargetAllocation{CustomerID: customerID, Funds: []model.FundAllocation{{FundID: 2, Percentage: 60000}, {FundID: 1, Percentage: 40000}}}); err != nil {
+			t.Fatalf("SetAllocation() error = %v", err)
+		}
+	}
+	all, err := repo.ListAllocations()
+	if err != nil {
+		t.Fatalf("ListAllocations() error = %v", err)
+	}
+	if len(all) != 2 || all[0].CustomerID != 1 || all[1].CustomerID != 2 || len(all[1].Funds) != 2 || all[1].Funds[0].FundID != 2 {
+		t.Errorf("ListAllocations() = %+v, want both allocations by customer with their funds in order", all)
+	}
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"log"
+)
+
+// APIKeyRepository is a PostgreSQL implementation of repository.APIKeyRepository.
+// Only a SHA-256 hash of each key is stored.
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates a new PostgreSQL API key repository
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// ValidateKey implements the APIKeyRepository interface
+func (r *APIKeyRepository) ValidateKey(key string) bool {
+	var exists bool
+	err := r.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM api_keys WHERE key_hash = $1)`,
+		hashKey(key),
+	).Scan(&exists)
+	if err != nil {
+		log.Printf("validating API key: %v", err)
+		return false
+	}
+	return exists
+}
+
+// AddKey adds a new API key to the repository. Adding an existing key is a no-op.
+func (r *APIKeyRepository) AddKey(key string) error {
+	_, err := r.db.Exec(
+		`INSERT INTO api_keys (key_hash) VALUES ($1) ON CONFLICT (key_hash) DO NOTHING`,
+		hashKey(key),
+	)
+	return err
+}
+
+// hashKey returns the hex encoded SHA-256 hash of an API key
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
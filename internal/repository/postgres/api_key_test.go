@@ -0,0 +1,24 @@
+package postgres
+
+import "testing"
+
+func TestAPIKeyRepository(t *testing.T) {
+	repo := NewAPIKeyRepository(openTestDB(t))
+
+	if repo.ValidateKey("valid-key") {
+		t.Error("ValidateKey() = true before the key was added")
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := repo.AddKey("valid-key"); err != nil {
+			t.Fatalf("AddKey() error = %v", err)
+		}
+	}
+
+	if !repo.ValidateKey("valid-key") {
+		t.Error("ValidateKey() = false for an added key")
+	}
+	if repo.ValidateKey("invalid-key") {
+		t.Error("ValidateKey() = true for an unknown key")
+	}
+}
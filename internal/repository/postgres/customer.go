@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"database/sql"
+	"errors"
+)
+
+// customerColumns lists the columns read by scanCustomer, in order
+const customerColumns = `id, name, employer_id, date_of_birth, retirement_age, glide_path_id, created_at, updated_at`
+
+// CustomerRepository is a PostgreSQL implementation of repository.CustomerRepository
+type CustomerRepository struct {
+	db *sql.DB
+}
+
+// NewCustomerRepository creates a new PostgreSQL customer repository
+func NewCustomerRepository(db *sql.DB) *CustomerRepository {
+	return &CustomerRepository{db: db}
+}
+
+// CreateCustomer creates a new customer
+func (r *CustomerRepository) CreateCustomer(customerName string, employerID *uint) (*model.Customer, error) {
+	if customerName == "" {
+		return nil, errors.New("customer name cannot be empty")
+	}
+
+	row := r.db.QueryRow(
+		`INSERT INTO customers (name, employer_id, created_at, updated_at)
+		 VALUES ($1, $2, now(), now())
+		 RETURNING `+customerColumns,
+		customerName, employerID,
+	)
+
+	customer, err := scanCustomer(row)
+	if err != nil {
+		if _, ok := violatedForeignKey(err); ok {
+			return nil, repository.ErrEmployerNotFound
+		}
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// GetCustomerByID retrieves a customer by its ID
+func (r *CustomerRepository) GetCustomerByID(id uint) (*model.Customer, error) {
+	row := r.db.QueryRow(`SELECT `+customerColumns+` FROM customers WHERE id = $1`, id)
+
+	customer, err := scanCustomer(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// ListCustomers retrieves the customers matching filter ordered by ID
+func (r *CustomerRepository) ListCustomers(filter model.CustomerFilter) ([]*model.Customer, error) {
+	rows, err := r.db.Query(
+		`SELECT `+customerColumns+` FROM customers
+		 WHERE ($1::BIGINT IS NULL OR employer_id = $1)
+		   AND (NOT $2 OR employer_id IS NULL)
+		   AND (NOT $3 OR glide_path_id IS NOT NULL)
+		 ORDER BY id`,
+		filter.EmployerID, filter.RetailOnly, filter.OnGlidePath,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := make([]*model.Customer, 0)
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, customer)
+	}
+	return customers, rows.Err()
+}
+
+// UpdateCustomer passes a customer to update and stores the name, employer and retirement plan it leaves, or
+// nothing if update returns an error. The customer's row is locked until it is stored, so concurrent updates
+// can't overwrite each other's changes.
+func (r *CustomerRepository) UpdateCustomer(id uint, update func(customer *model.Customer) error) (*model.Customer, error) {
+	var customer *model.Customer
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		current, err := scanCustomer(tx.QueryRow(`SELECT `+customerColumns+` FROM customers WHERE id = $1 FOR UPDATE`, id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrCustomerNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := update(current); err != nil {
+			return err
+		}
+		if current.Name == "" {
+			return errors.New("customer name cannot be empty")
+		}
+
+		customer, err = scanCustomer(tx.QueryRow(
+			`UPDATE customers
+			 SET name = $2, employer_id = $3, date_of_birth = $4, retirement_age = $5, glide_path_id = $6, updated_at = now()
+			 WHERE id = $1
+			 RETURNING `+customerColumns,
+			id, current.Name, current.EmployerID, nullableDate(current.DateOfBirth), current.RetirementAge, current.GlidePathID,
+		))
+		if constraint, ok := violatedForeignKey(err); ok {
+			if constraint == "customers_glide_path_id_fkey" {
+				return repository.ErrGlidePathNotFound
+			}
+			return repository.ErrEmployerNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// DeleteCustomerIfNoInvestments removes a customer, or fails with ErrCustomerHasInvestments if they have any
+// transactions. The customer's row is locked while it checks, so no transactions can be stored for them in the
+// meantime.
+func (r *CustomerRepository) DeleteCustomerIfNoInvestments(id uint) error {
+	return inTx(r.db, func(tx *sql.Tx) error {
+		if err := lockCustomer(tx, id); err != nil {
+			return err
+		}
+
+		var invested bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM investments WHERE client_id = $1)`, id).Scan(&invested); err != nil {
+			return err
+		}
+		if invested {
+			return repository.ErrCustomerHasInvestments
+		}
+
+		_, err := tx.Exec(`DELETE FROM customers WHERE id = $1`, id)
+		return err
+	})
+}
+
+// scanCustomer reads a row selected with customerColumns
+func scanCustomer(row scanner) (*model.Customer, error) {
+	customer := &model.Customer{}
+	var dateOfBirth sql.NullTime
+	err := row.Scan(
+		&customer.ID,
+		&customer.Name,
+		&customer.EmployerID,
+		&dateOfBirth,
+		&customer.RetirementAge,
+		&customer.GlidePathID,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if dateOfBirth.Valid {
+		date := model.DateOf(dateOfBirth.Time)
+		customer.DateOfBirth = &date
+	}
+	return customer, nil
+}
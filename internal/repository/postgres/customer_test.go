@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"cushon/internal/model"
+	"cushon/internal/repository"
+)
+
+func TestCustomerRepository_CreateCustomer(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := NewEmployerRepository(db).CreateEmployer("Acme Corp"); err != nil {
+		t.Fatalf("CreateEmployer() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		customerName string
+		employerID   *uint
+		wantErr      error
+	}{
+		{
+			name:         "Valid retail customer",
+			customerName: "John Doe",
+			employerID:   nil,
+			wantErr:      nil,
+		},
+		{
+			name:         "Valid employed customer",
+			customerName: "Jane Smith",
+			employerID:   uintPtr(1),
+			wantErr:      nil,
+		},
+		{
+			name:         "Empty customer name",
+			customerName: "",
+			employerID:   nil,
+			wantErr:      errors.New("customer name cannot be empty"),
+		},
+		{
+			name:         "Unknown employer",
+			customerName: "Jim Brown",
+			employerID:   uintPtr(99),
+			wantErr:      repository.ErrEmployerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewCustomerRepository(db)
+			got, err := repo.CreateCustomer(tt.customerName, tt.employerID)
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Errorf("CreateCustomer() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CreateCustomer() unexpected error = %v", err)
+			}
+			if got.ID == 0 {
+				t.Error("CreateCustomer() did not assign an ID")
+			}
+			if got.Name != tt.customerName {
+				t.Errorf("Name = %v, want %v", got.Name, tt.customerName)
+			}
+			if (got.EmployerID == nil) != (tt.employerID == nil) {
+				t.Errorf("EmployerID = %v, want %v", got.EmployerID, tt.employerID)
+			}
+			if got.CreatedAt.IsZero() || got.UpdatedAt.IsZero() {
+				t.Error("CreateCustomer() did not set timestamps")
+			}
+		})
+	}
+}
+
+func TestCustomerRepository_GetCustomerByID(t *testing.T) {
+	repo := NewCustomerRepository(openTestDB(t))
+	created, err := repo.CreateCustomer("John Doe", nil)
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+
+	got, err := repo.GetCustomerByID(created.ID)
+	if err != nil {
+		t.Fatalf("GetCustomerByID() error = %v", err)
+	}
+	if got.ID != created.ID || got.Name != created.Name || got.EmployerID != nil {
+		t.Errorf("GetCustomerByID() = %+v, want %+v", got, created)
+	}
+
+	if _, err := repo.GetCustomerByID(999); !errors.Is(err, repository.ErrCustomerNotFound) {
+		t.Errorf("GetCustomerByID() error = %v, want ErrCustomerNotFound", err)
+	}
+}
+
+func TestCustomerRepository_ListCustomers(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := NewEmployerRepository(db).CreateEmployer("Acme Corp"); err != nil {
+		t.Fatalf("CreateEmployer() error = %v", err)
+	}
+	repo := NewCustomerRepository(db)
+	for _, employerID := range []*uint{nil, uintPtr(1), nil} {
+		if _, err := repo.CreateCustomer("Customer", employerID); err != nil {
+			t.Fatalf("CreateCustomer() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		filter  model.CustomerFilter
+		wantIDs []uint
+	}{
+		{name: "All customers", filter: model.CustomerFilter{}, wantIDs: []uint{1, 2, 3}},
+		{name: "Customers of an employer", filter: model.CustomerFilter{EmployerID: uintPtr(1)}, wantIDs: []uint{2}},
+		{name: "Retail customers", filter: model.CustomerFilter{RetailOnly: true}, wantIDs: []uint{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ListCustomers(tt.filter)
+			if err != nil {
+				t.Fatalf("ListCustomers() error = %v", err)
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("ListCustomers() returned %d customers, want %d", len(got), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if got[i].ID != id {
+					t.Errorf("customers[%d].ID = %v, want %v", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+// replaceCustomer returns an update for UpdateCustomer that replaces a customer's name, employer and retirement plan
+func replaceCustomer(name string, employerID *uint, plan model.RetirementPlan) func(customer *model.Customer) error {
+	return func(customer *model.Customer) error {
+		customer.Name, customer.EmployerID, customer.RetirementPlan = name, employerID, plan
+		return nil
+	}
+}
+
+func TestCustomerRepository_UpdateCustomer(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := NewEmployerRepository(db).CreateEmployer("Acme Corp"); err != nil {
+		t.Fatalf("CreateEmployer() error = %v", err)
+	}
+	repo := NewCustomerRepository(db)
+	created, err := repo.CreateCustomer("Jane Smith", nil)
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+
+	got, err := repo.UpdateCustomer(created.ID, replaceCustomer("Jane Doe", uintPtr(1), model.RetirementPlan{}))
+	if err != nil {
+		t.Fatalf("UpdateCustomer() error = %v", err)
+	}
+	if got.Name != "Jane Doe" || got.EmployerID == nil || *got.EmployerID != 1 {
+		t.Errorf("UpdateCustomer() = %+v", got)
+	}
+	if got.UpdatedAt.Before(created.UpdatedAt) {
+		t.Errorf("UpdatedAt = %v, want after %v", got.UpdatedAt, created.UpdatedAt)
+	}
+
+	if _, err := repo.UpdateCustomer(created.ID, replaceCustomer("Jane Doe", uintPtr(99), model.RetirementPlan{})); !errors.Is(err, repository.ErrEmployerNotFound) {
+		t.Errorf("UpdateCustomer() error = %v, want ErrEmployerNotFound", err)
+	}
+	if _, err := repo.UpdateCustomer(999, replaceCustomer("Jane Doe", nil, model.RetirementPlan{})); !errors.Is(err, repository.ErrCustomerNotFound) {
+		t.Errorf("UpdateCustomer() error = %v, want ErrCustomerNotFound", err)
+	}
+
+	rejected := errors.New("employer inactive")
+	_, err = repo.UpdateCustomer(created.ID, func(customer *model.Customer) error {
+		customer.Name = "John Doe"
+		return rejected
+	})
+	if !errors.Is(err, rejected) {
+		t.Errorf("UpdateCustomer() error = %v, want %v", err, rejected)
+	}
+	if stored, _ := repo.GetCustomerByID(created.ID); stored.Name != "Jane Doe" {
+		t.Errorf("Name = %v after a rejected update, want Jane Doe", stored.Name)
+	}
+}
+
+func TestCustomerRepository_DeleteCustomerIfNoInvestments(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewCustomerRepository(db)
+	fund, err := NewFundRepository(db).CreateFund(model.FundCreate{Name: "Equities Fund"})
+	if err != nil {
+		t.Fatalf("CreateFund() error = %v", err)
+	}
+	invested, err := repo.CreateCustomer("John Doe", nil)
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+	if _, err := NewInvestmentRepository(db).CreateInvestment(&model.Investment{ClientID: invested.ID, FundID: fund.ID, Amount: model.NewMoney(1000, model.DefaultCurrency)}); err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+	uninvested, err := repo.CreateCustomer("Jane Smith", nil)
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+
+	if err := repo.DeleteCustomerIfNoInvestments(invested.ID); !errors.Is(err, repository.ErrCustomerHasInvestments) {
+		t.Errorf("DeleteCustomerIfNoInvestments() error = %v, want ErrCustomerHasInvestments", err)
+	}
+	if err := repo.DeleteCustomerIfNoInvestments(uninvested.ID); err != nil {
+		t.Fatalf("DeleteCustomerIfNoInvestments() error = %v", err)
+	}
+	if err := repo.DeleteCustomerIfNoInvestments(uninvested.ID); !errors.Is(err, repository.ErrCustomerNotFound) {
+		t.Errorf("DeleteCustomerIfNoInvestments() error = %v, want ErrCustomerNotFound", err)
+	}
+}
@@ -0,0 +1,239 @@
+package postgres
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/lib/pq"
+)
+
+// dealingBatchQuery selects the columns read by scanDealingBatch. Conditions are added with the WHERE clause
+// placeholder before the batches are grouped with the IDs of the transactions dealt in them.
+const dealingBatchQuery = `SELECT b.id, b.fund_id, b.dealing_date, b.price, b.currency, b.bought_minor, b.sold_minor,
+		b.units_bought, b.units_sold, b.created_at,
+		COALESCE(array_agg(i.id ORDER BY i.id) FILTER (WHERE i.id IS NOT NULL), '{}')
+	FROM dealing_batches b
+	LEFT JOIN investments i ON i.batch_id = b.id
+	%s
+	GROUP BY b.id
+	ORDER BY b.id`
+
+// DealingRepository is a PostgreSQL implementation of repository.DealingRepository
+type DealingRepository struct {
+	db *sql.DB
+}
+
+// NewDealingRepository creates a new PostgreSQL dealing repository
+func NewDealingRepository(db *sql.DB) *DealingRepository {
+	return &DealingRepository{db: db}
+}
+
+// CreateDealingBatches stores the batches, places the orders dealt in them and fails the orders that couldn't be
+// dealt in a single transaction, or does none of these when any order is no longer pending, any fund has already
+// been dealt on the batch's date or a customer would be left holding fewer dealt units than they have sold, which
+// fails with ErrInsufficientUnits. The orders' rows are locked while their status is checked, and their
+// customers' rows while their holdings are.
+func (r *DealingRepository) CreateDealingBatches(batches []*model.DealingBatch, orders, failed []*model.Investment) ([]*model.DealingBatch, error) {
+	keys := make([]int64, 0, len(orders)+len(failed))
+	for _, order := range orders {
+		keys = append(keys, int64(order.ID))
+	}
+	failedKeys := make([]int64, len(failed))
+	for i, order := range failed {
+		failedKeys[i] = int64(order.ID)
+	}
+	keys = append(keys, failedKeys...)
+
+	created := make([]*model.DealingBatch, len(batches))
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT id, client_id, status FROM investments WHERE id = ANY($1) ORDER BY id FOR UPDATE`, pq.Array(keys))
+		if err != nil {
+			return err
+		}
+		statuses := make(map[uint]model.InvestmentStatus)
+		clients := make(map[uint]uint)
+		for rows.Next() {
+			var id, clientID uint
+			var status model.InvestmentStatus
+			if err := rows.Scan(&id, &clientID, &status); err != nil {
+				rows.Close()
+				return err
+			}
+			statuses[id] = status
+			clients[id] = clientID
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			status, exists := statuses[uint(key)]
+			if !exists {
+				return repository.ErrInvestmentNotFound
+			}
+			if status != model.InvestmentStatusPending {
+				return repository.ErrInvestmentStatusChanged
+			}
+		}
+
+		// Customers are locked in ID order so concurrent runs can't deadlock
+		locked := make(map[uint]bool)
+		var clientKeys []int64
+		for _, order := range orders {
+			if clientID := clients[order.ID]; !locked[clientID] {
+				locked[clientID] = true
+				clientKeys = append(clientKeys, int64(clientID))
+			}
+		}
+		sort.Slice(clientKeys, func(i, j int) bool {
+			return clientKeys[i] < clientKeys[j]
+		})
+		for _, clientID := range clientKeys {
+			if err := lockCustomer(tx, uint(clientID)); err != nil {
+				return err
+			}
+		}
+
+		batchIDs := make(map[uint]uint, len(batches))
+		for i, batch := range batches {
+			stored := *batch
+			stored.InvestmentIDs = append([]uint(nil), batch.InvestmentIDs...)
+			err := tx.QueryRow(
+				`INSERT INTO dealing_batches (fund_id, dealing_date, price, currency, bought_minor, sold_minor, units_bought, units_sold, created_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now())
+				 RETURNING id, created_at`,
+				batch.FundID, batch.DealingDate.Time, batch.Price, batch.Bought.Currency, batch.Bought.Minor, batch.Sold.Minor,
+				batch.UnitsBought, batch.UnitsSold,
+			).Scan(&stored.ID, &stored.CreatedAt)
+			if err != nil {
+				if _, ok := violatedUnique(err); ok {
+					return repository.ErrDuplicateDealingBatch
+				}
+				if _, ok := violatedForeignKey(err); ok {
+					return repository.ErrFundNotFound
+				}
+				return err
+			}
+			batchIDs[stored.FundID] = stored.ID
+			created[i] = &stored
+		}
+
+		for _, order := range orders {
+			_, err := tx.Exec(
+				`UPDATE investments
+				 SET amount_minor = $2, units = $3, price = $4, price_date = $5, status = 'placed', batch_id = $6, updated_at = now()
+				 WHERE id = $1`,
+				order.ID, order.Amount.Minor, order.Units, order.Price, order.PriceDate.Time, batchIDs[order.FundID],
+			)
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(
+			`UPDATE investments SET status = 'failed', updated_at = now() WHERE id = ANY($1)`,
+			pq.Array(failedKeys),
+		); err != nil {
+			return err
+		}
+
+		var short bool
+		err = tx.QueryRow(
+			`SELECT EXISTS (
+				SELECT 1 FROM investments
+				WHERE client_id = ANY($1) AND status IN ('placed', 'settled')
+				GROUP BY client_id, fund_id
+				HAVING SUM(units) < 0
+			)`,
+			pq.Array(clientKeys),
+		).Scan(&short)
+		if err != nil {
+			return err
+		}
+		if short {
+			return repository.ErrInsufficientUnits
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetDealingBatchByID retrieves a dealing batch by its ID
+func (r *DealingRepository) GetDealingBatchByID(id uint) (*model.DealingBatch, error) {
+	row := r.db.QueryRow(fmt.Sprintf(dealingBatchQuery, `WHERE b.id = $1`), id)
+
+	batch, err := scanDealingBatch(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrDealingBatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// ListDealingBatches retrieves the batches matching filter ordered by ID
+func (r *DealingRepository) ListDealingBatches(filter model.DealingBatchFilter) ([]*model.DealingBatch, error) {
+	var dealingDate, fundID any
+	if filter.DealingDate != nil {
+		dealingDate = filter.DealingDate.Time
+	}
+	if filter.FundID != nil {
+		fundID = *filter.FundID
+	}
+
+	rows, err := r.db.Query(
+		fmt.Sprintf(dealingBatchQuery, `WHERE ($1::DATE IS NULL OR b.dealing_date = $1)
+	  AND ($2::BIGINT IS NULL OR b.fund_id = $2)`),
+		dealingDate, fundID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batches := make([]*model.DealingBatch, 0)
+	for rows.Next() {
+		batch, err := scanDealingBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+	return batches, rows.Err()
+}
+
+// scanDealingBatch reads a row selected with dealingBatchQuery
+func scanDealingBatch(row scanner) (*model.DealingBatch, error) {
+	batch := &model.DealingBatch{}
+	var investmentIDs pq.Int64Array
+	err := row.Scan(
+		&batch.ID,
+		&batch.FundID,
+		&batch.DealingDate.Time,
+		&batch.Price,
+		&batch.Bought.Currency,
+		&batch.Bought.Minor,
+		&batch.Sold.Minor,
+		&batch.UnitsBought,
+		&batch.UnitsSold,
+		&batch.CreatedAt,
+		&investmentIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	batch.DealingDate = model.DateOf(batch.DealingDate.Time)
+	batch.Sold.Currency = batch.Bought.Currency
+	batch.InvestmentIDs = make([]uint, len(investmentIDs))
+	for i, id := range investmentIDs {
+		batch.InvestmentIDs[i] = uint(id)
+	}
+	return batch, nil
+}
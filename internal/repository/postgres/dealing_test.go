@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"cushon/internal/model"
+	"cushon/internal/repository"
+)
+
+func TestDealingRepository_CreateDealingBatches(t *testing.T) {
+	db := openTestDB(t)
+	investments := NewInvestmentRepository(db)
+	seedInvestmentFixtures(t, investments)
+	repo := NewDealingRepository(db)
+
+	gbp := func(minor int64) model.Money { return model.NewMoney(minor, model.DefaultCurrency) }
+	friday := model.NewDate(2026, time.October, 16)
+
+	buy, err := investments.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: gbp(10000), Units: 40000000})
+	if err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+	other, err := investments.CreateInvestment(&model.Investment{ClientID: 2, FundID: 2, Amount: gbp(5000), Units: 5000000})
+	if err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+
+	orders := []*model.Investment{
+		{ID: buy.ID, FundID: 1, Amount: gbp(10000), Units: 50000000, Price: 2000000, PriceDate: friday},
+		{ID: other.ID, FundID: 2, Amount: gbp(5000), Units: 2500000, Price: 2000000, PriceDate: friday},
+	}
+	batches := []*model.DealingBatch{
+		{FundID: 1, DealingDate: friday, Price: 2000000, Bought: gbp(10000), Sold: gbp(0), UnitsBought: 50000000, InvestmentIDs: []uint{buy.ID}},
+		{FundID: 2, DealingDate: friday, Price: 2000000, Bought: gbp(5000), Sold: gbp(0), UnitsBought: 2500000, InvestmentIDs: []uint{other.ID}},
+	}
+	created, err := repo.CreateDealingBatches(batches, orders, nil)
+	if err != nil {
+		t.Fatalf("CreateDealingBatches() error = %v", err)
+	}
+	if len(created) != 2 || created[0].ID == 0 {
+		t.Fatalf("CreateDealingBatches() = %+v, want two batches", created)
+	}
+
+	placed, err := investments.GetInvestmentByID(buy.ID)
+	if err != nil {
+		t.Fatalf("GetInvestmentByID() error = %v", err)
+	}
+	if placed.Status != model.InvestmentStatusPlaced || placed.BatchID != created[0].ID || placed.Units != 50000000 ||
+		placed.Price != 2000000 || !placed.PriceDate.Equal(friday.Time) {
+		t.Errorf("investment after dealing = %+v, want it placed in batch %d", placed, created[0].ID)
+	}
+
+	got, err := repo.GetDealingBatchByID(created[0].ID)
+	if err != nil {
+		t.Fatalf("GetDealingBatchByID() error = %v", err)
+	}
+	if got.FundID != 1 || !got.DealingDate.Equal(friday.Time) || got.Bought != gbp(10000) || got.Sold != gbp(0) ||
+		got.UnitsBought != 50000000 || !reflect.DeepEqual(got.InvestmentIDs, []uint{buy.ID}) {
+		t.Errorf("GetDealingBatchByID() = %+v", got)
+	}
+	if _, err := repo.GetDealingBatchByID(999); !errors.Is(err, repository.ErrDealingBatchNotFound) {
+		t.Errorf("GetDealingBatchByID() error = %v, want ErrDealingBatchNotFound", err)
+	}
+
+	fundID := uint(2)
+	for _, tt := range []struct {
+		filter model.DealingBatchFilter
+		want   int
+	}{
+		{filter: model.DealingBatchFilter{}, want: 2},
+		{filter: model.DealingBatchFilter{DealingDate: &friday}, want: 2},
+		{filter: model.DealingBatchFilter{FundID: &fundID}, want: 1},
+	} {
+		got, err := repo.ListDealingBatches(tt.filter)
+		if err != nil || len(got) != tt.want {
+			t.Errorf("ListDealingBatches(%+v) = %d batches, %v, want %d", tt.filter, len(got), err, tt.want)
+		}
+	}
+
+	// Placed orders can't be dealt again, and each fund is dealt at most once a day
+	if _, err := repo.CreateDealingBatches(batches[:1], orders[:1], nil); !errors.Is(err, repository.ErrInvestmentStatusChanged) {
+		t.Errorf("CreateDealingBatches() error = %v, want ErrInvestmentStatusChanged", err)
+	}
+	late, err := investments.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: gbp(1000), Units: 500000})
+	if err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+	lateOrder := *orders[0]
+	lateOrder.ID = late.ID
+	if _, err := repo.CreateDealingBatches(batches[:1], []*model.Investment{&lateOrder}, nil); !errors.Is(err, repository.ErrDuplicateDealingBatch) {
+		t.Errorf("CreateDealingBatches() error = %v, want ErrDuplicateDealingBatch", err)
+	}
+
+	// The pending late order counts towards the withdrawal but not towards the units dealt, so selling them all
+	// can't be placed and can only be failed
+	sell, err := investments.CreateWithdrawal(&model.Investment{ClientID: 1, FundID: 1, Amount: gbp(-10100), Units: -50500000})
+	if err != nil {
+		t.Fatalf("CreateWithdrawal() error = %v", err)
+	}
+	monday := friday.AddDays(3)
+	sold := model.Investment{ID: sell.ID, FundID: 1, Amount: gbp(-10100), Units: -50500000, Price: 2000000, PriceDate: monday}
+	sellBatch := []*model.DealingBatch{{FundID: 1, DealingDate: monday, Price: 2000000, Bought: gbp(0), Sold: gbp(10100), UnitsSold: 50500000, InvestmentIDs: []uint{sell.ID}}}
+	if _, err := repo.CreateDealingBatches(sellBatch, []*model.Investment{&sold}, nil); !errors.Is(err, repository.ErrInsufficientUnits) {
+		t.Errorf("CreateDealingBatches() error = %v, want ErrInsufficientUnits", err)
+	}
+	if _, err := repo.CreateDealingBatches(nil, nil, []*model.Investment{&sold}); err != nil {
+		t.Fatalf("CreateDealingBatches() error = %v", err)
+	}
+	if failed, err := investments.GetInvestmentByID(sell.ID); err != nil || failed.Status != model.InvestmentStatusFailed {
+		t.Errorf("withdrawal after dealing = %+v, %v, want it failed", failed, err)
+	}
+}
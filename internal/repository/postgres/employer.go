@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"database/sql"
+	"errors"
+)
+
+// employerColumns lists the columns read by scanEmployer, in order
+const employerColumns = `id, name, active, employee_rate, employer_rate, matching, employer_cap, employer_cap_currency, salary_sacrifice, default_fund_id, created_at, updated_at`
+
+// EmployerRepository is a PostgreSQL implementation of repository.EmployerRepository
+type EmployerRepository struct {
+	db *sql.DB
+}
+
+// NewEmployerRepository creates a new PostgreSQL employer repository
+func NewEmployerRepository(db *sql.DB) *EmployerRepository {
+	return &EmployerRepository{db: db}
+}
+
+// CreateEmployer creates a new employer
+func (r *EmployerRepository) CreateEmployer(name string) (*model.Employer, error) {
+	if name == "" {
+		return nil, errors.New("employer name cannot be empty")
+	}
+
+	row := r.db.QueryRow(
+		`INSERT INTO employers (name, active, created_at, updated_at)
+		 VALUES ($1, true, now(), now())
+		 RETURNING `+employerColumns,
+		name,
+	)
+	return scanEmployer(row)
+}
+
+// GetEmployerByID retrieves an employer by its ID
+func (r *EmployerRepository) GetEmployerByID(id uint) (*model.Employer, error) {
+	row := r.db.QueryRow(`SELECT `+employerColumns+` FROM employers WHERE id = $1`, id)
+
+	employer, err := scanEmployer(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrEmployerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return employer, nil
+}
+
+// ListEmployers retrieves the employers matching filter ordered by ID
+func (r *EmployerRepository) ListEmployers(filter model.EmployerFilter) ([]*model.Employer, error) {
+	rows, err := r.db.Query(
+		`SELECT `+employerColumns+` FROM employers WHERE (NOT $1 OR active) ORDER BY id`,
+		filter.ActiveOnly,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	employers := make([]*model.Employer, 0)
+	for rows.Next() {
+		employer, err := scanEmployer(rows)
+		if err != nil {
+			return nil, err
+		}
+		employers = append(employers, employer)
+	}
+	return employers, rows.Err()
+}
+
+// UpdateEmployer renames an employer and replaces its contribution scheme and default fund. A nil scheme or
+// default fund removes it. The foreign key guarantees that the default fund exists.
+func (r *EmployerRepository) UpdateEmployer(id uint, name string, scheme *model.ContributionScheme, defaultFundID *uint) (*model.Employer, error) {
+	if name == "" {
+		return nil, errors.New("employer name cannot be empty")
+	}
+
+	var employeeRate, employerRate, employerCap sql.NullInt64
+	var matching, salarySacrifice sql.NullBool
+	var employerCapCurrency sql.NullString
+	if scheme != nil {
+		employeeRate = sql.NullInt64{Int64: int64(scheme.EmployeeRate), Valid: true}
+		employerRate = sql.NullInt64{Int64: int64(scheme.EmployerRate), Valid: true}
+		matching = sql.NullBool{Bool: scheme.Matching, Valid: true}
+		salarySacrifice = sql.NullBool{Bool: scheme.SalarySacrifice, Valid: true}
+		if scheme.EmployerCap != nil {
+			employerCap = sql.NullInt64{Int64: scheme.EmployerCap.Minor, Valid: true}
+			employerCapCurrency = sql.NullString{String: scheme.EmployerCap.Currency, Valid: true}
+		}
+	}
+
+	row := r.db.QueryRow(
+		`UPDATE employers
+		 SET name = $2, employee_rate = $3, employer_rate = $4, matching = $5, employer_cap = $6,
+		     employer_cap_currency = $7, salary_sacrifice = $8, default_fund_id = $9, updated_at = now()
+		 WHERE id = $1
+		 RETURNING `+employerColumns,
+		id, name, employeeRate, employerRate, matching, employerCap, employerCapCurrency, salarySacrifice, defaultFundID,
+	)
+
+	employer, err := scanEmployer(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrEmployerNotFound
+	}
+	if _, ok := violatedForeignKey(err); ok {
+		return nil, repository.ErrFundNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return employer, nil
+}
+
+// DeactivateEmployer marks an employer as no longer active. Deactivating an inactive employer has no effect.
+func (r *EmployerRepository) DeactivateEmployer(id uint) (*model.Employer, error) {
+	row := r.db.QueryRow(
+		`UPDATE employers
+		 SET updated_at = CASE WHEN active THEN now() ELSE updated_at END, active = false
+		 WHERE id = $1
+		 RETURNING `+employerColumns,
+		id,
+	)
+
+	employer, err := scanEmployer(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrEmployerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return employer, nil
+}
+
+// scanEmployer reads a row selected with employerColumns
+func scanEmployer(row scanner) (*model.Employer, error) {
+	employer := &model.Employer{}
+	var employeeRate, employerRate, employerCap sql.NullInt64
+	var matching, salarySacrifice sql.NullBool
+	var employerCapCurrency sql.NullString
+	err := row.Scan(
+		&employer.ID, &employer.Name, &employer.Active,
+		&employeeRate, &employerRate, &matching, &employerCap, &employerCapCurrency, &salarySacrifice,
+		&employer.DefaultFundID, &employer.CreatedAt, &employer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if employeeRate.Valid {
+		employer.ContributionScheme = &model.ContributionScheme{
+			EmployeeRate:    model.Percent(employeeRate.Int64),
+			EmployerRate:    model.Percent(employerRate.Int64),
+			Matching:        matching.Bool,
+			SalarySacrifice: salarySacrifice.Bool,
+		}
+		if employerCap.Valid {
+			capAmount := model.NewMoney(employerCap.Int64, employerCapCurrency.String)
+			employer.ContributionScheme.EmployerCap = &capAmount
+		}
+	}
+	return employer, nil
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"cushon/internal/model"
+	"cushon/internal/repository"
+)
+
+func TestEmployerRepository_CreateEmployer(t *testing.T) {
+	db := openTestDB(t)
+
+	tests := []struct {
+		name    string
+		empName string
+		wantID  uint
+		wantErr error
+	}{
+		{
+			name:    "Valid employer",
+			empName: "Test Employer",
+			wantID:  1,
+			wantErr: nil,
+		},
+		{
+			name:    "Empty employer name",
+			empName: "",
+			wantErr: errors.New("employer name cannot be empty"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewEmployerRepository(db)
+			got, err := repo.CreateEmployer(tt.empName)
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Errorf("CreateEmployer() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CreateEmployer() unexpected error = %v", err)
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("ID = %v, want %v", got.ID, tt.wantID)
+			}
+			if got.Name != tt.empName {
+				t.Errorf("Name = %v, want %v", got.Name, tt.empName)
+			}
+		})
+	}
+}
+
+func TestEmployerRepository_GetEmployerByID(t *testing.T) {
+	repo := NewEmployerRepository(openTestDB(t))
+	created, err := repo.CreateEmployer("Test Employer")
+	if err != nil {
+		t.Fatalf("CreateEmployer() error = %v", err)
+	}
+
+	got, err := repo.GetEmployerByID(created.ID)
+	if err != nil {
+		t.Fatalf("GetEmployerByID() error = %v", err)
+	}
+	if got.ID != created.ID || got.Name != created.Name {
+		t.Errorf("GetEmployerByID() = %+v, want %+v", got, created)
+	}
+
+	if _, err := repo.GetEmployerByID(999); !errors.Is(err, repository.ErrEmployerNotFound) {
+		t.Errorf("GetEmployerByID() error = %v, want ErrEmployerNotFound", err)
+	}
+}
+
+func TestEmployerRepository_ListEmployers(t *testing.T) {
+	repo := NewEmployerRepository(openTestDB(t))
+	for _, name := range []string{"First", "Second", "Third"} {
+		if _, err := repo.CreateEmployer(name); err != nil {
+			t.Fatalf("CreateEmployer() error = %v", err)
+		}
+	}
+	if _, err := repo.DeactivateEmployer(2); err != nil {
+		t.Fatalf("DeactivateEmployer() error = %v", err)
+	}
+
+	all, err := repo.ListEmployers(model.EmployerFilter{})
+	if err != nil {
+		t.Fatalf("ListEmployers() error = %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("ListEmployers() returned %d employers, want 3", len(all))
+	}
+
+	active, err := repo.ListEmployers(model.EmployerFilter{ActiveOnly: true})
+	if err != nil {
+		t.Fatalf("ListEmployers() error = %v", err)
+	}
+	if len(active) != 2 || active[0].ID != 1 || active[1].ID != 3 {
+		t.Errorf("ListEmployers(ActiveOnly) = %+v, want employers 1 and 3", active)
+	}
+}
+
+func TestEmployerRepository_UpdateAndDeactivateEmployer(t *testing.T) {
+	repo := NewEmployerRepository(openTestDB(t))
+	created, err := repo.CreateEmployer("Test Employer")
+	if err != nil {
+		t.Fatalf("CreateEmployer() error = %v", err)
+	}
+	if !created.Active || created.CreatedAt.IsZero() {
+		t.Errorf("CreateEmployer() = %+v, want an active employer with timestamps", created)
+	}
+
+	renamed, err := repo.UpdateEmployer(created.ID, "Renamed Employer", nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateEmployer() error = %v", err)
+	}
+	if renamed.Name != "Renamed Employer" {
+		t.Errorf("Name = %v, want Renamed Employer", renamed.Name)
+	}
+	if _, err := repo.UpdateEmployer(999, "Renamed Employer", nil, nil); !errors.Is(err, repository.ErrEmployerNotFound) {
+		t.Errorf("UpdateEmployer() error = %v, want ErrEmployerNotFound", err)
+	}
+
+	deactivated, err := repo.DeactivateEmployer(created.ID)
+	if err != nil {
+		t.Fatalf("DeactivateEmployer() error = %v", err)
+	}
+	if deactivated.Active {
+		t.Error("DeactivateEmployer() returned an active employer")
+	}
+	if _, err := repo.DeactivateEmployer(999); !errors.Is(err, repository.ErrEmployerNotFound) {
+		t.Errorf("DeactivateEmployer() error = %v, want ErrEmployerNotFound", err)
+	}
+}
+
+func TestEmployerRepository_ContributionScheme(t *testing.T) {
+	repo := NewEmployerRepository(openTestDB(t))
+	created, err := repo.CreateEmployer("Test Employer")
+	if err != nil {
+		t.Fatalf("CreateEmployer() error = %v", err)
+	}
+	if created.ContributionScheme != nil {
+		t.Errorf("CreateEmployer() ContributionScheme = %+v, want nil", created.ContributionScheme)
+	}
+
+	employerCap := model.NewMoney(50000, model.DefaultCurrency)
+	schemes := []*model.ContributionScheme{
+		{EmployeeRate: 5000, EmployerRate: 3000},
+		{EmployeeRate: 4000, EmployerRate: 6000, Matching: true, EmployerCap: &employerCap, SalarySacrifice: true},
+		nil,
+	}
+	for _, scheme := range schemes {
+		if _, err := repo.UpdateEmployer(created.ID, created.Name, scheme, nil); err != nil {
+			t.Fatalf("UpdateEmployer() error = %v", err)
+		}
+		stored, err := repo.GetEmployerByID(created.ID)
+		if err != nil {
+			t.Fatalf("GetEmployerByID() error = %v", err)
+		}
+		if !reflect.DeepEqual(stored.ContributionScheme, scheme) {
+			t.Errorf("ContributionScheme = %+v, want %+v", stored.ContributionScheme, scheme)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"database/sql"
+	"errors"
+)
+
+// fundColumns lists the columns read by scanFund, in order. Funds created before fund details were
+// recorded have no ISIN, asset class or risk rating, which are read as zero values.
+const fundColumns = `id, name, COALESCE(isin, ''), COALESCE(asset_class, ''), COALESCE(risk_rating, 0),
+	ongoing_charges, currency, description, status, created_at, updated_at`
+
+// FundRepository is a PostgreSQL implementation of repository.FundRepository
+type FundRepository struct {
+	db *sql.DB
+}
+
+// NewFundRepository creates a new PostgreSQL fund repository
+func NewFundRepository(db *sql.DB) *FundRepository {
+	return &FundRepository{db: db}
+}
+
+// CreateFund creates a new fund. New funds are open.
+func (r *FundRepository) CreateFund(create model.FundCreate) (*model.Fund, error) {
+	if create.Name == "" {
+		return nil, errors.New("fund name cannot be empty")
+	}
+
+	row := r.db.QueryRow(
+		`INSERT INTO funds (name, isin, asset_class, risk_rating, ongoing_charges, currency, description,
+		                    status, created_at, updated_at)
+		 VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, 0), $5, $6, $7, $8, now(), now())
+		 RETURNING `+fundColumns,
+		create.Name, create.ISIN, create.AssetClass, create.RiskRating, create.OngoingCharges,
+		create.Currency, create.Description, model.FundStatusOpen,
+	)
+
+	fund, err := scanFund(row)
+	if err != nil {
+		if _, ok := violatedUnique(err); ok {
+			return nil, repository.ErrDuplicateISIN
+		}
+		return nil, err
+	}
+	return fund, nil
+}
+
+// GetFundByID retrieves a fund by its ID
+func (r *FundRepository) GetFundByID(id uint) (*model.Fund, error) {
+	row := r.db.QueryRow(`SELECT `+fundColumns+` FROM funds WHERE id = $1`, id)
+
+	fund, err := scanFund(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrFundNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fund, nil
+}
+
+// ListFunds retrieves the funds matching filter ordered by ID
+func (r *FundRepository) ListFunds(filter model.FundFilter) ([]*model.Fund, error) {
+	rows, err := r.db.Query(
+		`SELECT `+fundColumns+` FROM funds
+		 WHERE ($1::TEXT IS NULL OR status = $1)
+		   AND ($2::TEXT IS NULL OR asset_class = $2)
+		   AND ($3::INT = 0 OR risk_rating >= $3)
+		   AND ($4::INT = 0 OR risk_rating <= $4)
+		   AND ($5::BIGINT IS NULL OR ongoing_charges <= $5)
+		   AND ($6::TEXT = '' OR currency = $6)
+		 ORDER BY id`,
+		filter.Status, filter.AssetClass, filter.MinRisk, filter.MaxRisk, filter.MaxOngoingCharges, filter.Currency,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	funds := make([]*model.Fund, 0)
+	for rows.Next() {
+		fund, err := scanFund(rows)
+		if err != nil {
+			return nil, err
+		}
+		funds = append(funds, fund)
+	}
+	return funds, rows.Err()
+}
+
+// UpdateFund replaces the name, status and descriptive details of the fund with fund.ID.
+// The ISIN and currency are fixed when the fund is created.
+func (r *FundRepository) UpdateFund(fund *model.Fund) (*model.Fund, error) {
+	if fund.Name == "" {
+		return nil, errors.New("fund name cannot be empty")
+	}
+	if !fund.Status.Valid() {
+		return nil, errors.New("invalid fund status")
+	}
+
+	row := r.db.QueryRow(
+		`UPDATE funds
+		 SET name = $2, status = $3, asset_class = NULLIF($4, ''), risk_rating = NULLIF($5, 0),
+		     ongoing_charges = $6, description = $7, updated_at = now()
+		 WHERE id = $1
+		 RETURNING `+fundColumns,
+		fund.ID, fund.Name, fund.Status, fund.AssetClass, fund.RiskRating, fund.OngoingCharges, fund.Description,
+	)
+
+	updated, err := scanFund(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrFundNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// scanFund reads a row selected with fundColumns
+func scanFund(row scanner) (*model.Fund, error) {
+	fund := &model.Fund{}
+	err := row.Scan(
+		&fund.ID, &fund.Name, &fund.ISIN, &fund.AssetClass, &fund.RiskRating,
+		&fund.OngoingCharges, &fund.Currency, &fund.Description, &fund.Status, &fund.CreatedAt, &fund.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return fund, nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"database/sql"
+	"errors"
+)
+
+// fundPriceColumns lists the columns read by scanFundPrice, in order
+const fundPriceColumns = `fund_id, price_date, nav, currency, created_at`
+
+// FundPriceRepository is a PostgreSQL implementation of repository.FundPriceRepository
+type FundPriceRepository struct {
+	db *sql.DB
+}
+
+// NewFundPriceRepository creates a new PostgreSQL fund price repository
+func NewFundPriceRepository(db *sql.DB) *FundPriceRepository {
+	return &FundPriceRepository{db: db}
+}
+
+// CreateFundPrice records a fund's price for a date. The foreign key guarantees that the fund exists.
+func (r *FundPriceRepository) CreateFundPrice(price *model.FundPrice) (*model.FundPrice, error) {
+	row := r.db.QueryRow(
+		`INSERT INTO fund_prices (fund_id, price_date, nav, currency, created_at)
+		 VALUES ($1, $2, $3, $4, now())
+		 RETURNING `+fundPriceColumns,
+		price.FundID, price.Date.Time, price.NAV, price.Currency,
+	)
+
+	created, err := scanFundPrice(row)
+	if err != nil {
+		if _, ok := violatedUnique(err); ok {
+			return nil, repository.ErrDuplicateFundPrice
+		}
+		if _, ok := violatedForeignKey(err); ok {
+			return nil, repository.ErrFundNotFound
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetLatestFundPrice retrieves a fund's most recent price on or before a date
+func (r *FundPriceRepository) GetLatestFundPrice(fundID uint, onOrBefore model.Date) (*model.FundPrice, error) {
+	row := r.db.QueryRow(
+		`SELECT `+fundPriceColumns+` FROM fund_prices
+		 WHERE fund_id = $1 AND price_date <= $2
+		 ORDER BY price_date DESC
+		 LIMIT 1`,
+		fundID, onOrBefore.Time,
+	)
+
+	price, err := scanFundPrice(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrFundPriceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// ListFundPrices retrieves a fund's prices matching filter ordered by date
+func (r *FundPriceRepository) ListFundPrices(fundID uint, filter model.FundPriceFilter) ([]*model.FundPrice, error) {
+	rows, err := r.db.Query(
+		`SELECT `+fundPriceColumns+` FROM fund_prices
+		 WHERE fund_id = $1
+		   AND ($2::DATE IS NULL OR price_date >= $2)
+		   AND ($3::DATE IS NULL OR price_date <= $3)
+		 ORDER BY price_date`,
+		fundID, nullableDate(filter.From), nullableDate(filter.To),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := make([]*model.FundPrice, 0)
+	for rows.Next() {
+		price, err := scanFundPrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+	return prices, rows.Err()
+}
+
+// scanFundPrice reads a row selected with fundPriceColumns
+func scanFundPrice(row scanner) (*model.FundPrice, error) {
+	price := &model.FundPrice{}
+	err := row.Scan(&price.FundID, &price.Date.Time, &price.NAV, &price.Currency, &price.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	price.Date = model.DateOf(price.Date.Time)
+	return price, nil
+}
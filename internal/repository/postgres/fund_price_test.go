@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/model"
+	"cushon/internal/repository"
+)
+
+func TestFundPriceRepository(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := NewFundRepository(db).CreateFund(model.FundCreate{Name: "Fund1", Currency: "GBP"}); err != nil {
+		t.Fatalf("CreateFund() error = %v", err)
+	}
+	repo := NewFundPriceRepository(db)
+
+	for _, day := range []int{16, 12, 14} {
+		price := &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, day), NAV: model.Price(day) * 100000, Currency: "GBP"}
+		got, err := repo.CreateFundPrice(price)
+		if err != nil {
+			t.Fatalf("CreateFundPrice() error = %v", err)
+		}
+		if got.Date != price.Date || got.NAV != price.NAV || got.Currency != "GBP" {
+			t.Errorf("CreateFundPrice() = %+v, want %+v", got, price)
+		}
+	}
+
+	duplicate := &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 1000000, Currency: "GBP"}
+	if _, err := repo.CreateFundPrice(duplicate); !errors.Is(err, repository.ErrDuplicateFundPrice) {
+		t.Errorf("CreateFundPrice() twice error = %v, want ErrDuplicateFundPrice", err)
+	}
+	unknownFund := &model.FundPrice{FundID: 101, Date: model.NewDate(2026, time.October, 16), NAV: 1000000, Currency: "GBP"}
+	if _, err := repo.CreateFundPrice(unknownFund); !errors.Is(err, repository.ErrFundNotFound) {
+		t.Errorf("CreateFundPrice() for an unknown fund error = %v, want ErrFundNotFound", err)
+	}
+
+	latest, err := repo.GetLatestFundPrice(1, model.NewDate(2026, time.October, 15))
+	if err != nil {
+		t.Fatalf("GetLatestFundPrice() error = %v", err)
+	}
+	if latest.Date != model.NewDate(2026, time.October, 14) {
+		t.Errorf("GetLatestFundPrice() date = %v, want 2026-10-14", latest.Date)
+	}
+	if _, err := repo.GetLatestFundPrice(1, model.NewDate(2026, time.October, 11)); !errors.Is(err, repository.ErrFundPriceNotFound) {
+		t.Errorf("GetLatestFundPrice() before the first price error = %v, want ErrFundPriceNotFound", err)
+	}
+
+	from := model.NewDate(2026, time.October, 13)
+	prices, err := repo.ListFundPrices(1, model.FundPriceFilter{From: &from})
+	if err != nil {
+		t.Fatalf("ListFundPrices() error = %v", err)
+	}
+	if len(prices) != 2 || prices[0].Date.Day() != 14 || prices[1].Date.Day() != 16 {
+		t.Errorf("ListFundPrices() = %+v, want the prices of the 14th and 16th", prices)
+	}
+}
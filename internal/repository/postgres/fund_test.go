@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"cushon/internal/model"
+	"cushon/internal/repository"
+)
+
+func TestFundRepository_CreateFund(t *testing.T) {
+	db := openTestDB(t)
+
+	tests := []struct {
+		name     string
+		fundName string
+		wantErr  error
+	}{
+		{
+			name:     "Valid fund",
+			fundName: "Test Fund",
+			wantErr:  nil,
+		},
+		{
+			name:     "Empty fund name",
+			fundName: "",
+			wantErr:  errors.New("fund name cannot be empty"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewFundRepository(db)
+			got, err := repo.CreateFund(model.FundCreate{Name: tt.fundName})
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Errorf("CreateFund() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CreateFund() unexpected error = %v", err)
+			}
+			if got.ID == 0 || got.Name != tt.fundName {
+				t.Errorf("CreateFund() = %+v, want fund named %v", got, tt.fundName)
+			}
+		})
+	}
+}
+
+func TestFundRepository_ListFunds(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewFundRepository(db)
+
+	got, err := repo.ListFunds(model.FundFilter{})
+	if err != nil {
+		t.Fatalf("ListFunds() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %d funds, want 0", len(got))
+	}
+
+	for _, name := range []string{"Test Fund 1", "Test Fund 2"} {
+		if _, err := repo.CreateFund(model.FundCreate{Name: name}); err != nil {
+			t.Fatalf("CreateFund() error = %v", err)
+		}
+	}
+
+	got, err = repo.ListFunds(model.FundFilter{})
+	if err != nil {
+		t.Fatalf("ListFunds() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d funds, want 2", len(got))
+	}
+	for i, want := range []string{"Test Fund 1", "Test Fund 2"} {
+		if got[i].ID != uint(i+1) || got[i].Name != want {
+			t.Errorf("fund[%d] = %+v, want ID %d named %v", i, got[i], i+1, want)
+		}
+	}
+}
+
+func TestFundRepository_GetFundByID(t *testing.T) {
+	repo := NewFundRepository(openTestDB(t))
+	created, err := repo.CreateFund(model.FundCreate{Name: "Test Fund"})
+	if err != nil {
+		t.Fatalf("CreateFund() error = %v", err)
+	}
+
+	got, err := repo.GetFundByID(created.ID)
+	if err != nil {
+		t.Fatalf("GetFundByID() error = %v", err)
+	}
+	if got.ID != created.ID || got.Name != created.Name {
+		t.Errorf("GetFundByID() = %+v, want %+v", got, created)
+	}
+
+	if _, err := repo.GetFundByID(999); !errors.Is(err, repository.ErrFundNotFound) {
+		t.Errorf("GetFundByID() error = %v, want ErrFundNotFound", err)
+	}
+}
+
+func TestFundRepository_UpdateFund(t *testing.T) {
+	repo := NewFundRepository(openTestDB(t))
+	created, err := repo.CreateFund(model.FundCreate{Name: "Equities Fund"})
+	if err != nil {
+		t.Fatalf("CreateFund() error = %v", err)
+	}
+	if created.Status != model.FundStatusOpen {
+		t.Errorf("CreateFund() Status = %v, want %v", created.Status, model.FundStatusOpen)
+	}
+
+	got, err := repo.UpdateFund(&model.Fund{ID: created.ID, Name: "Global Equities", Status: model.FundStatusClosed})
+	if err != nil {
+		t.Fatalf("UpdateFund() error = %v", err)
+	}
+	if got.Name != "Global Equities" || got.Status != model.FundStatusClosed {
+		t.Errorf("UpdateFund() = %+v", got)
+	}
+
+	stored, err := repo.GetFundByID(created.ID)
+	if err != nil {
+		t.Fatalf("GetFundByID() error = %v", err)
+	}
+	if stored.Status != model.FundStatusClosed {
+		t.Errorf("stored Status = %v, want %v", stored.Status, model.FundStatusClosed)
+	}
+
+	if _, err := repo.UpdateFund(&model.Fund{ID: 999, Name: "Global Equities", Status: model.FundStatusOpen}); !errors.Is(err, repository.ErrFundNotFound) {
+		t.Errorf("UpdateFund() error = %v, want ErrFundNotFound", err)
+	}
+}
+
+func TestFundRepository_FundDetails(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewFundRepository(db)
+
+	create := model.FundCreate{
+		Name:           "Global Equities",
+		ISIN:           "IE00B4L5Y983",
+		AssetClass:     model.AssetClassEquity,
+		RiskRating:     6,
+		OngoingCharges: 200,
+		Currency:       "GBP",
+		Description:    "Tracks the MSCI World index",
+	}
+	created, err := repo.CreateFund(create)
+	if err != nil {
+		t.Fatalf("CreateFund() error = %v", err)
+	}
+	if created.ISIN != create.ISIN || created.AssetClass != create.AssetClass || created.RiskRating != create.RiskRating ||
+		created.OngoingCharges != create.OngoingCharges || created.Currency != create.Currency || created.Description != create.Description {
+		t.Errorf("CreateFund() = %+v, want details of %+v", created, create)
+	}
+
+	if _, err := repo.CreateFund(model.FundCreate{Name: "Copy", ISIN: create.ISIN}); !errors.Is(err, repository.ErrDuplicateISIN) {
+		t.Errorf("CreateFund() duplicate ISIN error = %v, want ErrDuplicateISIN", err)
+	}
+
+	if _, err := repo.CreateFund(model.FundCreate{Name: "Gilts", ISIN: "GB00B3X7QG63", AssetClass: model.AssetClassBond, RiskRating: 3}); err != nil {
+		t.Fatalf("CreateFund() error = %v", err)
+	}
+
+	equity := model.AssetClassEquity
+	got, err := repo.ListFunds(model.FundFilter{AssetClass: &equity, MinRisk: 5})
+	if err != nil {
+		t.Fatalf("ListFunds() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != created.ID {
+		t.Errorf("ListFunds() = %+v, want only %v", got, created.Name)
+	}
+
+	got, err = repo.ListFunds(model.FundFilter{MaxRisk: 4})
+	if err != nil {
+		t.Fatalf("ListFunds() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "Gilts" {
+		t.Errorf("ListFunds() = %+v, want only Gilts", got)
+	}
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"database/sql"
+)
+
+// GlidePathRepository is a PostgreSQL implementation of repository.GlidePathRepository
+type GlidePathRepository struct {
+	db *sql.DB
+}
+
+// NewGlidePathRepository creates a new PostgreSQL glide path repository
+func NewGlidePathRepository(db *sql.DB) *GlidePathRepository {
+	return &GlidePathRepository{db: db}
+}
+
+// CreateGlidePath stores a new glide path and its stages in a single transaction. The foreign keys guarantee
+// that the funds exist.
+func (r *GlidePathRepository) CreateGlidePath(glidePath *model.GlidePath) (*model.GlidePath, error) {
+	var id uint
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(
+			`INSERT INTO glide_paths (name, created_at) VALUES ($1, now()) RETURNING id`,
+			glidePath.Name,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+		for _, stage := range glidePath.Stages {
+			for position, fund := range stage.Funds {
+				_, err := tx.Exec(
+					`INSERT INTO glide_path_stages (glide_path_id, years_to_retirement, position, fund_id, percentage)
+					 VALUES ($1, $2, $3, $4, $5)`,
+					id, stage.YearsToRetirement, position, fund.FundID, fund.Percentage,
+				)
+				if _, ok := violatedForeignKey(err); ok {
+					return repository.ErrFundNotFound
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetGlidePathByID(id)
+}
+
+// GetGlidePathByID retrieves a glide path by its ID
+func (r *GlidePathRepository) GetGlidePathByID(id uint) (*model.GlidePath, error) {
+	glidePaths, err := r.listGlidePaths(`WHERE g.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(glidePaths) == 0 {
+		return nil, repository.ErrGlidePathNotFound
+	}
+	return glidePaths[0], nil
+}
+
+// ListGlidePaths retrieves every glide path ordered by ID
+func (r *GlidePathRepository) ListGlidePaths() ([]*model.GlidePath, error) {
+	return r.listGlidePaths(``)
+}
+
+// listGlidePaths retrieves the glide paths matching where with their stages, ordered from the furthest from
+// retirement to the nearest
+func (r *GlidePathRepository) listGlidePaths(where string, args ...any) ([]*model.GlidePath, error) {
+	rows, err := r.db.Query(
+		`SELECT g.id, g.name, g.created_at, s.years_to_retirement, s.fund_id, s.percentage
+		 FROM glide_paths g
+		 JOIN glide_path_stages s ON s.glide_path_id = g.id
+		 `+where+`
+		 ORDER BY g.id, s.years_to_retirement DESC, s.position`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	glidePaths := make([]*model.GlidePath, 0)
+	for rows.Next() {
+		var glidePath model.GlidePath
+		var years int
+		var fund model.FundAllocation
+		if err := rows.Scan(&glidePath.ID, &glidePath.Name, &glidePath.CreatedAt, &years, &fund.FundID, &fund.Percentage); err != nil {
+			return nil, err
+		}
+		if len(glidePaths) == 0 || glidePaths[len(glidePaths)-1].ID != glidePath.ID {
+			glidePaths = append(glidePaths, &glidePath)
+		}
+		last := glidePaths[len(glidePaths)-1]
+		if len(last.Stages) == 0 || last.Stages[len(last.Stages)-1].YearsToRetirement != years {
+			last.Stages = append(last.Stages, model.GlidePathStage{YearsToRetirement: years})
+		}
+		stage := &last.Stages[len(last.Stages)-1]
+		stage.Funds = append(stage.Funds, fund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return glidePaths, nil
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/model"
+	"cushon/internal/repository"
+)
+
+func TestGlidePathRepository(t *testing.T) {
+	db := openTestDB(t)
+	seedInvestmentFixtures(t, NewInvestmentRepository(db))
+	repo := NewGlidePathRepository(db)
+
+	if _, err := repo.GetGlidePathByID(1); !errors.Is(err, repository.ErrGlidePathNotFound) {
+		t.Fatalf("GetGlidePathByID() error = %v, want ErrGlidePathNotFound", err)
+	}
+
+	created, err := repo.CreateGlidePath(&model.GlidePath{Name: "Balanced", Stages: []model.GlidePathStage{
+		{YearsToRetirement: 0, Funds: []model.FundAllocation{{FundID: 2, Percentage: model.OneHundredPercent}}},
+		{YearsToRetirement: 10, Funds: []model.FundAllocation{{FundID: 1, Percentage: 80000}, {FundID: 2, Percentage: 20000}}},
+	}})
+	if err != nil {
+		t.Fatalf("CreateGlidePath() error = %v", err)
+	}
+	if created.Name != "Balanced" || created.CreatedAt.IsZero() || len(created.Stages) != 2 ||
+		created.Stages[0].YearsToRetirement != 10 || len(created.Stages[0].Funds) != 2 || created.Stages[0].Funds[0].FundID != 1 {
+		t.Errorf("CreateGlidePath() = %+v, want its stages furthest from retirement first with their funds in order", created)
+	}
+
+	if _, err := repo.CreateGlidePath(&model.GlidePath{Name: "Unknown fund", Stages: []model.GlidePathStage{
+		{YearsToRetirement: 0, Funds: []model.FundAllocation{{FundID: 99, Percentage: model.OneHundredPercent}}},
+	}}); !errors.Is(err, repository.ErrFundNotFound) {
+		t.Errorf("CreateGlidePath() error = %v, want ErrFundNotFound", err)
+	}
+	all, err := repo.ListGlidePaths()
+	if err != nil {
+		t.Fatalf("ListGlidePaths() error = %v", err)
+	}
+	if len(all) != 1 || all[0].ID != created.ID {
+		t.Errorf("ListGlidePaths() = %+v, want only the glide path that was created", all)
+	}
+
+	customers := NewCustomerRepository(db)
+	dateOfBirth := model.NewDate(1970, time.June, 15)
+	retirementAge := 67
+	plan := model.RetirementPlan{DateOfBirth: &dateOfBirth, RetirementAge: &retirementAge, GlidePathID: &created.ID}
+	customer, err := customers.UpdateCustomer(1, replaceCustomer("John Doe", nil, plan))
+	if err != nil {
+		t.Fatalf("UpdateCustomer() error = %v", err)
+	}
+	if customer.DateOfBirth == nil || *customer.DateOfBirth != dateOfBirth || customer.RetirementAge == nil ||
+		*customer.RetirementAge != retirementAge || customer.GlidePathID == nil || *customer.GlidePathID != created.ID {
+		t.Errorf("UpdateCustomer() = %+v, want the retirement plan stored", customer.RetirementPlan)
+	}
+	following, err := customers.ListCustomers(model.CustomerFilter{OnGlidePath: true})
+	if err != nil {
+		t.Fatalf("ListCustomers() error = %v", err)
+	}
+	if len(following) != 1 || following[0].ID != 1 {
+		t.Errorf("ListCustomers() = %+v, want only the customer following a glide path", following)
+	}
+
+	plan.GlidePathID = uintPtr(99)
+	if _, err := customers.UpdateCustomer(1, replaceCustomer("John Doe", nil, plan)); !errors.Is(err, repository.ErrGlidePathNotFound) {
+		t.Errorf("UpdateCustomer() error = %v, want ErrGlidePathNotFound", err)
+	}
+}
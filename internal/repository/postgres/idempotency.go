@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// idempotencyColumns lists the columns read by scanIdempotencyRecord, in order
+const idempotencyColumns = `key, request_hash, status_code, content_type, body, created_at, expires_at`
+
+// IdempotencyRepository is a PostgreSQL implementation of repository.IdempotencyRepository.
+// Only a SHA-256 hash of each API key is stored.
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyRepository creates a new PostgreSQL idempotency repository
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// ReserveIdempotencyKey stores record, without a response, unless its key is already held by a record that
+// hasn't expired at now. In that case the existing record is returned with ErrIdempotencyKeyExists. The primary
+// key makes sure only one of several concurrent requests with the same key reserves it.
+func (r *IdempotencyRepository) ReserveIdempotencyKey(record *model.IdempotencyRecord, now time.Time) (*model.IdempotencyRecord, error) {
+	apiKeyHash := hashKey(record.APIKey)
+	for {
+		row := r.db.QueryRow(
+			`INSERT INTO idempotency_keys (api_key_hash, key, request_hash, created_at, expires_at)
+			 VALUES ($1, $2, $3, $4, $5)
+			 ON CONFLICT (api_key_hash, key) DO UPDATE
+			 SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL, body = NULL,
+			     created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+			 WHERE idempotency_keys.expires_at <= $6
+			 RETURNING `+idempotencyColumns,
+			apiKeyHash, record.Key, record.RequestHash, record.CreatedAt, record.ExpiresAt, now,
+		)
+		reserved, err := scanIdempotencyRecord(row, record.APIKey)
+		if err == nil {
+			return reserved, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		// The key is held by a record that hasn't expired
+		row = r.db.QueryRow(
+			`SELECT `+idempotencyColumns+` FROM idempotency_keys WHERE api_key_hash = $1 AND key = $2`,
+			apiKeyHash, record.Key,
+		)
+		existing, err := scanIdempotencyRecord(row, record.APIKey)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Released since the insert, so try to reserve it again
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return existing, repository.ErrIdempotencyKeyExists
+	}
+}
+
+// CompleteIdempotencyKey stores the response to replay for a reserved key
+func (r *IdempotencyRepository) CompleteIdempotencyKey(apiKey, key string, response *model.IdempotentResponse) error {
+	result, err := r.db.Exec(
+		`UPDATE idempotency_keys SET status_code = $3, content_type = $4, body = $5
+		 WHERE api_key_hash = $1 AND key = $2`,
+		hashKey(apiKey), key, response.StatusCode, response.ContentType, response.Body,
+	)
+	if err != nil {
+		return err
+	}
+	return requireKeyAffected(result)
+}
+
+// ReleaseIdempotencyKey forgets a reserved key so the request can be retried
+func (r *IdempotencyRepository) ReleaseIdempotencyKey(apiKey, key string) error {
+	result, err := r.db.Exec(
+		`DELETE FROM idempotency_keys WHERE api_key_hash = $1 AND key = $2`,
+		hashKey(apiKey), key,
+	)
+	if err != nil {
+		return err
+	}
+	return requireKeyAffected(result)
+}
+
+// DeleteExpiredIdempotencyKeys forgets every key that has expired at now and returns how many there were
+func (r *IdempotencyRepository) DeleteExpiredIdempotencyKeys(now time.Time) (int, error) {
+	result, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+// requireKeyAffected returns ErrIdempotencyKeyNotFound when a statement didn't change any key
+func requireKeyAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrIdempotencyKeyNotFound
+	}
+	return nil
+}
+
+// scanIdempotencyRecord reads a row selected with idempotencyColumns for apiKey, which is only stored hashed
+func scanIdempotencyRecord(row scanner, apiKey string) (*model.IdempotencyRecord, error) {
+	record := &model.IdempotencyRecord{APIKey: apiKey}
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	var body []byte
+	err := row.Scan(&record.Key, &record.RequestHash, &statusCode, &contentType, &body, &record.CreatedAt, &record.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode.Valid {
+		record.Response = &model.IdempotentResponse{
+			StatusCode:  int(statusCode.Int64),
+			ContentType: contentType.String,
+			Body:        body,
+		}
+	}
+	return record, nil
+}
@@ -0,0 +1,509 @@
+package postgres
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// investmentColumns lists the columns read by scanInvestment, in order
+const investmentColumns = `id, client_id, fund_id, type, status, switch_id, batch_id, source, isa, schedule_id, run_date, amount_minor, currency, units, price, price_date, created_at, updated_at`
+
+// InvestmentRepository is a PostgreSQL implementation of repository.InvestmentRepository
+type InvestmentRepository struct {
+	db *sql.DB
+}
+
+// NewInvestmentRepository creates a new PostgreSQL investment repository
+func NewInvestmentRepository(db *sql.DB) *InvestmentRepository {
+	return &InvestmentRepository{db: db}
+}
+
+// CreateInvestment stores a new pending investment. The foreign keys guarantee that the client and fund exist.
+func (r *InvestmentRepository) CreateInvestment(investment *model.Investment) (*model.Investment, error) {
+	return insertInvestment(r.db, investment, model.TransactionTypeInvestment)
+}
+
+// CreateISAInvestments stores new pending investments into one customer's ISA in a single transaction, or none
+// of them with repository.ErrISAAllowanceExceeded when together they would take the customer's subscriptions for
+// the current tax year over allowance. The investments are marked as paid into the ISA. The customer's row is locked while their subscriptions are totalled so
+// concurrent investments can't breach the allowance.
+func (r *InvestmentRepository) CreateISAInvestments(investments []*model.Investment, allowance model.Money) ([]*model.Investment, error) {
+	if len(investments) == 0 {
+		return []*model.Investment{}, nil
+	}
+
+	clientID := investments[0].ClientID
+	requested := model.NewMoney(0, allowance.Currency)
+	for _, investment := range investments {
+		var err error
+		if requested, err = requested.Add(investment.Amount); err != nil {
+			return nil, repository.ErrISAAllowanceExceeded
+		}
+	}
+
+	created := make([]*model.Investment, len(investments))
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		if err := lockCustomer(tx, clientID); err != nil {
+			return err
+		}
+
+		taxYear := model.TaxYearOf(time.Now())
+		var subscribed int64
+		err := tx.QueryRow(
+			`SELECT COALESCE(SUM(amount_minor), 0) FROM investments
+			 WHERE client_id = $1 AND type = 'investment' AND isa AND status NOT IN ('failed', 'cancelled')
+			   AND currency = $2 AND created_at >= $3 AND created_at < $4`,
+			clientID, allowance.Currency, taxYear.Start(), taxYear.End(),
+		).Scan(&subscribed)
+		if err != nil {
+			return err
+		}
+		if subscribed+requested.Minor > allowance.Minor {
+			return repository.ErrISAAllowanceExceeded
+		}
+
+		for i, investment := range investments {
+			subscription := *investment
+			subscription.ISA = true
+			if created[i], err = insertInvestment(tx, &subscription, model.TransactionTypeInvestment); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// CreateInvestments stores several new pending investments in a single transaction, so either all of them are
+// stored or none is
+func (r *InvestmentRepository) CreateInvestments(investments []*model.Investment) ([]*model.Investment, error) {
+	created := make([]*model.Investment, len(investments))
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		for i, investment := range investments {
+			var err error
+			if created[i], err = insertInvestment(tx, investment, model.TransactionTypeInvestment); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// CreateWithdrawal stores a withdrawal, whose amount and units are negative. The customer's row is locked while
+// their holding is checked so concurrent withdrawals can't overdraw it.
+func (r *InvestmentRepository) CreateWithdrawal(withdrawal *model.Investment) (*model.Investment, error) {
+	var created *model.Investment
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		if err := checkHolding(tx, withdrawal.ClientID, withdrawal.FundID, -withdrawal.Units); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = insertInvestment(tx, withdrawal, model.TransactionTypeWithdrawal)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// CreateSwitch stores a switch and both of its legs in a single transaction, so money is never sold without
+// being reinvested. The customer's holding is checked as for withdrawals.
+func (r *InvestmentRepository) CreateSwitch(fundSwitch *model.Switch) (*model.Switch, error) {
+	created, err := r.CreateSwitches([]*model.Switch{fundSwitch})
+	if err != nil {
+		return nil, err
+	}
+	return created[0], nil
+}
+
+// CreateSwitches stores several switches in a single transaction, so they are made together or not at all.
+// Each holding is checked after the switches before it have sold from it.
+func (r *InvestmentRepository) CreateSwitches(switches []*model.Switch) ([]*model.Switch, error) {
+	created := make([]*model.Switch, len(switches))
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		for i, fundSwitch := range switches {
+			var err error
+			if created[i], err = insertSwitch(tx, fundSwitch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// insertSwitch checks the customer holds the units sold and stores a switch and both of its legs
+func insertSwitch(tx *sql.Tx, fundSwitch *model.Switch) (*model.Switch, error) {
+	if err := checkHolding(tx, fundSwitch.ClientID, fundSwitch.FromFundID, -fundSwitch.Sell.Units); err != nil {
+		return nil, err
+	}
+
+	created := *fundSwitch
+	err := tx.QueryRow(
+		`INSERT INTO switches (client_id, from_fund_id, to_fund_id, created_at)
+		 VALUES ($1, $2, $3, now())
+		 RETURNING id, created_at`,
+		fundSwitch.ClientID, fundSwitch.FromFundID, fundSwitch.ToFundID,
+	).Scan(&created.ID, &created.CreatedAt)
+	if err != nil {
+		if _, ok := violatedForeignKey(err); ok {
+			return nil, repository.ErrFundNotFound
+		}
+		return nil, err
+	}
+
+	sell, buy := *fundSwitch.Sell, *fundSwitch.Buy
+	sell.SwitchID, buy.SwitchID = created.ID, created.ID
+	if created.Sell, err = insertInvestment(tx, &sell, model.TransactionTypeSwitchOut); err != nil {
+		return nil, err
+	}
+	if created.Buy, err = insertInvestment(tx, &buy, model.TransactionTypeSwitchIn); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetSwitchByID retrieves a switch and both of its legs by the switch's ID
+func (r *InvestmentRepository) GetSwitchByID(id uint) (*model.Switch, error) {
+	fundSwitch := &model.Switch{}
+	err := r.db.QueryRow(
+		`SELECT id, client_id, from_fund_id, to_fund_id, created_at FROM switches WHERE id = $1`, id,
+	).Scan(&fundSwitch.ID, &fundSwitch.ClientID, &fundSwitch.FromFundID, &fundSwitch.ToFundID, &fundSwitch.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrSwitchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`SELECT `+investmentColumns+` FROM investments WHERE switch_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		investment, err := scanInvestment(rows)
+		if err != nil {
+			return nil, err
+		}
+		if investment.Type == model.TransactionTypeSwitchOut {
+			fundSwitch.Sell = investment
+		} else {
+			fundSwitch.Buy = investment
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return fundSwitch, nil
+}
+
+// lockCustomer locks the customer's row until the end of the transaction, so concurrent transactions checking
+// the same customer's holdings or subscriptions are serialised
+func lockCustomer(tx *sql.Tx, clientID uint) error {
+	var id uint
+	err := tx.QueryRow(`SELECT id FROM customers WHERE id = $1 FOR UPDATE`, clientID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrCustomerNotFound
+	}
+	return err
+}
+
+// checkHolding locks the customer's row and checks they hold at least units of a fund
+func checkHolding(tx *sql.Tx, clientID, fundID uint, units model.Units) error {
+	if err := lockCustomer(tx, clientID); err != nil {
+		return err
+	}
+
+	var held model.Units
+	err := tx.QueryRow(
+		`SELECT COALESCE(SUM(units), 0) FROM investments
+		 WHERE client_id = $1 AND fund_id = $2 AND status NOT IN ('failed', 'cancelled')`,
+		clientID, fundID,
+	).Scan(&held)
+	if err != nil {
+		return err
+	}
+	if held < units {
+		return repository.ErrInsufficientUnits
+	}
+	return nil
+}
+
+// queryRower is implemented by both *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// insertInvestment stores a pending transaction of the given type
+func insertInvestment(db queryRower, investment *model.Investment, transactionType model.TransactionType) (*model.Investment, error) {
+	row := db.QueryRow(
+		`INSERT INTO investments (client_id, fund_id, type, status, switch_id, source, isa, schedule_id, run_date, amount_minor, currency, units, price, price_date, created_at, updated_at)
+		 VALUES ($1, $2, $3, 'pending', $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, now(), now())
+		 RETURNING `+investmentColumns,
+		investment.ClientID, investment.FundID, transactionType, nullableID(investment.SwitchID), nullableString(string(investment.Source)),
+		investment.ISA, nullableID(investment.ScheduleID), nullableDate(investment.RunDate),
+		investment.Amount.Minor, investment.Amount.Currency, investment.Units, investment.Price, nullableDate(pricedOn(investment)),
+	)
+
+	created, err := scanInvestment(row)
+	if err != nil {
+		if constraint, ok := violatedUnique(err); ok && constraint == "investments_schedule_run_idx" {
+			return nil, repository.ErrScheduleRunPaid
+		}
+		if constraint, ok := violatedForeignKey(err); ok {
+			switch constraint {
+			case "investments_fund_id_fkey":
+				return nil, repository.ErrFundNotFound
+			case "investments_schedule_id_fkey":
+				return nil, repository.ErrScheduleNotFound
+			}
+			return nil, repository.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetInvestmentByID retrieves an investment by its ID
+func (r *InvestmentRepository) GetInvestmentByID(id uint) (*model.Investment, error) {
+	row := r.db.QueryRow(`SELECT `+investmentColumns+` FROM investments WHERE id = $1`, id)
+
+	investment, err := scanInvestment(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrInvestmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return investment, nil
+}
+
+// GetInvestmentsByClientID retrieves all investments for a specific client ordered by ID
+func (r *InvestmentRepository) GetInvestmentsByClientID(clientID uint) ([]*model.Investment, error) {
+	rows, err := r.db.Query(
+		`SELECT `+investmentColumns+` FROM investments WHERE client_id = $1 ORDER BY id`,
+		clientID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	investments := make([]*model.Investment, 0)
+	for rows.Next() {
+		investment, err := scanInvestment(rows)
+		if err != nil {
+			return nil, err
+		}
+		investments = append(investments, investment)
+	}
+	return investments, rows.Err()
+}
+
+// ListInvestments retrieves the transactions matching filter ordered by ID
+func (r *InvestmentRepository) ListInvestments(filter model.InvestmentFilter) ([]*model.Investment, error) {
+	var clientID, status, scheduleID, runDate any
+	if filter.ClientID != nil {
+		clientID = *filter.ClientID
+	}
+	if filter.Status != nil {
+		status = string(*filter.Status)
+	}
+	if filter.Run != nil {
+		scheduleID, runDate = filter.Run.ScheduleID, filter.Run.RunDate.Time
+	}
+
+	rows, err := r.db.Query(
+		`SELECT `+investmentColumns+` FROM investments
+		 WHERE ($1::BIGINT IS NULL OR client_id = $1)
+		   AND ($2::TEXT IS NULL OR status = $2)
+		   AND ($3::BIGINT IS NULL OR (schedule_id = $3 AND run_date = $4::DATE))
+		 ORDER BY id`,
+		clientID, status, scheduleID, runDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	investments := make([]*model.Investment, 0)
+	for rows.Next() {
+		investment, err := scanInvestment(rows)
+		if err != nil {
+			return nil, err
+		}
+		investments = append(investments, investment)
+	}
+	return investments, rows.Err()
+}
+
+// SumDealtUnits returns the number of units of each of the holdings that the customer holds through transactions
+// that have been placed or settled. Holdings without any are left out.
+func (r *InvestmentRepository) SumDealtUnits(holdings []model.HoldingKey) (map[model.HoldingKey]model.Units, error) {
+	clientKeys := make([]int64, len(holdings))
+	fundKeys := make([]int64, len(holdings))
+	for i, holding := range holdings {
+		clientKeys[i], fundKeys[i] = int64(holding.ClientID), int64(holding.FundID)
+	}
+
+	rows, err := r.db.Query(
+		`SELECT client_id, fund_id, SUM(units) FROM investments
+		 WHERE status IN ('placed', 'settled')
+		   AND (client_id, fund_id) IN (SELECT * FROM unnest($1::BIGINT[], $2::BIGINT[]))
+		 GROUP BY client_id, fund_id`,
+		pq.Array(clientKeys), pq.Array(fundKeys),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dealt := make(map[model.HoldingKey]model.Units)
+	for rows.Next() {
+		var holding model.HoldingKey
+		var units model.Units
+		if err := rows.Scan(&holding.ClientID, &holding.FundID, &units); err != nil {
+			return nil, err
+		}
+		dealt[holding] = units
+	}
+	return dealt, rows.Err()
+}
+
+// UpdateInvestmentStatus moves every transaction in ids from status from to status to in a single transaction,
+// or none of them when any is no longer in status from. The rows are locked while they are checked. Voiding
+// transactions that bought units fails with ErrInsufficientUnits when the units have already been sold.
+func (r *InvestmentRepository) UpdateInvestmentStatus(ids []uint, from, to model.InvestmentStatus) ([]*model.Investment, error) {
+	keys := make([]int64, len(ids))
+	for i, id := range ids {
+		keys[i] = int64(id)
+	}
+
+	var updated []*model.Investment
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT `+investmentColumns+` FROM investments WHERE id = ANY($1) ORDER BY id FOR UPDATE`, pq.Array(keys))
+		if err != nil {
+			return err
+		}
+		current := make(map[uint]*model.Investment)
+		for rows.Next() {
+			investment, err := scanInvestment(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			current[investment.ID] = investment
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		type holding struct{ clientID, fundID uint }
+		released := make(map[holding]model.Units)
+		for _, id := range ids {
+			investment, exists := current[id]
+			if !exists {
+				return repository.ErrInvestmentNotFound
+			}
+			if investment.Status != from {
+				return repository.ErrInvestmentStatusChanged
+			}
+			if from.HoldsUnits() && !to.HoldsUnits() {
+				released[holding{investment.ClientID, investment.FundID}] += investment.Units
+			}
+		}
+		for h, units := range released {
+			if err := checkHolding(tx, h.clientID, h.fundID, units); err != nil {
+				return err
+			}
+		}
+
+		updated = make([]*model.Investment, len(ids))
+		for i, id := range ids {
+			row := tx.QueryRow(
+				`UPDATE investments SET status = $2, updated_at = now() WHERE id = $1 RETURNING `+investmentColumns,
+				id, to,
+			)
+			if updated[i], err = scanInvestment(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// pricedOn returns the date an investment was priced on, nil for investments without a price
+func pricedOn(investment *model.Investment) *model.Date {
+	if investment.PriceDate.IsZero() {
+		return nil
+	}
+	return &investment.PriceDate
+}
+
+// scanInvestment reads a row selected with investmentColumns
+func scanInvestment(row scanner) (*model.Investment, error) {
+	investment := &model.Investment{}
+	var switchID, batchID, scheduleID sql.NullInt64
+	var source sql.NullString
+	var runDate, priceDate sql.NullTime
+	err := row.Scan(
+		&investment.ID,
+		&investment.ClientID,
+		&investment.FundID,
+		&investment.Type,
+		&investment.Status,
+		&switchID,
+		&batchID,
+		&source,
+		&investment.ISA,
+		&scheduleID,
+		&runDate,
+		&investment.Amount.Minor,
+		&investment.Amount.Currency,
+		&investment.Units,
+		&investment.Price,
+		&priceDate,
+		&investment.CreatedAt,
+		&investment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	investment.SwitchID = uint(switchID.Int64)
+	investment.BatchID = uint(batchID.Int64)
+	investment.Source = model.ContributionSource(source.String)
+	investment.ScheduleID = uint(scheduleID.Int64)
+	if runDate.Valid {
+		date := model.DateOf(runDate.Time)
+		investment.RunDate = &date
+	}
+	if priceDate.Valid {
+		investment.PriceDate = model.DateOf(priceDate.Time)
+	}
+	return investment, nil
+}
@@ -0,0 +1,481 @@
+package postgres
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"cushon/internal/model"
+	"cushon/internal/repository"
+)
+
+// seedInvestmentFixtures creates customers 1 and 2 and funds 1 and 2
+func seedInvestmentFixtures(t *testing.T, repo *InvestmentRepository) {
+	t.Helper()
+
+	customers := NewCustomerRepository(repo.db)
+	funds := NewFundRepository(repo.db)
+	for _, name := range []string{"John Doe", "Jane Smith"} {
+		if _, err := customers.CreateCustomer(name, nil); err != nil {
+			t.Fatalf("CreateCustomer() error = %v", err)
+		}
+	}
+	for _, name := range []string{"Fund1", "Fund2"} {
+		if _, err := funds.CreateFund(model.FundCreate{Name: name}); err != nil {
+			t.Fatalf("CreateFund() error = %v", err)
+		}
+	}
+}
+
+func TestInvestmentRepository_CreateInvestment(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientID uint
+		fundID   uint
+		amount   model.Money
+		wantErr  error
+	}{
+		{
+			name:     "Valid investment",
+			clientID: 1,
+			fundID:   1,
+			amount:   model.NewMoney(123456789, model.DefaultCurrency),
+			wantErr:  nil,
+		},
+		{
+			name:     "Unknown client",
+			clientID: 101,
+			fundID:   1,
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
+			wantErr:  repository.ErrCustomerNotFound,
+		},
+		{
+			name:     "Unknown fund",
+			clientID: 1,
+			fundID:   101,
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
+			wantErr:  repository.ErrFundNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewInvestmentRepository(openTestDB(t))
+			seedInvestmentFixtures(t, repo)
+
+			got, err := repo.CreateInvestment(&model.Investment{ClientID: tt.clientID, FundID: tt.fundID, Amount: tt.amount})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateInvestment() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CreateInvestment() unexpected error = %v", err)
+			}
+			if got.ClientID != tt.clientID || got.FundID != tt.fundID {
+				t.Errorf("CreateInvestment() = %+v, want client %v fund %v", got, tt.clientID, tt.fundID)
+			}
+			if got.Amount != tt.amount {
+				t.Errorf("Amount = %v, want %v", got.Amount, tt.amount)
+			}
+		})
+	}
+}
+
+func TestInvestmentRepository_GetInvestmentByID(t *testing.T) {
+	repo := NewInvestmentRepository(openTestDB(t))
+	seedInvestmentFixtures(t, repo)
+
+	created, err := repo.CreateInvestment(&model.Investment{
+		ClientID:  1,
+		FundID:    1,
+		Amount:    model.NewMoney(100000, model.DefaultCurrency),
+		Units:     400000000,
+		Price:     2500000,
+		PriceDate: model.NewDate(2026, time.October, 16),
+	})
+	if err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+
+	got, err := repo.GetInvestmentByID(created.ID)
+	if err != nil {
+		t.Fatalf("GetInvestmentByID() error = %v", err)
+	}
+	if got.Units != 400000000 || got.Price != 2500000 || got.PriceDate != model.NewDate(2026, time.October, 16) {
+		t.Errorf("GetInvestmentByID() = %+v, want 400 units priced at 2.5 on 2026-10-16", got)
+	}
+	if got.ID != created.ID || got.Amount != created.Amount {
+		t.Errorf("GetInvestmentByID() = %+v, want %+v", got, created)
+	}
+
+	_, err = repo.GetInvestmentByID(999)
+	if !errors.Is(err, repository.ErrInvestmentNotFound) {
+		t.Errorf("GetInvestmentByID() error = %v, want ErrInvestmentNotFound", err)
+	}
+}
+
+func TestInvestmentRepository_GetInvestmentsByClientID(t *testing.T) {
+	repo := NewInvestmentRepository(openTestDB(t))
+	seedInvestmentFixtures(t, repo)
+
+	for _, inv := range []struct{ clientID, fundID uint }{{1, 1}, {1, 2}, {2, 1}} {
+		if _, err := repo.CreateInvestment(&model.Investment{ClientID: inv.clientID, FundID: inv.fundID, Amount: model.NewMoney(100000, model.DefaultCurrency)}); err != nil {
+			t.Fatalf("CreateInvestment() error = %v", err)
+		}
+	}
+
+	got, err := repo.GetInvestmentsByClientID(1)
+	if err != nil {
+		t.Fatalf("GetInvestmentsByClientID() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d investments, want 2", len(got))
+	}
+	for _, inv := range got {
+		if inv.ClientID != 1 {
+			t.Errorf("found investment with ClientID = %v, want 1", inv.ClientID)
+		}
+	}
+}
+
+func TestInvestmentRepository_CreateISAInvestments(t *testing.T) {
+	repo := NewInvestmentRepository(openTestDB(t))
+	seedInvestmentFixtures(t, repo)
+
+	investment := func(clientID uint, minor int64) *model.Investment {
+		return &model.Investment{ClientID: clientID, FundID: 1, Amount: model.NewMoney(minor, model.DefaultCurrency), Units: 1000000, Price: 1000000}
+	}
+
+	if _, err := repo.CreateISAInvestments([]*model.Investment{investment(1, 1500000)}, model.ISAAnnualAllowance); err != nil {
+		t.Fatalf("CreateISAInvestments() error = %v", err)
+	}
+	cancelled, err := repo.CreateISAInvestments([]*model.Investment{investment(1, 400000)}, model.ISAAnnualAllowance)
+	if err != nil {
+		t.Fatalf("CreateISAInvestments() error = %v", err)
+	}
+	if _, err := repo.UpdateInvestmentStatus([]uint{cancelled[0].ID}, model.InvestmentStatusPending, model.InvestmentStatusCancelled); err != nil {
+		t.Fatalf("UpdateInvestmentStatus() error = %v", err)
+	}
+	// Paid into a pension while the customer was employed, before they became retail
+	if _, err := repo.CreateInvestment(investment(1, 2000000)); err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+
+	if _, err := repo.CreateISAInvestments([]*model.Investment{investment(1, 500001)}, model.ISAAnnualAllowance); !errors.Is(err, repository.ErrISAAllowanceExceeded) {
+		t.Errorf("CreateISAInvestments() error = %v, want ErrISAAllowanceExceeded", err)
+	}
+	if _, err := repo.CreateISAInvestments([]*model.Investment{investment(1, 300000), investment(1, 200001)}, model.ISAAnnualAllowance); !errors.Is(err, repository.ErrISAAllowanceExceeded) {
+		t.Errorf("CreateISAInvestments() error = %v, want ErrISAAllowanceExceeded", err)
+	}
+	got, err := repo.CreateISAInvestments([]*model.Investment{investment(1, 300000), investment(1, 200000)}, model.ISAAnnualAllowance)
+	if err != nil {
+		t.Fatalf("CreateISAInvestments() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Type != model.TransactionTypeInvestment || got[1].Status != model.InvestmentStatusPending || !got[0].ISA || !got[1].ISA {
+		t.Errorf("CreateISAInvestments() = %+v, want two pending ISA investments", got)
+	}
+	if _, err := repo.CreateISAInvestments([]*model.Investment{investment(2, 2000000)}, model.ISAAnnualAllowance); err != nil {
+		t.Errorf("CreateISAInvestments() error = %v, another customer's subscriptions shouldn't count", err)
+	}
+	if _, err := repo.CreateISAInvestments([]*model.Investment{investment(101, 100)}, model.ISAAnnualAllowance); !errors.Is(err, repository.ErrCustomerNotFound) {
+		t.Errorf("CreateISAInvestments() error = %v, want ErrCustomerNotFound", err)
+	}
+}
+
+func TestInvestmentRepository_CreateInvestments(t *testing.T) {
+	repo := NewInvestmentRepository(openTestDB(t))
+	seedInvestmentFixtures(t, repo)
+
+	contribution := func(clientID uint, source model.ContributionSource) *model.Investment {
+		return &model.Investment{ClientID: clientID, FundID: 1, Amount: model.NewMoney(10000, model.DefaultCurrency), Units: 1000000, Price: 1000000, Source: source}
+	}
+
+	got, err := repo.CreateInvestments([]*model.Investment{contribution(1, model.ContributionSourceSalarySacrifice), contribution(1, model.ContributionSourceEmployer)})
+	if err != nil {
+		t.Fatalf("CreateInvestments() error = %v", err)
+	}
+	for i, want := range []model.ContributionSource{model.ContributionSourceSalarySacrifice, model.ContributionSourceEmployer} {
+		stored, err := repo.GetInvestmentByID(got[i].ID)
+		if err != nil {
+			t.Fatalf("GetInvestmentByID() error = %v", err)
+		}
+		if stored.Type != model.TransactionTypeInvestment || stored.Source != want {
+			t.Errorf("stored investment = %+v, want a %s investment", stored, want)
+		}
+	}
+
+	if _, err := repo.CreateInvestments([]*model.Investment{contribution(2, model.ContributionSourceEmployee), contribution(101, model.ContributionSourceEmployer)}); !errors.Is(err, repository.ErrCustomerNotFound) {
+		t.Fatalf("CreateInvestments() error = %v, want ErrCustomerNotFound", err)
+	}
+	if stored, _ := repo.GetInvestmentsByClientID(2); len(stored) != 0 {
+		t.Errorf("GetInvestmentsByClientID() = %+v, want nothing stored when one investment fails", stored)
+	}
+}
+
+func TestInvestmentRepository_CreateInvestments_ScheduleRuns(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewInvestmentRepository(db)
+	seedInvestmentFixtures(t, repo)
+
+	runDate := model.NewDate(2026, time.October, 16)
+	schedule, err := NewScheduleRepository(db).CreateSchedule(&model.ContributionSchedule{
+		CustomerID:  1,
+		Amount:      model.NewMoney(10000, model.DefaultCurrency),
+		Frequency:   model.ScheduleFrequencyWeekly,
+		StartDate:   runDate,
+		Status:      model.ScheduleStatusActive,
+		NextRunDate: runDate,
+	})
+	if err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+	regular := func(fundID uint) *model.Investment {
+		return &model.Investment{ClientID: 1, FundID: fundID, Amount: model.NewMoney(5000, model.DefaultCurrency), Units: 500000, Price: 1000000,
+			Source: model.ContributionSourceRegular, ScheduleID: schedule.ID, RunDate: &runDate}
+	}
+
+	got, err := repo.CreateInvestments([]*model.Investment{regular(1), regular(2)})
+	if err != nil {
+		t.Fatalf("CreateInvestments() error = %v", err)
+	}
+	if got[0].ScheduleID != schedule.ID || got[0].RunDate == nil || !got[0].RunDate.Equal(runDate.Time) {
+		t.Errorf("CreateInvestments() = %+v, want it to record the run on %s", got[0], runDate)
+	}
+	run := model.ScheduleRunKey{ScheduleID: schedule.ID, RunDate: runDate}
+	if paid, err := repo.ListInvestments(model.InvestmentFilter{Run: &run}); err != nil || len(paid) != 2 {
+		t.Errorf("ListInvestments() = %+v, %v, want the run's two investments", paid, err)
+	}
+
+	if _, err := repo.CreateInvestment(regular(2)); !errors.Is(err, repository.ErrScheduleRunPaid) {
+		t.Errorf("CreateInvestment() error = %v, want ErrScheduleRunPaid", err)
+	}
+	unknown := regular(1)
+	unknown.ScheduleID = 999
+	if _, err := repo.CreateInvestment(unknown); !errors.Is(err, repository.ErrScheduleNotFound) {
+		t.Errorf("CreateInvestment() error = %v, want ErrScheduleNotFound", err)
+	}
+}
+
+func TestInvestmentRepository_CreateWithdrawal(t *testing.T) {
+	repo := NewInvestmentRepository(openTestDB(t))
+	seedInvestmentFixtures(t, repo)
+
+	if _, err := repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(100000, model.DefaultCurrency), Units: 400000000, Price: 2500000}); err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+
+	withdrawal := func(clientID, fundID uint, units model.Units) *model.Investment {
+		return &model.Investment{ClientID: clientID, FundID: fundID, Amount: model.NewMoney(-100, model.DefaultCurrency), Units: units, Price: 2500000}
+	}
+
+	got, err := repo.CreateWithdrawal(withdrawal(1, 1, -300000000))
+	if err != nil {
+		t.Fatalf("CreateWithdrawal() error = %v", err)
+	}
+	if got.Type != model.TransactionTypeWithdrawal || got.Units != -300000000 {
+		t.Errorf("CreateWithdrawal() = %+v, want a withdrawal of 300 units", got)
+	}
+
+	stored, err := repo.GetInvestmentByID(got.ID)
+	if err != nil || stored.Type != model.TransactionTypeWithdrawal {
+		t.Errorf("GetInvestmentByID() = %+v, %v, want the withdrawal", stored, err)
+	}
+
+	if _, err := repo.CreateWithdrawal(withdrawal(1, 1, -100000001)); !errors.Is(err, repository.ErrInsufficientUnits) {
+		t.Errorf("CreateWithdrawal() error = %v, want ErrInsufficientUnits", err)
+	}
+	if _, err := repo.CreateWithdrawal(withdrawal(2, 1, -1)); !errors.Is(err, repository.ErrInsufficientUnits) {
+		t.Errorf("CreateWithdrawal() error = %v, want ErrInsufficientUnits", err)
+	}
+	if _, err := repo.CreateWithdrawal(withdrawal(101, 1, -1)); !errors.Is(err, repository.ErrCustomerNotFound) {
+		t.Errorf("CreateWithdrawal() error = %v, want ErrCustomerNotFound", err)
+	}
+}
+
+func TestInvestmentRepository_CreateSwitch(t *testing.T) {
+	repo := NewInvestmentRepository(openTestDB(t))
+	seedInvestmentFixtures(t, repo)
+
+	if _, err := repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(100000, model.DefaultCurrency), Units: 400000000, Price: 2500000}); err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+
+	newSwitch := func(toFundID uint, units model.Units) *model.Switch {
+		return &model.Switch{
+			ClientID:   1,
+			FromFundID: 1,
+			ToFundID:   toFundID,
+			Sell:       &model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(-10000, model.DefaultCurrency), Units: -units, Price: 2500000},
+			Buy:        &model.Investment{ClientID: 1, FundID: toFundID, Amount: model.NewMoney(10000, model.DefaultCurrency), Units: units, Price: 2500000},
+		}
+	}
+
+	got, err := repo.CreateSwitch(newSwitch(2, 40000000))
+	if err != nil {
+		t.Fatalf("CreateSwitch() error = %v", err)
+	}
+	if got.Sell.SwitchID != got.ID || got.Buy.SwitchID != got.ID || got.Sell.Type != model.TransactionTypeSwitchOut || got.Buy.Type != model.TransactionTypeSwitchIn {
+		t.Errorf("CreateSwitch() = %+v, want both legs of switch %d", got, got.ID)
+	}
+
+	stored, err := repo.GetSwitchByID(got.ID)
+	if err != nil {
+		t.Fatalf("GetSwitchByID() error = %v", err)
+	}
+	if stored.Sell.ID != got.Sell.ID || stored.Buy.ID != got.Buy.ID || stored.ToFundID != 2 {
+		t.Errorf("GetSwitchByID() = %+v, want %+v", stored, got)
+	}
+
+	// Neither leg is stored when the switch fails
+	if _, err := repo.CreateSwitch(newSwitch(2, 400000000)); !errors.Is(err, repository.ErrInsufficientUnits) {
+		t.Errorf("CreateSwitch() error = %v, want ErrInsufficientUnits", err)
+	}
+	if _, err := repo.CreateSwitch(newSwitch(101, 1)); !errors.Is(err, repository.ErrFundNotFound) {
+		t.Errorf("CreateSwitch() error = %v, want ErrFundNotFound", err)
+	}
+	investments, err := repo.GetInvestmentsByClientID(1)
+	if err != nil || len(investments) != 3 {
+		t.Errorf("GetInvestmentsByClientID() = %d transactions, %v, want 3", len(investments), err)
+	}
+
+	if _, err := repo.GetSwitchByID(999); !errors.Is(err, repository.ErrSwitchNotFound) {
+		t.Errorf("GetSwitchByID() error = %v, want ErrSwitchNotFound", err)
+	}
+}
+
+func TestInvestmentRepository_CreateSwitches(t *testing.T) {
+	repo := NewInvestmentRepository(openTestDB(t))
+	seedInvestmentFixtures(t, repo)
+
+	for _, fundID := range []uint{1, 2} {
+		if _, err := repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: fundID, Amount: model.NewMoney(100000, model.DefaultCurrency), Units: 400000000, Price: 2500000}); err != nil {
+			t.Fatalf("CreateInvestment() error = %v", err)
+		}
+	}
+
+	newSwitch := func(fromFundID, toFundID uint, units model.Units) *model.Switch {
+		return &model.Switch{
+			ClientID:   1,
+			FromFundID: fromFundID,
+			ToFundID:   toFundID,
+			Sell:       &model.Investment{ClientID: 1, FundID: fromFundID, Amount: model.NewMoney(-10000, model.DefaultCurrency), Units: -units, Price: 2500000},
+			Buy:        &model.Investment{ClientID: 1, FundID: toFundID, Amount: model.NewMoney(10000, model.DefaultCurrency), Units: units, Price: 2500000},
+		}
+	}
+
+	// None of the switches is stored when one of them fails
+	if _, err := repo.CreateSwitches([]*model.Switch{newSwitch(1, 2, 300000000), newSwitch(1, 2, 200000000)}); !errors.Is(err, repository.ErrInsufficientUnits) {
+		t.Errorf("CreateSwitches() error = %v, want ErrInsufficientUnits", err)
+	}
+	if _, err := repo.CreateSwitches([]*model.Switch{newSwitch(1, 2, 40000000), newSwitch(2, 101, 1)}); !errors.Is(err, repository.ErrFundNotFound) {
+		t.Errorf("CreateSwitches() error = %v, want ErrFundNotFound", err)
+	}
+	if investments, err := repo.GetInvestmentsByClientID(1); err != nil || len(investments) != 2 {
+		t.Fatalf("GetInvestmentsByClientID() = %d transactions, %v, want 2", len(investments), err)
+	}
+
+	got, err := repo.CreateSwitches([]*model.Switch{newSwitch(1, 2, 40000000), newSwitch(2, 1, 40000000)})
+	if err != nil {
+		t.Fatalf("CreateSwitches() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID == got[1].ID || got[1].Sell.FundID != 2 || got[1].Buy.SwitchID != got[1].ID {
+		t.Errorf("CreateSwitches() = %+v, want both switches stored in order", got)
+	}
+}
+
+func TestInvestmentRepository_UpdateInvestmentStatus(t *testing.T) {
+	repo := NewInvestmentRepository(openTestDB(t))
+	seedInvestmentFixtures(t, repo)
+
+	investment, err := repo.CreateInvestment(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(100000, model.DefaultCurrency), Units: 400000000})
+	if err != nil {
+		t.Fatalf("CreateInvestment() error = %v", err)
+	}
+	if investment.Status != model.InvestmentStatusPending {
+		t.Errorf("CreateInvestment() status = %v, want pending", investment.Status)
+	}
+
+	updated, err := repo.UpdateInvestmentStatus([]uint{investment.ID}, model.InvestmentStatusPending, model.InvestmentStatusPlaced)
+	if err != nil {
+		t.Fatalf("UpdateInvestmentStatus() error = %v", err)
+	}
+	if len(updated) != 1 || updated[0].Status != model.InvestmentStatusPlaced {
+		t.Errorf("UpdateInvestmentStatus() = %+v, want the investment placed", updated)
+	}
+
+	if _, err := repo.UpdateInvestmentStatus([]uint{investment.ID}, model.InvestmentStatusPending, model.InvestmentStatusCancelled); !errors.Is(err, repository.ErrInvestmentStatusChanged) {
+		t.Errorf("UpdateInvestmentStatus() error = %v, want ErrInvestmentStatusChanged", err)
+	}
+	if _, err := repo.UpdateInvestmentStatus([]uint{999}, model.InvestmentStatusPending, model.InvestmentStatusPlaced); !errors.Is(err, repository.ErrInvestmentNotFound) {
+		t.Errorf("UpdateInvestmentStatus() error = %v, want ErrInvestmentNotFound", err)
+	}
+
+	// Once units have been withdrawn the investment that bought them can no longer fail
+	if _, err := repo.CreateWithdrawal(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(-100, model.DefaultCurrency), Units: -100000000}); err != nil {
+		t.Fatalf("CreateWithdrawal() error = %v", err)
+	}
+	if _, err := repo.UpdateInvestmentStatus([]uint{investment.ID}, model.InvestmentStatusPlaced, model.InvestmentStatusFailed); !errors.Is(err, repository.ErrInsufficientUnits) {
+		t.Errorf("UpdateInvestmentStatus() error = %v, want ErrInsufficientUnits", err)
+	}
+
+	placed, pending := model.InvestmentStatusPlaced, model.InvestmentStatusPending
+	clientID := uint(1)
+	for _, tt := range []struct {
+		filter model.InvestmentFilter
+		want   int
+	}{
+		{filter: model.InvestmentFilter{ClientID: &clientID}, want: 2},
+		{filter: model.InvestmentFilter{Status: &placed}, want: 1},
+		{filter: model.InvestmentFilter{ClientID: &clientID, Status: &pending}, want: 1},
+	} {
+		got, err := repo.ListInvestments(tt.filter)
+		if err != nil || len(got) != tt.want {
+			t.Errorf("ListInvestments(%+v) = %d investments, %v, want %d", tt.filter, len(got), err, tt.want)
+		}
+	}
+}
+
+func TestInvestmentRepository_SumDealtUnits(t *testing.T) {
+	repo := NewInvestmentRepository(openTestDB(t))
+	seedInvestmentFixtures(t, repo)
+
+	create := func(clientID uint, units model.Units) *model.Investment {
+		investment, err := repo.CreateInvestment(&model.Investment{ClientID: clientID, FundID: 1, Amount: model.NewMoney(int64(units/10000), model.DefaultCurrency), Units: units, Price: 1000000})
+		if err != nil {
+			t.Fatalf("CreateInvestment() error = %v", err)
+		}
+		return investment
+	}
+	settled, failed, other := create(1, 100000000), create(1, 500000000), create(2, 40000000)
+	create(1, 70000000)
+	placed, err := repo.CreateWithdrawal(&model.Investment{ClientID: 1, FundID: 1, Amount: model.NewMoney(-3000, model.DefaultCurrency), Units: -30000000, Price: 1000000})
+	if err != nil {
+		t.Fatalf("CreateWithdrawal() error = %v", err)
+	}
+	for _, step := range []struct {
+		ids      []uint
+		from, to model.InvestmentStatus
+	}{
+		{[]uint{settled.ID, placed.ID, failed.ID, other.ID}, model.InvestmentStatusPending, model.InvestmentStatusPlaced},
+		{[]uint{settled.ID}, model.InvestmentStatusPlaced, model.InvestmentStatusSettled},
+		{[]uint{failed.ID}, model.InvestmentStatusPlaced, model.InvestmentStatusFailed},
+	} {
+		if _, err := repo.UpdateInvestmentStatus(step.ids, step.from, step.to); err != nil {
+			t.Fatalf("UpdateInvestmentStatus() error = %v", err)
+		}
+	}
+
+	// Pending and failed transactions haven't been dealt, and holdings that weren't asked for aren't totalled
+	got, err := repo.SumDealtUnits([]model.HoldingKey{{ClientID: 1, FundID: 1}, {ClientID: 1, FundID: 2}})
+	if err != nil {
+		t.Fatalf("SumDealtUnits() error = %v", err)
+	}
+	if want := map[model.HoldingKey]model.Units{{ClientID: 1, FundID: 1}: 70000000}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SumDealtUnits() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+// Package postgres implements the repository interfaces on top of a PostgreSQL database using database/sql.
+// The schema is managed by the migrate package.
+package postgres
+
+import (
+	"cushon/internal/model"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// PostgreSQL error codes raised when a constraint fails
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// Open connects to the PostgreSQL database described by dataSourceName and checks the connection
+func Open(dataSourceName string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+
+	return db, nil
+}
+
+// inTx runs fn in a transaction that is committed if fn succeeds and rolled back otherwise
+func inTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// violatedForeignKey returns the name of the foreign key constraint that caused err, if any
+func violatedForeignKey(err error) (string, bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return pqErr.Constraint, true
+	}
+	return "", false
+}
+
+// violatedUnique returns the name of the unique constraint that caused err, if any
+func violatedUnique(err error) (string, bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return pqErr.Constraint, true
+	}
+	return "", false
+}
+
+// nullableDate converts an optional date into a query argument, nil becoming NULL
+func nullableDate(date *model.Date) any {
+	if date == nil {
+		return nil
+	}
+	return date.Time
+}
+
+// nullableID converts an optional reference into a query argument, 0 becoming NULL
+func nullableID(id uint) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// nullableString converts an optional string into a query argument, "" becoming NULL
+func nullableString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,18 @@
+package postgres
+
+import "cushon/internal/repository"
+
+// Compile-time checks that the PostgreSQL repositories implement the repository interfaces
+var (
+	_ repository.AllocationRepository  = (*AllocationRepository)(nil)
+	_ repository.CustomerRepository    = (*CustomerRepository)(nil)
+	_ repository.EmployerRepository    = (*EmployerRepository)(nil)
+	_ repository.FundRepository        = (*FundRepository)(nil)
+	_ repository.FundPriceRepository   = (*FundPriceRepository)(nil)
+	_ repository.GlidePathRepository   = (*GlidePathRepository)(nil)
+	_ repository.ScheduleRepository    = (*ScheduleRepository)(nil)
+	_ repository.InvestmentRepository  = (*InvestmentRepository)(nil)
+	_ repository.DealingRepository     = (*DealingRepository)(nil)
+	_ repository.IdempotencyRepository = (*IdempotencyRepository)(nil)
+	_ repository.APIKeyRepository      = (*APIKeyRepository)(nil)
+)
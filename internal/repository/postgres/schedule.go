@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"database/sql"
+	"errors"
+)
+
+// scheduleColumns lists the columns read by scanSchedule, in order
+const scheduleColumns = `id, customer_id, fund_id, amount_minor, currency, frequency, start_date, end_date, status, next_run_date, created_at, updated_at`
+
+// ScheduleRepository is a PostgreSQL implementation of repository.ScheduleRepository
+type ScheduleRepository struct {
+	db *sql.DB
+}
+
+// NewScheduleRepository creates a new PostgreSQL contribution schedule repository
+func NewScheduleRepository(db *sql.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// CreateSchedule stores a new contribution schedule. The foreign keys guarantee that the customer and the fund
+// exist.
+func (r *ScheduleRepository) CreateSchedule(schedule *model.ContributionSchedule) (*model.ContributionSchedule, error) {
+	row := r.db.QueryRow(
+		`INSERT INTO contribution_schedules
+		     (customer_id, fund_id, amount_minor, currency, frequency, start_date, end_date, status, next_run_date, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now(), now())
+		 RETURNING `+scheduleColumns,
+		schedule.CustomerID, schedule.FundID, schedule.Amount.Minor, schedule.Amount.Currency, schedule.Frequency,
+		schedule.StartDate.Time, nullableDate(schedule.EndDate), schedule.Status, schedule.NextRunDate.Time,
+	)
+
+	created, err := scanSchedule(row)
+	if constraint, ok := violatedForeignKey(err); ok {
+		if constraint == "contribution_schedules_fund_id_fkey" {
+			return nil, repository.ErrFundNotFound
+		}
+		return nil, repository.ErrCustomerNotFound
+	}
+	return created, err
+}
+
+// GetScheduleByID retrieves a contribution schedule by its ID
+func (r *ScheduleRepository) GetScheduleByID(id uint) (*model.ContributionSchedule, error) {
+	row := r.db.QueryRow(`SELECT `+scheduleColumns+` FROM contribution_schedules WHERE id = $1`, id)
+
+	schedule, err := scanSchedule(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrScheduleNotFound
+	}
+	return schedule, err
+}
+
+// ListSchedules retrieves the contribution schedules matching filter ordered by ID
+func (r *ScheduleRepository) ListSchedules(filter model.ScheduleFilter) ([]*model.ContributionSchedule, error) {
+	rows, err := r.db.Query(
+		`SELECT `+scheduleColumns+` FROM contribution_schedules
+		 WHERE ($1::BIGINT IS NULL OR customer_id = $1)
+		   AND ($2::TEXT IS NULL OR status = $2)
+		 ORDER BY id`,
+		filter.CustomerID, filter.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := make([]*model.ContributionSchedule, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// UpdateSchedule replaces a contribution schedule's status and next run date
+func (r *ScheduleRepository) UpdateSchedule(schedule *model.ContributionSchedule) (*model.ContributionSchedule, error) {
+	row := r.db.QueryRow(
+		`UPDATE contribution_schedules SET status = $2, next_run_date = $3, updated_at = now()
+		 WHERE id = $1
+		 RETURNING `+scheduleColumns,
+		schedule.ID, schedule.Status, schedule.NextRunDate.Time,
+	)
+
+	updated, err := scanSchedule(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrScheduleNotFound
+	}
+	return updated, err
+}
+
+// scanSchedule reads a row selected with scheduleColumns
+func scanSchedule(row scanner) (*model.ContributionSchedule, error) {
+	schedule := &model.ContributionSchedule{}
+	var endDate sql.NullTime
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.CustomerID,
+		&schedule.FundID,
+		&schedule.Amount.Minor,
+		&schedule.Amount.Currency,
+		&schedule.Frequency,
+		&schedule.StartDate.Time,
+		&endDate,
+		&schedule.Status,
+		&schedule.NextRunDate.Time,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	schedule.StartDate = model.DateOf(schedule.StartDate.Time)
+	schedule.NextRunDate = model.DateOf(schedule.NextRunDate.Time)
+	if endDate.Valid {
+		date := model.DateOf(endDate.Time)
+		schedule.EndDate = &date
+	}
+	return schedule, nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/model"
+	"cushon/internal/repository"
+)
+
+func TestScheduleRepository(t *testing.T) {
+	db := openTestDB(t)
+	seedInvestmentFixtures(t, NewInvestmentRepository(db))
+	repo := NewScheduleRepository(db)
+
+	if _, err := repo.GetScheduleByID(1); !errors.Is(err, repository.ErrScheduleNotFound) {
+		t.Fatalf("GetScheduleByID() error = %v, want ErrScheduleNotFound", err)
+	}
+
+	fundID := uint(2)
+	start := model.NewDate(2027, time.January, 31)
+	end := model.NewDate(2027, time.December, 31)
+	schedule := &model.ContributionSchedule{
+		CustomerID:  1,
+		FundID:      &fundID,
+		Amount:      model.NewMoney(10000, model.DefaultCurrency),
+		Frequency:   model.ScheduleFrequencyMonthly,
+		StartDate:   start,
+		EndDate:     &end,
+		Status:      model.ScheduleStatusActive,
+		NextRunDate: start,
+	}
+	created, err := repo.CreateSchedule(schedule)
+	if err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+	if created.ID == 0 || created.FundID == nil || *created.FundID != 2 || created.StartDate != start ||
+		created.EndDate == nil || *created.EndDate != end || created.NextRunDate != start || created.Amount != schedule.Amount {
+		t.Errorf("CreateSchedule() = %+v, want the schedule as it was given", created)
+	}
+
+	created.Status = model.ScheduleStatusPaused
+	created.NextRunDate = model.NewDate(2027, time.February, 28)
+	updated, err := repo.UpdateSchedule(created)
+	if err != nil {
+		t.Fatalf("UpdateSchedule() error = %v", err)
+	}
+	if updated.Status != model.ScheduleStatusPaused || updated.NextRunDate != created.NextRunDate {
+		t.Errorf("UpdateSchedule() = %+v, want it paused with its next run moved on", updated)
+	}
+	if _, err := repo.UpdateSchedule(&model.ContributionSchedule{ID: 99, Status: model.ScheduleStatusActive}); !errors.Is(err, repository.ErrScheduleNotFound) {
+		t.Errorf("UpdateSchedule() error = %v, want ErrScheduleNotFound", err)
+	}
+
+	unknownFund := uint(99)
+	if _, err := repo.CreateSchedule(&model.ContributionSchedule{CustomerID: 1, FundID: &unknownFund, Amount: schedule.Amount, Frequency: schedule.Frequency, StartDate: start, Status: model.ScheduleStatusActive, NextRunDate: start}); !errors.Is(err, repository.ErrFundNotFound) {
+		t.Errorf("CreateSchedule() error = %v, want ErrFundNotFound", err)
+	}
+	if _, err := repo.CreateSchedule(&model.ContributionSchedule{CustomerID: 99, Amount: schedule.Amount, Frequency: schedule.Frequency, StartDate: start, Status: model.ScheduleStatusActive, NextRunDate: start}); !errors.Is(err, repository.ErrCustomerNotFound) {
+		t.Errorf("CreateSchedule() error = %v, want ErrCustomerNotFound", err)
+	}
+
+	// A schedule across customer 2's allocation
+	if _, err := repo.CreateSchedule(&model.ContributionSchedule{CustomerID: 2, Amount: schedule.Amount, Frequency: model.ScheduleFrequencyWeekly, StartDate: start, Status: model.ScheduleStatusActive, NextRunDate: start}); err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+
+	customerID := uint(1)
+	active := model.ScheduleStatusActive
+	byCustomer, err := repo.ListSchedules(model.ScheduleFilter{CustomerID: &customerID})
+	if err != nil {
+		t.Fatalf("ListSchedules() error = %v", err)
+	}
+	if len(byCustomer) != 1 || byCustomer[0].ID != created.ID {
+		t.Errorf("ListSchedules() = %+v, want customer 1's schedule", byCustomer)
+	}
+	byStatus, err := repo.ListSchedules(model.ScheduleFilter{Status: &active})
+	if err != nil {
+		t.Fatalf("ListSchedules() error = %v", err)
+	}
+	if len(byStatus) != 1 || byStatus[0].CustomerID != 2 || byStatus[0].FundID != nil || byStatus[0].EndDate != nil {
+		t.Errorf("ListSchedules() = %+v, want customer 2's active schedule without a fund or end date", byStatus)
+	}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"cushon/internal/model"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrScheduleNotFound is returned when a contribution schedule doesn't exist
+var ErrScheduleNotFound = errors.New("contribution schedule not found")
+
+// ScheduleRepository defines the contract for storing contribution schedules. Implementations don't check that
+// the customer or fund exist, that is up to the caller.
+type ScheduleRepository interface {
+	CreateSchedule(schedule *model.ContributionSchedule) (*model.ContributionSchedule, error)
+	GetScheduleByID(id uint) (*model.ContributionSchedule, error)
+	ListSchedules(filter model.ScheduleFilter) ([]*model.ContributionSchedule, error)
+	UpdateSchedule(schedule *model.ContributionSchedule) (*model.ContributionSchedule, error)
+}
+
+// InMemoryScheduleRepository is a simple in-memory implementation of ScheduleRepository.
+// It is safe for concurrent use.
+type InMemoryScheduleRepository struct {
+	mu        sync.RWMutex
+	schedules map[uint]*model.ContributionSchedule
+	nextID    uint
+}
+
+// NewInMemoryScheduleRepository creates a new in-memory contribution schedule repository
+func NewInMemoryScheduleRepository() *InMemoryScheduleRepository {
+	return &InMemoryScheduleRepository{
+		schedules: make(map[uint]*model.ContributionSchedule),
+		nextID:    1,
+	}
+}
+
+// CreateSchedule stores a new contribution schedule
+func (r *InMemoryScheduleRepository) CreateSchedule(schedule *model.ContributionSchedule) (*model.ContributionSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copySchedule(schedule)
+	stored.ID = r.nextID
+	stored.CreatedAt = time.Now()
+	stored.UpdatedAt = stored.CreatedAt
+	r.schedules[stored.ID] = stored
+	r.nextID++
+
+	return copySchedule(stored), nil
+}
+
+// GetScheduleByID retrieves a contribution schedule by its ID
+func (r *InMemoryScheduleRepository) GetScheduleByID(id uint) (*model.ContributionSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schedule, exists := r.schedules[id]
+	if !exists {
+		return nil, ErrScheduleNotFound
+	}
+	return copySchedule(schedule), nil
+}
+
+// ListSchedules retrieves the contribution schedules matching filter ordered by ID
+func (r *InMemoryScheduleRepository) ListSchedules(filter model.ScheduleFilter) ([]*model.ContributionSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schedules := make([]*model.ContributionSchedule, 0)
+	for _, schedule := range r.schedules {
+		if filter.Matches(schedule) {
+			schedules = append(schedules, copySchedule(schedule))
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].ID < schedules[j].ID })
+	return schedules, nil
+}
+
+// UpdateSchedule replaces a contribution schedule's status and next run date
+func (r *InMemoryScheduleRepository) UpdateSchedule(schedule *model.ContributionSchedule) (*model.ContributionSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.schedules[schedule.ID]
+	if !exists {
+		return nil, ErrScheduleNotFound
+	}
+	stored.Status = schedule.Status
+	stored.NextRunDate = schedule.NextRunDate
+	stored.UpdatedAt = time.Now()
+	return copySchedule(stored), nil
+}
+
+// copySchedule returns a copy of a contribution schedule that shares none of its optional fields
+func copySchedule(schedule *model.ContributionSchedule) *model.ContributionSchedule {
+	c := *schedule
+	if schedule.FundID != nil {
+		fundID := *schedule.FundID
+		c.FundID = &fundID
+	}
+	if schedule.EndDate != nil {
+		endDate := *schedule.EndDate
+		c.EndDate = &endDate
+	}
+	return &c
+}
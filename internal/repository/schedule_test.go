@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/model"
+)
+
+func TestInMemoryScheduleRepository(t *testing.T) {
+	repo := NewInMemoryScheduleRepository()
+
+	if _, err := repo.GetScheduleByID(1); !errors.Is(err, ErrScheduleNotFound) {
+		t.Fatalf("GetScheduleByID() error = %v, want ErrScheduleNotFound", err)
+	}
+
+	fundID := uint(2)
+	start := model.NewDate(2027, time.January, 31)
+	created, err := repo.CreateSchedule(&model.ContributionSchedule{
+		CustomerID:  1,
+		FundID:      &fundID,
+		Amount:      model.NewMoney(10000, model.DefaultCurrency),
+		Frequency:   model.ScheduleFrequencyMonthly,
+		StartDate:   start,
+		Status:      model.ScheduleStatusActive,
+		NextRunDate: start,
+	})
+	if err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+	if created.ID != 1 || created.CreatedAt.IsZero() {
+		t.Errorf("CreateSchedule() = %+v, want ID 1 with CreatedAt set", created)
+	}
+	fundID = 99
+	*created.FundID = 99
+
+	created.Status = model.ScheduleStatusPaused
+	created.NextRunDate = model.NewDate(2027, time.February, 28)
+	created.Amount = model.NewMoney(1, model.DefaultCurrency)
+	updated, err := repo.UpdateSchedule(created)
+	if err != nil {
+		t.Fatalf("UpdateSchedule() error = %v", err)
+	}
+	if updated.Status != model.ScheduleStatusPaused || updated.NextRunDate != created.NextRunDate ||
+		updated.Amount.Minor != 10000 || *updated.FundID != 2 {
+		t.Errorf("UpdateSchedule() = %+v, want only the status and next run date changed", updated)
+	}
+	if _, err := repo.UpdateSchedule(&model.ContributionSchedule{ID: 99}); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("UpdateSchedule() error = %v, want ErrScheduleNotFound", err)
+	}
+
+	repo.CreateSchedule(&model.ContributionSchedule{CustomerID: 2, Status: model.ScheduleStatusActive})
+	repo.CreateSchedule(&model.ContributionSchedule{CustomerID: 1, Status: model.ScheduleStatusActive})
+
+	customerID := uint(1)
+	active := model.ScheduleStatusActive
+	tests := []struct {
+		name    string
+		filter  model.ScheduleFilter
+		wantIDs []uint
+	}{
+		{name: "No filter", wantIDs: []uint{1, 2, 3}},
+		{name: "By customer", filter: model.ScheduleFilter{CustomerID: &customerID}, wantIDs: []uint{1, 3}},
+		{name: "By status", filter: model.ScheduleFilter{Status: &active}, wantIDs: []uint{2, 3}},
+		{name: "By customer and status", filter: model.ScheduleFilter{CustomerID: &customerID, Status: &active}, wantIDs: []uint{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ListSchedules(tt.filter)
+			if err != nil {
+				t.Fatalf("ListSchedules() error = %v", err)
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("ListSchedules() returned %d schedules, want %d", len(got), len(tt.wantIDs))
+			}
+			for i, schedule := range got {
+				if schedule.ID != tt.wantIDs[i] {
+					t.Errorf("ListSchedules()[%d].ID = %d, want %d", i, schedule.ID, tt.wantIDs[i])
+				}
+			}
+		})
+	}
+}
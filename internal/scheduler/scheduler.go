@@ -0,0 +1,77 @@
+// Package scheduler runs background jobs inside the server process.
+package scheduler
+
+import (
+	"context"
+	"cushon/internal/clock"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is run on every tick with the clock's time. Jobs decide for themselves whether any work is due at now,
+// so a job that missed its time, e.g. while the server was down, catches up on the next tick.
+type Job func(now time.Time) error
+
+// Scheduler runs its jobs one after the other at a fixed interval
+type Scheduler struct {
+	clock    clock.Clock
+	interval time.Duration
+
+	mu   sync.Mutex
+	jobs []namedJob
+}
+
+// namedJob is a job and the name its errors are logged under
+type namedJob struct {
+	name string
+	run  Job
+}
+
+// New creates a scheduler that runs its jobs every interval at the time told by c
+func New(c clock.Clock, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		clock:    c,
+		interval: interval,
+	}
+}
+
+// Add registers a job to be run on every tick
+func (s *Scheduler) Add(name string, run Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, namedJob{name: name, run: run})
+}
+
+// Tick runs every job once in the order they were added. A failing job is logged and doesn't stop the others.
+// It returns the number of jobs that failed.
+func (s *Scheduler) Tick() int {
+	s.mu.Lock()
+	jobs := append([]namedJob(nil), s.jobs...)
+	s.mu.Unlock()
+
+	now := s.clock.Now()
+	failed := 0
+	for _, job := range jobs {
+		if err := job.run(now); err != nil {
+			log.Printf("Scheduled job %s failed: %v", job.name, err)
+			failed++
+		}
+	}
+	return failed
+}
+
+// Run ticks straight away and then every interval until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.Tick()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
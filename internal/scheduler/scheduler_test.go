@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"cushon/internal/mocks"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Tick(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	clock := &mocks.Clock{MockNow: now}
+	s := New(clock, time.Minute)
+
+	var ran []string
+	s.Add("first", func(at time.Time) error {
+		if !at.Equal(now) {
+			t.Errorf("job ran at %v, want %v", at, now)
+		}
+		ran = append(ran, "first")
+		return errors.New("failed")
+	})
+	s.Add("second", func(time.Time) error {
+		ran = append(ran, "second")
+		return nil
+	})
+
+	if failed := s.Tick(); failed != 1 {
+		t.Errorf("Tick() = %d failed jobs, want 1", failed)
+	}
+	if want := []string{"first", "second"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("jobs ran = %v, want %v", ran, want)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	s := New(&mocks.Clock{}, time.Millisecond)
+
+	ticks := make(chan struct{}, 3)
+	s.Add("count", func(time.Time) error {
+		select {
+		case ticks <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	for i := 0; i < cap(ticks); i++ {
+		select {
+		case <-ticks:
+		case <-time.After(time.Second):
+			t.Fatalf("job ran %d times, want %d", i, cap(ticks))
+		}
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() didn't return after the context was cancelled")
+	}
+}
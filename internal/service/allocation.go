@@ -0,0 +1,134 @@
+package service
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"errors"
+	"fmt"
+)
+
+// Allocation defines the interface for managing customers' target allocations
+type Allocation interface {
+	GetAllocation(customerID uint) (*model.TargetAllocation, error)
+	SetAllocation(customerID uint, update model.TargetAllocationUpdate) (*model.TargetAllocation, error)
+	DeleteAllocation(customerID uint) error
+}
+
+// defaultAllocationService is a concrete implementation of Allocation
+type defaultAllocationService struct {
+	repo         repository.AllocationRepository
+	customerRepo repository.CustomerRepository
+	employerRepo repository.EmployerRepository
+	fundRepo     repository.FundRepository
+}
+
+// NewDefaultAllocationService creates a new default allocation service
+func NewDefaultAllocationService(repo repository.AllocationRepository, customerRepo repository.CustomerRepository, employerRepo repository.EmployerRepository, fundRepo repository.FundRepository) *defaultAllocationService {
+	return &defaultAllocationService{
+		repo:         repo,
+		customerRepo: customerRepo,
+		employerRepo: employerRepo,
+		fundRepo:     fundRepo,
+	}
+}
+
+// GetAllocation retrieves the allocation a customer's contributions are invested by. Employed customers who
+// haven't chosen one get their employer's default fund.
+func (s *defaultAllocationService) GetAllocation(customerID uint) (*model.TargetAllocation, error) {
+	customer, err := s.customerRepo.GetCustomerByID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return targetAllocation(s.repo, s.employerRepo, customer)
+}
+
+// SetAllocation replaces a customer's target allocation. Each fund must be open to new investments and appear
+// once with a share greater than 0, and the shares must add up to 100%.
+func (s *defaultAllocationService) SetAllocation(customerID uint, update model.TargetAllocationUpdate) (*model.TargetAllocation, error) {
+	if err := validateAllocationFunds(update.Funds); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAllocation, err)
+	}
+
+	if _, err := s.customerRepo.GetCustomerByID(customerID); err != nil {
+		return nil, err
+	}
+	if err := checkFundsOpen(s.fundRepo, update.Funds); err != nil {
+		return nil, err
+	}
+
+	return s.repo.SetAllocation(&model.TargetAllocation{CustomerID: customerID, Funds: update.Funds})
+}
+
+// DeleteAllocation removes a customer's target allocation, after which employed customers' contributions go into
+// their employer's default fund
+func (s *defaultAllocationService) DeleteAllocation(customerID uint) error {
+	if _, err := s.customerRepo.GetCustomerByID(customerID); err != nil {
+		return err
+	}
+	return s.repo.DeleteAllocation(customerID)
+}
+
+// validateAllocationFunds checks that each fund appears once with a share greater than 0 and that the shares add
+// up to 100%
+func validateAllocationFunds(funds []model.FundAllocation) error {
+	if len(funds) == 0 {
+		return errors.New("at least one fund is required")
+	}
+	seen := make(map[uint]bool, len(funds))
+	var total model.Percent
+	for _, fund := range funds {
+		if fund.FundID == 0 {
+			return errors.New("fund ID is required")
+		}
+		if seen[fund.FundID] {
+			return fmt.Errorf("fund %d appears more than once", fund.FundID)
+		}
+		seen[fund.FundID] = true
+		if fund.Percentage <= 0 || fund.Percentage > model.OneHundredPercent {
+			return fmt.Errorf("fund %d must have a percentage greater than 0 and at most 100", fund.FundID)
+		}
+		total += fund.Percentage
+	}
+	if total != model.OneHundredPercent {
+		return fmt.Errorf("percentages add up to %s, not 100", total)
+	}
+	return nil
+}
+
+// checkFundsOpen returns an error unless every fund in an allocation exists and is open to new investments
+func checkFundsOpen(fundRepo repository.FundRepository, funds []model.FundAllocation) error {
+	for _, fund := range funds {
+		if err := checkFundOpen(fundRepo, fund.FundID); err != nil {
+			if errors.Is(err, ErrFundNotFound) {
+				return fmt.Errorf("fund %d: %w", fund.FundID, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// targetAllocation returns the allocation a customer's contributions are invested by: the one they have chosen,
+// or all of it in their employer's default fund when they haven't chosen one
+func targetAllocation(allocationRepo repository.AllocationRepository, employerRepo repository.EmployerRepository, customer *model.Customer) (*model.TargetAllocation, error) {
+	allocation, err := allocationRepo.GetAllocation(customer.ID)
+	if !errors.Is(err, ErrAllocationNotFound) {
+		return allocation, err
+	}
+	if customer.EmployerID == nil {
+		return nil, fmt.Errorf("%w: customer %d hasn't chosen one", ErrAllocationNotFound, customer.ID)
+	}
+
+	employer, err := employerRepo.GetEmployerByID(*customer.EmployerID)
+	if err != nil {
+		return nil, err
+	}
+	if employer.DefaultFundID == nil {
+		return nil, fmt.Errorf("%w: customer %d hasn't chosen one and employer %d has no default fund", ErrAllocationNotFound, customer.ID, employer.ID)
+	}
+	return &model.TargetAllocation{
+		CustomerID:      customer.ID,
+		Funds:           []model.FundAllocation{{FundID: *employer.DefaultFundID, Percentage: model.OneHundredPercent}},
+		EmployerDefault: true,
+	}, nil
+}
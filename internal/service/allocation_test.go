@@ -0,0 +1,194 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+)
+
+func TestDefaultAllocationService_GetAllocation(t *testing.T) {
+	employerID, defaultFundID := uint(7), uint(2)
+	chosen := &model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 1, Percentage: 60000}, {FundID: 3, Percentage: 40000}}}
+
+	tests := []struct {
+		name                string
+		employed            bool
+		employer            *model.Employer
+		allocation          *model.TargetAllocation
+		customerErr         error
+		wantFunds           []model.FundAllocation
+		wantEmployerDefault bool
+		wantErr             error
+	}{
+		{
+			name:       "Chosen allocation",
+			allocation: chosen,
+			wantFunds:  chosen.Funds,
+		},
+		{
+			name:                "Employer default fund",
+			employed:            true,
+			employer:            &model.Employer{ID: employerID, DefaultFundID: &defaultFundID},
+			wantFunds:           []model.FundAllocation{{FundID: defaultFundID, Percentage: model.OneHundredPercent}},
+			wantEmployerDefault: true,
+		},
+		{
+			name:     "Employer without a default fund",
+			employed: true,
+			employer: &model.Employer{ID: employerID},
+			wantErr:  ErrAllocationNotFound,
+		},
+		{
+			name:    "Retail customer without an allocation",
+			wantErr: ErrAllocationNotFound,
+		},
+		{
+			name:        "Customer not found",
+			customerErr: ErrCustomerNotFound,
+			wantErr:     ErrCustomerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customer := &model.Customer{ID: 1}
+			if tt.employed {
+				customer.EmployerID = &employerID
+			}
+			allocationRepo := &mocks.AllocationRepository{MockAllocation: tt.allocation}
+			if tt.allocation == nil {
+				allocationRepo.MockErr = ErrAllocationNotFound
+			}
+
+			service := NewDefaultAllocationService(
+				allocationRepo,
+				&mocks.CustomerRepository{MockCustomer: customer, MockErr: tt.customerErr},
+				&mocks.EmployerRepository{MockEmployer: tt.employer},
+				&mocks.FundRepository{},
+			)
+
+			got, err := service.GetAllocation(1)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetAllocation() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetAllocation() unexpected error = %v", err)
+			}
+			if len(got.Funds) != len(tt.wantFunds) {
+				t.Fatalf("GetAllocation() = %+v, want funds %+v", got.Funds, tt.wantFunds)
+			}
+			for i, fund := range tt.wantFunds {
+				if got.Funds[i] != fund {
+					t.Errorf("funds[%d] = %+v, want %+v", i, got.Funds[i], fund)
+				}
+			}
+			if got.EmployerDefault != tt.wantEmployerDefault {
+				t.Errorf("EmployerDefault = %v, want %v", got.EmployerDefault, tt.wantEmployerDefault)
+			}
+		})
+	}
+}
+
+func TestDefaultAllocationService_SetAllocation(t *testing.T) {
+	tests := []struct {
+		name        string
+		funds       []model.FundAllocation
+		fundStatus  model.FundStatus
+		fundErr     error
+		customerErr error
+		wantErr     error
+	}{
+		{
+			name:  "Split between two funds",
+			funds: []model.FundAllocation{{FundID: 1, Percentage: 60000}, {FundID: 3, Percentage: 40000}},
+		},
+		{
+			name:  "Fractional percentages",
+			funds: []model.FundAllocation{{FundID: 1, Percentage: 33333}, {FundID: 2, Percentage: 33333}, {FundID: 3, Percentage: 33334}},
+		},
+		{
+			name:    "No funds",
+			wantErr: ErrInvalidAllocation,
+		},
+		{
+			name:    "Percentages add up to less than 100",
+			funds:   []model.FundAllocation{{FundID: 1, Percentage: 60000}, {FundID: 3, Percentage: 30000}},
+			wantErr: ErrInvalidAllocation,
+		},
+		{
+			name:    "Percentages add up to more than 100",
+			funds:   []model.FundAllocation{{FundID: 1, Percentage: 60000}, {FundID: 3, Percentage: 50000}},
+			wantErr: ErrInvalidAllocation,
+		},
+		{
+			name:    "Zero percentage",
+			funds:   []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}, {FundID: 3, Percentage: 0}},
+			wantErr: ErrInvalidAllocation,
+		},
+		{
+			name:    "Fund chosen twice",
+			funds:   []model.FundAllocation{{FundID: 1, Percentage: 50000}, {FundID: 1, Percentage: 50000}},
+			wantErr: ErrInvalidAllocation,
+		},
+		{
+			name:    "Missing fund ID",
+			funds:   []model.FundAllocation{{Percentage: model.OneHundredPercent}},
+			wantErr: ErrInvalidAllocation,
+		},
+		{
+			name:       "Closed fund",
+			funds:      []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}},
+			fundStatus: model.FundStatusClosed,
+			wantErr:    ErrFundNotOpen,
+		},
+		{
+			name:    "Fund not found",
+			funds:   []model.FundAllocation{{FundID: 99, Percentage: model.OneHundredPercent}},
+			fundErr: ErrFundNotFound,
+			wantErr: ErrFundNotFound,
+		},
+		{
+			name:        "Customer not found",
+			funds:       []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}},
+			customerErr: ErrCustomerNotFound,
+			wantErr:     ErrCustomerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fundStatus := tt.fundStatus
+			if fundStatus == "" {
+				fundStatus = model.FundStatusOpen
+			}
+
+			service := NewDefaultAllocationService(
+				&mocks.AllocationRepository{},
+				&mocks.CustomerRepository{MockCustomer: &model.Customer{ID: 1}, MockErr: tt.customerErr},
+				&mocks.EmployerRepository{},
+				&mocks.FundRepository{MockFund: &model.Fund{ID: 1, Status: fundStatus}, MockErr: tt.fundErr},
+			)
+
+			got, err := service.SetAllocation(1, model.TargetAllocationUpdate{Funds: tt.funds})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("SetAllocation() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetAllocation() unexpected error = %v", err)
+			}
+			if got.CustomerID != 1 || len(got.Funds) != len(tt.funds) {
+				t.Errorf("SetAllocation() = %+v, want customer 1's allocation of %+v", got, tt.funds)
+			}
+		})
+	}
+}
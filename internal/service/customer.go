@@ -3,22 +3,33 @@ package service
 import (
 	"cushon/internal/model"
 	"cushon/internal/repository"
+	"fmt"
 )
 
 // Customer defines the interface for customer operations
 type Customer interface {
 	NewRetailCustomer(name string) (*model.Customer, error)
 	NewEmployedCustomer(name string, employerID uint) (*model.Customer, error)
+	GetCustomer(id uint) (*model.Customer, error)
+	ListCustomers(filter model.CustomerFilter) ([]*model.Customer, error)
+	UpdateCustomer(id uint, update model.CustomerUpdate) (*model.Customer, error)
+	DeleteCustomer(id uint) error
 }
 
 // defaultCustomerService is a concrete implementation of CustomerService.
 type defaultCustomerService struct {
-	repo repository.CustomerRepository
+	repo          repository.CustomerRepository
+	employerRepo  repository.EmployerRepository
+	glidePathRepo repository.GlidePathRepository
 }
 
 // NewDefaultCustomerService creates a new default user service.
-func NewDefaultCustomerService(repo repository.CustomerRepository) *defaultCustomerService {
-	return &defaultCustomerService{repo: repo}
+func NewDefaultCustomerService(repo repository.CustomerRepository, employerRepo repository.EmployerRepository, glidePathRepo repository.GlidePathRepository) *defaultCustomerService {
+	return &defaultCustomerService{
+		repo:          repo,
+		employerRepo:  employerRepo,
+		glidePathRepo: glidePathRepo,
+	}
 }
 
 // NewRetailCustomer creates a new retail customer
@@ -26,7 +37,87 @@ func (s *defaultCustomerService) NewRetailCustomer(name string) (*model.Customer
 	return s.repo.CreateCustomer(name, nil)
 }
 
-// NewEmployedCustomer creates a new employed customer
+// NewEmployedCustomer creates a new employed customer. The employer must exist and be active.
 func (s *defaultCustomerService) NewEmployedCustomer(name string, employerID uint) (*model.Customer, error) {
+	if err := s.checkEmployerCanEnrol(employerID); err != nil {
+		return nil, err
+	}
 	return s.repo.CreateCustomer(name, &employerID)
 }
+
+// GetCustomer retrieves a customer by ID
+func (s *defaultCustomerService) GetCustomer(id uint) (*model.Customer, error) {
+	return s.repo.GetCustomerByID(id)
+}
+
+// ListCustomers retrieves the customers matching filter
+func (s *defaultCustomerService) ListCustomers(filter model.CustomerFilter) ([]*model.Customer, error) {
+	return s.repo.ListCustomers(filter)
+}
+
+// UpdateCustomer applies a partial update to a customer. Setting an employer moves the customer to that
+// employer (or turns a retail customer into an employed one), clearing it turns the customer into a retail one.
+// Customers can only join active employers, and can only opt into a glide path once they have given their date of
+// birth and retirement age. The customer is changed under the repository's lock, so concurrent updates don't
+// overwrite each other.
+func (s *defaultCustomerService) UpdateCustomer(id uint, update model.CustomerUpdate) (*model.Customer, error) {
+	return s.repo.UpdateCustomer(id, func(customer *model.Customer) error {
+		if update.Name != nil {
+			customer.Name = *update.Name
+		}
+
+		if update.EmployerID.Set {
+			employerID := update.EmployerID.Value
+			joining := employerID != nil && (customer.EmployerID == nil || *customer.EmployerID != *employerID)
+			if joining {
+				if err := s.checkEmployerCanEnrol(*employerID); err != nil {
+					return err
+				}
+			}
+			customer.EmployerID = employerID
+		}
+
+		plan := &customer.RetirementPlan
+		if update.DateOfBirth != nil {
+			if !update.DateOfBirth.Before(model.Today().Time) {
+				return fmt.Errorf("%w: date of birth must be in the past", ErrInvalidRetirementPlan)
+			}
+			plan.DateOfBirth = update.DateOfBirth
+		}
+		if update.RetirementAge != nil {
+			if *update.RetirementAge < model.MinRetirementAge || *update.RetirementAge > model.MaxRetirementAge {
+				return fmt.Errorf("%w: retirement age must be between %d and %d", ErrInvalidRetirementPlan, model.MinRetirementAge, model.MaxRetirementAge)
+			}
+			plan.RetirementAge = update.RetirementAge
+		}
+		if update.GlidePathID.Set {
+			plan.GlidePathID = update.GlidePathID.Value
+			if plan.GlidePathID != nil {
+				if _, err := s.glidePathRepo.GetGlidePathByID(*plan.GlidePathID); err != nil {
+					return err
+				}
+			}
+		}
+		if plan.GlidePathID != nil && (plan.DateOfBirth == nil || plan.RetirementAge == nil) {
+			return fmt.Errorf("%w: a date of birth and retirement age are needed to follow a glide path", ErrInvalidRetirementPlan)
+		}
+		return nil
+	})
+}
+
+// DeleteCustomer removes a customer. Customers that have investments are kept for audit purposes.
+func (s *defaultCustomerService) DeleteCustomer(id uint) error {
+	return s.repo.DeleteCustomerIfNoInvestments(id)
+}
+
+// checkEmployerCanEnrol returns an error unless the employer exists and is still active
+func (s *defaultCustomerService) checkEmployerCanEnrol(employerID uint) error {
+	employer, err := s.employerRepo.GetEmployerByID(employerID)
+	if err != nil {
+		return err
+	}
+	if !employer.Active {
+		return ErrEmployerInactive
+	}
+	return nil
+}
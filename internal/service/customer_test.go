@@ -53,7 +53,7 @@ func TestDefaultCustomerService_NewRetailCustomer(t *testing.T) {
 				MockCustomer: tt.mockCustomer,
 			}
 
-			service := NewDefaultCustomerService(mockRepo)
+			service := NewDefaultCustomerService(mockRepo, &mocks.EmployerRepository{}, &mocks.GlidePathRepository{})
 			got, err := service.NewRetailCustomer(tt.customerName)
 
 			if tt.wantErr != nil {
@@ -90,6 +90,8 @@ func TestDefaultCustomerService_NewEmployedCustomer(t *testing.T) {
 		customerName string
 		employerID   uint
 		mockCustomer *model.Customer
+		employerErr  error
+		inactive     bool
 		mockErr      error
 		wantErr      error
 	}{
@@ -115,6 +117,24 @@ func TestDefaultCustomerService_NewEmployedCustomer(t *testing.T) {
 			mockErr:      errors.New("customer name cannot be empty"),
 			wantErr:      errors.New("customer name cannot be empty"),
 		},
+		{
+			name:         "Unknown employer",
+			customerName: "Jane Smith",
+			employerID:   99,
+			mockCustomer: nil,
+			employerErr:  ErrEmployerNotFound,
+			mockErr:      nil,
+			wantErr:      ErrEmployerNotFound,
+		},
+		{
+			name:         "Inactive employer",
+			customerName: "Jane Smith",
+			employerID:   3,
+			mockCustomer: nil,
+			inactive:     true,
+			mockErr:      nil,
+			wantErr:      ErrEmployerInactive,
+		},
 		{
 			name:         "Repository error",
 			customerName: "John Doe",
@@ -132,7 +152,12 @@ func TestDefaultCustomerService_NewEmployedCustomer(t *testing.T) {
 				MockCustomer: tt.mockCustomer,
 			}
 
-			service := NewDefaultCustomerService(mockRepo)
+			mockEmployerRepo := &mocks.EmployerRepository{
+				MockErr:      tt.employerErr,
+				MockEmployer: &model.Employer{ID: tt.employerID, Active: !tt.inactive},
+			}
+
+			service := NewDefaultCustomerService(mockRepo, mockEmployerRepo, &mocks.GlidePathRepository{})
 			got, err := service.NewEmployedCustomer(tt.customerName, tt.employerID)
 
 			if tt.wantErr != nil {
@@ -169,3 +194,189 @@ func TestDefaultCustomerService_NewEmployedCustomer(t *testing.T) {
 func uintPtr(n uint) *uint {
 	return &n
 }
+
+func TestDefaultCustomerService_UpdateCustomer(t *testing.T) {
+	existing := &model.Customer{ID: 1, Name: "Jane Smith", EmployerID: uintPtr(1)}
+	dateOfBirth := model.NewDate(1970, time.June, 15)
+	tomorrow := model.Today().AddDays(1)
+	planned := &model.Customer{
+		ID:             1,
+		Name:           "Jane Smith",
+		RetirementPlan: model.RetirementPlan{DateOfBirth: &dateOfBirth, RetirementAge: intPtr(67)},
+	}
+
+	tests := []struct {
+		name            string
+		existing        *model.Customer
+		update          model.CustomerUpdate
+		customerErr     error
+		employerErr     error
+		glidePathErr    error
+		inactive        bool
+		wantName        string
+		wantEmployerID  *uint
+		wantGlidePathID *uint
+		wantErr         error
+	}{
+		{
+			name:           "Rename keeps employer",
+			update:         model.CustomerUpdate{Name: stringPtr("Jane Doe")},
+			wantName:       "Jane Doe",
+			wantEmployerID: uintPtr(1),
+		},
+		{
+			name:           "Move to another employer",
+			update:         model.CustomerUpdate{EmployerID: model.OptionalID{Set: true, Value: uintPtr(2)}},
+			wantName:       "Jane Smith",
+			wantEmployerID: uintPtr(2),
+		},
+		{
+			name:     "Clear employer makes the customer retail",
+			update:   model.CustomerUpdate{EmployerID: model.OptionalID{Set: true}},
+			wantName: "Jane Smith",
+		},
+		{
+			name:        "Unknown employer",
+			update:      model.CustomerUpdate{EmployerID: model.OptionalID{Set: true, Value: uintPtr(99)}},
+			employerErr: ErrEmployerNotFound,
+			wantErr:     ErrEmployerNotFound,
+		},
+		{
+			name:     "Inactive employer",
+			update:   model.CustomerUpdate{EmployerID: model.OptionalID{Set: true, Value: uintPtr(2)}},
+			inactive: true,
+			wantErr:  ErrEmployerInactive,
+		},
+		{
+			name:           "Staying with an inactive employer",
+			update:         model.CustomerUpdate{Name: stringPtr("Jane Doe"), EmployerID: model.OptionalID{Set: true, Value: uintPtr(1)}},
+			inactive:       true,
+			wantName:       "Jane Doe",
+			wantEmployerID: uintPtr(1),
+		},
+		{
+			name:        "Unknown customer",
+			update:      model.CustomerUpdate{Name: stringPtr("Jane Doe")},
+			customerErr: ErrCustomerNotFound,
+			wantErr:     ErrCustomerNotFound,
+		},
+		{
+			name:            "Opt into a glide path in the same update as the retirement plan",
+			update:          model.CustomerUpdate{DateOfBirth: &dateOfBirth, RetirementAge: intPtr(67), GlidePathID: model.OptionalID{Set: true, Value: uintPtr(1)}},
+			wantName:        "Jane Smith",
+			wantEmployerID:  uintPtr(1),
+			wantGlidePathID: uintPtr(1),
+		},
+		{
+			name:            "Opt into a glide path after giving the retirement plan",
+			existing:        planned,
+			update:          model.CustomerUpdate{GlidePathID: model.OptionalID{Set: true, Value: uintPtr(1)}},
+			wantName:        "Jane Smith",
+			wantGlidePathID: uintPtr(1),
+		},
+		{
+			name:    "Opt into a glide path without a retirement plan",
+			update:  model.CustomerUpdate{GlidePathID: model.OptionalID{Set: true, Value: uintPtr(1)}},
+			wantErr: ErrInvalidRetirementPlan,
+		},
+		{
+			name:         "Unknown glide path",
+			existing:     planned,
+			update:       model.CustomerUpdate{GlidePathID: model.OptionalID{Set: true, Value: uintPtr(99)}},
+			glidePathErr: ErrGlidePathNotFound,
+			wantErr:      ErrGlidePathNotFound,
+		},
+		{
+			name:    "Date of birth in the future",
+			update:  model.CustomerUpdate{DateOfBirth: &tomorrow},
+			wantErr: ErrInvalidRetirementPlan,
+		},
+		{
+			name:    "Retirement age too early",
+			update:  model.CustomerUpdate{RetirementAge: intPtr(model.MinRetirementAge - 1)},
+			wantErr: ErrInvalidRetirementPlan,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customer := existing
+			if tt.existing != nil {
+				customer = tt.existing
+			}
+			mockRepo := &mocks.CustomerRepository{MockCustomer: customer, MockErr: tt.customerErr}
+			mockEmployerRepo := &mocks.EmployerRepository{MockEmployer: &model.Employer{ID: 2, Active: !tt.inactive}, MockErr: tt.employerErr}
+			mockGlidePathRepo := &mocks.GlidePathRepository{MockGlidePath: &model.GlidePath{ID: 1}, MockErr: tt.glidePathErr}
+
+			service := NewDefaultCustomerService(mockRepo, mockEmployerRepo, mockGlidePathRepo)
+			got, err := service.UpdateCustomer(1, tt.update)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("UpdateCustomer() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UpdateCustomer() unexpected error = %v", err)
+			}
+			if got.Name != tt.wantName {
+				t.Errorf("Name = %v, want %v", got.Name, tt.wantName)
+			}
+			if (got.EmployerID == nil) != (tt.wantEmployerID == nil) ||
+				(got.EmployerID != nil && *got.EmployerID != *tt.wantEmployerID) {
+				t.Errorf("EmployerID = %v, want %v", got.EmployerID, tt.wantEmployerID)
+			}
+			if (got.GlidePathID == nil) != (tt.wantGlidePathID == nil) ||
+				(got.GlidePathID != nil && *got.GlidePathID != *tt.wantGlidePathID) {
+				t.Errorf("GlidePathID = %v, want %v", got.GlidePathID, tt.wantGlidePathID)
+			}
+		})
+	}
+}
+
+func TestDefaultCustomerService_DeleteCustomer(t *testing.T) {
+	tests := []struct {
+		name        string
+		customerErr error
+		wantErr     error
+	}{
+		{
+			name: "Customer without investments",
+		},
+		{
+			name:        "Customer with investments",
+			customerErr: ErrCustomerHasInvestments,
+			wantErr:     ErrCustomerHasInvestments,
+		},
+		{
+			name:        "Unknown customer",
+			customerErr: ErrCustomerNotFound,
+			wantErr:     ErrCustomerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.CustomerRepository{MockCustomer: &model.Customer{ID: 1}, MockErr: tt.customerErr}
+
+			service := NewDefaultCustomerService(mockRepo, &mocks.EmployerRepository{}, &mocks.GlidePathRepository{})
+			err := service.DeleteCustomer(1)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteCustomer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Helper function to create a pointer to string
+func stringPtr(s string) *string {
+	return &s
+}
+
+// Helper function to create a pointer to int
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"cushon/internal/clock"
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Dealing defines the interface for placing customers' pending orders with fund managers
+type Dealing interface {
+	RunDealing(date model.Date) ([]*model.DealingBatch, error)
+	GetBatch(id uint) (*model.DealingBatch, error)
+	ListBatches(filter model.DealingBatchFilter) ([]*model.DealingBatch, error)
+}
+
+// defaultDealingService is a concrete implementation of Dealing
+type defaultDealingService struct {
+	investmentRepo repository.InvestmentRepository
+	fundPriceRepo  repository.FundPriceRepository
+	dealingRepo    repository.DealingRepository
+	clock          clock.Clock
+	cutOff         model.TimeOfDay
+}
+
+// NewDefaultDealingService creates a new default dealing service that deals each day's orders once the clock
+// has passed cutOff
+func NewDefaultDealingService(investmentRepo repository.InvestmentRepository, fundPriceRepo repository.FundPriceRepository, dealingRepo repository.DealingRepository, c clock.Clock, cutOff model.TimeOfDay) *defaultDealingService {
+	return &defaultDealingService{
+		investmentRepo: investmentRepo,
+		fundPriceRepo:  fundPriceRepo,
+		dealingRepo:    dealingRepo,
+		clock:          c,
+		cutOff:         cutOff,
+	}
+}
+
+// RunDealing places every pending order received by the cut-off on date in one batch per fund, dealt at the
+// fund's price for that date. Buys keep their amount and sells their units, and the other side is worked out
+// again at the dealing price; the buy leg of a switch invests what its sell leg raises. Orders in funds that
+// haven't been priced for the date, or that have already been dealt on it, stay pending for a later run, as do
+// both legs of a switch when either can't be dealt. Sells of more units than the customer will hold once the
+// orders are dealt fail instead of being placed. It returns the batches created, ordered by fund ID.
+func (s *defaultDealingService) RunDealing(date model.Date) ([]*model.DealingBatch, error) {
+	if date.IsWeekend() {
+		return nil, fmt.Errorf("%w: %s is a %s", ErrNotDealingDay, date, date.Weekday())
+	}
+	cutOff := s.cutOff.On(date)
+	if s.clock.Now().Before(cutOff) {
+		return nil, fmt.Errorf("%w: orders for %s are dealt after %s", ErrDealingNotOpen, date, cutOff.Format(time.RFC3339))
+	}
+
+	dealt, err := s.dealingRepo.ListDealingBatches(model.DealingBatchFilter{DealingDate: &date})
+	if err != nil {
+		return nil, err
+	}
+	dealtFunds := make(map[uint]bool, len(dealt))
+	for _, batch := range dealt {
+		dealtFunds[batch.FundID] = true
+	}
+
+	pending := model.InvestmentStatusPending
+	investments, err := s.investmentRepo.ListInvestments(model.InvestmentFilter{Status: &pending})
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[uint]*model.FundPrice)
+	var orders []*model.Investment
+	switchLegs := make(map[uint][]*model.Investment)
+	for _, investment := range investments {
+		if investment.Status != model.InvestmentStatusPending || investment.CreatedAt.After(cutOff) || dealtFunds[investment.FundID] {
+			continue
+		}
+		if investment.SwitchID != 0 {
+			switchLegs[investment.SwitchID] = append(switchLegs[investment.SwitchID], investment)
+			continue
+		}
+
+		price, err := s.priceOn(prices, investment.FundID, date)
+		if err != nil {
+			return nil, err
+		}
+		if price != nil && price.Currency == investment.Amount.Currency {
+			orders = append(orders, reprice(investment, price))
+		}
+	}
+
+	for _, legs := range switchLegs {
+		if len(legs) != 2 {
+			continue
+		}
+		sell, buy := legs[0], legs[1]
+		if sell.Type != model.TransactionTypeSwitchOut {
+			sell, buy = buy, sell
+		}
+
+		sellPrice, err := s.priceOn(prices, sell.FundID, date)
+		if err != nil {
+			return nil, err
+		}
+		buyPrice, err := s.priceOn(prices, buy.FundID, date)
+		if err != nil {
+			return nil, err
+		}
+		if sellPrice == nil || buyPrice == nil || sellPrice.Currency != sell.Amount.Currency || buyPrice.Currency != sell.Amount.Currency {
+			continue
+		}
+
+		sold := reprice(sell, sellPrice)
+		bought := *buy
+		bought.Amount = model.NewMoney(-sold.Amount.Minor, sold.Amount.Currency)
+		orders = append(orders, sold, reprice(&bought, buyPrice))
+	}
+
+	held, err := s.unitsDealt(orders)
+	if err != nil {
+		return nil, err
+	}
+	orders, failed := coverSells(orders, held)
+	if len(orders) == 0 && len(failed) == 0 {
+		return []*model.DealingBatch{}, nil
+	}
+	batches, err := aggregate(orders, prices, date)
+	if err != nil {
+		return nil, err
+	}
+	return s.dealingRepo.CreateDealingBatches(batches, orders, failed)
+}
+
+// RunDue deals today's orders when now is past the cut-off of a dealing day, and does nothing otherwise. It is
+// run by the scheduler, and as funds already dealt are skipped, orders in funds priced late are picked up by a
+// later run on the same day.
+func (s *defaultDealingService) RunDue(now time.Time) error {
+	today := model.DateOf(now.UTC())
+	if today.IsWeekend() || now.Before(s.cutOff.On(today)) {
+		return nil
+	}
+	_, err := s.RunDealing(today)
+	return err
+}
+
+// priceOn returns a fund's price for exactly date, or nil when it hasn't been priced for it. Prices are cached
+// in prices, including funds without one.
+func (s *defaultDealingService) priceOn(prices map[uint]*model.FundPrice, fundID uint, date model.Date) (*model.FundPrice, error) {
+	if price, cached := prices[fundID]; cached {
+		return price, nil
+	}
+
+	price, err := s.fundPriceRepo.GetLatestFundPrice(fundID, date)
+	switch {
+	case errors.Is(err, repository.ErrFundPriceNotFound):
+		price = nil
+	case err != nil:
+		return nil, err
+	case !price.Date.Equal(date.Time):
+		price = nil
+	}
+	prices[fundID] = price
+	return price, nil
+}
+
+// unitsDealt returns the units already dealt in each holding the orders sell from. Only those holdings are
+// totalled, so the cost of a run doesn't grow with the customers' whole history.
+func (s *defaultDealingService) unitsDealt(orders []*model.Investment) (map[model.HoldingKey]model.Units, error) {
+	seen := make(map[model.HoldingKey]bool)
+	var holdings []model.HoldingKey
+	for _, order := range orders {
+		holding := model.HoldingKey{ClientID: order.ClientID, FundID: order.FundID}
+		if order.Units < 0 && !seen[holding] {
+			seen[holding] = true
+			holdings = append(holdings, holding)
+		}
+	}
+	if len(holdings) == 0 {
+		return map[model.HoldingKey]model.Units{}, nil
+	}
+	return s.investmentRepo.SumDealtUnits(holdings)
+}
+
+// reprice returns a copy of an order dealt at price. A buy keeps its amount and a sell its units.
+func reprice(order *model.Investment, price *model.FundPrice) *model.Investment {
+	dealt := *order
+	if dealt.Units < 0 {
+		value := price.NAV.ValueOf(-dealt.Units, dealt.Amount.Currency)
+		dealt.Amount = model.NewMoney(-value.Minor, value.Currency)
+	} else {
+		dealt.Units = price.NAV.UnitsFor(dealt.Amount)
+	}
+	dealt.Price = price.NAV
+	dealt.PriceDate = price.Date
+	return &dealt
+}
+
+// coverSells splits orders into those that can be dealt and the sells that can't, because they sell more units
+// than the customer holds once the orders are dealt. Only units that have been dealt count, held by the
+// transactions already placed or settled in held and bought by the orders, as buys still pending may be dealt for
+// fewer units than they were made for. Sells are covered in the order they were made, and a switch whose sell
+// fails fails with its buy.
+func coverSells(orders []*model.Investment, held map[model.HoldingKey]model.Units) (dealt, failed []*model.Investment) {
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].ID < orders[j].ID
+	})
+
+	failing := make(map[uint]bool)
+	for changed := true; changed; {
+		changed = false
+		units := make(map[model.HoldingKey]model.Units, len(held))
+		for h, u := range held {
+			units[h] = u
+		}
+		for _, order := range orders {
+			if order.Units >= 0 && !failing[order.ID] {
+				units[model.HoldingKey{ClientID: order.ClientID, FundID: order.FundID}] += order.Units
+			}
+		}
+
+		failedSwitches := make(map[uint]bool)
+		for _, order := range orders {
+			if order.Units >= 0 || failing[order.ID] {
+				continue
+			}
+			h := model.HoldingKey{ClientID: order.ClientID, FundID: order.FundID}
+			if units[h]+order.Units < 0 {
+				failing[order.ID], changed = true, true
+				continue
+			}
+			units[h] += order.Units
+		}
+		for _, order := range orders {
+			if order.SwitchID != 0 && order.Units < 0 && failing[order.ID] {
+				failedSwitches[order.SwitchID] = true
+			}
+		}
+		// Failing a switch's buy takes away units that may have covered later sells, so they are checked again
+		for _, order := range orders {
+			if order.Units >= 0 && failedSwitches[order.SwitchID] && !failing[order.ID] {
+				failing[order.ID], changed = true, true
+			}
+		}
+	}
+
+	for _, order := range orders {
+		if failing[order.ID] {
+			failed = append(failed, order)
+		} else {
+			dealt = append(dealt, order)
+		}
+	}
+	return dealt, failed
+}
+
+// aggregate nets the orders in each fund into a batch, ordered by fund ID
+func aggregate(orders []*model.Investment, prices map[uint]*model.FundPrice, date model.Date) ([]*model.DealingBatch, error) {
+	byFund := make(map[uint]*model.DealingBatch)
+	batches := make([]*model.DealingBatch, 0)
+	for _, order := range orders {
+		batch, exists := byFund[order.FundID]
+		if !exists {
+			price := prices[order.FundID]
+			batch = &model.DealingBatch{
+				FundID:      order.FundID,
+				DealingDate: date,
+				Price:       price.NAV,
+				Bought:      model.NewMoney(0, price.Currency),
+				Sold:        model.NewMoney(0, price.Currency),
+			}
+			byFund[order.FundID] = batch
+			batches = append(batches, batch)
+		}
+
+		var err error
+		if order.Units < 0 {
+			batch.Sold, err = batch.Sold.Sub(order.Amount)
+			batch.UnitsSold -= order.Units
+		} else {
+			batch.Bought, err = batch.Bought.Add(order.Amount)
+			batch.UnitsBought += order.Units
+		}
+		if err != nil {
+			return nil, err
+		}
+		batch.InvestmentIDs = append(batch.InvestmentIDs, order.ID)
+	}
+
+	for _, batch := range batches {
+		sort.Slice(batch.InvestmentIDs, func(i, j int) bool {
+			return batch.InvestmentIDs[i] < batch.InvestmentIDs[j]
+		})
+	}
+	sort.Slice(batches, func(i, j int) bool {
+		return batches[i].FundID < batches[j].FundID
+	})
+	return batches, nil
+}
+
+// GetBatch implements the Dealing interface
+func (s *defaultDealingService) GetBatch(id uint) (*model.DealingBatch, error) {
+	return s.dealingRepo.GetDealingBatchByID(id)
+}
+
+// ListBatches implements the Dealing interface
+func (s *defaultDealingService) ListBatches(filter model.DealingBatchFilter) ([]*model.DealingBatch, error) {
+	return s.dealingRepo.ListDealingBatches(filter)
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+)
+
+// recordingDealing is a dealing repository mock that records the orders it is asked to place
+type recordingDealing struct {
+	mocks.DealingRepository
+	orders []*model.Investment
+	failed []*model.Investment
+}
+
+// CreateDealingBatches records the orders and returns the batches with IDs assigned in order
+func (r *recordingDealing) CreateDealingBatches(batches []*model.DealingBatch, orders, failed []*model.Investment) ([]*model.DealingBatch, error) {
+	r.orders, r.failed = orders, failed
+	return r.DealingRepository.CreateDealingBatches(batches, orders, failed)
+}
+
+func TestDefaultDealingService_RunDealing(t *testing.T) {
+	friday := model.NewDate(2026, time.October, 16)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, time.October, 16, hour, minute, 0, 0, time.UTC)
+	}
+	gbp := func(minor int64) model.Money { return model.NewMoney(minor, model.DefaultCurrency) }
+	pending := func(id, fundID uint, transactionType model.TransactionType, amount model.Money, units model.Units, switchID uint, createdAt time.Time) *model.Investment {
+		return &model.Investment{
+			ID:        id,
+			ClientID:  1,
+			FundID:    fundID,
+			Type:      transactionType,
+			Status:    model.InvestmentStatusPending,
+			SwitchID:  switchID,
+			Amount:    amount,
+			Units:     units,
+			Price:     1000000,
+			CreatedAt: createdAt,
+		}
+	}
+
+	orders := []*model.Investment{
+		pending(1, 1, model.TransactionTypeInvestment, gbp(10000), 100000000, 0, at(9, 0)),
+		pending(2, 1, model.TransactionTypeWithdrawal, gbp(-1000), -10000000, 0, at(10, 0)),
+		pending(3, 1, model.TransactionTypeInvestment, gbp(5000), 50000000, 0, at(12, 1)),
+		pending(4, 2, model.TransactionTypeInvestment, gbp(3000), 30000000, 0, at(10, 0)),
+		pending(5, 1, model.TransactionTypeSwitchOut, gbp(-500), -5000000, 1, at(11, 0)),
+		pending(6, 3, model.TransactionTypeSwitchIn, gbp(500), 500000, 1, at(11, 0)),
+		pending(7, 1, model.TransactionTypeSwitchOut, gbp(-500), -5000000, 2, at(11, 0)),
+		pending(8, 2, model.TransactionTypeSwitchIn, gbp(500), 500000, 2, at(11, 0)),
+	}
+	prices := map[uint]*model.FundPrice{
+		1: {FundID: 1, Date: friday, NAV: 2000000, Currency: model.DefaultCurrency},
+		2: {FundID: 2, Date: friday.AddDays(-1), NAV: 1000000, Currency: model.DefaultCurrency},
+		3: {FundID: 3, Date: friday, NAV: 4000000, Currency: model.DefaultCurrency},
+	}
+	fund1 := &model.DealingBatch{
+		FundID:        1,
+		DealingDate:   friday,
+		Price:         2000000,
+		Bought:        gbp(10000),
+		Sold:          gbp(3000),
+		UnitsBought:   50000000,
+		UnitsSold:     15000000,
+		InvestmentIDs: []uint{1, 2, 5},
+	}
+	fund3 := &model.DealingBatch{
+		FundID:        3,
+		DealingDate:   friday,
+		Price:         4000000,
+		Bought:        gbp(1000),
+		Sold:          gbp(0),
+		UnitsBought:   2500000,
+		InvestmentIDs: []uint{6},
+	}
+
+	tests := []struct {
+		name          string
+		date          model.Date
+		now           time.Time
+		investments   []*model.Investment
+		investmentErr error
+		dealt         []*model.DealingBatch
+		wantBatches   []*model.DealingBatch
+		wantOrders    map[uint]*model.Investment
+		wantFailed    []uint
+		wantErr       error
+	}{
+		{
+			name:        "Orders received by the cut-off are dealt at the day's price",
+			date:        friday,
+			now:         at(12, 5),
+			investments: orders,
+			wantBatches: []*model.DealingBatch{fund1, fund3},
+			wantOrders: map[uint]*model.Investment{
+				1: {Amount: gbp(10000), Units: 50000000},
+				2: {Amount: gbp(-2000), Units: -10000000},
+				5: {Amount: gbp(-1000), Units: -5000000},
+				6: {Amount: gbp(1000), Units: 2500000},
+			},
+		},
+		{
+			name:        "Funds already dealt on the day are left pending",
+			date:        friday,
+			now:         at(12, 5),
+			investments: orders,
+			dealt:       []*model.DealingBatch{{ID: 1, FundID: 3, DealingDate: friday}},
+			wantBatches: []*model.DealingBatch{{
+				FundID:        1,
+				DealingDate:   friday,
+				Price:         2000000,
+				Bought:        gbp(10000),
+				Sold:          gbp(2000),
+				UnitsBought:   50000000,
+				UnitsSold:     10000000,
+				InvestmentIDs: []uint{1, 2},
+			}},
+			wantOrders: map[uint]*model.Investment{
+				1: {Amount: gbp(10000), Units: 50000000},
+				2: {Amount: gbp(-2000), Units: -10000000},
+			},
+		},
+		{
+			// £100 invested at 1.00 a unit only buys 50 units at 2.00, so selling the 100 it was made for fails
+			name: "Sells of more units than are dealt fail",
+			date: friday,
+			now:  at(12, 5),
+			investments: []*model.Investment{
+				pending(1, 1, model.TransactionTypeInvestment, gbp(10000), 100000000, 0, at(9, 0)),
+				pending(2, 1, model.TransactionTypeWithdrawal, gbp(-10000), -100000000, 0, at(10, 0)),
+			},
+			wantBatches: []*model.DealingBatch{{
+				FundID:        1,
+				DealingDate:   friday,
+				Price:         2000000,
+				Bought:        gbp(10000),
+				Sold:          gbp(0),
+				UnitsBought:   50000000,
+				InvestmentIDs: []uint{1},
+			}},
+			wantOrders: map[uint]*model.Investment{
+				1: {Amount: gbp(10000), Units: 50000000},
+			},
+			wantFailed: []uint{2},
+		},
+		{
+			name: "Sells are covered by units already dealt and fail with the rest of their switch",
+			date: friday,
+			now:  at(12, 5),
+			investments: []*model.Investment{
+				{ID: 1, ClientID: 1, FundID: 1, Status: model.InvestmentStatusSettled, Amount: gbp(1000), Units: 10000000},
+				{ID: 2, ClientID: 1, FundID: 1, Status: model.InvestmentStatusFailed, Amount: gbp(1000), Units: 10000000},
+				pending(3, 1, model.TransactionTypeWithdrawal, gbp(-1000), -10000000, 0, at(9, 0)),
+				pending(4, 1, model.TransactionTypeSwitchOut, gbp(-500), -5000000, 1, at(10, 0)),
+				pending(5, 3, model.TransactionTypeSwitchIn, gbp(500), 500000, 1, at(10, 0)),
+			},
+			wantBatches: []*model.DealingBatch{{
+				FundID:        1,
+				DealingDate:   friday,
+				Price:         2000000,
+				Bought:        gbp(0),
+				Sold:          gbp(2000),
+				UnitsSold:     10000000,
+				InvestmentIDs: []uint{3},
+			}},
+			wantOrders: map[uint]*model.Investment{
+				3: {Amount: gbp(-2000), Units: -10000000},
+			},
+			wantFailed: []uint{4, 5},
+		},
+		{
+			name:        "No orders to deal",
+			date:        friday,
+			now:         at(12, 5),
+			investments: []*model.Investment{orders[2], orders[3]},
+			wantBatches: []*model.DealingBatch{},
+		},
+		{
+			name:        "Weekend",
+			date:        friday.AddDays(1),
+			now:         at(23, 0).AddDate(0, 0, 1),
+			investments: orders,
+			wantErr:     errors.New("not a dealing day: 2026-10-17 is a Saturday"),
+		},
+		{
+			name:        "Before the cut-off",
+			date:        friday,
+			now:         at(11, 59),
+			investments: orders,
+			wantErr:     errors.New("dealing cut-off has not passed: orders for 2026-10-16 are dealt after 2026-10-16T12:00:00Z"),
+		},
+		{
+			name:          "Listing pending orders fails",
+			date:          friday,
+			now:           at(12, 5),
+			investmentErr: errors.New("database error"),
+			wantErr:       errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dealingRepo := &recordingDealing{DealingRepository: mocks.DealingRepository{MockBatches: tt.dealt}}
+			service := NewDefaultDealingService(
+				&mocks.InvestmentRepository{MockInvestments: tt.investments, MockErr: tt.investmentErr},
+				&pricesByFund{prices: prices},
+				dealingRepo,
+				&mocks.Clock{MockNow: tt.now},
+				model.TimeOfDay{Hour: 12},
+			)
+
+			got, err := service.RunDealing(tt.date)
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Errorf("RunDealing() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunDealing() unexpected error = %v", err)
+			}
+
+			if len(got) != len(tt.wantBatches) {
+				t.Fatalf("got %d batches, want %d", len(got), len(tt.wantBatches))
+			}
+			for i, want := range tt.wantBatches {
+				batch := *got[i]
+				batch.ID = 0
+				if !reflect.DeepEqual(&batch, want) {
+					t.Errorf("batch %d = %+v, want %+v", i, batch, *want)
+				}
+			}
+
+			if len(dealingRepo.orders) != len(tt.wantOrders) {
+				t.Fatalf("placed %d orders, want %d", len(dealingRepo.orders), len(tt.wantOrders))
+			}
+			var failed []uint
+			for _, order := range dealingRepo.failed {
+				failed = append(failed, order.ID)
+			}
+			if !reflect.DeepEqual(failed, tt.wantFailed) {
+				t.Errorf("failed orders %v, want %v", failed, tt.wantFailed)
+			}
+			for _, order := range dealingRepo.orders {
+				want, exists := tt.wantOrders[order.ID]
+				if !exists {
+					t.Errorf("order %d was placed, want it left pending", order.ID)
+					continue
+				}
+				if order.Amount != want.Amount || order.Units != want.Units {
+					t.Errorf("order %d dealt as %v for %v units, want %v for %v units", order.ID, order.Amount, order.Units, want.Amount, want.Units)
+				}
+				if price := prices[order.FundID]; order.Price != price.NAV || !order.PriceDate.Equal(friday.Time) {
+					t.Errorf("order %d priced at %v on %v, want %v on %v", order.ID, order.Price, order.PriceDate, price.NAV, friday)
+				}
+			}
+		})
+	}
+}
+
+// recordingInvestments is an investment repository mock that records what dealing asks it for
+type recordingInvestments struct {
+	mocks.InvestmentRepository
+	filter   model.InvestmentFilter
+	holdings []model.HoldingKey
+}
+
+// ListInvestments records the filter and returns the MockInvestments
+func (r *recordingInvestments) ListInvestments(filter model.InvestmentFilter) ([]*model.Investment, error) {
+	r.filter = filter
+	return r.InvestmentRepository.ListInvestments(filter)
+}
+
+// SumDealtUnits records the holdings and totals the MockInvestments dealt in them
+func (r *recordingInvestments) SumDealtUnits(holdings []model.HoldingKey) (map[model.HoldingKey]model.Units, error) {
+	r.holdings = holdings
+	return r.InvestmentRepository.SumDealtUnits(holdings)
+}
+
+func TestDefaultDealingService_RunDealing_OnlyTotalsHoldingsSold(t *testing.T) {
+	friday := model.NewDate(2026, time.October, 16)
+	orderedAt := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+	investments := &recordingInvestments{InvestmentRepository: mocks.InvestmentRepository{MockInvestments: []*model.Investment{
+		{ID: 1, ClientID: 1, FundID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusSettled, Amount: model.NewMoney(10000, model.DefaultCurrency), Units: 100000000},
+		{ID: 2, ClientID: 2, FundID: 2, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusSettled, Amount: model.NewMoney(10000, model.DefaultCurrency), Units: 100000000},
+		{ID: 3, ClientID: 1, FundID: 1, Type: model.TransactionTypeWithdrawal, Status: model.InvestmentStatusPending, Amount: model.NewMoney(-5000, model.DefaultCurrency), Units: -50000000, CreatedAt: orderedAt},
+		{ID: 4, ClientID: 2, FundID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusPending, Amount: model.NewMoney(5000, model.DefaultCurrency), Units: 50000000, CreatedAt: orderedAt},
+	}}}
+	dealingRepo := &recordingDealing{}
+	service := NewDefaultDealingService(
+		investments,
+		&pricesByFund{prices: map[uint]*model.FundPrice{1: {FundID: 1, Date: friday, NAV: 1000000, Currency: model.DefaultCurrency}}},
+		dealingRepo,
+		&mocks.Clock{MockNow: time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)},
+		model.TimeOfDay{Hour: 12},
+	)
+
+	if _, err := service.RunDealing(friday); err != nil {
+		t.Fatalf("RunDealing() unexpected error = %v", err)
+	}
+	if investments.filter.Status == nil || *investments.filter.Status != model.InvestmentStatusPending {
+		t.Errorf("ListInvestments() filter = %+v, want only pending orders", investments.filter)
+	}
+	if want := []model.HoldingKey{{ClientID: 1, FundID: 1}}; !reflect.DeepEqual(investments.holdings, want) {
+		t.Errorf("SumDealtUnits() holdings = %v, want only the holding sold from %v", investments.holdings, want)
+	}
+	if len(dealingRepo.orders) != 2 || len(dealingRepo.failed) != 0 {
+		t.Errorf("placed %d orders and failed %d, want both placed", len(dealingRepo.orders), len(dealingRepo.failed))
+	}
+}
+
+func TestDefaultDealingService_RunDue(t *testing.T) {
+	tests := []struct {
+		name    string
+		now     time.Time
+		wantRun bool
+	}{
+		{name: "Weekday after the cut-off", now: time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC), wantRun: true},
+		{name: "Weekday before the cut-off", now: time.Date(2026, time.October, 16, 11, 59, 0, 0, time.UTC), wantRun: false},
+		{name: "Weekend", now: time.Date(2026, time.October, 17, 15, 0, 0, 0, time.UTC), wantRun: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Listing pending orders fails, so any run shows up as an error
+			runErr := errors.New("dealing ran")
+			service := NewDefaultDealingService(
+				&mocks.InvestmentRepository{MockErr: runErr},
+				&pricesByFund{},
+				&mocks.DealingRepository{},
+				&mocks.Clock{MockNow: tt.now},
+				model.TimeOfDay{Hour: 12},
+			)
+
+			err := service.RunDue(tt.now)
+			if ran := errors.Is(err, runErr); ran != tt.wantRun {
+				t.Errorf("RunDue() ran = %v, want %v (error %v)", ran, tt.wantRun, err)
+			}
+		})
+	}
+}
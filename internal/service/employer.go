@@ -3,24 +3,109 @@ package service
 import (
 	"cushon/internal/model"
 	"cushon/internal/repository"
+	"errors"
+	"fmt"
 )
 
 // Employer defines the interface for employer operations
 type Employer interface {
 	NewEmployer(name string) (*model.Employer, error)
+	GetEmployer(id uint) (*model.Employer, error)
+	ListEmployers(filter model.EmployerFilter) ([]*model.Employer, error)
+	UpdateEmployer(id uint, update model.EmployerUpdate) (*model.Employer, error)
+	DeactivateEmployer(id uint) (*model.Employer, error)
+	ListEmployees(id uint) ([]*model.Customer, error)
 }
 
 // defaultEmployerService is a concrete implementation of Employer
 type defaultEmployerService struct {
-	repo repository.EmployerRepository
+	repo         repository.EmployerRepository
+	customerRepo repository.CustomerRepository
+	fundRepo     repository.FundRepository
 }
 
 // NewDefaultEmployerService creates a new default employer service
-func NewDefaultEmployerService(repo repository.EmployerRepository) *defaultEmployerService {
-	return &defaultEmployerService{repo: repo}
+func NewDefaultEmployerService(repo repository.EmployerRepository, customerRepo repository.CustomerRepository, fundRepo repository.FundRepository) *defaultEmployerService {
+	return &defaultEmployerService{
+		repo:         repo,
+		customerRepo: customerRepo,
+		fundRepo:     fundRepo,
+	}
 }
 
 // Create creates a new employer
 func (s *defaultEmployerService) NewEmployer(name string) (*model.Employer, error) {
 	return s.repo.CreateEmployer(name)
 }
+
+// GetEmployer retrieves an employer by ID
+func (s *defaultEmployerService) GetEmployer(id uint) (*model.Employer, error) {
+	return s.repo.GetEmployerByID(id)
+}
+
+// ListEmployers retrieves the employers matching filter
+func (s *defaultEmployerService) ListEmployers(filter model.EmployerFilter) ([]*model.Employer, error) {
+	return s.repo.ListEmployers(filter)
+}
+
+// UpdateEmployer applies a partial update to an employer. A contribution scheme replaces the employer's current one,
+// and a default fund must be open to new investments.
+func (s *defaultEmployerService) UpdateEmployer(id uint, update model.EmployerUpdate) (*model.Employer, error) {
+	employer, err := s.repo.GetEmployerByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	name := employer.Name
+	if update.Name != nil {
+		name = *update.Name
+	}
+	scheme := employer.ContributionScheme
+	if update.ContributionScheme != nil {
+		if err := validateContributionScheme(update.ContributionScheme); err != nil {
+			return nil, err
+		}
+		scheme = update.ContributionScheme
+	}
+	defaultFundID := employer.DefaultFundID
+	if update.DefaultFundID != nil {
+		if err := checkFundOpen(s.fundRepo, *update.DefaultFundID); err != nil {
+			return nil, err
+		}
+		defaultFundID = update.DefaultFundID
+	}
+
+	return s.repo.UpdateEmployer(id, name, scheme, defaultFundID)
+}
+
+// validateContributionScheme checks that a scheme's rates are shares of salary and its cap can be paid in
+func validateContributionScheme(scheme *model.ContributionScheme) error {
+	for _, rate := range []model.Percent{scheme.EmployeeRate, scheme.EmployerRate} {
+		if rate < 0 || rate > model.OneHundredPercent {
+			return errors.New("contribution rates must be between 0% and 100% of salary")
+		}
+	}
+	if scheme.EmployerCap != nil {
+		if !scheme.EmployerCap.IsPositive() {
+			return errors.New("employer contribution cap must be greater than 0")
+		}
+		if scheme.EmployerCap.Currency != model.DefaultCurrency {
+			return fmt.Errorf("employer contribution cap must be in %s", model.DefaultCurrency)
+		}
+	}
+	return nil
+}
+
+// DeactivateEmployer marks an employer as having left the scheme. Its employees keep their
+// accounts and investments, but no new employees can be enrolled under it.
+func (s *defaultEmployerService) DeactivateEmployer(id uint) (*model.Employer, error) {
+	return s.repo.DeactivateEmployer(id)
+}
+
+// ListEmployees retrieves the customers enrolled under an employer
+func (s *defaultEmployerService) ListEmployees(id uint) ([]*model.Customer, error) {
+	if _, err := s.repo.GetEmployerByID(id); err != nil {
+		return nil, err
+	}
+	return s.customerRepo.ListCustomers(model.CustomerFilter{EmployerID: &id})
+}
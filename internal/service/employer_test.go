@@ -49,7 +49,7 @@ func TestDefaultEmployerService_NewEmployer(t *testing.T) {
 				MockEmployer: tt.mockEmployer,
 			}
 
-			service := NewDefaultEmployerService(mockRepo)
+			service := NewDefaultEmployerService(mockRepo, &mocks.CustomerRepository{}, &mocks.FundRepository{})
 			got, err := service.NewEmployer(tt.employerName)
 
 			if tt.wantErr != nil {
@@ -79,3 +79,161 @@ func TestDefaultEmployerService_NewEmployer(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultEmployerService_UpdateEmployer(t *testing.T) {
+	scheme := &model.ContributionScheme{EmployeeRate: 5000, EmployerRate: 3000}
+	newScheme := &model.ContributionScheme{EmployeeRate: 4000, EmployerRate: 6000, Matching: true}
+
+	tests := []struct {
+		name              string
+		update            model.EmployerUpdate
+		mockErr           error
+		fundStatus        model.FundStatus
+		fundErr           error
+		wantName          string
+		wantScheme        *model.ContributionScheme
+		wantDefaultFundID *uint
+		wantErr           error
+	}{
+		{
+			name:       "Rename employer",
+			update:     model.EmployerUpdate{Name: stringPtr("New Company")},
+			wantName:   "New Company",
+			wantScheme: scheme,
+		},
+		{
+			name:       "Empty update keeps the name and scheme",
+			update:     model.EmployerUpdate{},
+			wantName:   "Test Company",
+			wantScheme: scheme,
+		},
+		{
+			name:       "Replace the contribution scheme",
+			update:     model.EmployerUpdate{ContributionScheme: newScheme},
+			wantName:   "Test Company",
+			wantScheme: newScheme,
+		},
+		{
+			name:    "Contribution rate above 100%",
+			update:  model.EmployerUpdate{ContributionScheme: &model.ContributionScheme{EmployeeRate: model.OneHundredPercent + 1}},
+			wantErr: errors.New("contribution rates must be between 0% and 100% of salary"),
+		},
+		{
+			name: "Employer cap in another currency",
+			update: model.EmployerUpdate{ContributionScheme: &model.ContributionScheme{
+				EmployerRate: 3000, EmployerCap: &model.Money{Minor: 10000, Currency: "USD"},
+			}},
+			wantErr: errors.New("employer contribution cap must be in GBP"),
+		},
+		{
+			name:              "Set the default fund",
+			update:            model.EmployerUpdate{DefaultFundID: uintPtr(2)},
+			fundStatus:        model.FundStatusOpen,
+			wantName:          "Test Company",
+			wantScheme:        scheme,
+			wantDefaultFundID: uintPtr(2),
+		},
+		{
+			name:       "Closed default fund",
+			update:     model.EmployerUpdate{DefaultFundID: uintPtr(2)},
+			fundStatus: model.FundStatusClosed,
+			wantErr:    ErrFundNotOpen,
+		},
+		{
+			name:    "Unknown default fund",
+			update:  model.EmployerUpdate{DefaultFundID: uintPtr(99)},
+			fundErr: ErrFundNotFound,
+			wantErr: ErrFundNotFound,
+		},
+		{
+			name:    "Unknown employer",
+			update:  model.EmployerUpdate{Name: stringPtr("New Company")},
+			mockErr: ErrEmployerNotFound,
+			wantErr: ErrEmployerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.EmployerRepository{
+				MockEmployer: &model.Employer{ID: 1, Name: "Test Company", Active: true, ContributionScheme: scheme},
+				MockErr:      tt.mockErr,
+			}
+
+			mockFundRepo := &mocks.FundRepository{MockFund: &model.Fund{ID: 2, Status: tt.fundStatus}, MockErr: tt.fundErr}
+
+			service := NewDefaultEmployerService(mockRepo, &mocks.CustomerRepository{}, mockFundRepo)
+			got, err := service.UpdateEmployer(1, tt.update)
+
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error()) {
+					t.Errorf("UpdateEmployer() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UpdateEmployer() unexpected error = %v", err)
+			}
+			if got.Name != tt.wantName {
+				t.Errorf("Name = %v, want %v", got.Name, tt.wantName)
+			}
+			if got.ContributionScheme != tt.wantScheme {
+				t.Errorf("ContributionScheme = %+v, want %+v", got.ContributionScheme, tt.wantScheme)
+			}
+			if (got.DefaultFundID == nil) != (tt.wantDefaultFundID == nil) || (got.DefaultFundID != nil && *got.DefaultFundID != *tt.wantDefaultFundID) {
+				t.Errorf("DefaultFundID = %v, want %v", got.DefaultFundID, tt.wantDefaultFundID)
+			}
+		})
+	}
+}
+
+func TestDefaultEmployerService_ListEmployees(t *testing.T) {
+	tests := []struct {
+		name          string
+		employerErr   error
+		mockCustomers []*model.Customer
+		wantCount     int
+		wantErr       error
+	}{
+		{
+			name:          "Employer with employees",
+			mockCustomers: []*model.Customer{{ID: 1, EmployerID: uintPtr(1)}, {ID: 2, EmployerID: uintPtr(1)}},
+			wantCount:     2,
+		},
+		{
+			name:          "Employer without employees",
+			mockCustomers: []*model.Customer{},
+			wantCount:     0,
+		},
+		{
+			name:        "Unknown employer",
+			employerErr: ErrEmployerNotFound,
+			wantErr:     ErrEmployerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.EmployerRepository{MockEmployer: &model.Employer{ID: 1}, MockErr: tt.employerErr}
+			mockCustomerRepo := &mocks.CustomerRepository{MockCustomers: tt.mockCustomers}
+
+			service := NewDefaultEmployerService(mockRepo, mockCustomerRepo, &mocks.FundRepository{})
+			got, err := service.ListEmployees(1)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ListEmployees() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ListEmployees() unexpected error = %v", err)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("ListEmployees() returned %d customers, want %d", len(got), tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"cushon/internal/repository"
+	"errors"
+)
+
+// Errors returned by the services that callers can check with errors.Is
+var (
+	ErrCustomerNotFound        = repository.ErrCustomerNotFound
+	ErrCustomerHasInvestments  = repository.ErrCustomerHasInvestments
+	ErrEmployerNotFound        = repository.ErrEmployerNotFound
+	ErrFundNotFound            = repository.ErrFundNotFound
+	ErrDuplicateISIN           = repository.ErrDuplicateISIN
+	ErrInvestmentNotFound      = repository.ErrInvestmentNotFound
+	ErrInsufficientUnits       = repository.ErrInsufficientUnits
+	ErrSwitchNotFound          = repository.ErrSwitchNotFound
+	ErrInvestmentStatusChanged = repository.ErrInvestmentStatusChanged
+	ErrFundPriceNotFound       = repository.ErrFundPriceNotFound
+	ErrDuplicateFundPrice      = repository.ErrDuplicateFundPrice
+	ErrDealingBatchNotFound    = repository.ErrDealingBatchNotFound
+	ErrDuplicateDealingBatch   = repository.ErrDuplicateDealingBatch
+	ErrISAAllowanceExceeded    = repository.ErrISAAllowanceExceeded
+	ErrAllocationNotFound      = repository.ErrAllocationNotFound
+	ErrGlidePathNotFound       = repository.ErrGlidePathNotFound
+	ErrScheduleNotFound        = repository.ErrScheduleNotFound
+	ErrScheduleRunPaid         = repository.ErrScheduleRunPaid
+)
+
+// Errors for business rules enforced by the services
+var (
+	// ErrEmployerInactive is returned when enrolling a customer under an employer that has been deactivated
+	ErrEmployerInactive = errors.New("employer is not active")
+	// ErrFundNotOpen is returned when investing in a fund that is closed or retired
+	ErrFundNotOpen = errors.New("fund is not open to new investments")
+	// ErrInvalidFundStatus is returned for a fund status that doesn't exist
+	ErrInvalidFundStatus = errors.New("invalid fund status")
+	// ErrInvalidFundTransition is returned when a fund can't move to the requested status, e.g. reopening a retired fund
+	ErrInvalidFundTransition = errors.New("invalid fund status change")
+	// ErrFundNotPriced is returned when investing in a fund that has no price to convert the investment into units at
+	ErrFundNotPriced = errors.New("fund has no price")
+	// ErrInvalidInvestmentStatus is returned for an investment status that doesn't exist
+	ErrInvalidInvestmentStatus = errors.New("invalid investment status")
+	// ErrInvalidInvestmentTransition is returned when a transaction can't move to the requested status, e.g. cancelling a placed order
+	ErrInvalidInvestmentTransition = errors.New("invalid investment status change")
+	// ErrInvalidFundFilter is returned when listing funds with a filter that can never match, e.g. a minimum risk above the maximum
+	ErrInvalidFundFilter = errors.New("invalid fund filter")
+	// ErrNotDealingDay is returned when dealing on a day fund managers don't deal on, i.e. a weekend
+	ErrNotDealingDay = errors.New("not a dealing day")
+	// ErrDealingNotOpen is returned when dealing a day's orders before that day's cut-off has passed
+	ErrDealingNotOpen = errors.New("dealing cut-off has not passed")
+	// ErrInvalidContributionSource is returned for a contribution source that doesn't exist
+	ErrInvalidContributionSource = errors.New("invalid contribution source")
+	// ErrContributionSourceNotAllowed is returned when a retail customer makes a contribution that only comes through an employer's payroll
+	ErrContributionSourceNotAllowed = errors.New("contribution source not allowed for customer")
+	// ErrNoContributionScheme is returned when making salary contributions through an employer that hasn't set up a contribution scheme
+	ErrNoContributionScheme = errors.New("employer has no contribution scheme")
+	// ErrPayrollTotalsMismatch is returned when the contributions in a payroll file don't add up to its header's control totals
+	ErrPayrollTotalsMismatch = errors.New("payroll contributions don't match the header totals")
+	// ErrInvalidAllocation is returned for a target allocation whose funds don't add up to 100%
+	ErrInvalidAllocation = errors.New("invalid target allocation")
+	// ErrNotISACustomer is returned when asking for the ISA allowance of a customer investing through their employer
+	ErrNotISACustomer = errors.New("customer does not have an ISA")
+	// ErrInvalidGlidePath is returned for a glide path without stages or with a stage whose allocation is invalid
+	ErrInvalidGlidePath = errors.New("invalid glide path")
+	// ErrInvalidRetirementPlan is returned for an implausible date of birth or retirement age, or when opting into
+	// a glide path without both of them
+	ErrInvalidRetirementPlan = errors.New("invalid retirement plan")
+	// ErrInvalidSchedule is returned for a contribution schedule with an invalid amount, frequency or dates
+	ErrInvalidSchedule = errors.New("invalid contribution schedule")
+	// ErrInvalidScheduleTransition is returned when pausing a schedule that isn't active or resuming one that isn't paused
+	ErrInvalidScheduleTransition = errors.New("invalid contribution schedule status change")
+	// ErrNoReturnHistory is returned when asking for the returns of a customer who hasn't invested yet
+	ErrNoReturnHistory = errors.New("no investments to calculate returns from")
+)
@@ -3,30 +3,184 @@ package service
 import (
 	"cushon/internal/model"
 	"cushon/internal/repository"
+	"errors"
+	"fmt"
+	"strings"
 )
 
 // Fund defines the interface for fund operations
 type Fund interface {
-	NewFund(name string) (*model.Fund, error)
-	GetAllFunds() ([]*model.Fund, error)
+	NewFund(create model.FundCreate) (*model.Fund, error)
+	GetFund(id uint) (*model.Fund, error)
+	ListFunds(filter model.FundFilter, order model.FundOrder) ([]*model.Fund, error)
+	UpdateFund(id uint, update model.FundUpdate) (*model.Fund, error)
+	CloseFund(id uint) (*model.Fund, error)
+	AddFundPrice(fundID uint, create model.FundPriceCreate) (*model.FundPrice, error)
+	ListFundPrices(fundID uint, filter model.FundPriceFilter) ([]*model.FundPrice, error)
+	GetLatestFundPrice(fundID uint) (*model.FundPrice, error)
 }
 
 // defaultFundService is a concrete implementation of FundService
 type defaultFundService struct {
-	repo repository.FundRepository
+	repo      repository.FundRepository
+	priceRepo repository.FundPriceRepository
 }
 
 // NewDefaultFundService creates a new default fund service
-func NewDefaultFundService(repo repository.FundRepository) *defaultFundService {
-	return &defaultFundService{repo: repo}
+func NewDefaultFundService(repo repository.FundRepository, priceRepo repository.FundPriceRepository) *defaultFundService {
+	return &defaultFundService{repo: repo, priceRepo: priceRepo}
 }
 
-// Create creates a new fund
-func (s *defaultFundService) NewFund(name string) (*model.Fund, error) {
-	return s.repo.CreateFund(name)
+// NewFund validates the fund details and creates a new open fund. The ISIN and currency are
+// normalised to upper case and the currency defaults to GBP.
+func (s *defaultFundService) NewFund(create model.FundCreate) (*model.Fund, error) {
+	create.Name = strings.TrimSpace(create.Name)
+	create.ISIN = strings.ToUpper(strings.TrimSpace(create.ISIN))
+	create.Currency = strings.ToUpper(strings.TrimSpace(create.Currency))
+	if create.Currency == "" {
+		create.Currency = model.DefaultCurrency
+	}
+
+	if create.Name == "" {
+		return nil, errors.New("fund name cannot be empty")
+	}
+	if err := model.ValidateISIN(create.ISIN); err != nil {
+		return nil, err
+	}
+	if !model.IsValidCurrency(create.Currency) {
+		return nil, fmt.Errorf("currency %q must be a 3 letter ISO 4217 code", create.Currency)
+	}
+	if err := validateFundDetails(create.AssetClass, create.RiskRating, create.OngoingCharges); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateFund(create)
+}
+
+// GetFund retrieves a fund by ID
+func (s *defaultFundService) GetFund(id uint) (*model.Fund, error) {
+	return s.repo.GetFundByID(id)
+}
+
+// ListFunds retrieves the funds matching filter in the given order
+func (s *defaultFundService) ListFunds(filter model.FundFilter, order model.FundOrder) ([]*model.Fund, error) {
+	if filter.MinRisk != 0 && filter.MaxRisk != 0 && filter.MinRisk > filter.MaxRisk {
+		return nil, fmt.Errorf("%w: minimum risk rating can't be above the maximum risk rating", ErrInvalidFundFilter)
+	}
+
+	funds, err := s.repo.ListFunds(filter)
+	if err != nil {
+		return nil, err
+	}
+	model.SortFunds(funds, order)
+	return funds, nil
+}
+
+// UpdateFund applies a partial update to a fund. Status changes must follow the fund lifecycle,
+// see model.FundStatus.CanTransitionTo.
+func (s *defaultFundService) UpdateFund(id uint, update model.FundUpdate) (*model.Fund, error) {
+	fund, err := s.repo.GetFundByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Status != nil {
+		status := *update.Status
+		if !status.Valid() {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidFundStatus, status)
+		}
+		if !fund.Status.CanTransitionTo(status) {
+			return nil, fmt.Errorf("%w: from %s to %s", ErrInvalidFundTransition, fund.Status, status)
+		}
+		fund.Status = status
+	}
+
+	if update.Name != nil {
+		fund.Name = strings.TrimSpace(*update.Name)
+	}
+	if update.AssetClass != nil {
+		fund.AssetClass = *update.AssetClass
+	}
+	if update.RiskRating != nil {
+		fund.RiskRating = *update.RiskRating
+	}
+	if update.OngoingCharges != nil {
+		fund.OngoingCharges = *update.OngoingCharges
+	}
+	if update.Description != nil {
+		fund.Description = *update.Description
+	}
+
+	if update.AssetClass != nil || update.RiskRating != nil || update.OngoingCharges != nil {
+		if err := validateFundDetails(fund.AssetClass, fund.RiskRating, fund.OngoingCharges); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.UpdateFund(fund)
+}
+
+// CloseFund closes a fund to new investments. Existing holdings are unaffected.
+func (s *defaultFundService) CloseFund(id uint) (*model.Fund, error) {
+	closed := model.FundStatusClosed
+	return s.UpdateFund(id, model.FundUpdate{Status: &closed})
+}
+
+// AddFundPrice records a fund's NAV per unit for a valuation date, in the fund's currency.
+// Prices can't be recorded for future dates and each date can only be priced once.
+func (s *defaultFundService) AddFundPrice(fundID uint, create model.FundPriceCreate) (*model.FundPrice, error) {
+	if create.Date.IsZero() {
+		return nil, errors.New("price date is required")
+	}
+	if create.Date.After(model.Today().Time) {
+		return nil, fmt.Errorf("can't record a price for %s, which is in the future", create.Date)
+	}
+	if create.NAV <= 0 {
+		return nil, errors.New("price must be greater than 0")
+	}
+
+	fund, err := s.repo.GetFundByID(fundID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.priceRepo.CreateFundPrice(&model.FundPrice{
+		FundID:   fund.ID,
+		Date:     create.Date,
+		NAV:      create.NAV,
+		Currency: fund.Currency,
+	})
+}
+
+// ListFundPrices retrieves a fund's price history ordered by date
+func (s *defaultFundService) ListFundPrices(fundID uint, filter model.FundPriceFilter) ([]*model.FundPrice, error) {
+	if filter.From != nil && filter.To != nil && filter.From.After(filter.To.Time) {
+		return nil, fmt.Errorf("%w: from date can't be after the to date", ErrInvalidFundFilter)
+	}
+	if _, err := s.repo.GetFundByID(fundID); err != nil {
+		return nil, err
+	}
+	return s.priceRepo.ListFundPrices(fundID, filter)
+}
+
+// GetLatestFundPrice retrieves a fund's most recent price
+func (s *defaultFundService) GetLatestFundPrice(fundID uint) (*model.FundPrice, error) {
+	if _, err := s.repo.GetFundByID(fundID); err != nil {
+		return nil, err
+	}
+	return s.priceRepo.GetLatestFundPrice(fundID, model.Today())
 }
 
-// GetAllFunds retrieves all funds
-func (s *defaultFundService) GetAllFunds() ([]*model.Fund, error) {
-	return s.repo.GetAllFunds()
+// validateFundDetails checks the details customers compare funds on
+func validateFundDetails(assetClass model.AssetClass, riskRating int, ongoingCharges model.Percent) error {
+	if !assetClass.Valid() {
+		return fmt.Errorf("invalid asset class %q", assetClass)
+	}
+	if riskRating < model.MinRiskRating || riskRating > model.MaxRiskRating {
+		return fmt.Errorf("risk rating must be between %d and %d", model.MinRiskRating, model.MaxRiskRating)
+	}
+	if ongoingCharges < 0 || ongoingCharges >= model.OneHundredPercent {
+		return errors.New("ongoing charges must be at least 0% and below 100%")
+	}
+	return nil
 }
@@ -9,106 +9,192 @@ import (
 )
 
 func TestDefaultFundService_NewFund(t *testing.T) {
+	// validFund returns a valid fund with fn applied to it
+	validFund := func(fn func(*model.FundCreate)) model.FundCreate {
+		create := model.FundCreate{
+			Name:           "Global Equities",
+			ISIN:           "IE00B4L5Y983",
+			AssetClass:     model.AssetClassEquity,
+			RiskRating:     6,
+			OngoingCharges: 200,
+			Currency:       "GBP",
+			Description:    "Tracks the MSCI World index",
+		}
+		if fn != nil {
+			fn(&create)
+		}
+		return create
+	}
+
 	tests := []struct {
 		name          string
-		fundName      string
-		wantFund      *model.Fund
+		create        model.FundCreate
 		repositoryErr error
-		wantErr       error
+		wantErr       string
 	}{
 		{
-			name:     "Valid fund",
-			fundName: "Test Fund",
-			wantFund: &model.Fund{
-				ID:   1,
-				Name: "Test Fund",
-			},
-			wantErr: nil,
+			name:   "Valid fund",
+			create: validFund(nil),
 		},
 		{
-			name:          "Empty fund name",
-			fundName:      "",
-			wantFund:      nil,
-			repositoryErr: errors.New("fund name cannot be empty"),
-			wantErr:       errors.New("fund name cannot be empty"),
+			name:   "Lower case ISIN and currency are normalised",
+			create: validFund(func(c *model.FundCreate) { c.ISIN = "ie00b4l5y983"; c.Currency = "gbp" }),
 		},
 		{
-			name:          "Repository error",
-			fundName:      "Test Fund",
-			wantFund:      nil,
-			repositoryErr: errors.New("repository error"),
-			wantErr:       errors.New("repository error"),
+			name:   "Currency defaults to GBP",
+			create: validFund(func(c *model.FundCreate) { c.Currency = "" }),
+		},
+		{
+			name:    "Empty fund name",
+			create:  validFund(func(c *model.FundCreate) { c.Name = " " }),
+			wantErr: "fund name cannot be empty",
+		},
+		{
+			name:    "Missing ISIN",
+			create:  validFund(func(c *model.FundCreate) { c.ISIN = "" }),
+			wantErr: `invalid ISIN: "" must be 12 characters long`,
+		},
+		{
+			name:    "ISIN with wrong check digit",
+			create:  validFund(func(c *model.FundCreate) { c.ISIN = "IE00B4L5Y984" }),
+			wantErr: `invalid ISIN: "IE00B4L5Y984" has an incorrect check digit`,
+		},
+		{
+			name:    "Unknown asset class",
+			create:  validFund(func(c *model.FundCreate) { c.AssetClass = "crypto" }),
+			wantErr: `invalid asset class "crypto"`,
+		},
+		{
+			name:    "Risk rating too low",
+			create:  validFund(func(c *model.FundCreate) { c.RiskRating = 0 }),
+			wantErr: "risk rating must be between 1 and 7",
+		},
+		{
+			name:    "Risk rating too high",
+			create:  validFund(func(c *model.FundCreate) { c.RiskRating = 8 }),
+			wantErr: "risk rating must be between 1 and 7",
+		},
+		{
+			name:    "Negative ongoing charges",
+			create:  validFund(func(c *model.FundCreate) { c.OngoingCharges = -1 }),
+			wantErr: "ongoing charges must be at least 0% and below 100%",
+		},
+		{
+			name:    "Invalid currency",
+			create:  validFund(func(c *model.FundCreate) { c.Currency = "POUNDS" }),
+			wantErr: `currency "POUNDS" must be a 3 letter ISO 4217 code`,
+		},
+		{
+			name:          "Duplicate ISIN",
+			create:        validFund(nil),
+			repositoryErr: ErrDuplicateISIN,
+			wantErr:       ErrDuplicateISIN.Error(),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := &mocks.FundRepository{
-				MockErr:  tt.repositoryErr,
-				MockFund: tt.wantFund,
-			}
+			mockRepo := &recordingFundRepository{FundRepository: mocks.FundRepository{MockErr: tt.repositoryErr}}
 
-			service := NewDefaultFundService(mockRepo)
-			got, err := service.NewFund(tt.fundName)
+			service := NewDefaultFundService(mockRepo, &mocks.FundPriceRepository{})
+			got, err := service.NewFund(tt.create)
 
-			if tt.wantErr != nil {
-				if err == nil || err.Error() != tt.wantErr.Error() {
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
 					t.Errorf("NewFund() error = %v, wantErr %v", err, tt.wantErr)
 				}
 				return
 			}
 
 			if err != nil {
-				t.Errorf("NewFund() unexpected error = %v", err)
-				return
+				t.Fatalf("NewFund() unexpected error = %v", err)
 			}
-
-			if got == nil {
-				t.Error("NewFund() returned nil fund")
-				return
-			}
-
-			if got.ID != tt.wantFund.ID {
-				t.Errorf("ID = %v, want %v", got.ID, tt.wantFund.ID)
-			}
-			if got.Name != tt.wantFund.Name {
-				t.Errorf("Name = %v, want %v", got.Name, tt.wantFund.Name)
+			if got.ISIN != "IE00B4L5Y983" || got.Currency != "GBP" {
+				t.Errorf("NewFund() stored ISIN %q and currency %q, want normalised values", got.ISIN, got.Currency)
 			}
 		})
 	}
 }
 
-func TestDefaultFundService_GetAllFunds(t *testing.T) {
+// recordingFundRepository is a fund repository mock that creates the fund it is given
+type recordingFundRepository struct {
+	mocks.FundRepository
+}
+
+// CreateFund returns the fund that would have been stored
+func (r *recordingFundRepository) CreateFund(create model.FundCreate) (*model.Fund, error) {
+	if r.MockErr != nil {
+		return nil, r.MockErr
+	}
+	return &model.Fund{
+		ID:             1,
+		Name:           create.Name,
+		ISIN:           create.ISIN,
+		AssetClass:     create.AssetClass,
+		RiskRating:     create.RiskRating,
+		OngoingCharges: create.OngoingCharges,
+		Currency:       create.Currency,
+		Description:    create.Description,
+		Status:         model.FundStatusOpen,
+	}, nil
+}
+
+func TestDefaultFundService_ListFunds(t *testing.T) {
+	funds := func() []*model.Fund {
+		return []*model.Fund{
+			{ID: 1, Name: "Bonds", RiskRating: 3, OngoingCharges: 150},
+			{ID: 2, Name: "Equities", RiskRating: 6, OngoingCharges: 100},
+			{ID: 3, Name: "Cash", RiskRating: 1, OngoingCharges: 100},
+		}
+	}
+
 	tests := []struct {
 		name          string
-		wantFunds     []*model.Fund
+		filter        model.FundFilter
+		order         model.FundOrder
+		mockFunds     []*model.Fund
 		repositoryErr error
-		wantErr       error
+		wantIDs       []uint
+		wantErr       bool
 	}{
 		{
-			name: "Multiple funds",
-			wantFunds: []*model.Fund{
-				{
-					ID:   1,
-					Name: "Test Fund 1",
-				},
-				{
-					ID:   2,
-					Name: "Test Fund 2",
-				},
-			},
-			wantErr: nil,
+			name:      "Default order",
+			order:     model.FundOrder{Field: model.FundSortByID},
+			mockFunds: funds(),
+			wantIDs:   []uint{1, 2, 3},
+		},
+		{
+			name:      "Lowest risk first",
+			order:     model.FundOrder{Field: model.FundSortByRiskRating},
+			mockFunds: funds(),
+			wantIDs:   []uint{3, 1, 2},
+		},
+		{
+			name:      "Most expensive first, ties by ID",
+			order:     model.FundOrder{Field: model.FundSortByOngoingCharges, Descending: true},
+			mockFunds: funds(),
+			wantIDs:   []uint{1, 2, 3},
+		},
+		{
+			name:      "By name",
+			order:     model.FundOrder{Field: model.FundSortByName},
+			mockFunds: funds(),
+			wantIDs:   []uint{1, 3, 2},
 		},
 		{
 			name:      "Empty funds",
-			wantFunds: []*model.Fund{},
-			wantErr:   nil,
+			mockFunds: []*model.Fund{},
+			wantIDs:   []uint{},
+		},
+		{
+			name:    "Minimum risk above maximum",
+			filter:  model.FundFilter{MinRisk: 5, MaxRisk: 2},
+			wantErr: true,
 		},
 		{
 			name:          "Repository error",
-			wantFunds:     nil,
 			repositoryErr: errors.New("repository error"),
-			wantErr:       errors.New("repository error"),
+			wantErr:       true,
 		},
 	}
 
@@ -116,37 +202,235 @@ func TestDefaultFundService_GetAllFunds(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mocks.FundRepository{
 				MockErr:   tt.repositoryErr,
-				MockFunds: tt.wantFunds,
+				MockFunds: tt.mockFunds,
 			}
 
-			service := NewDefaultFundService(mockRepo)
-			gotFunds, err := service.GetAllFunds()
+			service := NewDefaultFundService(mockRepo, &mocks.FundPriceRepository{})
+			got, err := service.ListFunds(tt.filter, tt.order)
 
-			if tt.wantErr != nil {
-				if err == nil || err.Error() != tt.wantErr.Error() {
-					t.Errorf("GetAllFunds() error = %v, wantErr %v", err, tt.wantErr)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("ListFunds() expected an error")
 				}
 				return
 			}
 
 			if err != nil {
-				t.Errorf("GetAllFunds() unexpected error = %v", err)
-				return
+				t.Fatalf("ListFunds() unexpected error = %v", err)
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("got %d funds, want %d", len(got), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if got[i].ID != id {
+					t.Errorf("fund[%d].ID = %v, want %v", i, got[i].ID, id)
+				}
 			}
+		})
+	}
+}
 
-			if len(gotFunds) != len(tt.wantFunds) {
-				t.Errorf("got %d funds, want %d", len(gotFunds), len(tt.wantFunds))
-				return
+func TestDefaultFundService_UpdateFund(t *testing.T) {
+	open := model.FundStatusOpen
+	closed := model.FundStatusClosed
+	retired := model.FundStatusRetired
+	unknown := model.FundStatus("suspended")
+
+	tests := []struct {
+		name       string
+		current    model.FundStatus
+		update     model.FundUpdate
+		fundErr    error
+		wantName   string
+		wantStatus model.FundStatus
+		wantErr    error
+	}{
+		{
+			name:       "Rename fund",
+			current:    model.FundStatusOpen,
+			update:     model.FundUpdate{Name: stringPtr("Global Equities")},
+			wantName:   "Global Equities",
+			wantStatus: model.FundStatusOpen,
+		},
+		{
+			name:       "Close open fund",
+			current:    model.FundStatusOpen,
+			update:     model.FundUpdate{Status: &closed},
+			wantName:   "Equities Fund",
+			wantStatus: model.FundStatusClosed,
+		},
+		{
+			name:       "Reopen closed fund",
+			current:    model.FundStatusClosed,
+			update:     model.FundUpdate{Status: &open},
+			wantName:   "Equities Fund",
+			wantStatus: model.FundStatusOpen,
+		},
+		{
+			name:       "Retire closed fund",
+			current:    model.FundStatusClosed,
+			update:     model.FundUpdate{Status: &retired},
+			wantName:   "Equities Fund",
+			wantStatus: model.FundStatusRetired,
+		},
+		{
+			name:    "Reopen retired fund",
+			current: model.FundStatusRetired,
+			update:  model.FundUpdate{Status: &open},
+			wantErr: ErrInvalidFundTransition,
+		},
+		{
+			name:    "Unknown status",
+			current: model.FundStatusOpen,
+			update:  model.FundUpdate{Status: &unknown},
+			wantErr: ErrInvalidFundStatus,
+		},
+		{
+			name:    "Unknown fund",
+			update:  model.FundUpdate{Status: &closed},
+			fundErr: ErrFundNotFound,
+			wantErr: ErrFundNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.FundRepository{
+				MockFund: &model.Fund{ID: 1, Name: "Equities Fund", AssetClass: model.AssetClassEquity, RiskRating: 6, Status: tt.current},
+				MockErr:  tt.fundErr,
 			}
 
-			for i, wantFund := range tt.wantFunds {
-				if gotFunds[i].ID != wantFund.ID {
-					t.Errorf("fund[%d].ID = %v, want %v", i, gotFunds[i].ID, wantFund.ID)
+			service := NewDefaultFundService(mockRepo, &mocks.FundPriceRepository{})
+			got, err := service.UpdateFund(1, tt.update)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("UpdateFund() error = %v, wantErr %v", err, tt.wantErr)
 				}
-				if gotFunds[i].Name != wantFund.Name {
-					t.Errorf("fund[%d].Name = %v, want %v", i, gotFunds[i].Name, wantFund.Name)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UpdateFund() unexpected error = %v", err)
+			}
+			if got.Name != tt.wantName || got.Status != tt.wantStatus {
+				t.Errorf("UpdateFund() = %+v, want name %v and status %v", got, tt.wantName, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestDefaultFundService_CloseFund(t *testing.T) {
+	mockRepo := &mocks.FundRepository{
+		MockFund: &model.Fund{ID: 1, Name: "Equities Fund", Status: model.FundStatusOpen},
+	}
+
+	got, err := NewDefaultFundService(mockRepo, &mocks.FundPriceRepository{}).CloseFund(1)
+	if err != nil {
+		t.Fatalf("CloseFund() error = %v", err)
+	}
+	if got.Status != model.FundStatusClosed {
+		t.Errorf("Status = %v, want %v", got.Status, model.FundStatusClosed)
+	}
+
+	mockRepo.MockFund.Status = model.FundStatusRetired
+	if _, err := NewDefaultFundService(mockRepo, &mocks.FundPriceRepository{}).CloseFund(1); !errors.Is(err, ErrInvalidFundTransition) {
+		t.Errorf("CloseFund() on a retired fund error = %v, want ErrInvalidFundTransition", err)
+	}
+}
+
+func TestDefaultFundService_AddFundPrice(t *testing.T) {
+	yesterday := model.Today().AddDays(-1)
+
+	tests := []struct {
+		name     string
+		create   model.FundPriceCreate
+		fundErr  error
+		priceErr error
+		wantErr  error
+	}{
+		{
+			name:   "Valid price",
+			create: model.FundPriceCreate{Date: yesterday, NAV: 1234567},
+		},
+		{
+			name:    "Missing date",
+			create:  model.FundPriceCreate{NAV: 1234567},
+			wantErr: errors.New("price date is required"),
+		},
+		{
+			name:    "Future date",
+			create:  model.FundPriceCreate{Date: model.Today().AddDays(1), NAV: 1234567},
+			wantErr: errors.New("can't record a price for " + model.Today().AddDays(1).String() + ", which is in the future"),
+		},
+		{
+			name:    "Zero price",
+			create:  model.FundPriceCreate{Date: yesterday},
+			wantErr: errors.New("price must be greater than 0"),
+		},
+		{
+			name:    "Fund does not exist",
+			create:  model.FundPriceCreate{Date: yesterday, NAV: 1234567},
+			fundErr: ErrFundNotFound,
+			wantErr: ErrFundNotFound,
+		},
+		{
+			name:     "Date already priced",
+			create:   model.FundPriceCreate{Date: yesterday, NAV: 1234567},
+			priceErr: ErrDuplicateFundPrice,
+			wantErr:  ErrDuplicateFundPrice,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.FundRepository{
+				MockErr:  tt.fundErr,
+				MockFund: &model.Fund{ID: 1, Currency: "USD", Status: model.FundStatusOpen},
+			}
+			mockPriceRepo := &mocks.FundPriceRepository{MockErr: tt.priceErr}
+
+			got, err := NewDefaultFundService(mockRepo, mockPriceRepo).AddFundPrice(1, tt.create)
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Errorf("AddFundPrice() error = %v, wantErr %v", err, tt.wantErr)
 				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("AddFundPrice() unexpected error = %v", err)
+			}
+			if got.FundID != 1 || got.Date != tt.create.Date || got.NAV != tt.create.NAV || got.Currency != "USD" {
+				t.Errorf("AddFundPrice() = %+v, want the price in the fund's currency", got)
 			}
 		})
 	}
 }
+
+func TestDefaultFundService_ListFundPrices(t *testing.T) {
+	from, to := model.NewDate(2026, 10, 1), model.NewDate(2026, 9, 1)
+	mockRepo := &mocks.FundRepository{MockFund: &model.Fund{ID: 1}}
+	mockPriceRepo := &mocks.FundPriceRepository{
+		MockPrices: []*model.FundPrice{{FundID: 1, Date: from, NAV: 1000000}},
+	}
+	service := NewDefaultFundService(mockRepo, mockPriceRepo)
+
+	got, err := service.ListFundPrices(1, model.FundPriceFilter{From: &from})
+	if err != nil {
+		t.Fatalf("ListFundPrices() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("got %d prices, want 1", len(got))
+	}
+
+	if _, err := service.ListFundPrices(1, model.FundPriceFilter{From: &from, To: &to}); !errors.Is(err, ErrInvalidFundFilter) {
+		t.Errorf("ListFundPrices() with from after to error = %v, want ErrInvalidFundFilter", err)
+	}
+
+	mockRepo.MockErr = ErrFundNotFound
+	if _, err := service.ListFundPrices(1, model.FundPriceFilter{}); !errors.Is(err, ErrFundNotFound) {
+		t.Errorf("ListFundPrices() for an unknown fund error = %v, want ErrFundNotFound", err)
+	}
+}
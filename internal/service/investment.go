@@ -4,32 +4,614 @@ import (
 	"cushon/internal/model"
 	"cushon/internal/repository"
 	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
 )
 
 // Investment defines the interface for investment operations
 type Investment interface {
-	NewInvestment(clientID, fundID uint, amount float32) (*model.Investment, error)
+	NewInvestment(create model.InvestmentCreate) (*model.Investment, error)
+	NewSalaryContribution(create model.SalaryContributionCreate) ([]*model.Investment, error)
+	ImportPayroll(employerID uint, file io.Reader) (*model.PayrollImport, error)
+	Contribute(create model.ContributionCreate) ([]*model.Investment, error)
+	NewWithdrawal(create model.WithdrawalCreate) (*model.Investment, error)
+	NewSwitch(create model.SwitchCreate) (*model.Switch, error)
+	GetSwitch(id uint) (*model.Switch, error)
 	GetInvestment(id uint) (*model.Investment, error)
-	GetInvestmentsByClientID(clientID uint) ([]*model.Investment, error)
+	ListInvestments(filter model.InvestmentFilter) ([]*model.Investment, error)
+	TransitionInvestment(id uint, status model.InvestmentStatus) (*model.Investment, error)
+	CancelInvestment(id uint) (*model.Investment, error)
+	GetISAAllowance(customerID uint) (*model.ISAAllowance, error)
 }
 
 // defaultInvestmentService is a concrete implementation of InvestmentService
 type defaultInvestmentService struct {
-	repo repository.InvestmentRepository
+	repo           repository.InvestmentRepository
+	customerRepo   repository.CustomerRepository
+	employerRepo   repository.EmployerRepository
+	allocationRepo repository.AllocationRepository
+	fundRepo       repository.FundRepository
+	fundPriceRepo  repository.FundPriceRepository
 }
 
 // NewDefaultInvestmentService creates a new default investment service
-func NewDefaultInvestmentService(repo repository.InvestmentRepository) *defaultInvestmentService {
-	return &defaultInvestmentService{repo: repo}
+func NewDefaultInvestmentService(repo repository.InvestmentRepository, customerRepo repository.CustomerRepository, employerRepo repository.EmployerRepository, allocationRepo repository.AllocationRepository, fundRepo repository.FundRepository, fundPriceRepo repository.FundPriceRepository) *defaultInvestmentService {
+	return &defaultInvestmentService{
+		repo:           repo,
+		customerRepo:   customerRepo,
+		employerRepo:   employerRepo,
+		allocationRepo: allocationRepo,
+		fundRepo:       fundRepo,
+		fundPriceRepo:  fundPriceRepo,
+	}
 }
 
-// Create creates a new investment from a customer into a fund
-func (s *defaultInvestmentService) NewInvestment(clientID, fundID uint, amount float32) (*model.Investment, error) {
-	if amount <= 0 {
+// NewInvestment creates a new investment from a customer into an open fund. The amount is converted into
+// units at the fund's latest price. Investments are one-off contributions unless another source is given,
+// and only employed customers can contribute through payroll. Retail customers invest through an ISA, so their
+// investment is refused when it would take their subscriptions for the tax year over the annual allowance.
+func (s *defaultInvestmentService) NewInvestment(create model.InvestmentCreate) (*model.Investment, error) {
+	amount := create.Amount
+	if !amount.IsPositive() {
 		return nil, errors.New("investment amount must be greater than 0")
 	}
+	if amount.Currency != model.DefaultCurrency {
+		return nil, fmt.Errorf("investments must be made in %s", model.DefaultCurrency)
+	}
+	source, err := contributionSource(create.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepo.GetCustomerByID(create.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if source.Workplace() && customer.EmployerID == nil {
+		return nil, fmt.Errorf("%w: customer %d has no employer to make %s contributions through", ErrContributionSourceNotAllowed, create.ClientID, source)
+	}
+
+	price, err := s.openFundPrice(create.FundID)
+	if err != nil {
+		return nil, err
+	}
+	investment, err := purchase(create.ClientID, create.FundID, amount, source, price)
+	if err != nil {
+		return nil, err
+	}
+	payRun(investment, create.Run)
+	if customer.EmployerID != nil {
+		return s.repo.CreateInvestment(investment)
+	}
+
+	created, err := s.createISAInvestments(customer.ID, []*model.Investment{investment}, amount)
+	if err != nil {
+		return nil, err
+	}
+	return created[0], nil
+}
+
+// Contribute invests an amount across a customer's target allocation, or in their employer's default fund when
+// they haven't chosen one. Each fund's share is stored as a separate investment, all of them together, and
+// retail customers' contributions count towards their ISA allowance like any other investment.
+func (s *defaultInvestmentService) Contribute(create model.ContributionCreate) ([]*model.Investment, error) {
+	amount := create.Amount
+	if !amount.IsPositive() {
+		return nil, errors.New("contribution amount must be greater than 0")
+	}
+	if amount.Currency != model.DefaultCurrency {
+		return nil, fmt.Errorf("contributions must be made in %s", model.DefaultCurrency)
+	}
+	source, err := contributionSource(create.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepo.GetCustomerByID(create.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if source.Workplace() && customer.EmployerID == nil {
+		return nil, fmt.Errorf("%w: customer %d has no employer to make %s contributions through", ErrContributionSourceNotAllowed, create.ClientID, source)
+	}
+	allocation, err := targetAllocation(s.allocationRepo, s.employerRepo, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := allocation.Split(amount)
+	investments := make([]*model.Investment, 0, len(shares))
+	for i, fund := range allocation.Funds {
+		if shares[i].IsZero() {
+			continue
+		}
+		price, err := s.openFundPrice(fund.FundID)
+		if err != nil {
+			return nil, err
+		}
+		investment, err := purchase(create.ClientID, fund.FundID, shares[i], source, price)
+		if err != nil {
+			return nil, err
+		}
+		payRun(investment, create.Run)
+		investments = append(investments, investment)
+	}
+
+	if customer.EmployerID != nil {
+		return s.repo.CreateInvestments(investments)
+	}
+	return s.createISAInvestments(customer.ID, investments, amount)
+}
+
+// contributionSource checks the source of a contribution, which is a one-off contribution unless given
+func contributionSource(source model.ContributionSource) (model.ContributionSource, error) {
+	if source == "" {
+		return model.ContributionSourceOneOff, nil
+	}
+	if !source.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidContributionSource, source)
+	}
+	return source, nil
+}
+
+// createISAInvestments stores investments into a retail customer's ISA. When they would breach the allowance the
+// error says how much of it is left.
+func (s *defaultInvestmentService) createISAInvestments(customerID uint, investments []*model.Investment, requested model.Money) ([]*model.Investment, error) {
+	created, err := s.repo.CreateISAInvestments(investments, model.ISAAnnualAllowance)
+	if errors.Is(err, ErrISAAllowanceExceeded) {
+		allowance, allowanceErr := s.isaAllowance(customerID)
+		if allowanceErr != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s remaining for the %s tax year, %s requested",
+			err, allowance.Remaining, allowance.TaxYear, requested)
+	}
+	return created, err
+}
+
+// NewSalaryContribution invests a pay period's workplace contributions for an employed customer into an open
+// fund. The contributions are worked out from their salary by their employer's contribution scheme, and the
+// employee's and employer's shares are stored together as separate investments recording where the money came
+// from. Shares that come to nothing, such as the employer's when it doesn't contribute, are left out.
+func (s *defaultInvestmentService) NewSalaryContribution(create model.SalaryContributionCreate) ([]*model.Investment, error) {
+	if !create.Salary.IsPositive() {
+		return nil, errors.New("salary must be greater than 0")
+	}
+	if create.Salary.Currency != model.DefaultCurrency {
+		return nil, fmt.Errorf("salaries must be paid in %s", model.DefaultCurrency)
+	}
+	if create.EmployeeRate != nil && (*create.EmployeeRate < 0 || *create.EmployeeRate > model.OneHundredPercent) {
+		return nil, errors.New("employee rate must be between 0% and 100% of salary")
+	}
+
+	customer, err := s.customerRepo.GetCustomerByID(create.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if customer.EmployerID == nil {
+		return nil, fmt.Errorf("%w: customer %d has no employer to make salary contributions through", ErrContributionSourceNotAllowed, create.ClientID)
+	}
+	scheme, err := s.contributionScheme(*customer.EmployerID)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.repo.CreateInvestment(clientID, fundID, amount)
+	employeeRate := scheme.EmployeeRate
+	if create.EmployeeRate != nil {
+		employeeRate = *create.EmployeeRate
+	}
+	employee, employer := scheme.Contributions(create.Salary, employeeRate)
+
+	price, err := s.openFundPrice(create.FundID)
+	if err != nil {
+		return nil, err
+	}
+	investments := make([]*model.Investment, 0, 2)
+	for _, share := range []struct {
+		amount model.Money
+		source model.ContributionSource
+	}{
+		{amount: employee, source: scheme.EmployeeSource()},
+		{amount: employer, source: model.ContributionSourceEmployer},
+	} {
+		if share.amount.IsZero() {
+			continue
+		}
+		investment, err := purchase(create.ClientID, create.FundID, share.amount, share.source, price)
+		if err != nil {
+			return nil, err
+		}
+		investments = append(investments, investment)
+	}
+	if len(investments) == 0 {
+		return nil, fmt.Errorf("a salary of %s doesn't make any contributions under the scheme", create.Salary)
+	}
+
+	return s.repo.CreateInvestments(investments)
+}
+
+// ImportPayroll invests the workplace contributions listed in an employer's payroll file. Every row is checked
+// before anything is stored: the customer must be employed by the employer, the fund open and priced, and the
+// amount enough to buy units. The contributions must also add up to the header's control totals. When any row
+// fails a *model.PayrollError reports each one, otherwise the investments are all stored in one step.
+func (s *defaultInvestmentService) ImportPayroll(employerID uint, file io.Reader) (*model.PayrollImport, error) {
+	employer, err := s.employerRepo.GetEmployerByID(employerID)
+	if err != nil {
+		return nil, err
+	}
+	if !employer.Active {
+		return nil, fmt.Errorf("%w: employer %d has left the scheme", ErrEmployerInactive, employerID)
+	}
+
+	payroll, err := model.ParsePayroll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	rowErrors := payroll.Errors
+	customers := make(map[uint]*model.Customer)
+	prices := make(map[uint]*model.FundPrice)
+	investments := make([]*model.Investment, 0, len(payroll.Contributions))
+	total := model.NewMoney(0, model.DefaultCurrency)
+	for _, contribution := range payroll.Contributions {
+		investment, err := s.payrollInvestment(employerID, contribution, customers, prices)
+		if err != nil {
+			rowErrors = append(rowErrors, model.PayrollRowError{Line: contribution.Line, Error: err.Error()})
+			continue
+		}
+		if total, err = total.Add(investment.Amount); err != nil {
+			return nil, err
+		}
+		investments = append(investments, investment)
+	}
+	if len(rowErrors) > 0 {
+		sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Line < rowErrors[j].Line })
+		return nil, &model.PayrollError{Rows: rowErrors}
+	}
+
+	if len(investments) != payroll.Count || total != payroll.Total {
+		return nil, fmt.Errorf("%w: the header lists %d contributions totalling %s, the file has %d totalling %s",
+			ErrPayrollTotalsMismatch, payroll.Count, payroll.Total, len(investments), total)
+	}
+	if len(investments) == 0 {
+		return nil, fmt.Errorf("%w: the file has no contributions", model.ErrInvalidPayroll)
+	}
+
+	created, err := s.repo.CreateInvestments(investments)
+	if err != nil {
+		return nil, err
+	}
+	return &model.PayrollImport{EmployerID: employerID, Total: total, Investments: created}, nil
+}
+
+// payrollInvestment checks a payroll contribution and prices the investment it makes. Customers and fund prices
+// already looked up for earlier rows are reused, since a payroll file lists most employees and funds many times.
+func (s *defaultInvestmentService) payrollInvestment(employerID uint, contribution model.PayrollContribution, customers map[uint]*model.Customer, prices map[uint]*model.FundPrice) (*model.Investment, error) {
+	if !contribution.Source.Workplace() {
+		return nil, fmt.Errorf("%w: payroll contributions can't be %s", ErrInvalidContributionSource, contribution.Source)
+	}
+	if !contribution.Amount.IsPositive() {
+		return nil, errors.New("contribution amount must be greater than 0")
+	}
+
+	customer, ok := customers[contribution.ClientID]
+	if !ok {
+		var err error
+		if customer, err = s.customerRepo.GetCustomerByID(contribution.ClientID); err != nil {
+			return nil, fmt.Errorf("customer %d: %w", contribution.ClientID, err)
+		}
+		customers[contribution.ClientID] = customer
+	}
+	if customer.EmployerID == nil || *customer.EmployerID != employerID {
+		return nil, fmt.Errorf("customer %d isn't employed by employer %d", contribution.ClientID, employerID)
+	}
+
+	price, ok := prices[contribution.FundID]
+	if !ok {
+		var err error
+		if price, err = s.openFundPrice(contribution.FundID); err != nil {
+			if errors.Is(err, ErrFundNotFound) {
+				return nil, fmt.Errorf("fund %d: %w", contribution.FundID, err)
+			}
+			return nil, err
+		}
+		prices[contribution.FundID] = price
+	}
+	return purchase(contribution.ClientID, contribution.FundID, contribution.Amount, contribution.Source, price)
+}
+
+// contributionScheme retrieves the contribution scheme of an employer that is still in the scheme
+func (s *defaultInvestmentService) contributionScheme(employerID uint) (*model.ContributionScheme, error) {
+	employer, err := s.employerRepo.GetEmployerByID(employerID)
+	if err != nil {
+		return nil, err
+	}
+	if !employer.Active {
+		return nil, fmt.Errorf("%w: employer %d has left the scheme", ErrEmployerInactive, employerID)
+	}
+	if employer.ContributionScheme == nil {
+		return nil, fmt.Errorf("%w: employer %d", ErrNoContributionScheme, employerID)
+	}
+	return employer.ContributionScheme, nil
+}
+
+// openFundPrice retrieves the latest price of a fund that is open to new investments
+func (s *defaultInvestmentService) openFundPrice(fundID uint) (*model.FundPrice, error) {
+	if err := checkFundOpen(s.fundRepo, fundID); err != nil {
+		return nil, err
+	}
+	return s.latestPrice(fundID, model.DefaultCurrency)
+}
+
+// checkFundOpen checks that a fund exists and is open to new investments
+func checkFundOpen(fundRepo repository.FundRepository, fundID uint) error {
+	fund, err := fundRepo.GetFundByID(fundID)
+	if err != nil {
+		return err
+	}
+	if fund.Status != model.FundStatusOpen {
+		return fmt.Errorf("%w: fund %d is %s", ErrFundNotOpen, fundID, fund.Status)
+	}
+	return nil
+}
+
+// purchase prices investing amount in a fund at the fund's price, returning the investment to store
+func purchase(clientID, fundID uint, amount model.Money, source model.ContributionSource, price *model.FundPrice) (*model.Investment, error) {
+	units := price.NAV.UnitsFor(amount)
+	if units <= 0 {
+		return nil, fmt.Errorf("%s is too small to buy any units at %s %s per unit", amount, price.NAV, price.Currency)
+	}
+	return &model.Investment{
+		ClientID:  clientID,
+		FundID:    fundID,
+		Source:    source,
+		Amount:    amount,
+		Units:     units,
+		Price:     price.NAV,
+		PriceDate: price.Date,
+	}, nil
+}
+
+// payRun records the schedule run an investment pays in, if any
+func payRun(investment *model.Investment, run *model.ScheduleRunKey) {
+	if run == nil {
+		return
+	}
+	runDate := run.RunDate
+	investment.ScheduleID, investment.RunDate = run.ScheduleID, &runDate
+}
+
+// GetISAAllowance reports how much of a retail customer's ISA allowance they have used in the current tax year.
+// Customers investing through their employer don't have an ISA.
+func (s *defaultInvestmentService) GetISAAllowance(customerID uint) (*model.ISAAllowance, error) {
+	customer, err := s.customerRepo.GetCustomerByID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer.EmployerID != nil {
+		return nil, fmt.Errorf("%w: customer %d invests through employer %d", ErrNotISACustomer, customerID, *customer.EmployerID)
+	}
+	return s.isaAllowance(customerID)
+}
+
+// isaAllowance totals a customer's ISA subscriptions in the current tax year
+func (s *defaultInvestmentService) isaAllowance(customerID uint) (*model.ISAAllowance, error) {
+	investments, err := s.repo.GetInvestmentsByClientID(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	taxYear := model.TaxYearOf(time.Now())
+	subscribed := model.NewMoney(0, model.ISAAnnualAllowance.Currency)
+	for _, investment := range investments {
+		if investment.IsISASubscription() && taxYear.Contains(investment.CreatedAt) {
+			if subscribed, err = subscribed.Add(investment.Amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return model.NewISAAllowance(customerID, taxYear, model.ISAAnnualAllowance, subscribed)
+}
+
+// NewWithdrawal sells units from a customer's holding in a fund at the fund's latest price and records the
+// withdrawal with a negative amount and units. Either an amount is withdrawn, selling enough units to raise it,
+// or a number of units is sold for what they are worth. Customers can't withdraw more than they hold.
+// Withdrawals are allowed from closed and retired funds.
+func (s *defaultInvestmentService) NewWithdrawal(create model.WithdrawalCreate) (*model.Investment, error) {
+	if (create.Amount == nil) == (create.Units == nil) {
+		return nil, errors.New("either an amount or a number of units must be withdrawn")
+	}
+	if create.Amount != nil {
+		if !create.Amount.IsPositive() {
+			return nil, errors.New("withdrawal amount must be greater than 0")
+		}
+		if create.Amount.Currency != model.DefaultCurrency {
+			return nil, fmt.Errorf("withdrawals must be made in %s", model.DefaultCurrency)
+		}
+	}
+	if create.Units != nil && *create.Units <= 0 {
+		return nil, errors.New("units withdrawn must be greater than 0")
+	}
+
+	if _, err := s.customerRepo.GetCustomerByID(create.ClientID); err != nil {
+		return nil, err
+	}
+	if _, err := s.fundRepo.GetFundByID(create.FundID); err != nil {
+		return nil, err
+	}
+
+	withdrawal, err := s.sale(create.ClientID, create.FundID, create.Amount, create.Units, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CreateWithdrawal(withdrawal)
+}
+
+// NewSwitch moves money from one of a customer's funds into another open fund. Units of the source fund are
+// sold at its latest price, by amount, number of units or percentage of the holding, and the proceeds are
+// invested in the target fund at its latest price. Both legs are stored atomically by the repository.
+func (s *defaultInvestmentService) NewSwitch(create model.SwitchCreate) (*model.Switch, error) {
+	if create.FromFundID == create.ToFundID {
+		return nil, errors.New("a switch must be between two different funds")
+	}
+	given := 0
+	for _, isSet := range []bool{create.Amount != nil, create.Units != nil, create.Percentage != nil} {
+		if isSet {
+			given++
+		}
+	}
+	if given != 1 {
+		return nil, errors.New("exactly one of an amount, a number of units or a percentage must be switched")
+	}
+	if create.Amount != nil {
+		if !create.Amount.IsPositive() {
+			return nil, errors.New("switch amount must be greater than 0")
+		}
+		if create.Amount.Currency != model.DefaultCurrency {
+			return nil, fmt.Errorf("switches must be made in %s", model.DefaultCurrency)
+		}
+	}
+	if create.Units != nil && *create.Units <= 0 {
+		return nil, errors.New("units switched must be greater than 0")
+	}
+	if create.Percentage != nil && (*create.Percentage <= 0 || *create.Percentage > model.OneHundredPercent) {
+		return nil, errors.New("percentage switched must be greater than 0 and at most 100")
+	}
+
+	if _, err := s.customerRepo.GetCustomerByID(create.ClientID); err != nil {
+		return nil, err
+	}
+	if _, err := s.fundRepo.GetFundByID(create.FromFundID); err != nil {
+		return nil, err
+	}
+	toFund, err := s.fundRepo.GetFundByID(create.ToFundID)
+	if err != nil {
+		return nil, err
+	}
+	if toFund.Status != model.FundStatusOpen {
+		return nil, fmt.Errorf("%w: fund %d is %s", ErrFundNotOpen, create.ToFundID, toFund.Status)
+	}
+	toPrice, err := s.latestPrice(create.ToFundID, model.DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	sell, err := s.sale(create.ClientID, create.FromFundID, create.Amount, create.Units, create.Percentage)
+	if err != nil {
+		return nil, err
+	}
+
+	proceeds := model.NewMoney(-sell.Amount.Minor, sell.Amount.Currency)
+	units := toPrice.NAV.UnitsFor(proceeds)
+	if units <= 0 {
+		return nil, fmt.Errorf("%s is too small to buy any units at %s %s per unit", proceeds, toPrice.NAV, toPrice.Currency)
+	}
+
+	return s.repo.CreateSwitch(&model.Switch{
+		ClientID:   create.ClientID,
+		FromFundID: create.FromFundID,
+		ToFundID:   create.ToFundID,
+		Sell:       sell,
+		Buy: &model.Investment{
+			ClientID:  create.ClientID,
+			FundID:    create.ToFundID,
+			Amount:    proceeds,
+			Units:     units,
+			Price:     toPrice.NAV,
+			PriceDate: toPrice.Date,
+		},
+	})
+}
+
+// GetSwitch implements the Investment interface
+func (s *defaultInvestmentService) GetSwitch(id uint) (*model.Switch, error) {
+	return s.repo.GetSwitchByID(id)
+}
+
+// sale prices selling from a customer's holding in a fund at the fund's latest price, and returns the sale as a
+// transaction with a negative amount and units. Exactly one of amount, units and percentage must be set. An
+// amount sells enough units to raise it, units are sold for what they are worth and a percentage sells that
+// share of the units held. Customers can't sell more than they hold.
+func (s *defaultInvestmentService) sale(clientID, fundID uint, amount *model.Money, units *model.Units, percentage *model.Percent) (*model.Investment, error) {
+	price, err := s.latestPrice(fundID, model.DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := s.unitsHeld(clientID, fundID)
+	if err != nil {
+		return nil, err
+	}
+
+	if percentage != nil {
+		share := held.Share(*percentage)
+		if share <= 0 {
+			return nil, fmt.Errorf("%w: %s units of fund %d held", ErrInsufficientUnits, held, fundID)
+		}
+		units = &share
+	}
+
+	var sold model.Money
+	var unitsSold model.Units
+	if amount != nil {
+		sold = *amount
+		unitsSold = price.NAV.UnitsToSell(sold)
+		if unitsSold > held && sold == price.NAV.ValueOf(held, sold.Currency) {
+			// Selling the whole value of the holding sells every unit, even if rounding asks for a fraction more
+			unitsSold = held
+		}
+	} else {
+		unitsSold = *units
+		sold = price.NAV.ValueOf(unitsSold, model.DefaultCurrency)
+		if !sold.IsPositive() {
+			return nil, fmt.Errorf("%s units are worth less than 0.01 %s", unitsSold, sold.Currency)
+		}
+	}
+	if unitsSold > held {
+		return nil, fmt.Errorf("%w: %s units of fund %d held, %s needed", ErrInsufficientUnits, held, fundID, unitsSold)
+	}
+
+	return &model.Investment{
+		ClientID:  clientID,
+		FundID:    fundID,
+		Amount:    model.NewMoney(-sold.Minor, sold.Currency),
+		Units:     -unitsSold,
+		Price:     price.NAV,
+		PriceDate: price.Date,
+	}, nil
+}
+
+// latestPrice retrieves a fund's latest price, which must be in currency
+func (s *defaultInvestmentService) latestPrice(fundID uint, currency string) (*model.FundPrice, error) {
+	price, err := s.fundPriceRepo.GetLatestFundPrice(fundID, model.Today())
+	if errors.Is(err, repository.ErrFundPriceNotFound) {
+		return nil, fmt.Errorf("%w: fund %d can't be dealt in until it has been priced", ErrFundNotPriced, fundID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if price.Currency != currency {
+		return nil, fmt.Errorf("fund %d is priced in %s, not %s", fundID, price.Currency, currency)
+	}
+	return price, nil
+}
+
+// unitsHeld returns the number of units of a fund a customer holds, leaving out failed and cancelled transactions
+func (s *defaultInvestmentService) unitsHeld(clientID, fundID uint) (model.Units, error) {
+	investments, err := s.repo.GetInvestmentsByClientID(clientID)
+	if err != nil {
+		return 0, err
+	}
+
+	var held model.Units
+	for _, investment := range investments {
+		if investment.FundID == fundID && investment.Status.HoldsUnits() {
+			held += investment.Units
+		}
+	}
+	return held, nil
 }
 
 // GetInvestment implements the Investment interface
@@ -37,7 +619,51 @@ func (s *defaultInvestmentService) GetInvestment(id uint) (*model.Investment, er
 	return s.repo.GetInvestmentByID(id)
 }
 
-// GetInvestmentsByClientID implements the Investment interface
-func (s *defaultInvestmentService) GetInvestmentsByClientID(clientID uint) ([]*model.Investment, error) {
-	return s.repo.GetInvestmentsByClientID(clientID)
+// ListInvestments implements the Investment interface
+func (s *defaultInvestmentService) ListInvestments(filter model.InvestmentFilter) ([]*model.Investment, error) {
+	if filter.Status != nil && !filter.Status.Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidInvestmentStatus, *filter.Status)
+	}
+	return s.repo.ListInvestments(filter)
+}
+
+// TransitionInvestment moves a transaction to a new status, following the lifecycle described by
+// model.InvestmentStatus.CanTransitionTo. Both legs of a switch always move together.
+func (s *defaultInvestmentService) TransitionInvestment(id uint, status model.InvestmentStatus) (*model.Investment, error) {
+	if !status.Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidInvestmentStatus, status)
+	}
+
+	investment, err := s.repo.GetInvestmentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !investment.Status.CanTransitionTo(status) {
+		return nil, fmt.Errorf("%w: from %s to %s", ErrInvalidInvestmentTransition, investment.Status, status)
+	}
+
+	ids := []uint{id}
+	if investment.SwitchID != 0 {
+		fundSwitch, err := s.repo.GetSwitchByID(investment.SwitchID)
+		if err != nil {
+			return nil, err
+		}
+		ids = []uint{fundSwitch.Sell.ID, fundSwitch.Buy.ID}
+	}
+
+	updated, err := s.repo.UpdateInvestmentStatus(ids, investment.Status, status)
+	if err != nil {
+		return nil, err
+	}
+	for _, transaction := range updated {
+		if transaction.ID == id {
+			return transaction, nil
+		}
+	}
+	return nil, ErrInvestmentNotFound
+}
+
+// CancelInvestment cancels a pending transaction
+func (s *defaultInvestmentService) CancelInvestment(id uint) (*model.Investment, error) {
+	return s.TransitionInvestment(id, model.InvestmentStatusCancelled)
 }
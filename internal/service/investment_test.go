@@ -2,6 +2,9 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,45 +13,217 @@ import (
 )
 
 func TestDefaultInvestmentService_NewInvestment(t *testing.T) {
+	taxYear := model.TaxYearOf(time.Now())
+	employerID := uint(1)
+	subscription := func(minor int64, status model.InvestmentStatus, createdAt time.Time) *model.Investment {
+		return &model.Investment{
+			ClientID:  1,
+			Type:      model.TransactionTypeInvestment,
+			Status:    status,
+			ISA:       true,
+			Amount:    model.NewMoney(minor, model.DefaultCurrency),
+			CreatedAt: createdAt,
+		}
+	}
+
 	tests := []struct {
 		name             string
 		clientID         uint
 		fundID           uint
-		amount           float32
+		amount           model.Money
 		wantInvestmentID uint
+		source           model.ContributionSource
+		employerID       *uint
+		subscriptions    []*model.Investment
+		customerErr      error
+		fundErr          error
+		fundStatus       model.FundStatus
+		price            *model.FundPrice
+		priceErr         error
 		repositoryErr    error
+		wantUnits        model.Units
+		wantSource       model.ContributionSource
 		wantErr          error
 	}{
 		{
 			name:             "Valid investment",
 			clientID:         1,
 			fundID:           1,
-			amount:           1000.0,
+			amount:           model.NewMoney(100000, model.DefaultCurrency),
 			wantInvestmentID: 5,
+			wantUnits:        400000000,
 			wantErr:          nil,
 		},
+		{
+			name:             "Units are rounded down",
+			clientID:         1,
+			fundID:           1,
+			amount:           model.NewMoney(100000, model.DefaultCurrency),
+			price:            &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 3000000, Currency: "GBP"},
+			wantInvestmentID: 6,
+			wantUnits:        333333333,
+		},
+		{
+			name:     "Fund has no price",
+			clientID: 1,
+			fundID:   1,
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
+			priceErr: ErrFundPriceNotFound,
+			wantErr:  errors.New("fund has no price: fund 1 can't be dealt in until it has been priced"),
+		},
+		{
+			name:     "Fund priced in another currency",
+			clientID: 1,
+			fundID:   1,
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
+			price:    &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 2500000, Currency: "USD"},
+			wantErr:  errors.New("fund 1 is priced in USD, not GBP"),
+		},
+		{
+			name:     "Amount too small to buy a unit fraction",
+			clientID: 1,
+			fundID:   1,
+			amount:   model.NewMoney(1, model.DefaultCurrency),
+			price:    &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 200000000000, Currency: "GBP"},
+			wantErr:  errors.New("0.01 GBP is too small to buy any units at 200000.000000 GBP per unit"),
+		},
 		{
 			name:     "Zero amount",
 			clientID: 1,
 			fundID:   1,
-			amount:   0,
+			amount:   model.NewMoney(0, model.DefaultCurrency),
 			wantErr:  errors.New("investment amount must be greater than 0"),
 		},
 		{
 			name:     "Negative amount",
 			clientID: 1,
 			fundID:   1,
-			amount:   -100.0,
+			amount:   model.NewMoney(-10000, model.DefaultCurrency),
 			wantErr:  errors.New("investment amount must be greater than 0"),
 		},
+		{
+			name:     "Unsupported currency",
+			clientID: 1,
+			fundID:   1,
+			amount:   model.NewMoney(100000, "USD"),
+			wantErr:  errors.New("investments must be made in GBP"),
+		},
+		{
+			name:        "Customer does not exist",
+			clientID:    101,
+			fundID:      1,
+			amount:      model.NewMoney(100000, model.DefaultCurrency),
+			customerErr: ErrCustomerNotFound,
+			wantErr:     ErrCustomerNotFound,
+		},
+		{
+			name:     "Fund does not exist",
+			clientID: 1,
+			fundID:   101,
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
+			fundErr:  ErrFundNotFound,
+			wantErr:  ErrFundNotFound,
+		},
+		{
+			name:       "Closed fund",
+			clientID:   1,
+			fundID:     2,
+			amount:     model.NewMoney(100000, model.DefaultCurrency),
+			fundStatus: model.FundStatusClosed,
+			wantErr:    errors.New("fund is not open to new investments: fund 2 is closed"),
+		},
+		{
+			name:       "Retired fund",
+			clientID:   1,
+			fundID:     3,
+			amount:     model.NewMoney(100000, model.DefaultCurrency),
+			fundStatus: model.FundStatusRetired,
+			wantErr:    errors.New("fund is not open to new investments: fund 3 is retired"),
+		},
 		{
 			name:          "Repository error",
 			clientID:      1,
 			fundID:        1,
-			amount:        1000.0,
+			amount:        model.NewMoney(100000, model.DefaultCurrency),
 			repositoryErr: errors.New("repository error"),
 			wantErr:       errors.New("repository error"),
 		},
+		{
+			name:     "Retail customer using the rest of their ISA allowance",
+			clientID: 1,
+			fundID:   1,
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
+			subscriptions: []*model.Investment{
+				subscription(1900000, model.InvestmentStatusSettled, time.Now()),
+			},
+			wantInvestmentID: 7,
+			wantUnits:        400000000,
+		},
+		{
+			name:     "Retail customer over their ISA allowance",
+			clientID: 1,
+			fundID:   1,
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
+			subscriptions: []*model.Investment{
+				subscription(1950000, model.InvestmentStatusPending, time.Now()),
+				// Neither last tax year's subscriptions nor cancelled investments count
+				subscription(2000000, model.InvestmentStatusSettled, taxYear.Start().Add(-time.Hour)),
+				subscription(500000, model.InvestmentStatusCancelled, time.Now()),
+			},
+			wantErr: fmt.Errorf("ISA annual allowance exceeded: 500.00 GBP remaining for the %s tax year, 1000.00 GBP requested", taxYear),
+		},
+		{
+			name:     "Retail customer isn't limited by what they paid into their pension while employed",
+			clientID: 1,
+			fundID:   1,
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
+			subscriptions: func() []*model.Investment {
+				pension := subscription(2000000, model.InvestmentStatusSettled, time.Now())
+				pension.ISA = false
+				return []*model.Investment{pension}
+			}(),
+			wantInvestmentID: 7,
+			wantUnits:        400000000,
+		},
+		{
+			name:       "Employed customer isn't limited by the ISA allowance",
+			clientID:   1,
+			fundID:     1,
+			amount:     model.NewMoney(100000, model.DefaultCurrency),
+			employerID: &employerID,
+			subscriptions: []*model.Investment{
+				subscription(2000000, model.InvestmentStatusSettled, time.Now()),
+			},
+			wantInvestmentID: 8,
+			wantUnits:        400000000,
+		},
+		{
+			name:             "Employer contribution for an employed customer",
+			clientID:         1,
+			fundID:           1,
+			amount:           model.NewMoney(100000, model.DefaultCurrency),
+			source:           model.ContributionSourceEmployer,
+			employerID:       &employerID,
+			wantInvestmentID: 9,
+			wantUnits:        400000000,
+			wantSource:       model.ContributionSourceEmployer,
+		},
+		{
+			name:     "Salary sacrifice for a retail customer",
+			clientID: 1,
+			fundID:   1,
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
+			source:   model.ContributionSourceSalarySacrifice,
+			wantErr:  errors.New("contribution source not allowed for customer: customer 1 has no employer to make salary_sacrifice contributions through"),
+		},
+		{
+			name:     "Unknown contribution source",
+			clientID: 1,
+			fundID:   1,
+			amount:   model.NewMoney(100000, model.DefaultCurrency),
+			source:   "bonus",
+			wantErr:  errors.New(`invalid contribution source: "bonus"`),
+		},
 	}
 
 	for _, tt := range tests {
@@ -56,9 +231,10 @@ func TestDefaultInvestmentService_NewInvestment(t *testing.T) {
 			var mockRepo *mocks.InvestmentRepository
 			now := time.Now()
 
-			if tt.wantErr == nil || tt.repositoryErr != nil {
+			if tt.wantErr == nil || tt.repositoryErr != nil || tt.subscriptions != nil {
 				mockRepo = &mocks.InvestmentRepository{
-					MockErr: tt.repositoryErr,
+					MockErr:         tt.repositoryErr,
+					MockInvestments: tt.subscriptions,
 					MockInvestment: &model.Investment{
 						ID:        tt.wantInvestmentID,
 						ClientID:  tt.clientID,
@@ -70,8 +246,27 @@ func TestDefaultInvestmentService_NewInvestment(t *testing.T) {
 				}
 			}
 
-			service := NewDefaultInvestmentService(mockRepo)
-			gotInvestment, err := service.NewInvestment(tt.clientID, tt.fundID, tt.amount)
+			mockCustomerRepo := &mocks.CustomerRepository{
+				MockErr:      tt.customerErr,
+				MockCustomer: &model.Customer{ID: tt.clientID, EmployerID: tt.employerID},
+			}
+			fundStatus := tt.fundStatus
+			if fundStatus == "" {
+				fundStatus = model.FundStatusOpen
+			}
+			mockFundRepo := &mocks.FundRepository{
+				MockErr:  tt.fundErr,
+				MockFund: &model.Fund{ID: tt.fundID, Status: fundStatus},
+			}
+
+			price := tt.price
+			if price == nil {
+				price = &model.FundPrice{FundID: tt.fundID, Date: model.NewDate(2026, time.October, 16), NAV: 2500000, Currency: "GBP"}
+			}
+			mockPriceRepo := &mocks.FundPriceRepository{MockErr: tt.priceErr, MockPrice: price}
+
+			service := NewDefaultInvestmentService(mockRepo, mockCustomerRepo, &mocks.EmployerRepository{}, &mocks.AllocationRepository{}, mockFundRepo, mockPriceRepo)
+			gotInvestment, err := service.NewInvestment(model.InvestmentCreate{ClientID: tt.clientID, FundID: tt.fundID, Amount: tt.amount, Source: tt.source})
 
 			if tt.wantErr != nil {
 				if err == nil || err.Error() != tt.wantErr.Error() {
@@ -99,6 +294,503 @@ func TestDefaultInvestmentService_NewInvestment(t *testing.T) {
 			if gotInvestment.Amount != tt.amount {
 				t.Errorf("got Amount %v, want %v", gotInvestment.Amount, tt.amount)
 			}
+			if gotInvestment.ID != tt.wantInvestmentID {
+				t.Errorf("got ID %v, want %v", gotInvestment.ID, tt.wantInvestmentID)
+			}
+			if gotInvestment.Units != tt.wantUnits {
+				t.Errorf("got Units %v, want %v", gotInvestment.Units, tt.wantUnits)
+			}
+			if gotInvestment.Price != price.NAV || gotInvestment.PriceDate != price.Date {
+				t.Errorf("got price %v on %v, want %v on %v", gotInvestment.Price, gotInvestment.PriceDate, price.NAV, price.Date)
+			}
+			wantSource := tt.wantSource
+			if wantSource == "" {
+				wantSource = model.ContributionSourceOneOff
+			}
+			if gotInvestment.Source != wantSource {
+				t.Errorf("got Source %v, want %v", gotInvestment.Source, wantSource)
+			}
+			if wantISA := tt.employerID == nil; gotInvestment.ISA != wantISA {
+				t.Errorf("got ISA %v, want %v", gotInvestment.ISA, wantISA)
+			}
+		})
+	}
+}
+
+func TestDefaultInvestmentService_NewSalaryContribution(t *testing.T) {
+	employerID := uint(7)
+	percent := func(p model.Percent) *model.Percent { return &p }
+	gbp := func(minor int64) model.Money { return model.NewMoney(minor, model.DefaultCurrency) }
+	scheme := &model.ContributionScheme{EmployeeRate: 5000, EmployerRate: 3000}
+
+	type wantInvestment struct {
+		source model.ContributionSource
+		amount model.Money
+		units  model.Units
+	}
+
+	tests := []struct {
+		name         string
+		retail       bool
+		employer     *model.Employer
+		salary       model.Money
+		employeeRate *model.Percent
+		fundStatus   model.FundStatus
+		want         []wantInvestment
+		wantErr      error
+	}{
+		{
+			name:     "Employee and employer contributions",
+			employer: &model.Employer{ID: employerID, Active: true, ContributionScheme: scheme},
+			salary:   gbp(300000),
+			// 5% and 3% of 3000.00 at 2.50 per unit
+			want: []wantInvestment{
+				{source: model.ContributionSourceEmployee, amount: gbp(15000), units: 60000000},
+				{source: model.ContributionSourceEmployer, amount: gbp(9000), units: 36000000},
+			},
+		},
+		{
+			name: "Matched salary sacrifice up to the employer's cap",
+			employer: &model.Employer{ID: employerID, Active: true, ContributionScheme: &model.ContributionScheme{
+				EmployeeRate: 4000, EmployerRate: 6000, Matching: true, EmployerCap: &model.Money{Minor: 10000, Currency: model.DefaultCurrency}, SalarySacrifice: true,
+			}},
+			salary:       gbp(500000),
+			employeeRate: percent(5000),
+			// The employer matches 5% of 5000.00, capped at 100.00
+			want: []wantInvestment{
+				{source: model.ContributionSourceSalarySacrifice, amount: gbp(25000), units: 100000000},
+				{source: model.ContributionSourceEmployer, amount: gbp(10000), units: 40000000},
+			},
+		},
+		{
+			name:         "Employee opted out of contributing",
+			employer:     &model.Employer{ID: employerID, Active: true, ContributionScheme: &model.ContributionScheme{EmployeeRate: 5000, EmployerRate: 3000, Matching: true}},
+			salary:       gbp(300000),
+			employeeRate: percent(0),
+			wantErr:      errors.New("a salary of 3000.00 GBP doesn't make any contributions under the scheme"),
+		},
+		{
+			name:    "Retail customer",
+			retail:  true,
+			salary:  gbp(300000),
+			wantErr: ErrContributionSourceNotAllowed,
+		},
+		{
+			name:     "Employer without a scheme",
+			employer: &model.Employer{ID: employerID, Active: true},
+			salary:   gbp(300000),
+			wantErr:  ErrNoContributionScheme,
+		},
+		{
+			name:     "Employer that has left the scheme",
+			employer: &model.Employer{ID: employerID, ContributionScheme: scheme},
+			salary:   gbp(300000),
+			wantErr:  ErrEmployerInactive,
+		},
+		{
+			name:       "Closed fund",
+			employer:   &model.Employer{ID: employerID, Active: true, ContributionScheme: scheme},
+			salary:     gbp(300000),
+			fundStatus: model.FundStatusClosed,
+			wantErr:    ErrFundNotOpen,
+		},
+		{
+			name:    "Salary in another currency",
+			salary:  model.NewMoney(300000, "USD"),
+			wantErr: errors.New("salaries must be paid in GBP"),
+		},
+		{
+			name:         "Employee rate above 100%",
+			salary:       gbp(300000),
+			employeeRate: percent(model.OneHundredPercent + 1),
+			wantErr:      errors.New("employee rate must be between 0% and 100% of salary"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customer := &model.Customer{ID: 1, EmployerID: &employerID}
+			if tt.retail {
+				customer.EmployerID = nil
+			}
+			fundStatus := tt.fundStatus
+			if fundStatus == "" {
+				fundStatus = model.FundStatusOpen
+			}
+
+			service := NewDefaultInvestmentService(
+				&mocks.InvestmentRepository{MockInvestment: &model.Investment{ID: 10}},
+				&mocks.CustomerRepository{MockCustomer: customer},
+				&mocks.EmployerRepository{MockEmployer: tt.employer},
+				&mocks.AllocationRepository{},
+				&mocks.FundRepository{MockFund: &model.Fund{ID: 1, Status: fundStatus}},
+				&mocks.FundPriceRepository{MockPrice: &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 2500000, Currency: "GBP"}},
+			)
+
+			got, err := service.NewSalaryContribution(model.SalaryContributionCreate{ClientID: 1, FundID: 1, Salary: tt.salary, EmployeeRate: tt.employeeRate})
+
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error()) {
+					t.Errorf("NewSalaryContribution() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewSalaryContribution() unexpected error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("NewSalaryContribution() created %d investments, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got[i].Source != want.source || got[i].Amount != want.amount || got[i].Units != want.units || got[i].ClientID != 1 || got[i].FundID != 1 {
+					t.Errorf("investment %d = %+v, want %v of %v buying %v units", i, got[i], want.source, want.amount, want.units)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultInvestmentService_Contribute(t *testing.T) {
+	employerID, defaultFundID := uint(7), uint(2)
+	gbp := func(minor int64) model.Money { return model.NewMoney(minor, model.DefaultCurrency) }
+	chosen := &model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 1, Percentage: 60000}, {FundID: 3, Percentage: 40000}}}
+	subscribed := []*model.Investment{
+		{ClientID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusSettled, ISA: true, Amount: gbp(1995000), CreatedAt: time.Now()},
+	}
+
+	type wantInvestment struct {
+		fundID uint
+		amount model.Money
+		units  model.Units
+	}
+
+	tests := []struct {
+		name        string
+		employed    bool
+		employer    *model.Employer
+		allocation  *model.TargetAllocation
+		investments []*model.Investment
+		create      model.ContributionCreate
+		fundStatus  model.FundStatus
+		want        []wantInvestment
+		wantSource  model.ContributionSource
+		wantErr     error
+	}{
+		{
+			name:       "Split across the chosen allocation",
+			allocation: chosen,
+			create:     model.ContributionCreate{ClientID: 1, Amount: gbp(10000)},
+			// 60.00 and 40.00 at 2.50 per unit
+			want:       []wantInvestment{{fundID: 1, amount: gbp(6000), units: 24000000}, {fundID: 3, amount: gbp(4000), units: 16000000}},
+			wantSource: model.ContributionSourceOneOff,
+		},
+		{
+			name:       "Employer default fund",
+			employed:   true,
+			employer:   &model.Employer{ID: employerID, Active: true, DefaultFundID: &defaultFundID},
+			create:     model.ContributionCreate{ClientID: 1, Amount: gbp(10000), Source: model.ContributionSourceEmployer},
+			want:       []wantInvestment{{fundID: 2, amount: gbp(10000), units: 40000000}},
+			wantSource: model.ContributionSourceEmployer,
+		},
+		{
+			name:       "Chosen allocation overrides the employer default",
+			employed:   true,
+			employer:   &model.Employer{ID: employerID, Active: true, DefaultFundID: &defaultFundID},
+			allocation: chosen,
+			create:     model.ContributionCreate{ClientID: 1, Amount: gbp(10000)},
+			want:       []wantInvestment{{fundID: 1, amount: gbp(6000), units: 24000000}, {fundID: 3, amount: gbp(4000), units: 16000000}},
+			wantSource: model.ContributionSourceOneOff,
+		},
+		{
+			name:       "Funds whose share rounds to nothing are skipped",
+			allocation: &model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 1, Percentage: 99000}, {FundID: 3, Percentage: 1000}}},
+			create:     model.ContributionCreate{ClientID: 1, Amount: gbp(50)},
+			want:       []wantInvestment{{fundID: 1, amount: gbp(50), units: 200000}},
+			wantSource: model.ContributionSourceOneOff,
+		},
+		{
+			name:    "Retail customer without an allocation",
+			create:  model.ContributionCreate{ClientID: 1, Amount: gbp(10000)},
+			wantErr: ErrAllocationNotFound,
+		},
+		{
+			name:     "Employer without a default fund",
+			employed: true,
+			employer: &model.Employer{ID: employerID, Active: true},
+			create:   model.ContributionCreate{ClientID: 1, Amount: gbp(10000)},
+			wantErr:  ErrAllocationNotFound,
+		},
+		{
+			name:        "Over the ISA allowance",
+			allocation:  chosen,
+			investments: subscribed,
+			create:      model.ContributionCreate{ClientID: 1, Amount: gbp(10000)},
+			wantErr:     ErrISAAllowanceExceeded,
+		},
+		{
+			name:       "Workplace contribution from a retail customer",
+			allocation: chosen,
+			create:     model.ContributionCreate{ClientID: 1, Amount: gbp(10000), Source: model.ContributionSourceEmployee},
+			wantErr:    ErrContributionSourceNotAllowed,
+		},
+		{
+			name:       "Invalid source",
+			allocation: chosen,
+			create:     model.ContributionCreate{ClientID: 1, Amount: gbp(10000), Source: "bonus"},
+			wantErr:    ErrInvalidContributionSource,
+		},
+		{
+			name:       "Allocated fund closed",
+			allocation: chosen,
+			create:     model.ContributionCreate{ClientID: 1, Amount: gbp(10000)},
+			fundStatus: model.FundStatusClosed,
+			wantErr:    ErrFundNotOpen,
+		},
+		{
+			name:    "Amount in another currency",
+			create:  model.ContributionCreate{ClientID: 1, Amount: model.NewMoney(10000, "USD")},
+			wantErr: errors.New("contributions must be made in GBP"),
+		},
+		{
+			name:    "Zero amount",
+			create:  model.ContributionCreate{ClientID: 1, Amount: gbp(0)},
+			wantErr: errors.New("contribution amount must be greater than 0"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customer := &model.Customer{ID: 1}
+			if tt.employed {
+				customer.EmployerID = &employerID
+			}
+			allocationRepo := &mocks.AllocationRepository{MockAllocation: tt.allocation}
+			if tt.allocation == nil {
+				allocationRepo.MockErr = ErrAllocationNotFound
+			}
+			fundStatus := tt.fundStatus
+			if fundStatus == "" {
+				fundStatus = model.FundStatusOpen
+			}
+
+			service := NewDefaultInvestmentService(
+				&mocks.InvestmentRepository{MockInvestment: &model.Investment{ID: 10}, MockInvestments: tt.investments},
+				&mocks.CustomerRepository{MockCustomer: customer},
+				&mocks.EmployerRepository{MockEmployer: tt.employer},
+				allocationRepo,
+				&mocks.FundRepository{MockFund: &model.Fund{ID: 1, Status: fundStatus}},
+				&mocks.FundPriceRepository{MockPrice: &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 2500000, Currency: "GBP"}},
+			)
+
+			got, err := service.Contribute(tt.create)
+
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error()) {
+					t.Errorf("Contribute() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Contribute() unexpected error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Contribute() created %d investments, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got[i].FundID != want.fundID || got[i].Amount != want.amount || got[i].Units != want.units || got[i].Source != tt.wantSource || got[i].ClientID != 1 {
+					t.Errorf("investment %d = %+v, want %v of %v in fund %d buying %v units", i, got[i], tt.wantSource, want.amount, want.fundID, want.units)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultInvestmentService_ImportPayroll(t *testing.T) {
+	employerID, otherEmployerID := uint(7), uint(8)
+	header := "contributions,total\n%s\nclient_id,fund_id,source,amount\n"
+
+	tests := []struct {
+		name       string
+		employer   *model.Employer
+		customer   *model.Customer
+		fundStatus model.FundStatus
+		file       string
+		wantTotal  model.Money
+		wantCount  int
+		wantLines  []int
+		wantErr    error
+	}{
+		{
+			name:      "Valid payroll",
+			file:      fmt.Sprintf(header, "3,450.00") + "1,1,employee,125.00\n1,1,employer,75.00\n1,1,salary_sacrifice,250.00\n",
+			wantTotal: model.NewMoney(45000, model.DefaultCurrency),
+			wantCount: 3,
+		},
+		{
+			name:    "Total doesn't match the header",
+			file:    fmt.Sprintf(header, "2,200.01") + "1,1,employee,125.00\n1,1,employer,75.00\n",
+			wantErr: ErrPayrollTotalsMismatch,
+		},
+		{
+			name:    "Contributions missing from the file",
+			file:    fmt.Sprintf(header, "3,200.00") + "1,1,employee,125.00\n1,1,employer,75.00\n",
+			wantErr: ErrPayrollTotalsMismatch,
+		},
+		{
+			name:      "Every invalid row is reported",
+			file:      fmt.Sprintf(header, "4,400.00") + "1,1,employee,100.00\n1,1,one_off,100.00\nx,1,employee,100.00\n1,1,employer,0.00\n",
+			wantLines: []int{5, 6, 7},
+		},
+		{
+			name:      "Customer employed by another employer",
+			customer:  &model.Customer{ID: 1, EmployerID: &otherEmployerID},
+			file:      fmt.Sprintf(header, "1,100.00") + "1,1,employee,100.00\n",
+			wantLines: []int{4},
+		},
+		{
+			name:       "Closed fund",
+			fundStatus: model.FundStatusClosed,
+			file:       fmt.Sprintf(header, "1,100.00") + "1,1,employee,100.00\n",
+			wantLines:  []int{4},
+		},
+		{
+			name:     "Employer that has left the scheme",
+			employer: &model.Employer{ID: employerID},
+			file:     fmt.Sprintf(header, "1,100.00") + "1,1,employee,100.00\n",
+			wantErr:  ErrEmployerInactive,
+		},
+		{
+			name:    "No contributions",
+			file:    fmt.Sprintf(header, "0,0.00"),
+			wantErr: model.ErrInvalidPayroll,
+		},
+		{
+			name:    "Missing header",
+			file:    "client_id,fund_id,source,amount\n1,1,employee,100.00\n",
+			wantErr: model.ErrInvalidPayroll,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employer := tt.employer
+			if employer == nil {
+				employer = &model.Employer{ID: employerID, Active: true}
+			}
+			customer := tt.customer
+			if customer == nil {
+				customer = &model.Customer{ID: 1, EmployerID: &employerID}
+			}
+			fundStatus := tt.fundStatus
+			if fundStatus == "" {
+				fundStatus = model.FundStatusOpen
+			}
+
+			service := NewDefaultInvestmentService(
+				&mocks.InvestmentRepository{MockInvestment: &model.Investment{ID: 10}},
+				&mocks.CustomerRepository{MockCustomer: customer},
+				&mocks.EmployerRepository{MockEmployer: employer},
+				&mocks.AllocationRepository{},
+				&mocks.FundRepository{MockFund: &model.Fund{ID: 1, Status: fundStatus}},
+				&mocks.FundPriceRepository{MockPrice: &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 2500000, Currency: "GBP"}},
+			)
+
+			got, err := service.ImportPayroll(employerID, strings.NewReader(tt.file))
+
+			if tt.wantLines != nil {
+				var payrollErr *model.PayrollError
+				if !errors.As(err, &payrollErr) {
+					t.Fatalf("ImportPayroll() error = %v, want a PayrollError", err)
+				}
+				lines := make([]int, len(payrollErr.Rows))
+				for i, row := range payrollErr.Rows {
+					lines[i] = row.Line
+				}
+				if !reflect.DeepEqual(lines, tt.wantLines) {
+					t.Errorf("ImportPayroll() rejected lines %v, want %v: %+v", lines, tt.wantLines, payrollErr.Rows)
+				}
+				return
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ImportPayroll() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ImportPayroll() unexpected error = %v", err)
+			}
+			if got.EmployerID != employerID || got.Total != tt.wantTotal || len(got.Investments) != tt.wantCount {
+				t.Errorf("ImportPayroll() = %+v, want %d investments totalling %v", got, tt.wantCount, tt.wantTotal)
+			}
+			for _, investment := range got.Investments {
+				if !investment.Source.Workplace() || investment.Units != investment.Price.UnitsFor(investment.Amount) {
+					t.Errorf("investment = %+v, want a workplace contribution bought at the fund's price", investment)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultInvestmentService_GetISAAllowance(t *testing.T) {
+	taxYear := model.TaxYearOf(time.Now())
+	employerID := uint(1)
+	investments := []*model.Investment{
+		{ClientID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusSettled, ISA: true, Amount: model.NewMoney(500000, model.DefaultCurrency), CreatedAt: time.Now()},
+		{ClientID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusPending, ISA: true, Amount: model.NewMoney(250050, model.DefaultCurrency), CreatedAt: time.Now()},
+		{ClientID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusFailed, ISA: true, Amount: model.NewMoney(100000, model.DefaultCurrency), CreatedAt: time.Now()},
+		{ClientID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusSettled, ISA: true, Amount: model.NewMoney(900000, model.DefaultCurrency), CreatedAt: taxYear.Start().Add(-time.Minute)},
+		// Paid into a pension earlier in the tax year, while the customer was employed
+		{ClientID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusSettled, Amount: model.NewMoney(400000, model.DefaultCurrency), CreatedAt: time.Now()},
+		{ClientID: 1, Type: model.TransactionTypeWithdrawal, Status: model.InvestmentStatusSettled, Amount: model.NewMoney(-300000, model.DefaultCurrency), CreatedAt: time.Now()},
+		{ClientID: 1, Type: model.TransactionTypeSwitchIn, SwitchID: 1, Status: model.InvestmentStatusPending, Amount: model.NewMoney(200000, model.DefaultCurrency), CreatedAt: time.Now()},
+	}
+
+	tests := []struct {
+		name        string
+		employerID  *uint
+		customerErr error
+		want        *model.ISAAllowance
+		wantErr     error
+	}{
+		{
+			name: "Retail customer who was employed",
+			want: &model.ISAAllowance{
+				CustomerID: 1,
+				TaxYear:    taxYear,
+				Allowance:  model.ISAAnnualAllowance,
+				Subscribed: model.NewMoney(750050, model.DefaultCurrency),
+				Remaining:  model.NewMoney(1249950, model.DefaultCurrency),
+			},
+		},
+		{name: "Employed customer", employerID: &employerID, wantErr: ErrNotISACustomer},
+		{name: "Customer not found", customerErr: ErrCustomerNotFound, wantErr: ErrCustomerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewDefaultInvestmentService(
+				&mocks.InvestmentRepository{MockInvestments: investments},
+				&mocks.CustomerRepository{MockCustomer: &model.Customer{ID: 1, EmployerID: tt.employerID}, MockErr: tt.customerErr},
+				&mocks.EmployerRepository{},
+				&mocks.AllocationRepository{},
+				&mocks.FundRepository{},
+				&mocks.FundPriceRepository{},
+			)
+
+			got, err := service.GetISAAllowance(1)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetISAAllowance() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetISAAllowance() unexpected error = %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("GetISAAllowance() = %+v, want %+v", got, tt.want)
+			}
 		})
 	}
 }
@@ -117,7 +809,7 @@ func TestDefaultInvestmentService_GetInvestment(t *testing.T) {
 				ID:       5,
 				ClientID: 1,
 				FundID:   1,
-				Amount:   1000.0,
+				Amount:   model.NewMoney(100000, model.DefaultCurrency),
 			},
 			repositoryErr: nil,
 		},
@@ -136,7 +828,7 @@ func TestDefaultInvestmentService_GetInvestment(t *testing.T) {
 				MockInvestment: tt.wantInvestment,
 			}
 
-			service := NewDefaultInvestmentService(mockRepo)
+			service := NewDefaultInvestmentService(mockRepo, &mocks.CustomerRepository{}, &mocks.EmployerRepository{}, &mocks.AllocationRepository{}, &mocks.FundRepository{}, &mocks.FundPriceRepository{})
 			gotInvestment, gotErr := service.GetInvestment(tt.ID)
 
 			if tt.repositoryErr != nil && gotErr.Error() != tt.repositoryErr.Error() {
@@ -152,7 +844,7 @@ func TestDefaultInvestmentService_GetInvestment(t *testing.T) {
 	}
 }
 
-func TestDefaultInvestmentService_GetInvestmentsByClientID(t *testing.T) {
+func TestDefaultInvestmentService_ListInvestments(t *testing.T) {
 	tests := []struct {
 		name            string
 		clientID        uint
@@ -167,7 +859,7 @@ func TestDefaultInvestmentService_GetInvestmentsByClientID(t *testing.T) {
 					ID:       1,
 					ClientID: 1,
 					FundID:   1,
-					Amount:   1000.0,
+					Amount:   model.NewMoney(100000, model.DefaultCurrency),
 				},
 			},
 			repositoryErr: nil,
@@ -180,13 +872,13 @@ func TestDefaultInvestmentService_GetInvestmentsByClientID(t *testing.T) {
 					ID:       1,
 					ClientID: 1,
 					FundID:   1,
-					Amount:   1000.0,
+					Amount:   model.NewMoney(100000, model.DefaultCurrency),
 				},
 				{
 					ID:       2,
 					ClientID: 1,
 					FundID:   2,
-					Amount:   2000.0,
+					Amount:   model.NewMoney(200000, model.DefaultCurrency),
 				},
 			},
 			repositoryErr: nil,
@@ -212,8 +904,8 @@ func TestDefaultInvestmentService_GetInvestmentsByClientID(t *testing.T) {
 				MockInvestments: tt.wantInvestments,
 			}
 
-			service := NewDefaultInvestmentService(mockRepo)
-			gotInvestments, gotErr := service.GetInvestmentsByClientID(tt.clientID)
+			service := NewDefaultInvestmentService(mockRepo, &mocks.CustomerRepository{}, &mocks.EmployerRepository{}, &mocks.AllocationRepository{}, &mocks.FundRepository{}, &mocks.FundPriceRepository{})
+			gotInvestments, gotErr := service.ListInvestments(model.InvestmentFilter{ClientID: &tt.clientID})
 
 			if tt.repositoryErr != nil && gotErr.Error() != tt.repositoryErr.Error() {
 				t.Errorf("got error %v, want %v", gotErr, tt.repositoryErr)
@@ -243,3 +935,502 @@ func TestDefaultInvestmentService_GetInvestmentsByClientID(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultInvestmentService_NewWithdrawal(t *testing.T) {
+	gbp := func(minor int64) *model.Money {
+		amount := model.NewMoney(minor, model.DefaultCurrency)
+		return &amount
+	}
+	units := func(u model.Units) *model.Units { return &u }
+
+	// The customer holds 400 units of fund 1, worth 1100.00 at the price of 2.75
+	holding := []*model.Investment{
+		{ID: 1, ClientID: 1, FundID: 1, Amount: *gbp(100000), Units: 400000000, Price: 2500000},
+		{ID: 2, ClientID: 1, FundID: 2, Amount: *gbp(50000), Units: 500000000, Price: 1000000},
+	}
+
+	tests := []struct {
+		name          string
+		create        model.WithdrawalCreate
+		customerErr   error
+		fundErr       error
+		priceErr      error
+		repositoryErr error
+		wantAmount    model.Money
+		wantUnits     model.Units
+		wantErr       string
+	}{
+		{
+			name:       "Withdraw an amount",
+			create:     model.WithdrawalCreate{ClientID: 1, FundID: 1, Amount: gbp(27500)},
+			wantAmount: model.NewMoney(-27500, "GBP"),
+			wantUnits:  -100000000,
+		},
+		{
+			name:       "Units sold for an amount are rounded up",
+			create:     model.WithdrawalCreate{ClientID: 1, FundID: 1, Amount: gbp(10000)},
+			wantAmount: model.NewMoney(-10000, "GBP"),
+			wantUnits:  -36363637,
+		},
+		{
+			name:       "Withdraw units",
+			create:     model.WithdrawalCreate{ClientID: 1, FundID: 1, Units: units(150000000)},
+			wantAmount: model.NewMoney(-41250, "GBP"),
+			wantUnits:  -150000000,
+		},
+		{
+			name:       "Withdraw the whole holding by amount",
+			create:     model.WithdrawalCreate{ClientID: 1, FundID: 1, Amount: gbp(110000)},
+			wantAmount: model.NewMoney(-110000, "GBP"),
+			wantUnits:  -400000000,
+		},
+		{
+			name:       "Withdraw the whole holding by units",
+			create:     model.WithdrawalCreate{ClientID: 1, FundID: 1, Units: units(400000000)},
+			wantAmount: model.NewMoney(-110000, "GBP"),
+			wantUnits:  -400000000,
+		},
+		{
+			name:    "Amount above the holding's value",
+			create:  model.WithdrawalCreate{ClientID: 1, FundID: 1, Amount: gbp(110001)},
+			wantErr: "not enough units held: 400.000000 units of fund 1 held, 400.003637 needed",
+		},
+		{
+			name:    "More units than held",
+			create:  model.WithdrawalCreate{ClientID: 1, FundID: 1, Units: units(400000001)},
+			wantErr: "not enough units held: 400.000000 units of fund 1 held, 400.000001 needed",
+		},
+		{
+			name:    "Fund not held",
+			create:  model.WithdrawalCreate{ClientID: 1, FundID: 3, Units: units(1000000)},
+			wantErr: "not enough units held: 0.000000 units of fund 3 held, 1.000000 needed",
+		},
+		{
+			name:    "Both amount and units",
+			create:  model.WithdrawalCreate{ClientID: 1, FundID: 1, Amount: gbp(100), Units: units(1000000)},
+			wantErr: "either an amount or a number of units must be withdrawn",
+		},
+		{
+			name:    "Neither amount nor units",
+			create:  model.WithdrawalCreate{ClientID: 1, FundID: 1},
+			wantErr: "either an amount or a number of units must be withdrawn",
+		},
+		{
+			name:    "Zero amount",
+			create:  model.WithdrawalCreate{ClientID: 1, FundID: 1, Amount: gbp(0)},
+			wantErr: "withdrawal amount must be greater than 0",
+		},
+		{
+			name:    "Negative units",
+			create:  model.WithdrawalCreate{ClientID: 1, FundID: 1, Units: units(-1)},
+			wantErr: "units withdrawn must be greater than 0",
+		},
+		{
+			name:    "Units worth less than a penny",
+			create:  model.WithdrawalCreate{ClientID: 1, FundID: 1, Units: units(1)},
+			wantErr: "0.000001 units are worth less than 0.01 GBP",
+		},
+		{
+			name:        "Customer does not exist",
+			create:      model.WithdrawalCreate{ClientID: 101, FundID: 1, Amount: gbp(100)},
+			customerErr: ErrCustomerNotFound,
+			wantErr:     ErrCustomerNotFound.Error(),
+		},
+		{
+			name:    "Fund does not exist",
+			create:  model.WithdrawalCreate{ClientID: 1, FundID: 101, Amount: gbp(100)},
+			fundErr: ErrFundNotFound,
+			wantErr: ErrFundNotFound.Error(),
+		},
+		{
+			name:     "Fund has no price",
+			create:   model.WithdrawalCreate{ClientID: 1, FundID: 1, Amount: gbp(100)},
+			priceErr: ErrFundPriceNotFound,
+			wantErr:  "fund has no price: fund 1 can't be dealt in until it has been priced",
+		},
+		{
+			name:          "Holding overdrawn by a concurrent withdrawal",
+			create:        model.WithdrawalCreate{ClientID: 1, FundID: 1, Amount: gbp(100)},
+			repositoryErr: ErrInsufficientUnits,
+			wantErr:       ErrInsufficientUnits.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &failingSales{
+				InvestmentRepository: mocks.InvestmentRepository{MockInvestments: holding},
+				err:                  tt.repositoryErr,
+			}
+			service := NewDefaultInvestmentService(
+				mockRepo,
+				&mocks.CustomerRepository{MockErr: tt.customerErr, MockCustomer: &model.Customer{ID: 1}},
+				&mocks.EmployerRepository{},
+				&mocks.AllocationRepository{},
+				&mocks.FundRepository{MockErr: tt.fundErr, MockFund: &model.Fund{ID: 1, Status: model.FundStatusClosed}},
+				&mocks.FundPriceRepository{
+					MockErr:   tt.priceErr,
+					MockPrice: &model.FundPrice{FundID: 1, Date: model.NewDate(2026, time.October, 16), NAV: 2750000, Currency: "GBP"},
+				},
+			)
+
+			got, err := service.NewWithdrawal(tt.create)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("NewWithdrawal() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewWithdrawal() unexpected error = %v", err)
+			}
+
+			if got.Type != model.TransactionTypeWithdrawal {
+				t.Errorf("Type = %v, want %v", got.Type, model.TransactionTypeWithdrawal)
+			}
+			if got.Amount != tt.wantAmount || got.Units != tt.wantUnits {
+				t.Errorf("NewWithdrawal() = %v for %v units, want %v for %v units", got.Amount, got.Units, tt.wantAmount, tt.wantUnits)
+			}
+			if got.Price != 2750000 || got.PriceDate != model.NewDate(2026, time.October, 16) {
+				t.Errorf("NewWithdrawal() priced at %v on %v, want the latest price", got.Price, got.PriceDate)
+			}
+		})
+	}
+}
+
+// failingSales is an investment repository mock whose withdrawals and switches fail with err when it is set
+type failingSales struct {
+	mocks.InvestmentRepository
+	err error
+}
+
+// CreateWithdrawal fails with err when it is set
+func (r *failingSales) CreateWithdrawal(withdrawal *model.Investment) (*model.Investment, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.InvestmentRepository.CreateWithdrawal(withdrawal)
+}
+
+// CreateSwitch fails with err when it is set
+func (r *failingSales) CreateSwitch(fundSwitch *model.Switch) (*model.Switch, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.InvestmentRepository.CreateSwitch(fundSwitch)
+}
+
+// fundsByID is a fund repository mock holding funds by their ID
+type fundsByID struct {
+	mocks.FundRepository
+	funds map[uint]*model.Fund
+}
+
+// GetFundByID returns the fund, or ErrFundNotFound if it doesn't exist
+func (r *fundsByID) GetFundByID(id uint) (*model.Fund, error) {
+	fund, exists := r.funds[id]
+	if !exists {
+		return nil, ErrFundNotFound
+	}
+	return fund, nil
+}
+
+func TestDefaultInvestmentService_NewSwitch(t *testing.T) {
+	gbp := func(minor int64) *model.Money {
+		amount := model.NewMoney(minor, model.DefaultCurrency)
+		return &amount
+	}
+	units := func(u model.Units) *model.Units { return &u }
+	percent := func(p model.Percent) *model.Percent { return &p }
+
+	// The customer holds 400 units of retired fund 1, worth 1100.00 at the price of 2.75, and nothing else
+	holding := []*model.Investment{
+		{ID: 1, ClientID: 1, FundID: 1, Amount: *gbp(100000), Units: 400000000, Price: 2500000},
+	}
+	funds := map[uint]*model.Fund{
+		1: {ID: 1, Status: model.FundStatusRetired},
+		2: {ID: 2, Status: model.FundStatusOpen},
+		3: {ID: 3, Status: model.FundStatusOpen},
+		4: {ID: 4, Status: model.FundStatusOpen},
+		5: {ID: 5, Status: model.FundStatusClosed},
+	}
+	priceDate := model.NewDate(2026, time.October, 16)
+	prices := map[uint]*model.FundPrice{
+		1: {FundID: 1, Date: priceDate, NAV: 2750000, Currency: "GBP"},
+		2: {FundID: 2, Date: priceDate, NAV: 1250000, Currency: "GBP"},
+		3: {FundID: 3, Date: priceDate, NAV: 1000000, Currency: "GBP"},
+	}
+
+	tests := []struct {
+		name          string
+		create        model.SwitchCreate
+		customerErr   error
+		repositoryErr error
+		wantSold      model.Money
+		wantSell      model.Units
+		wantBuy       model.Units
+		wantErr       string
+	}{
+		{
+			name:     "Switch an amount",
+			create:   model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 2, Amount: gbp(27500)},
+			wantSold: model.NewMoney(27500, "GBP"),
+			wantSell: 100000000,
+			wantBuy:  220000000,
+		},
+		{
+			name:     "Switch units",
+			create:   model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 2, Units: units(150000000)},
+			wantSold: model.NewMoney(41250, "GBP"),
+			wantSell: 150000000,
+			wantBuy:  330000000,
+		},
+		{
+			name:     "Switch a percentage",
+			create:   model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 2, Percentage: percent(50000)},
+			wantSold: model.NewMoney(55000, "GBP"),
+			wantSell: 200000000,
+			wantBuy:  440000000,
+		},
+		{
+			name:     "Switch the whole holding",
+			create:   model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 2, Percentage: percent(model.OneHundredPercent)},
+			wantSold: model.NewMoney(110000, "GBP"),
+			wantSell: 400000000,
+			wantBuy:  880000000,
+		},
+		{
+			name:    "Same fund",
+			create:  model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 1, Amount: gbp(100)},
+			wantErr: "a switch must be between two different funds",
+		},
+		{
+			name:    "Nothing to switch",
+			create:  model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 2},
+			wantErr: "exactly one of an amount, a number of units or a percentage must be switched",
+		},
+		{
+			name:    "Amount and percentage",
+			create:  model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 2, Amount: gbp(100), Percentage: percent(1000)},
+			wantErr: "exactly one of an amount, a number of units or a percentage must be switched",
+		},
+		{
+			name:    "Percentage above 100",
+			create:  model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 2, Percentage: percent(100001)},
+			wantErr: "percentage switched must be greater than 0 and at most 100",
+		},
+		{
+			name:    "Amount above the holding's value",
+			create:  model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 2, Amount: gbp(110001)},
+			wantErr: "not enough units held: 400.000000 units of fund 1 held, 400.003637 needed",
+		},
+		{
+			name:    "Percentage of a fund not held",
+			create:  model.SwitchCreate{ClientID: 1, FromFundID: 2, ToFundID: 3, Percentage: percent(50000)},
+			wantErr: "not enough units held: 0.000000 units of fund 2 held",
+		},
+		{
+			name:    "Target fund retired",
+			create:  model.SwitchCreate{ClientID: 1, FromFundID: 2, ToFundID: 1, Percentage: percent(50000)},
+			wantErr: "fund is not open to new investments: fund 1 is retired",
+		},
+		{
+			name:    "Target fund closed",
+			create:  model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 5, Amount: gbp(100)},
+			wantErr: "fund is not open to new investments: fund 5 is closed",
+		},
+		{
+			name:    "Target fund has no price",
+			create:  model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 4, Amount: gbp(100)},
+			wantErr: "fund has no price: fund 4 can't be dealt in until it has been priced",
+		},
+		{
+			name:    "Target fund does not exist",
+			create:  model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 101, Amount: gbp(100)},
+			wantErr: ErrFundNotFound.Error(),
+		},
+		{
+			name:        "Customer does not exist",
+			create:      model.SwitchCreate{ClientID: 101, FromFundID: 1, ToFundID: 2, Amount: gbp(100)},
+			customerErr: ErrCustomerNotFound,
+			wantErr:     ErrCustomerNotFound.Error(),
+		},
+		{
+			name:          "Holding overdrawn by a concurrent sale",
+			create:        model.SwitchCreate{ClientID: 1, FromFundID: 1, ToFundID: 2, Amount: gbp(100)},
+			repositoryErr: ErrInsufficientUnits,
+			wantErr:       ErrInsufficientUnits.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewDefaultInvestmentService(
+				&failingSales{InvestmentRepository: mocks.InvestmentRepository{MockInvestments: holding}, err: tt.repositoryErr},
+				&mocks.CustomerRepository{MockErr: tt.customerErr, MockCustomer: &model.Customer{ID: 1}},
+				&mocks.EmployerRepository{},
+				&mocks.AllocationRepository{},
+				&fundsByID{funds: funds},
+				&pricesByFund{prices: prices},
+			)
+
+			got, err := service.NewSwitch(tt.create)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("NewSwitch() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewSwitch() unexpected error = %v", err)
+			}
+
+			if got.Sell.FundID != tt.create.FromFundID || got.Sell.Amount != model.NewMoney(-tt.wantSold.Minor, "GBP") || got.Sell.Units != -tt.wantSell {
+				t.Errorf("Sell = %v for %v units of fund %d, want -%v for -%v units", got.Sell.Amount, got.Sell.Units, got.Sell.FundID, tt.wantSold, tt.wantSell)
+			}
+			if got.Buy.FundID != tt.create.ToFundID || got.Buy.Amount != tt.wantSold || got.Buy.Units != tt.wantBuy {
+				t.Errorf("Buy = %v for %v units of fund %d, want %v for %v units", got.Buy.Amount, got.Buy.Units, got.Buy.FundID, tt.wantSold, tt.wantBuy)
+			}
+			if got.Sell.Price != 2750000 || got.Buy.Price != 1250000 || got.Buy.PriceDate != priceDate {
+				t.Errorf("NewSwitch() priced at %v and %v, want each fund's latest price", got.Sell.Price, got.Buy.Price)
+			}
+		})
+	}
+}
+
+// recordingStatusUpdates is an investment repository mock that records the IDs passed to UpdateInvestmentStatus,
+// and fails the update with err when it is set
+type recordingStatusUpdates struct {
+	mocks.InvestmentRepository
+	updatedIDs []uint
+	err        error
+}
+
+// UpdateInvestmentStatus records ids before updating them
+func (r *recordingStatusUpdates) UpdateInvestmentStatus(ids []uint, from, to model.InvestmentStatus) ([]*model.Investment, error) {
+	r.updatedIDs = ids
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.InvestmentRepository.UpdateInvestmentStatus(ids, from, to)
+}
+
+func TestDefaultInvestmentService_TransitionInvestment(t *testing.T) {
+	tests := []struct {
+		name          string
+		investment    *model.Investment
+		status        model.InvestmentStatus
+		repositoryErr error
+		wantIDs       []uint
+		wantErr       string
+	}{
+		{
+			name:       "Place a pending investment",
+			investment: &model.Investment{ID: 1, Status: model.InvestmentStatusPending},
+			status:     model.InvestmentStatusPlaced,
+			wantIDs:    []uint{1},
+		},
+		{
+			name:       "Settle a placed investment",
+			investment: &model.Investment{ID: 1, Status: model.InvestmentStatusPlaced},
+			status:     model.InvestmentStatusSettled,
+			wantIDs:    []uint{1},
+		},
+		{
+			name:       "Fail a placed investment",
+			investment: &model.Investment{ID: 1, Status: model.InvestmentStatusPlaced},
+			status:     model.InvestmentStatusFailed,
+			wantIDs:    []uint{1},
+		},
+		{
+			name:       "Both legs of a switch move together",
+			investment: &model.Investment{ID: 3, SwitchID: 1, Status: model.InvestmentStatusPending},
+			status:     model.InvestmentStatusPlaced,
+			wantIDs:    []uint{2, 3},
+		},
+		{
+			name:       "Settle a pending investment",
+			investment: &model.Investment{ID: 1, Status: model.InvestmentStatusPending},
+			status:     model.InvestmentStatusSettled,
+			wantErr:    "invalid investment status change: from pending to settled",
+		},
+		{
+			name:       "Cancel a placed investment",
+			investment: &model.Investment{ID: 1, Status: model.InvestmentStatusPlaced},
+			status:     model.InvestmentStatusCancelled,
+			wantErr:    "invalid investment status change: from placed to cancelled",
+		},
+		{
+			name:       "Unknown status",
+			investment: &model.Investment{ID: 1, Status: model.InvestmentStatusPending},
+			status:     "dealt",
+			wantErr:    `invalid investment status: "dealt"`,
+		},
+		{
+			name:          "Status changed concurrently",
+			investment:    &model.Investment{ID: 1, Status: model.InvestmentStatusPending},
+			status:        model.InvestmentStatusPlaced,
+			repositoryErr: ErrInvestmentStatusChanged,
+			wantErr:       ErrInvestmentStatusChanged.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &recordingStatusUpdates{
+				InvestmentRepository: mocks.InvestmentRepository{
+					MockInvestment: tt.investment,
+					MockInvestments: []*model.Investment{
+						tt.investment,
+						{ID: 2, SwitchID: 1, Status: tt.investment.Status},
+					},
+					MockSwitch: &model.Switch{ID: 1, Sell: &model.Investment{ID: 2}, Buy: &model.Investment{ID: 3}},
+				},
+				err: tt.repositoryErr,
+			}
+			service := NewDefaultInvestmentService(mockRepo, &mocks.CustomerRepository{}, &mocks.EmployerRepository{}, &mocks.AllocationRepository{}, &mocks.FundRepository{}, &mocks.FundPriceRepository{})
+
+			got, err := service.TransitionInvestment(tt.investment.ID, tt.status)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("TransitionInvestment() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TransitionInvestment() unexpected error = %v", err)
+			}
+			if got.ID != tt.investment.ID || got.Status != tt.status {
+				t.Errorf("TransitionInvestment() = %+v, want investment %d %s", got, tt.investment.ID, tt.status)
+			}
+			if !reflect.DeepEqual(mockRepo.updatedIDs, tt.wantIDs) {
+				t.Errorf("updated IDs = %v, want %v", mockRepo.updatedIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestDefaultInvestmentService_CancelInvestment(t *testing.T) {
+	mockRepo := &mocks.InvestmentRepository{
+		MockInvestment:  &model.Investment{ID: 1, Status: model.InvestmentStatusPending},
+		MockInvestments: []*model.Investment{{ID: 1, Status: model.InvestmentStatusPending}},
+	}
+	service := NewDefaultInvestmentService(mockRepo, &mocks.CustomerRepository{}, &mocks.EmployerRepository{}, &mocks.AllocationRepository{}, &mocks.FundRepository{}, &mocks.FundPriceRepository{})
+
+	got, err := service.CancelInvestment(1)
+	if err != nil || got.Status != model.InvestmentStatusCancelled {
+		t.Errorf("CancelInvestment() = %+v, %v, want a cancelled investment", got, err)
+	}
+}
+
+func TestDefaultInvestmentService_ListInvestments_InvalidStatus(t *testing.T) {
+	service := NewDefaultInvestmentService(&mocks.InvestmentRepository{}, &mocks.CustomerRepository{}, &mocks.EmployerRepository{}, &mocks.AllocationRepository{}, &mocks.FundRepository{}, &mocks.FundPriceRepository{})
+
+	status := model.InvestmentStatus("dealt")
+	if _, err := service.ListInvestments(model.InvestmentFilter{Status: &status}); !errors.Is(err, ErrInvalidInvestmentStatus) {
+		t.Errorf("ListInvestments() error = %v, want ErrInvalidInvestmentStatus", err)
+	}
+}
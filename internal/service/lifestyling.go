@@ -0,0 +1,196 @@
+package service
+
+import (
+	"cushon/internal/clock"
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Lifestyling defines the interface for managing glide paths and moving the customers who follow them into
+// lower-risk funds as they approach retirement
+type Lifestyling interface {
+	CreateGlidePath(create model.GlidePathCreate) (*model.GlidePath, error)
+	GetGlidePath(id uint) (*model.GlidePath, error)
+	ListGlidePaths() ([]*model.GlidePath, error)
+	ApplyGlidePaths() (*model.GlidePathRun, error)
+}
+
+// defaultLifestylingService is a concrete implementation of Lifestyling
+type defaultLifestylingService struct {
+	repo           repository.GlidePathRepository
+	customerRepo   repository.CustomerRepository
+	allocationRepo repository.AllocationRepository
+	fundRepo       repository.FundRepository
+	rebalancing    Rebalancing
+	clock          clock.Clock
+	runAt          model.TimeOfDay
+
+	mu sync.Mutex
+	// lastRun is the date of the last scheduled run that got through every customer
+	lastRun model.Date
+}
+
+// NewDefaultLifestylingService creates a new default lifestyling service that takes today's date from c and moves
+// customers along their glide paths once each weekday after runAt
+func NewDefaultLifestylingService(repo repository.GlidePathRepository, customerRepo repository.CustomerRepository, allocationRepo repository.AllocationRepository, fundRepo repository.FundRepository, rebalancing Rebalancing, c clock.Clock, runAt model.TimeOfDay) *defaultLifestylingService {
+	return &defaultLifestylingService{
+		repo:           repo,
+		customerRepo:   customerRepo,
+		allocationRepo: allocationRepo,
+		fundRepo:       fundRepo,
+		rebalancing:    rebalancing,
+		clock:          c,
+		runAt:          runAt,
+	}
+}
+
+// CreateGlidePath creates a glide path. It needs a name and at least one stage, no two stages can start the same
+// number of years before retirement, and each stage's allocation follows the same rules as a target allocation.
+func (s *defaultLifestylingService) CreateGlidePath(create model.GlidePathCreate) (*model.GlidePath, error) {
+	name := strings.TrimSpace(create.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidGlidePath)
+	}
+	if len(create.Stages) == 0 {
+		return nil, fmt.Errorf("%w: at least one stage is required", ErrInvalidGlidePath)
+	}
+	seen := make(map[int]bool, len(create.Stages))
+	for _, stage := range create.Stages {
+		if stage.YearsToRetirement < 0 {
+			return nil, fmt.Errorf("%w: years to retirement can't be negative", ErrInvalidGlidePath)
+		}
+		if seen[stage.YearsToRetirement] {
+			return nil, fmt.Errorf("%w: more than one stage at %d years", ErrInvalidGlidePath, stage.YearsToRetirement)
+		}
+		seen[stage.YearsToRetirement] = true
+		if err := validateAllocationFunds(stage.Funds); err != nil {
+			return nil, fmt.Errorf("%w: stage at %d years: %w", ErrInvalidGlidePath, stage.YearsToRetirement, err)
+		}
+	}
+	for _, stage := range create.Stages {
+		if err := checkFundsOpen(s.fundRepo, stage.Funds); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.CreateGlidePath(&model.GlidePath{Name: name, Stages: create.Stages})
+}
+
+// GetGlidePath retrieves a glide path by ID
+func (s *defaultLifestylingService) GetGlidePath(id uint) (*model.GlidePath, error) {
+	return s.repo.GetGlidePathByID(id)
+}
+
+// ListGlidePaths retrieves every glide path
+func (s *defaultLifestylingService) ListGlidePaths() ([]*model.GlidePath, error) {
+	return s.repo.ListGlidePaths()
+}
+
+// ApplyGlidePaths sets the target allocation of every customer following a glide path to the stage they have
+// reached today, and rebalances their portfolio into it. Customers already on their stage's allocation are left
+// out, and customers who can't be moved are reported as failures without stopping the run.
+func (s *defaultLifestylingService) ApplyGlidePaths() (*model.GlidePathRun, error) {
+	return s.apply(model.DateOf(s.clock.Now().UTC()))
+}
+
+// apply moves every customer following a glide path to the stage they have reached on today
+func (s *defaultLifestylingService) apply(today model.Date) (*model.GlidePathRun, error) {
+	customers, err := s.customerRepo.ListCustomers(model.CustomerFilter{OnGlidePath: true})
+	if err != nil {
+		return nil, err
+	}
+
+	glidePaths := make(map[uint]*model.GlidePath)
+	run := &model.GlidePathRun{Moved: []*model.GlidePathMove{}, Failed: []model.GlidePathFailure{}}
+	for _, customer := range customers {
+		glidePath, cached := glidePaths[*customer.GlidePathID]
+		if !cached {
+			if glidePath, err = s.repo.GetGlidePathByID(*customer.GlidePathID); err != nil {
+				return nil, err
+			}
+			glidePaths[glidePath.ID] = glidePath
+		}
+
+		move, err := s.move(customer, glidePath, today)
+		switch {
+		case err != nil:
+			run.Failed = append(run.Failed, model.GlidePathFailure{CustomerID: customer.ID, Error: err.Error()})
+		case move != nil:
+			run.Moved = append(run.Moved, move)
+		}
+	}
+	return run, nil
+}
+
+// RunDue moves customers along their glide paths once a day, the first time it is called after the run time on
+// a weekday, and does nothing otherwise. It is meant to be run by the scheduler, which logs the customers who
+// couldn't be moved.
+func (s *defaultLifestylingService) RunDue(now time.Time) error {
+	today := model.DateOf(now.UTC())
+	if today.IsWeekend() || now.Before(s.runAt.On(today)) {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastRun == today {
+		return nil
+	}
+
+	run, err := s.apply(today)
+	if err != nil {
+		// Try again on the next tick
+		return err
+	}
+	// Customers who couldn't be moved are retried the next day rather than on every tick
+	s.lastRun = today
+	errs := make([]error, len(run.Failed))
+	for i, failure := range run.Failed {
+		errs[i] = fmt.Errorf("customer %d: %s", failure.CustomerID, failure.Error)
+	}
+	return errors.Join(errs...)
+}
+
+// move sets a customer's target allocation to the glide path stage they have reached and rebalances into it. It
+// returns nil when their allocation is already the stage's.
+func (s *defaultLifestylingService) move(customer *model.Customer, glidePath *model.GlidePath, today model.Date) (*model.GlidePathMove, error) {
+	years, ok := customer.YearsToRetirement(today)
+	if !ok {
+		return nil, fmt.Errorf("%w: date of birth and retirement age are needed to follow a glide path", ErrInvalidRetirementPlan)
+	}
+	stage := glidePath.StageFor(years)
+
+	current, err := s.allocationRepo.GetAllocation(customer.ID)
+	if err != nil && !errors.Is(err, ErrAllocationNotFound) {
+		return nil, err
+	}
+	if current != nil && slices.Equal(current.Funds, stage.Funds) {
+		return nil, nil
+	}
+
+	if err := checkFundsOpen(s.fundRepo, stage.Funds); err != nil {
+		return nil, err
+	}
+	allocation, err := s.allocationRepo.SetAllocation(&model.TargetAllocation{CustomerID: customer.ID, Funds: stage.Funds})
+	if err != nil {
+		return nil, err
+	}
+	rebalance, err := s.rebalancing.Rebalance(customer.ID)
+	if err != nil {
+		return nil, fmt.Errorf("allocation changed but not rebalanced: %w", err)
+	}
+
+	return &model.GlidePathMove{
+		CustomerID:        customer.ID,
+		GlidePathID:       glidePath.ID,
+		YearsToRetirement: years,
+		Allocation:        allocation,
+		Rebalance:         rebalance,
+	}, nil
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+)
+
+func TestDefaultLifestylingService_CreateGlidePath(t *testing.T) {
+	allIn := func(fundID uint) []model.FundAllocation {
+		return []model.FundAllocation{{FundID: fundID, Percentage: model.OneHundredPercent}}
+	}
+
+	tests := []struct {
+		name       string
+		create     model.GlidePathCreate
+		fundStatus model.FundStatus
+		fundErr    error
+		wantErr    error
+	}{
+		{
+			name: "Valid glide path",
+			create: model.GlidePathCreate{Name: "Balanced", Stages: []model.GlidePathStage{
+				{YearsToRetirement: 0, Funds: allIn(2)},
+				{YearsToRetirement: 10, Funds: []model.FundAllocation{{FundID: 1, Percentage: 80000}, {FundID: 2, Percentage: 20000}}},
+			}},
+		},
+		{
+			name:    "Missing name",
+			create:  model.GlidePathCreate{Name: " ", Stages: []model.GlidePathStage{{Funds: allIn(1)}}},
+			wantErr: ErrInvalidGlidePath,
+		},
+		{
+			name:    "No stages",
+			create:  model.GlidePathCreate{Name: "Balanced"},
+			wantErr: ErrInvalidGlidePath,
+		},
+		{
+			name: "Two stages at the same years",
+			create: model.GlidePathCreate{Name: "Balanced", Stages: []model.GlidePathStage{
+				{YearsToRetirement: 5, Funds: allIn(1)},
+				{YearsToRetirement: 5, Funds: allIn(2)},
+			}},
+			wantErr: ErrInvalidGlidePath,
+		},
+		{
+			name:    "Negative years",
+			create:  model.GlidePathCreate{Name: "Balanced", Stages: []model.GlidePathStage{{YearsToRetirement: -1, Funds: allIn(1)}}},
+			wantErr: ErrInvalidGlidePath,
+		},
+		{
+			name: "Stage doesn't add up to 100%",
+			create: model.GlidePathCreate{Name: "Balanced", Stages: []model.GlidePathStage{
+				{YearsToRetirement: 0, Funds: []model.FundAllocation{{FundID: 1, Percentage: 50000}}},
+			}},
+			wantErr: ErrInvalidGlidePath,
+		},
+		{
+			name:       "Closed fund",
+			create:     model.GlidePathCreate{Name: "Balanced", Stages: []model.GlidePathStage{{Funds: allIn(1)}}},
+			fundStatus: model.FundStatusClosed,
+			wantErr:    ErrFundNotOpen,
+		},
+		{
+			name:    "Unknown fund",
+			create:  model.GlidePathCreate{Name: "Balanced", Stages: []model.GlidePathStage{{Funds: allIn(99)}}},
+			fundErr: ErrFundNotFound,
+			wantErr: ErrFundNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fundStatus := tt.fundStatus
+			if fundStatus == "" {
+				fundStatus = model.FundStatusOpen
+			}
+			service := NewDefaultLifestylingService(
+				&mocks.GlidePathRepository{},
+				&mocks.CustomerRepository{},
+				&mocks.AllocationRepository{},
+				&mocks.FundRepository{MockFund: &model.Fund{ID: 1, Status: fundStatus}, MockErr: tt.fundErr},
+				&mocks.RebalancingService{},
+				&mocks.Clock{},
+				model.TimeOfDay{Hour: 9},
+			)
+
+			got, err := service.CreateGlidePath(tt.create)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateGlidePath() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateGlidePath() unexpected error = %v", err)
+			}
+			if got.Name != tt.create.Name || len(got.Stages) != len(tt.create.Stages) {
+				t.Errorf("CreateGlidePath() = %+v, want %+v", got, tt.create)
+			}
+		})
+	}
+}
+
+func TestDefaultLifestylingService_ApplyGlidePaths(t *testing.T) {
+	growth := []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}}
+	cautious := []model.FundAllocation{{FundID: 2, Percentage: model.OneHundredPercent}}
+	glidePath := &model.GlidePath{ID: 1, Stages: []model.GlidePathStage{
+		{YearsToRetirement: 10, Funds: growth},
+		{YearsToRetirement: 5, Funds: cautious},
+	}}
+	now := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+	// following returns a customer on the glide path who retires at 67 in yearsToRetirement years' time
+	following := func(yearsToRetirement int) *model.Customer {
+		dateOfBirth := model.DateOf(now.AddDate(yearsToRetirement-67, 0, 0))
+		retirementAge := 67
+		return &model.Customer{ID: 1, RetirementPlan: model.RetirementPlan{DateOfBirth: &dateOfBirth, RetirementAge: &retirementAge, GlidePathID: &glidePath.ID}}
+	}
+
+	tests := []struct {
+		name         string
+		customer     *model.Customer
+		allocation   *model.TargetAllocation
+		fundStatus   model.FundStatus
+		rebalanceErr error
+		wantFunds    []model.FundAllocation
+		wantFailed   bool
+	}{
+		{
+			name:       "Already on their stage",
+			customer:   following(12),
+			allocation: &model.TargetAllocation{CustomerID: 1, Funds: growth},
+		},
+		{
+			name:       "Reached the next stage",
+			customer:   following(3),
+			allocation: &model.TargetAllocation{CustomerID: 1, Funds: growth},
+			wantFunds:  cautious,
+		},
+		{
+			name:      "No allocation yet",
+			customer:  following(12),
+			wantFunds: growth,
+		},
+		{
+			name:       "Stage fund closed",
+			customer:   following(3),
+			allocation: &model.TargetAllocation{CustomerID: 1, Funds: growth},
+			fundStatus: model.FundStatusClosed,
+			wantFailed: true,
+		},
+		{
+			name:         "Rebalance fails",
+			customer:     following(3),
+			allocation:   &model.TargetAllocation{CustomerID: 1, Funds: growth},
+			rebalanceErr: ErrFundNotPriced,
+			wantFailed:   true,
+		},
+		{
+			name:       "Retirement plan incomplete",
+			customer:   &model.Customer{ID: 1, RetirementPlan: model.RetirementPlan{GlidePathID: &glidePath.ID}},
+			wantFailed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fundStatus := tt.fundStatus
+			if fundStatus == "" {
+				fundStatus = model.FundStatusOpen
+			}
+			service := NewDefaultLifestylingService(
+				&mocks.GlidePathRepository{MockGlidePath: glidePath},
+				&mocks.CustomerRepository{MockCustomers: []*model.Customer{tt.customer}},
+				&mocks.AllocationRepository{MockAllocation: tt.allocation},
+				&mocks.FundRepository{MockFund: &model.Fund{Status: fundStatus}},
+				&mocks.RebalancingService{MockRebalance: &model.Rebalance{CustomerID: 1, Executed: true}, MockErr: tt.rebalanceErr},
+				&mocks.Clock{MockNow: now},
+				model.TimeOfDay{Hour: 9},
+			)
+
+			run, err := service.ApplyGlidePaths()
+			if err != nil {
+				t.Fatalf("ApplyGlidePaths() error = %v", err)
+			}
+			if (len(run.Failed) > 0) != tt.wantFailed {
+				t.Fatalf("ApplyGlidePaths() failed = %+v, want failed %v", run.Failed, tt.wantFailed)
+			}
+			if tt.wantFunds == nil {
+				if len(run.Moved) != 0 {
+					t.Errorf("ApplyGlidePaths() moved = %+v, want nobody moved", run.Moved)
+				}
+				return
+			}
+			if len(run.Moved) != 1 {
+				t.Fatalf("ApplyGlidePaths() moved %d customers, want 1", len(run.Moved))
+			}
+			move := run.Moved[0]
+			if move.Allocation.Funds[0] != tt.wantFunds[0] || move.Rebalance == nil || !move.Rebalance.Executed {
+				t.Errorf("ApplyGlidePaths() move = %+v, want the customer moved into %+v and rebalanced", move, tt.wantFunds)
+			}
+		})
+	}
+}
+
+func TestDefaultLifestylingService_RunDue(t *testing.T) {
+	tests := []struct {
+		name    string
+		now     []time.Time
+		wantRun []bool
+	}{
+		{
+			name:    "Weekday after the run time",
+			now:     []time.Time{time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)},
+			wantRun: []bool{true},
+		},
+		{
+			name:    "Weekday before the run time",
+			now:     []time.Time{time.Date(2026, time.October, 16, 8, 59, 0, 0, time.UTC)},
+			wantRun: []bool{false},
+		},
+		{
+			name:    "Weekend",
+			now:     []time.Time{time.Date(2026, time.October, 17, 15, 0, 0, 0, time.UTC)},
+			wantRun: []bool{false},
+		},
+		{
+			name: "Once a day",
+			now: []time.Time{
+				time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, time.October, 16, 9, 1, 0, 0, time.UTC),
+				time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC),
+			},
+			wantRun: []bool{true, false, true},
+		},
+	}
+
+	t.Run("Failed run is retried", func(t *testing.T) {
+		runErr := errors.New("customers unavailable")
+		customerRepo := &mocks.CustomerRepository{MockErr: runErr}
+		service := NewDefaultLifestylingService(
+			&mocks.GlidePathRepository{},
+			customerRepo,
+			&mocks.AllocationRepository{},
+			&mocks.FundRepository{},
+			&mocks.RebalancingService{},
+			&mocks.Clock{},
+			model.TimeOfDay{Hour: 9},
+		)
+
+		now := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+		if err := service.RunDue(now); !errors.Is(err, runErr) {
+			t.Fatalf("RunDue() error = %v, want %v", err, runErr)
+		}
+		customerRepo.MockErr = nil
+		if err := service.RunDue(now.Add(time.Minute)); err != nil {
+			t.Errorf("RunDue() error = %v, want the run to be retried and succeed", err)
+		}
+	})
+
+	t.Run("Stages are taken on the date it runs for", func(t *testing.T) {
+		// On the clock's date the customer is still on the growth stage. On the run's date they have reached the
+		// cautious stage, whose fund is closed, so moving them shows up as an error.
+		now := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+		dateOfBirth := model.DateOf(now.AddDate(3-67, 0, 0))
+		retirementAge := 67
+		glidePathID := uint(1)
+		service := NewDefaultLifestylingService(
+			&mocks.GlidePathRepository{MockGlidePath: &model.GlidePath{ID: 1, Stages: []model.GlidePathStage{
+				{YearsToRetirement: 10, Funds: []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}}},
+				{YearsToRetirement: 5, Funds: []model.FundAllocation{{FundID: 2, Percentage: model.OneHundredPercent}}},
+			}}},
+			&mocks.CustomerRepository{MockCustomers: []*model.Customer{{ID: 1, RetirementPlan: model.RetirementPlan{DateOfBirth: &dateOfBirth, RetirementAge: &retirementAge, GlidePathID: &glidePathID}}}},
+			&mocks.AllocationRepository{MockAllocation: &model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}}}},
+			&mocks.FundRepository{MockFund: &model.Fund{Status: model.FundStatusClosed}},
+			&mocks.RebalancingService{},
+			&mocks.Clock{MockNow: now.AddDate(-10, 0, 0)},
+			model.TimeOfDay{Hour: 9},
+		)
+
+		if err := service.RunDue(now); err == nil || !strings.Contains(err.Error(), "customer 1") {
+			t.Errorf("RunDue() error = %v, want the customer moved to the stage reached on %v", err, model.DateOf(now))
+		}
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Customer 1 has no retirement plan, so any run shows up as an error
+			glidePathID := uint(1)
+			service := NewDefaultLifestylingService(
+				&mocks.GlidePathRepository{MockGlidePath: &model.GlidePath{ID: 1}},
+				&mocks.CustomerRepository{MockCustomers: []*model.Customer{{ID: 1, RetirementPlan: model.RetirementPlan{GlidePathID: &glidePathID}}}},
+				&mocks.AllocationRepository{},
+				&mocks.FundRepository{},
+				&mocks.RebalancingService{},
+				&mocks.Clock{},
+				model.TimeOfDay{Hour: 9},
+			)
+
+			for i, now := range tt.now {
+				err := service.RunDue(now)
+				if ran := err != nil && strings.Contains(err.Error(), "customer 1"); ran != tt.wantRun[i] {
+					t.Errorf("RunDue(%v) ran = %v, want %v (error %v)", now, ran, tt.wantRun[i], err)
+				}
+			}
+		})
+	}
+}
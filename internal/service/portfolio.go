@@ -0,0 +1,132 @@
+package service
+
+import (
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"errors"
+	"sort"
+)
+
+// Portfolio defines the interface for valuing customers' investments
+type Portfolio interface {
+	GetPortfolio(customerID uint) (*model.Portfolio, error)
+}
+
+// defaultPortfolioService is a concrete implementation of Portfolio
+type defaultPortfolioService struct {
+	customerRepo   repository.CustomerRepository
+	investmentRepo repository.InvestmentRepository
+	fundRepo       repository.FundRepository
+	fundPriceRepo  repository.FundPriceRepository
+}
+
+// NewDefaultPortfolioService creates a new default portfolio service
+func NewDefaultPortfolioService(customerRepo repository.CustomerRepository, investmentRepo repository.InvestmentRepository, fundRepo repository.FundRepository, fundPriceRepo repository.FundPriceRepository) *defaultPortfolioService {
+	return &defaultPortfolioService{
+		customerRepo:   customerRepo,
+		investmentRepo: investmentRepo,
+		fundRepo:       fundRepo,
+		fundPriceRepo:  fundPriceRepo,
+	}
+}
+
+// GetPortfolio aggregates a customer's investments by fund and values each holding at the fund's latest price.
+// Holdings are ordered by fund ID. Funds that have never been priced are valued at zero. Failed and cancelled
+// transactions are left out. Contributions are also broken down by the source of the money paid in.
+func (s *defaultPortfolioService) GetPortfolio(customerID uint) (*model.Portfolio, error) {
+	if _, err := s.customerRepo.GetCustomerByID(customerID); err != nil {
+		return nil, err
+	}
+
+	investments, err := s.investmentRepo.GetInvestmentsByClientID(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	holdings := make(map[uint]*model.Holding)
+	bySource := make(map[model.ContributionSource]model.Money)
+	for _, investment := range investments {
+		if !investment.Status.HoldsUnits() {
+			continue
+		}
+		if investment.Type == model.TransactionTypeInvestment && investment.Source != "" {
+			paidIn, exists := bySource[investment.Source]
+			if !exists {
+				paidIn = model.NewMoney(0, investment.Amount.Currency)
+			}
+			if bySource[investment.Source], err = paidIn.Add(investment.Amount); err != nil {
+				return nil, err
+			}
+		}
+		holding, exists := holdings[investment.FundID]
+		if !exists {
+			holding = &model.Holding{
+				FundID:      investment.FundID,
+				Contributed: model.NewMoney(0, investment.Amount.Currency),
+			}
+			holdings[investment.FundID] = holding
+		}
+
+		holding.Units += investment.Units
+		if holding.Contributed, err = holding.Contributed.Add(investment.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	portfolio := &model.Portfolio{
+		CustomerID:            customerID,
+		Holdings:              make([]*model.Holding, 0, len(holdings)),
+		Contributed:           model.NewMoney(0, model.DefaultCurrency),
+		ContributionsBySource: bySource,
+		Value:                 model.NewMoney(0, model.DefaultCurrency),
+	}
+	for _, holding := range holdings {
+		if err := s.valueHolding(holding); err != nil {
+			return nil, err
+		}
+		if portfolio.Contributed, err = portfolio.Contributed.Add(holding.Contributed); err != nil {
+			return nil, err
+		}
+		if portfolio.Value, err = portfolio.Value.Add(holding.Value); err != nil {
+			return nil, err
+		}
+		portfolio.Holdings = append(portfolio.Holdings, holding)
+	}
+	sort.Slice(portfolio.Holdings, func(i, j int) bool {
+		return portfolio.Holdings[i].FundID < portfolio.Holdings[j].FundID
+	})
+
+	for _, holding := range portfolio.Holdings {
+		holding.Allocation = model.PercentOf(holding.Value.Minor, portfolio.Value.Minor)
+	}
+	if portfolio.Gain, err = portfolio.Value.Sub(portfolio.Contributed); err != nil {
+		return nil, err
+	}
+
+	return portfolio, nil
+}
+
+// valueHolding fills in the fund's name and the holding's value and gain at the fund's latest price
+func (s *defaultPortfolioService) valueHolding(holding *model.Holding) error {
+	fund, err := s.fundRepo.GetFundByID(holding.FundID)
+	if err != nil {
+		return err
+	}
+	holding.FundName = fund.Name
+
+	holding.Value = model.NewMoney(0, holding.Contributed.Currency)
+	price, err := s.fundPriceRepo.GetLatestFundPrice(holding.FundID, model.Today())
+	switch {
+	case errors.Is(err, repository.ErrFundPriceNotFound):
+		// Only investments made before funds were priced can be in an unpriced fund, and they hold no units
+	case err != nil:
+		return err
+	default:
+		holding.Price = price.NAV
+		holding.PriceDate = price.Date
+		holding.Value = price.NAV.ValueOf(holding.Units, holding.Contributed.Currency)
+	}
+
+	holding.Gain, err = holding.Value.Sub(holding.Contributed)
+	return err
+}
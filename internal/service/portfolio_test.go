@@ -0,0 +1,203 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+)
+
+// pricesByFund is a fund price repository mock holding the latest price of each fund
+type pricesByFund struct {
+	mocks.FundPriceRepository
+	prices map[uint]*model.FundPrice
+}
+
+// GetLatestFundPrice returns the fund's price, or ErrFundPriceNotFound if it has none
+func (r *pricesByFund) GetLatestFundPrice(fundID uint, onOrBefore model.Date) (*model.FundPrice, error) {
+	if r.MockErr != nil {
+		return nil, r.MockErr
+	}
+	price, exists := r.prices[fundID]
+	if !exists {
+		return nil, ErrFundPriceNotFound
+	}
+	return price, nil
+}
+
+func TestDefaultPortfolioService_GetPortfolio(t *testing.T) {
+	priceDate := model.NewDate(2026, time.October, 16)
+	gbp := func(minor int64) model.Money { return model.NewMoney(minor, model.DefaultCurrency) }
+
+	type wantHolding struct {
+		fundID      uint
+		units       model.Units
+		contributed model.Money
+		value       model.Money
+		gain        model.Money
+		allocation  model.Percent
+	}
+
+	tests := []struct {
+		name            string
+		investments     []*model.Investment
+		prices          map[uint]*model.FundPrice
+		customerErr     error
+		investmentErr   error
+		wantHoldings    []wantHolding
+		wantContributed model.Money
+		wantValue       model.Money
+		wantGain        model.Money
+		wantErr         error
+	}{
+		{
+			name:            "No investments",
+			investments:     []*model.Investment{},
+			wantHoldings:    []wantHolding{},
+			wantContributed: gbp(0),
+			wantValue:       gbp(0),
+			wantGain:        gbp(0),
+		},
+		{
+			name: "Investments aggregated by fund",
+			investments: []*model.Investment{
+				// 1000.00 at 2.50 and 500.00 at 2.00 buy 650 units of fund 2
+				{ID: 1, FundID: 2, Amount: gbp(100000), Units: 400000000, Price: 2500000},
+				{ID: 2, FundID: 1, Amount: gbp(100000), Units: 1000000000, Price: 1000000},
+				{ID: 3, FundID: 2, Amount: gbp(50000), Units: 250000000, Price: 2000000},
+			},
+			prices: map[uint]*model.FundPrice{
+				1: {FundID: 1, Date: priceDate, NAV: 900000, Currency: "GBP"},
+				2: {FundID: 2, Date: priceDate, NAV: 3000000, Currency: "GBP"},
+			},
+			wantHoldings: []wantHolding{
+				// 1000 units at 0.90 are worth 900.00, a 100.00 loss
+				{fundID: 1, units: 1000000000, contributed: gbp(100000), value: gbp(90000), gain: gbp(-10000), allocation: 31579},
+				// 650 units at 3.00 are worth 1950.00, a 450.00 gain
+				{fundID: 2, units: 650000000, contributed: gbp(150000), value: gbp(195000), gain: gbp(45000), allocation: 68421},
+			},
+			wantContributed: gbp(250000),
+			wantValue:       gbp(285000),
+			wantGain:        gbp(35000),
+		},
+		{
+			name: "Failed and cancelled transactions are left out",
+			investments: []*model.Investment{
+				{ID: 1, FundID: 1, Amount: gbp(100000), Units: 1000000000, Status: model.InvestmentStatusSettled},
+				{ID: 2, FundID: 1, Amount: gbp(50000), Units: 500000000, Status: model.InvestmentStatusCancelled},
+				{ID: 3, FundID: 2, Amount: gbp(50000), Units: 500000000, Status: model.InvestmentStatusFailed},
+			},
+			prices: map[uint]*model.FundPrice{
+				1: {FundID: 1, Date: priceDate, NAV: 1000000, Currency: "GBP"},
+			},
+			wantHoldings: []wantHolding{
+				{fundID: 1, units: 1000000000, contributed: gbp(100000), value: gbp(100000), gain: gbp(0), allocation: model.OneHundredPercent},
+			},
+			wantContributed: gbp(100000),
+			wantValue:       gbp(100000),
+			wantGain:        gbp(0),
+		},
+		{
+			name: "Unpriced fund is valued at zero",
+			investments: []*model.Investment{
+				{ID: 1, FundID: 1, Amount: gbp(100000)},
+			},
+			prices: map[uint]*model.FundPrice{},
+			wantHoldings: []wantHolding{
+				{fundID: 1, contributed: gbp(100000), value: gbp(0), gain: gbp(-100000), allocation: 0},
+			},
+			wantContributed: gbp(100000),
+			wantValue:       gbp(0),
+			wantGain:        gbp(-100000),
+		},
+		{
+			name:        "Customer does not exist",
+			customerErr: ErrCustomerNotFound,
+			wantErr:     ErrCustomerNotFound,
+		},
+		{
+			name:          "Repository error",
+			investmentErr: errors.New("repository error"),
+			wantErr:       errors.New("repository error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewDefaultPortfolioService(
+				&mocks.CustomerRepository{MockErr: tt.customerErr, MockCustomer: &model.Customer{ID: 1}},
+				&mocks.InvestmentRepository{MockErr: tt.investmentErr, MockInvestments: tt.investments},
+				&mocks.FundRepository{MockFund: &model.Fund{ID: 1, Name: "Global Equities"}},
+				&pricesByFund{prices: tt.prices},
+			)
+
+			got, err := service.GetPortfolio(1)
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Errorf("GetPortfolio() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetPortfolio() unexpected error = %v", err)
+			}
+
+			if got.Contributed != tt.wantContributed || got.Value != tt.wantValue || got.Gain != tt.wantGain {
+				t.Errorf("GetPortfolio() totals = %v contributed, %v value, %v gain, want %v, %v, %v",
+					got.Contributed, got.Value, got.Gain, tt.wantContributed, tt.wantValue, tt.wantGain)
+			}
+			if len(got.Holdings) != len(tt.wantHoldings) {
+				t.Fatalf("got %d holdings, want %d", len(got.Holdings), len(tt.wantHoldings))
+			}
+			for i, want := range tt.wantHoldings {
+				holding := got.Holdings[i]
+				if holding.FundID != want.fundID || holding.Units != want.units || holding.Contributed != want.contributed ||
+					holding.Value != want.value || holding.Gain != want.gain || holding.Allocation != want.allocation {
+					t.Errorf("holding[%d] = %+v, want %+v", i, holding, want)
+				}
+				if holding.FundName != "Global Equities" {
+					t.Errorf("holding[%d].FundName = %v, want Global Equities", i, holding.FundName)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultPortfolioService_GetPortfolio_ContributionsBySource(t *testing.T) {
+	gbp := func(minor int64) model.Money { return model.NewMoney(minor, model.DefaultCurrency) }
+	investments := []*model.Investment{
+		{ID: 1, FundID: 1, Type: model.TransactionTypeInvestment, Source: model.ContributionSourceEmployee, Amount: gbp(15000), Units: 15000000},
+		{ID: 2, FundID: 1, Type: model.TransactionTypeInvestment, Source: model.ContributionSourceEmployer, Amount: gbp(9000), Units: 9000000},
+		{ID: 3, FundID: 1, Type: model.TransactionTypeInvestment, Source: model.ContributionSourceEmployee, Amount: gbp(15000), Units: 15000000},
+		{ID: 4, FundID: 1, Type: model.TransactionTypeInvestment, Source: model.ContributionSourceOneOff, Amount: gbp(50000), Units: 50000000, Status: model.InvestmentStatusCancelled},
+		// Withdrawals don't change how much was paid in from each source
+		{ID: 5, FundID: 1, Type: model.TransactionTypeWithdrawal, Amount: gbp(-10000), Units: -10000000},
+	}
+
+	service := NewDefaultPortfolioService(
+		&mocks.CustomerRepository{MockCustomer: &model.Customer{ID: 1}},
+		&mocks.InvestmentRepository{MockInvestments: investments},
+		&mocks.FundRepository{MockFund: &model.Fund{ID: 1, Name: "Global Equities"}},
+		&pricesByFund{prices: map[uint]*model.FundPrice{}},
+	)
+
+	got, err := service.GetPortfolio(1)
+	if err != nil {
+		t.Fatalf("GetPortfolio() unexpected error = %v", err)
+	}
+
+	want := map[model.ContributionSource]model.Money{
+		model.ContributionSourceEmployee: gbp(30000),
+		model.ContributionSourceEmployer: gbp(9000),
+	}
+	if !reflect.DeepEqual(got.ContributionsBySource, want) {
+		t.Errorf("GetPortfolio().ContributionsBySource = %v, want %v", got.ContributionsBySource, want)
+	}
+	if got.Contributed != gbp(29000) {
+		t.Errorf("GetPortfolio().Contributed = %v, want %v", got.Contributed, gbp(29000))
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"cushon/internal/clock"
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Rebalancing defines the interface for bringing customers' portfolios back to their target allocations
+type Rebalancing interface {
+	PreviewRebalance(customerID uint) (*model.Rebalance, error)
+	Rebalance(customerID uint) (*model.Rebalance, error)
+	RebalanceAll() (*model.RebalanceRun, error)
+}
+
+// defaultRebalancingService is a concrete implementation of Rebalancing
+type defaultRebalancingService struct {
+	investmentRepo repository.InvestmentRepository
+	customerRepo   repository.CustomerRepository
+	employerRepo   repository.EmployerRepository
+	allocationRepo repository.AllocationRepository
+	fundRepo       repository.FundRepository
+	fundPriceRepo  repository.FundPriceRepository
+	policy         model.RebalancePolicy
+	clock          clock.Clock
+	runAt          model.TimeOfDay
+
+	mu sync.Mutex
+	// lastRun is the date of the last scheduled run that got through every customer
+	lastRun model.Date
+}
+
+// NewDefaultRebalancingService creates a new default rebalancing service that trades by policy at the prices of
+// the date taken from c, and runs the scheduled rebalance once each weekday after runAt
+func NewDefaultRebalancingService(investmentRepo repository.InvestmentRepository, customerRepo repository.CustomerRepository, employerRepo repository.EmployerRepository, allocationRepo repository.AllocationRepository, fundRepo repository.FundRepository, fundPriceRepo repository.FundPriceRepository, policy model.RebalancePolicy, c clock.Clock, runAt model.TimeOfDay) *defaultRebalancingService {
+	return &defaultRebalancingService{
+		investmentRepo: investmentRepo,
+		customerRepo:   customerRepo,
+		employerRepo:   employerRepo,
+		allocationRepo: allocationRepo,
+		fundRepo:       fundRepo,
+		fundPriceRepo:  fundPriceRepo,
+		policy:         policy,
+		clock:          c,
+		runAt:          runAt,
+	}
+}
+
+// PreviewRebalance values a customer's holdings at the funds' latest prices, compares them with their target
+// allocation and returns the trades rebalancing would make, without making them
+func (s *defaultRebalancingService) PreviewRebalance(customerID uint) (*model.Rebalance, error) {
+	rebalance, _, err := s.plan(customerID, s.today())
+	return rebalance, err
+}
+
+// Rebalance makes the trades PreviewRebalance would return, as one switch each. The switches are stored
+// atomically: either all of them are made or none are.
+func (s *defaultRebalancingService) Rebalance(customerID uint) (*model.Rebalance, error) {
+	return s.rebalance(customerID, s.today())
+}
+
+// rebalance makes the trades of a customer's rebalance at the latest prices on today
+func (s *defaultRebalancingService) rebalance(customerID uint, today model.Date) (*model.Rebalance, error) {
+	rebalance, switches, err := s.plan(customerID, today)
+	if err != nil {
+		return nil, err
+	}
+	if len(switches) == 0 {
+		return rebalance, nil
+	}
+
+	if rebalance.Switches, err = s.investmentRepo.CreateSwitches(switches); err != nil {
+		return nil, err
+	}
+	rebalance.Executed = true
+	return rebalance, nil
+}
+
+// RebalanceAll rebalances every customer who has chosen a target allocation. Customers whose portfolio can't be
+// rebalanced are reported as failures without stopping the run; those within tolerance are left out.
+func (s *defaultRebalancingService) RebalanceAll() (*model.RebalanceRun, error) {
+	return s.rebalanceAll(s.today())
+}
+
+// rebalanceAll rebalances every customer who has chosen a target allocation at the latest prices on today
+func (s *defaultRebalancingService) rebalanceAll(today model.Date) (*model.RebalanceRun, error) {
+	allocations, err := s.allocationRepo.ListAllocations()
+	if err != nil {
+		return nil, err
+	}
+
+	run := &model.RebalanceRun{Rebalanced: []*model.Rebalance{}, Failed: []model.RebalanceFailure{}}
+	for _, allocation := range allocations {
+		rebalance, err := s.rebalance(allocation.CustomerID, today)
+		switch {
+		case errors.Is(err, ErrCustomerNotFound):
+			// The customer was deleted after choosing their allocation
+		case err != nil:
+			run.Failed = append(run.Failed, model.RebalanceFailure{CustomerID: allocation.CustomerID, Error: err.Error()})
+		case rebalance.Executed:
+			run.Rebalanced = append(run.Rebalanced, rebalance)
+		}
+	}
+	return run, nil
+}
+
+// RunDue rebalances every customer once a day, the first time it is called after the rebalance time on a
+// weekday, and does nothing otherwise. It is meant to be run by the scheduler, which logs the customers who
+// couldn't be rebalanced.
+func (s *defaultRebalancingService) RunDue(now time.Time) error {
+	today := model.DateOf(now.UTC())
+	if today.IsWeekend() || now.Before(s.runAt.On(today)) {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastRun == today {
+		return nil
+	}
+
+	run, err := s.rebalanceAll(today)
+	if err != nil {
+		// Try again on the next tick
+		return err
+	}
+	// Customers who couldn't be rebalanced are retried the next day rather than on every tick
+	s.lastRun = today
+	errs := make([]error, len(run.Failed))
+	for i, failure := range run.Failed {
+		errs[i] = fmt.Errorf("customer %d: %s", failure.CustomerID, failure.Error)
+	}
+	return errors.Join(errs...)
+}
+
+// plan works out a customer's rebalance at the latest prices on today and the switches that would make its trades
+func (s *defaultRebalancingService) plan(customerID uint, today model.Date) (*model.Rebalance, []*model.Switch, error) {
+	customer, err := s.customerRepo.GetCustomerByID(customerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	allocation, err := targetAllocation(s.allocationRepo, s.employerRepo, customer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	investments, err := s.investmentRepo.GetInvestmentsByClientID(customerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	held := make(map[uint]model.Units)
+	for _, investment := range investments {
+		if investment.Status.HoldsUnits() {
+			held[investment.FundID] += investment.Units
+		}
+	}
+
+	prices := make(map[uint]*model.FundPrice)
+	values := make(map[uint]model.Money)
+	for fundID, units := range held {
+		if units == 0 {
+			continue
+		}
+		if prices[fundID], err = s.latestPrice(fundID, today); err != nil {
+			return nil, nil, err
+		}
+		values[fundID] = prices[fundID].NAV.ValueOf(units, model.DefaultCurrency)
+	}
+
+	rebalance, err := model.PlanRebalance(allocation, values, s.policy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switches := make([]*model.Switch, 0, len(rebalance.Trades))
+	for i, trade := range rebalance.Trades {
+		if err := checkFundOpen(s.fundRepo, trade.ToFundID); err != nil {
+			if errors.Is(err, ErrFundNotFound) {
+				return nil, nil, fmt.Errorf("fund %d: %w", trade.ToFundID, err)
+			}
+			return nil, nil, err
+		}
+		toPrice, exists := prices[trade.ToFundID]
+		if !exists {
+			if toPrice, err = s.latestPrice(trade.ToFundID, today); err != nil {
+				return nil, nil, err
+			}
+			prices[trade.ToFundID] = toPrice
+		}
+		fromPrice := prices[trade.FromFundID]
+
+		unitsSold := fromPrice.NAV.UnitsToSell(trade.Amount)
+		if unitsSold > held[trade.FromFundID] {
+			// The last trade out of a fund sells what is left of it, even if rounding asks for a fraction more,
+			// and only moves what those units are worth
+			unitsSold = held[trade.FromFundID]
+			trade.Amount = fromPrice.NAV.ValueOf(unitsSold, trade.Amount.Currency)
+			rebalance.Trades[i] = trade
+		}
+		held[trade.FromFundID] -= unitsSold
+
+		switches = append(switches, &model.Switch{
+			ClientID:   customerID,
+			FromFundID: trade.FromFundID,
+			ToFundID:   trade.ToFundID,
+			Sell: &model.Investment{
+				ClientID:  customerID,
+				FundID:    trade.FromFundID,
+				Amount:    model.NewMoney(-trade.Amount.Minor, trade.Amount.Currency),
+				Units:     -unitsSold,
+				Price:     fromPrice.NAV,
+				PriceDate: fromPrice.Date,
+			},
+			Buy: &model.Investment{
+				ClientID:  customerID,
+				FundID:    trade.ToFundID,
+				Amount:    trade.Amount,
+				Units:     toPrice.NAV.UnitsFor(trade.Amount),
+				Price:     toPrice.NAV,
+				PriceDate: toPrice.Date,
+			},
+		})
+	}
+	return rebalance, switches, nil
+}
+
+// latestPrice retrieves a fund's latest price on today, which must be in the default currency
+func (s *defaultRebalancingService) latestPrice(fundID uint, today model.Date) (*model.FundPrice, error) {
+	price, err := s.fundPriceRepo.GetLatestFundPrice(fundID, today)
+	if errors.Is(err, repository.ErrFundPriceNotFound) {
+		return nil, fmt.Errorf("%w: fund %d can't be dealt in until it has been priced", ErrFundNotPriced, fundID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if price.Currency != model.DefaultCurrency {
+		return nil, fmt.Errorf("fund %d is priced in %s, not %s", fundID, price.Currency, model.DefaultCurrency)
+	}
+	return price, nil
+}
+
+// today returns the clock's current date in UTC
+func (s *defaultRebalancingService) today() model.Date {
+	return model.DateOf(s.clock.Now().UTC())
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+)
+
+func TestDefaultRebalancingService_Rebalance(t *testing.T) {
+	gbp := func(minor int64) model.Money { return model.NewMoney(minor, model.DefaultCurrency) }
+	priceDate := model.NewDate(2026, time.October, 16)
+	prices := map[uint]*model.FundPrice{
+		1: {FundID: 1, Date: priceDate, NAV: 2500000, Currency: "GBP"},
+		3: {FundID: 3, Date: priceDate, NAV: 2000000, Currency: "GBP"},
+	}
+	allocation := &model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 1, Percentage: 60000}, {FundID: 3, Percentage: 40000}}}
+	holding := func(fundID uint, units model.Units) *model.Investment {
+		return &model.Investment{ClientID: 1, FundID: fundID, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusSettled, Units: units}
+	}
+	// 700.00 in fund 1 and 300.00 in fund 3, 10 percentage points away from 60/40
+	drifted := []*model.Investment{holding(1, 280000000), holding(3, 150000000)}
+
+	tests := []struct {
+		name        string
+		allocation  *model.TargetAllocation
+		investments []*model.Investment
+		prices      map[uint]*model.FundPrice
+		fundStatus  model.FundStatus
+		preview     bool
+		wantValue   model.Money
+		wantTrades  []model.RebalanceTrade
+		wantErr     error
+	}{
+		{
+			name:        "Drifted portfolio is rebalanced",
+			allocation:  allocation,
+			investments: drifted,
+			prices:      prices,
+			wantValue:   gbp(100000),
+			wantTrades:  []model.RebalanceTrade{{FromFundID: 1, ToFundID: 3, Amount: gbp(10000)}},
+		},
+		{
+			name:        "Preview makes no switches",
+			allocation:  allocation,
+			investments: drifted,
+			prices:      prices,
+			preview:     true,
+			wantValue:   gbp(100000),
+			wantTrades:  []model.RebalanceTrade{{FromFundID: 1, ToFundID: 3, Amount: gbp(10000)}},
+		},
+		{
+			name:        "Within tolerance",
+			allocation:  allocation,
+			investments: []*model.Investment{holding(1, 256000000), holding(3, 180000000)},
+			prices:      prices,
+			wantValue:   gbp(100000),
+		},
+		{
+			name:        "Cancelled transactions don't count",
+			allocation:  allocation,
+			investments: append([]*model.Investment{{ClientID: 1, FundID: 1, Status: model.InvestmentStatusCancelled, Units: 100000000}}, holding(1, 240000000), holding(3, 200000000)),
+			prices:      prices,
+			wantValue:   gbp(100000),
+		},
+		{
+			name:        "Target fund closed",
+			allocation:  allocation,
+			investments: drifted,
+			prices:      prices,
+			fundStatus:  model.FundStatusClosed,
+			wantErr:     ErrFundNotOpen,
+		},
+		{
+			name:        "Held fund not priced",
+			allocation:  allocation,
+			investments: drifted,
+			prices:      map[uint]*model.FundPrice{1: prices[1]},
+			wantErr:     ErrFundNotPriced,
+		},
+		{
+			name:        "No target allocation",
+			investments: drifted,
+			prices:      prices,
+			wantErr:     ErrAllocationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocationRepo := &mocks.AllocationRepository{MockAllocation: tt.allocation}
+			if tt.allocation == nil {
+				allocationRepo.MockErr = ErrAllocationNotFound
+			}
+			fundStatus := tt.fundStatus
+			if fundStatus == "" {
+				fundStatus = model.FundStatusOpen
+			}
+
+			service := NewDefaultRebalancingService(
+				&mocks.InvestmentRepository{MockInvestments: tt.investments, MockSwitch: &model.Switch{ID: 5}},
+				&mocks.CustomerRepository{MockCustomer: &model.Customer{ID: 1}},
+				&mocks.EmployerRepository{},
+				allocationRepo,
+				&mocks.FundRepository{MockFund: &model.Fund{ID: 3, Status: fundStatus}},
+				&pricesByFund{prices: tt.prices},
+				model.RebalancePolicy{Tolerance: 5000, MinimumTrade: gbp(2500)},
+				&mocks.Clock{MockNow: time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)},
+				model.TimeOfDay{Hour: 9},
+			)
+
+			var got *model.Rebalance
+			var err error
+			if tt.preview {
+				got, err = service.PreviewRebalance(1)
+			} else {
+				got, err = service.Rebalance(1)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Rebalance() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Rebalance() unexpected error = %v", err)
+			}
+			if got.Value != tt.wantValue || len(got.Trades) != len(tt.wantTrades) {
+				t.Fatalf("Rebalance() = %v with trades %v, want %v with trades %v", got.Value, got.Trades, tt.wantValue, tt.wantTrades)
+			}
+			for i, want := range tt.wantTrades {
+				if got.Trades[i] != want {
+					t.Errorf("trade %d = %+v, want %+v", i, got.Trades[i], want)
+				}
+			}
+
+			wantExecuted := !tt.preview && len(tt.wantTrades) > 0
+			if got.Executed != wantExecuted || (len(got.Switches) > 0) != wantExecuted {
+				t.Fatalf("Rebalance() executed = %v with %d switches, want executed %v", got.Executed, len(got.Switches), wantExecuted)
+			}
+			if wantExecuted {
+				// 100.00 sells 40 units of fund 1 at 2.50 and buys 50 units of fund 3 at 2.00
+				sw := got.Switches[0]
+				if sw.ID != 5 || sw.Sell.Units != -40000000 || sw.Sell.Amount != gbp(-10000) || sw.Buy.Units != 50000000 || sw.Buy.Amount != gbp(10000) {
+					t.Errorf("switch = %+v sell %+v buy %+v, want 40 units of fund 1 switched for 50 of fund 3", sw, sw.Sell, sw.Buy)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultRebalancingService_Rebalance_SellsWhatIsLeft(t *testing.T) {
+	gbp := func(minor int64) model.Money { return model.NewMoney(minor, model.DefaultCurrency) }
+	priceDate := model.NewDate(2026, time.October, 16)
+	// 100.01 units of fund 1 at 3.00 are worth 300.03, split into 150.02 for fund 3 and 150.01 for fund 2. Selling
+	// 150.02 rounds up to 50.006667 units, which leaves 50.003333 units worth 150.00 for the second trade.
+	service := NewDefaultRebalancingService(
+		&mocks.InvestmentRepository{
+			MockInvestments: []*model.Investment{{ClientID: 1, FundID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusSettled, Units: 100010000}},
+			MockSwitch:      &model.Switch{ID: 5},
+		},
+		&mocks.CustomerRepository{MockCustomer: &model.Customer{ID: 1}},
+		&mocks.EmployerRepository{},
+		&mocks.AllocationRepository{MockAllocation: &model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 2, Percentage: 50000}, {FundID: 3, Percentage: 50000}}}},
+		&mocks.FundRepository{MockFund: &model.Fund{Status: model.FundStatusOpen}},
+		&pricesByFund{prices: map[uint]*model.FundPrice{
+			1: {FundID: 1, Date: priceDate, NAV: 3000000, Currency: "GBP"},
+			2: {FundID: 2, Date: priceDate, NAV: 2000000, Currency: "GBP"},
+			3: {FundID: 3, Date: priceDate, NAV: 2000000, Currency: "GBP"},
+		}},
+		model.RebalancePolicy{Tolerance: 5000, MinimumTrade: gbp(100)},
+		&mocks.Clock{MockNow: time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)},
+		model.TimeOfDay{Hour: 9},
+	)
+
+	got, err := service.Rebalance(1)
+	if err != nil {
+		t.Fatalf("Rebalance() unexpected error = %v", err)
+	}
+	if len(got.Trades) != 2 || len(got.Switches) != 2 {
+		t.Fatalf("Rebalance() trades = %+v with %d switches, want 2 of each", got.Trades, len(got.Switches))
+	}
+	if got.Trades[1].Amount != gbp(15000) {
+		t.Errorf("last trade = %+v, want it to move the 150.00 the rest of fund 1 is worth", got.Trades[1])
+	}
+	sw := got.Switches[1]
+	if sw.Sell.Units != -50003333 || sw.Sell.Amount != gbp(-15000) || sw.Buy.Amount != gbp(15000) || sw.Buy.Units != 75000000 {
+		t.Errorf("last switch sell %+v buy %+v, want the rest of fund 1 sold for 150.00 and 75 units of fund 2 bought", sw.Sell, sw.Buy)
+	}
+}
+
+func TestDefaultRebalancingService_RebalanceAll(t *testing.T) {
+	runErr := errors.New("holdings unavailable")
+	service := NewDefaultRebalancingService(
+		&mocks.InvestmentRepository{MockErr: runErr},
+		&mocks.CustomerRepository{MockCustomer: &model.Customer{ID: 1}},
+		&mocks.EmployerRepository{},
+		&mocks.AllocationRepository{
+			MockAllocation:  &model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}}},
+			MockAllocations: []*model.TargetAllocation{{CustomerID: 1}, {CustomerID: 2}},
+		},
+		&mocks.FundRepository{},
+		&pricesByFund{},
+		model.RebalancePolicy{},
+		&mocks.Clock{},
+		model.TimeOfDay{Hour: 9},
+	)
+
+	got, err := service.RebalanceAll()
+	if err != nil {
+		t.Fatalf("RebalanceAll() unexpected error = %v", err)
+	}
+	if len(got.Rebalanced) != 0 || len(got.Failed) != 2 || got.Failed[1].CustomerID != 2 || got.Failed[1].Error != runErr.Error() {
+		t.Errorf("RebalanceAll() = %+v, want both customers reported as failures", got)
+	}
+}
+
+func TestDefaultRebalancingService_RunDue(t *testing.T) {
+	tests := []struct {
+		name    string
+		now     []time.Time
+		wantRun []bool
+	}{
+		{
+			name:    "Weekday after the rebalance time",
+			now:     []time.Time{time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)},
+			wantRun: []bool{true},
+		},
+		{
+			name:    "Weekday before the rebalance time",
+			now:     []time.Time{time.Date(2026, time.October, 16, 8, 59, 0, 0, time.UTC)},
+			wantRun: []bool{false},
+		},
+		{
+			name:    "Weekend",
+			now:     []time.Time{time.Date(2026, time.October, 17, 15, 0, 0, 0, time.UTC)},
+			wantRun: []bool{false},
+		},
+		{
+			name: "Once a day",
+			now: []time.Time{
+				time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, time.October, 16, 9, 1, 0, 0, time.UTC),
+				time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC),
+			},
+			wantRun: []bool{true, false, true},
+		},
+	}
+
+	t.Run("Failed run is retried", func(t *testing.T) {
+		runErr := errors.New("allocations unavailable")
+		allocationRepo := &mocks.AllocationRepository{MockErr: runErr}
+		service := NewDefaultRebalancingService(
+			&mocks.InvestmentRepository{},
+			&mocks.CustomerRepository{},
+			&mocks.EmployerRepository{},
+			allocationRepo,
+			&mocks.FundRepository{},
+			&pricesByFund{},
+			model.RebalancePolicy{},
+			&mocks.Clock{},
+			model.TimeOfDay{Hour: 9},
+		)
+
+		now := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+		if err := service.RunDue(now); !errors.Is(err, runErr) {
+			t.Fatalf("RunDue() error = %v, want %v", err, runErr)
+		}
+		allocationRepo.MockErr = nil
+		if err := service.RunDue(now.Add(time.Minute)); err != nil {
+			t.Errorf("RunDue() error = %v, want the run to be retried and succeed", err)
+		}
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Customer 1's holdings can't be read, so any run shows up as an error
+			runErr := errors.New("rebalancing ran")
+			service := NewDefaultRebalancingService(
+				&mocks.InvestmentRepository{MockErr: runErr},
+				&mocks.CustomerRepository{MockCustomer: &model.Customer{ID: 1}},
+				&mocks.EmployerRepository{},
+				&mocks.AllocationRepository{
+					MockAllocation:  &model.TargetAllocation{CustomerID: 1},
+					MockAllocations: []*model.TargetAllocation{{CustomerID: 1}},
+				},
+				&mocks.FundRepository{},
+				&pricesByFund{},
+				model.RebalancePolicy{},
+				&mocks.Clock{},
+				model.TimeOfDay{Hour: 9},
+			)
+
+			for i, now := range tt.now {
+				err := service.RunDue(now)
+				if ran := err != nil && strings.Contains(err.Error(), runErr.Error()); ran != tt.wantRun[i] {
+					t.Errorf("RunDue(%v) ran = %v, want %v (error %v)", now, ran, tt.wantRun[i], err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"cushon/internal/clock"
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"errors"
+	"fmt"
+)
+
+// Returns defines the interface for measuring how funds and customers' investments have performed
+type Returns interface {
+	GetFundReturns(fundID uint) (*model.FundReturns, error)
+	GetCustomerReturns(customerID uint) (*model.CustomerReturns, error)
+}
+
+// defaultReturnsService is a concrete implementation of Returns
+type defaultReturnsService struct {
+	customerRepo   repository.CustomerRepository
+	investmentRepo repository.InvestmentRepository
+	fundRepo       repository.FundRepository
+	fundPriceRepo  repository.FundPriceRepository
+	clock          clock.Clock
+}
+
+// NewDefaultReturnsService creates a new default returns service that measures periods ending on c's date
+func NewDefaultReturnsService(customerRepo repository.CustomerRepository, investmentRepo repository.InvestmentRepository, fundRepo repository.FundRepository, fundPriceRepo repository.FundPriceRepository, c clock.Clock) *defaultReturnsService {
+	return &defaultReturnsService{
+		customerRepo:   customerRepo,
+		investmentRepo: investmentRepo,
+		fundRepo:       fundRepo,
+		fundPriceRepo:  fundPriceRepo,
+		clock:          c,
+	}
+}
+
+// GetFundReturns works out a fund's time-weighted return over each standard period from its price history. Each
+// period runs from the fund's price on its start date, or the last one before it, to its latest price. Periods
+// that start before the fund's first price are measured from that price instead and reported as partial.
+func (s *defaultReturnsService) GetFundReturns(fundID uint) (*model.FundReturns, error) {
+	fund, err := s.fundRepo.GetFundByID(fundID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := s.today()
+	prices, err := s.fundPriceRepo.ListFundPrices(fundID, model.FundPriceFilter{To: &today})
+	if err != nil {
+		return nil, err
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("%w: fund %d has no price history to calculate returns from", ErrFundNotPriced, fundID)
+	}
+	latest := prices[len(prices)-1]
+
+	returns := &model.FundReturns{FundID: fund.ID, FundName: fund.Name, Returns: make([]model.PeriodReturn, 0, len(model.ReturnPeriods))}
+	for _, period := range model.ReturnPeriods {
+		start, partial := period.Start(today, prices[0].Date)
+		growth := model.TimeWeightedReturn(priceOn(prices, start), latest)
+		returns.Returns = append(returns.Returns, model.NewPeriodReturn(period, start, today, partial, growth))
+	}
+	return returns, nil
+}
+
+// GetCustomerReturns works out a customer's money-weighted return over each standard period from the money they
+// paid in and took out. Each period starts with the portfolio's value on its start date; the investments and
+// withdrawals priced after it are its cash flows, and it ends with the portfolio's value at the latest prices.
+// Switches move money between funds without paying any in or out, so they only change the portfolio's value.
+// Periods that start before the customer's first investment are measured from it instead and reported as
+// partial, and periods in which nothing was invested have no return and are left out.
+func (s *defaultReturnsService) GetCustomerReturns(customerID uint) (*model.CustomerReturns, error) {
+	if _, err := s.customerRepo.GetCustomerByID(customerID); err != nil {
+		return nil, err
+	}
+
+	investments, err := s.investmentRepo.GetInvestmentsByClientID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	var held []*model.Investment
+	for _, investment := range investments {
+		if investment.Status.HoldsUnits() {
+			held = append(held, investment)
+		}
+	}
+	if len(held) == 0 {
+		return nil, fmt.Errorf("%w: customer %d", ErrNoReturnHistory, customerID)
+	}
+	inception := held[0].PriceDate
+	for _, investment := range held {
+		if investment.PriceDate.Before(inception.Time) {
+			inception = investment.PriceDate
+		}
+	}
+
+	today := s.today()
+	endValue, err := s.valueOn(held, today)
+	if err != nil {
+		return nil, err
+	}
+
+	returns := &model.CustomerReturns{CustomerID: customerID, Value: endValue, Returns: make([]model.PeriodReturn, 0, len(model.ReturnPeriods))}
+	for _, period := range model.ReturnPeriods {
+		start, partial := period.Start(today, inception)
+		startValue, err := s.valueOn(held, start)
+		if err != nil {
+			return nil, err
+		}
+		var flows []model.CashFlow
+		for _, investment := range held {
+			isFlow := investment.Type == model.TransactionTypeInvestment || investment.Type == model.TransactionTypeWithdrawal
+			if isFlow && investment.PriceDate.After(start.Time) {
+				flows = append(flows, model.CashFlow{Date: investment.PriceDate, Amount: investment.Amount})
+			}
+		}
+
+		growth, err := model.MoneyWeightedReturn(startValue, start, flows, endValue, today)
+		if errors.Is(err, model.ErrNoReturn) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		returns.Returns = append(returns.Returns, model.NewPeriodReturn(period, start, today, partial, growth))
+	}
+	return returns, nil
+}
+
+// valueOn values the units the investments priced on or before date held, at each fund's price on that date or
+// the last one before it
+func (s *defaultReturnsService) valueOn(investments []*model.Investment, date model.Date) (model.Money, error) {
+	units := make(map[uint]model.Units)
+	for _, investment := range investments {
+		if !investment.PriceDate.After(date.Time) {
+			units[investment.FundID] += investment.Units
+		}
+	}
+
+	value := model.NewMoney(0, model.DefaultCurrency)
+	for fundID, held := range units {
+		if held == 0 {
+			continue
+		}
+		price, err := s.fundPriceRepo.GetLatestFundPrice(fundID, date)
+		if errors.Is(err, repository.ErrFundPriceNotFound) {
+			// Units are only ever bought at a price, so there is one on or before any date they are held on
+			continue
+		}
+		if err != nil {
+			return model.Money{}, err
+		}
+		if value, err = value.Add(price.NAV.ValueOf(held, model.DefaultCurrency)); err != nil {
+			return model.Money{}, err
+		}
+	}
+	return value, nil
+}
+
+// today returns the clock's current date in UTC
+func (s *defaultReturnsService) today() model.Date {
+	return model.DateOf(s.clock.Now().UTC())
+}
+
+// priceOn returns the last of prices, ordered by date, on or before date. The first price must be on or before it.
+func priceOn(prices []*model.FundPrice, date model.Date) *model.FundPrice {
+	price := prices[0]
+	for _, candidate := range prices[1:] {
+		if candidate.Date.After(date.Time) {
+			break
+		}
+		price = candidate
+	}
+	return price
+}
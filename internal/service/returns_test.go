@@ -0,0 +1,249 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+)
+
+// returnsToday is the date the mock clock tells in the returns tests
+var returnsToday = model.NewDate(2027, time.March, 31)
+
+func TestDefaultReturnsService_GetFundReturns(t *testing.T) {
+	price := func(date model.Date, nav model.Price) *model.FundPrice {
+		return &model.FundPrice{FundID: 1, Date: date, NAV: nav, Currency: model.DefaultCurrency}
+	}
+	type want struct {
+		start   model.Date
+		partial bool
+		ret     model.Percent
+	}
+
+	tests := []struct {
+		name    string
+		prices  []*model.FundPrice
+		fundErr error
+		want    []want
+		wantErr error
+	}{
+		{
+			// The fund grows 10% a quarter; the last price before the end of February is on Friday the 26th
+			name: "Full history",
+			prices: []*model.FundPrice{
+				price(model.NewDate(2026, time.March, 31), 1000000),
+				price(model.NewDate(2026, time.December, 31), 1100000),
+				price(model.NewDate(2027, time.February, 26), 1210000),
+				price(returnsToday, 1331000),
+			},
+			want: []want{
+				{start: model.NewDate(2027, time.February, 28), ret: 10000},
+				{start: model.NewDate(2026, time.December, 31), ret: 21000},
+				{start: model.NewDate(2026, time.December, 31), ret: 21000},
+				{start: model.NewDate(2026, time.March, 31), ret: 33100},
+				{start: model.NewDate(2026, time.March, 31), ret: 33100},
+			},
+		},
+		{
+			name: "Launched this year",
+			prices: []*model.FundPrice{
+				price(model.NewDate(2027, time.January, 15), 2000000),
+				price(model.NewDate(2027, time.March, 30), 2500000),
+			},
+			want: []want{
+				{start: model.NewDate(2027, time.February, 28), ret: 25000},
+				{start: model.NewDate(2027, time.January, 15), partial: true, ret: 25000},
+				{start: model.NewDate(2027, time.January, 15), partial: true, ret: 25000},
+				{start: model.NewDate(2027, time.January, 15), partial: true, ret: 25000},
+				{start: model.NewDate(2027, time.January, 15), ret: 25000},
+			},
+		},
+		{
+			name:    "Never priced",
+			wantErr: ErrFundNotPriced,
+		},
+		{
+			name:    "Unknown fund",
+			fundErr: ErrFundNotFound,
+			wantErr: ErrFundNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewDefaultReturnsService(
+				&mocks.CustomerRepository{},
+				&mocks.InvestmentRepository{},
+				&mocks.FundRepository{MockFund: &model.Fund{ID: 1, Name: "Fund1"}, MockErr: tt.fundErr},
+				&mocks.FundPriceRepository{MockPrices: tt.prices},
+				&mocks.Clock{MockNow: returnsToday.Add(10 * time.Hour)},
+			)
+
+			got, err := service.GetFundReturns(1)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetFundReturns() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetFundReturns() unexpected error = %v", err)
+			}
+			if len(got.Returns) != len(tt.want) {
+				t.Fatalf("GetFundReturns() returned %d periods, want %d", len(got.Returns), len(tt.want))
+			}
+			for i, want := range tt.want {
+				period := got.Returns[i]
+				if period.Period != model.ReturnPeriods[i] || period.StartDate != want.start || period.EndDate != returnsToday ||
+					period.Partial != want.partial || period.Return != want.ret {
+					t.Errorf("GetFundReturns() %s = %+v, want %+v", model.ReturnPeriods[i], period, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultReturnsService_GetCustomerReturns(t *testing.T) {
+	gbp := func(minor int64) model.Money { return model.NewMoney(minor, model.DefaultCurrency) }
+	// The history starts two years before today, with prices a year apart
+	inception := returnsToday.AddDays(-730)
+	oneYearAgo := returnsToday.AddDays(-365)
+	investment := func(fundID uint, kind model.TransactionType, date model.Date, amount int64, units model.Units) *model.Investment {
+		return &model.Investment{ClientID: 1, FundID: fundID, Type: kind, Status: model.InvestmentStatusSettled, Amount: gbp(amount), Units: units, PriceDate: date}
+	}
+	// Fund 1 grows 10% a year; fund 2 doubles and then halves
+	prices := []*model.FundPrice{
+		{FundID: 1, Date: inception, NAV: 1000000},
+		{FundID: 2, Date: inception, NAV: 1000000},
+		{FundID: 1, Date: oneYearAgo, NAV: 1100000},
+		{FundID: 2, Date: oneYearAgo, NAV: 2000000},
+		{FundID: 1, Date: returnsToday, NAV: 1210000},
+		{FundID: 2, Date: returnsToday, NAV: 1000000},
+	}
+	type want struct {
+		period     model.ReturnPeriod
+		ret        model.Percent
+		annualised model.Percent
+	}
+
+	tests := []struct {
+		name        string
+		investments []*model.Investment
+		customerErr error
+		wantValue   int64
+		want        []want
+		wantErr     error
+	}{
+		{
+			// Half the units are withdrawn after a year, so 1000 grows into 550 taken out and 605 left: 10% a year
+			name: "Withdrawal",
+			investments: []*model.Investment{
+				investment(1, model.TransactionTypeInvestment, inception, 100000, 1000000000),
+				investment(1, model.TransactionTypeWithdrawal, oneYearAgo, -55000, -500000000),
+				{ClientID: 1, FundID: 1, Type: model.TransactionTypeInvestment, Status: model.InvestmentStatusFailed, Amount: gbp(99999), Units: 99999000000, PriceDate: oneYearAgo},
+			},
+			wantValue: 60500,
+			want: []want{
+				{period: model.ReturnPeriodOneMonth, ret: 10000},
+				{period: model.ReturnPeriodThreeMonths, ret: 10000},
+				{period: model.ReturnPeriodYearToDate, ret: 10000},
+				{period: model.ReturnPeriodOneYear, ret: 10000, annualised: 10000},
+				{period: model.ReturnPeriodSinceInception, ret: 21000, annualised: 10000},
+			},
+		},
+		{
+			// The fund's time-weighted return is 0 but twice as much was invested for its fall as for its rise:
+			// 1000g² + 1000g = 1500, so g = (√7 - 1) / 2
+			name: "Paid in before a fall",
+			investments: []*model.Investment{
+				investment(2, model.TransactionTypeInvestment, inception, 100000, 1000000000),
+				investment(2, model.TransactionTypeInvestment, oneYearAgo, 100000, 500000000),
+			},
+			wantValue: 150000,
+			want: []want{
+				{period: model.ReturnPeriodOneMonth, ret: -50000},
+				{period: model.ReturnPeriodThreeMonths, ret: -50000},
+				{period: model.ReturnPeriodYearToDate, ret: -50000},
+				{period: model.ReturnPeriodOneYear, ret: -50000, annualised: -50000},
+				{period: model.ReturnPeriodSinceInception, ret: -32288, annualised: -17712},
+			},
+		},
+		{
+			// Switching everything from fund 1 into fund 2 pays nothing in or out: 1000 becomes 550, g² = 0.55
+			name: "Switch",
+			investments: []*model.Investment{
+				investment(1, model.TransactionTypeInvestment, inception, 100000, 1000000000),
+				investment(1, model.TransactionTypeSwitchOut, oneYearAgo, -110000, -1000000000),
+				investment(2, model.TransactionTypeSwitchIn, oneYearAgo, 110000, 550000000),
+			},
+			wantValue: 55000,
+			want: []want{
+				{period: model.ReturnPeriodOneMonth, ret: -50000},
+				{period: model.ReturnPeriodThreeMonths, ret: -50000},
+				{period: model.ReturnPeriodYearToDate, ret: -50000},
+				{period: model.ReturnPeriodOneYear, ret: -50000, annualised: -50000},
+				{period: model.ReturnPeriodSinceInception, ret: -45000, annualised: -25838},
+			},
+		},
+		{
+			// Nothing has been invested since everything was withdrawn a year ago
+			name: "Fully withdrawn",
+			investments: []*model.Investment{
+				investment(1, model.TransactionTypeInvestment, inception, 100000, 1000000000),
+				investment(1, model.TransactionTypeWithdrawal, oneYearAgo, -110000, -1000000000),
+			},
+			want: []want{
+				{period: model.ReturnPeriodSinceInception, ret: 21000, annualised: 10000},
+			},
+		},
+		{
+			name:    "Not invested yet",
+			wantErr: ErrNoReturnHistory,
+		},
+		{
+			name:        "Unknown customer",
+			customerErr: ErrCustomerNotFound,
+			wantErr:     ErrCustomerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewDefaultReturnsService(
+				&mocks.CustomerRepository{MockCustomer: &model.Customer{ID: 1}, MockErr: tt.customerErr},
+				&mocks.InvestmentRepository{MockInvestments: tt.investments},
+				&mocks.FundRepository{},
+				&mocks.FundPriceRepository{MockPrices: prices},
+				&mocks.Clock{MockNow: returnsToday.Add(10 * time.Hour)},
+			)
+
+			got, err := service.GetCustomerReturns(1)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetCustomerReturns() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetCustomerReturns() unexpected error = %v", err)
+			}
+			if got.Value.Minor != tt.wantValue {
+				t.Errorf("GetCustomerReturns() value = %s, want %d", got.Value, tt.wantValue)
+			}
+			if len(got.Returns) != len(tt.want) {
+				t.Fatalf("GetCustomerReturns() = %+v, want %d periods", got.Returns, len(tt.want))
+			}
+			for i, want := range tt.want {
+				period := got.Returns[i]
+				if period.Period != want.period || period.Return != want.ret {
+					t.Errorf("GetCustomerReturns() %s return = %s, want %s", want.period, period.Return, want.ret)
+				}
+				if want.annualised != 0 && (period.Annualised == nil || *period.Annualised != want.annualised) {
+					t.Errorf("GetCustomerReturns() %s annualised = %v, want %s", want.period, period.Annualised, want.annualised)
+				}
+			}
+		})
+	}
+}
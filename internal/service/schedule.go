@@ -0,0 +1,321 @@
+package service
+
+import (
+	"cushon/internal/clock"
+	"cushon/internal/model"
+	"cushon/internal/repository"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Schedule defines the interface for managing customers' contribution schedules and paying them in
+type Schedule interface {
+	CreateSchedule(customerID uint, create model.ContributionScheduleCreate) (*model.ContributionSchedule, error)
+	GetSchedule(id uint) (*model.ContributionSchedule, error)
+	ListSchedules(customerID uint) ([]*model.ContributionSchedule, error)
+	PauseSchedule(id uint) (*model.ContributionSchedule, error)
+	ResumeSchedule(id uint) (*model.ContributionSchedule, error)
+	RunSchedules() (*model.ScheduleRun, error)
+}
+
+// defaultScheduleService is a concrete implementation of Schedule
+type defaultScheduleService struct {
+	repo              repository.ScheduleRepository
+	customerRepo      repository.CustomerRepository
+	employerRepo      repository.EmployerRepository
+	allocationRepo    repository.AllocationRepository
+	fundRepo          repository.FundRepository
+	investmentService Investment
+	clock             clock.Clock
+
+	// mu stops schedules being paused or resumed while their runs are paid in
+	mu sync.Mutex
+	// failedOn is the date each schedule's last failed run was tried, so the scheduler only retries it daily
+	failedOn map[uint]model.Date
+}
+
+// NewDefaultScheduleService creates a new default contribution schedule service that pays in through
+// investmentService and takes today's date from c
+func NewDefaultScheduleService(repo repository.ScheduleRepository, customerRepo repository.CustomerRepository, employerRepo repository.EmployerRepository, allocationRepo repository.AllocationRepository, fundRepo repository.FundRepository, investmentService Investment, c clock.Clock) *defaultScheduleService {
+	return &defaultScheduleService{
+		repo:              repo,
+		customerRepo:      customerRepo,
+		employerRepo:      employerRepo,
+		allocationRepo:    allocationRepo,
+		fundRepo:          fundRepo,
+		investmentService: investmentService,
+		clock:             c,
+		failedOn:          make(map[uint]model.Date),
+	}
+}
+
+// CreateSchedule sets up a contribution schedule for a customer. It can't start in the past, and pays into an
+// open fund or, when no fund is given, across the customer's target allocation, which they must already have.
+func (s *defaultScheduleService) CreateSchedule(customerID uint, create model.ContributionScheduleCreate) (*model.ContributionSchedule, error) {
+	if !create.Amount.IsPositive() {
+		return nil, fmt.Errorf("%w: amount must be greater than 0", ErrInvalidSchedule)
+	}
+	if create.Amount.Currency != model.DefaultCurrency {
+		return nil, fmt.Errorf("%w: contributions must be made in %s", ErrInvalidSchedule, model.DefaultCurrency)
+	}
+	if !create.Frequency.Valid() {
+		return nil, fmt.Errorf("%w: unknown frequency %q", ErrInvalidSchedule, create.Frequency)
+	}
+	if create.StartDate.IsZero() {
+		return nil, fmt.Errorf("%w: start date is required", ErrInvalidSchedule)
+	}
+	if create.StartDate.Before(s.today().Time) {
+		return nil, fmt.Errorf("%w: start date can't be in the past", ErrInvalidSchedule)
+	}
+	if create.EndDate != nil && create.EndDate.Before(create.StartDate.Time) {
+		return nil, fmt.Errorf("%w: end date is before the start date", ErrInvalidSchedule)
+	}
+
+	customer, err := s.customerRepo.GetCustomerByID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	if create.FundID != nil {
+		if err := checkFundOpen(s.fundRepo, *create.FundID); err != nil {
+			if errors.Is(err, ErrFundNotFound) {
+				return nil, fmt.Errorf("fund %d: %w", *create.FundID, err)
+			}
+			return nil, err
+		}
+	} else if _, err := targetAllocation(s.allocationRepo, s.employerRepo, customer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateSchedule(&model.ContributionSchedule{
+		CustomerID:  customerID,
+		FundID:      create.FundID,
+		Amount:      create.Amount,
+		Frequency:   create.Frequency,
+		StartDate:   create.StartDate,
+		EndDate:     create.EndDate,
+		Status:      model.ScheduleStatusActive,
+		NextRunDate: create.StartDate,
+	})
+}
+
+// GetSchedule retrieves a contribution schedule by ID
+func (s *defaultScheduleService) GetSchedule(id uint) (*model.ContributionSchedule, error) {
+	return s.repo.GetScheduleByID(id)
+}
+
+// ListSchedules retrieves a customer's contribution schedules
+func (s *defaultScheduleService) ListSchedules(customerID uint) ([]*model.ContributionSchedule, error) {
+	if _, err := s.customerRepo.GetCustomerByID(customerID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSchedules(model.ScheduleFilter{CustomerID: &customerID})
+}
+
+// PauseSchedule stops an active schedule paying in. Runs that fall while it is paused are skipped.
+func (s *defaultScheduleService) PauseSchedule(id uint) (*model.ContributionSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, err := s.repo.GetScheduleByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status != model.ScheduleStatusActive {
+		return nil, fmt.Errorf("%w: schedule %d is %s", ErrInvalidScheduleTransition, id, schedule.Status)
+	}
+	schedule.Status = model.ScheduleStatusPaused
+	return s.repo.UpdateSchedule(schedule)
+}
+
+// ResumeSchedule restarts a paused schedule from its next run on or after today, ending it if that is past its
+// end date
+func (s *defaultScheduleService) ResumeSchedule(id uint) (*model.ContributionSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, err := s.repo.GetScheduleByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status != model.ScheduleStatusPaused {
+		return nil, fmt.Errorf("%w: schedule %d is %s", ErrInvalidScheduleTransition, id, schedule.Status)
+	}
+	schedule.Resume(s.today())
+	return s.repo.UpdateSchedule(schedule)
+}
+
+// RunSchedules pays in every run of the active schedules that has fallen due by today, including runs missed
+// while the server was down, oldest first. A run rejected by a business rule, e.g. because it would breach the
+// customer's ISA allowance or its fund has closed, is reported as skipped and the schedule moves on to its next
+// run, and a schedule whose customer has been deleted ends. A schedule whose run can't be paid in for any other
+// reason is reported as a failure and tried again on the next run, without stopping the others.
+func (s *defaultScheduleService) RunSchedules() (*model.ScheduleRun, error) {
+	return s.run(s.today(), true)
+}
+
+// RunDue pays in the runs that have fallen due by now. It is meant to be run by the scheduler on every tick, so
+// schedules whose run failed are only retried the next day; the scheduler logs the failures.
+func (s *defaultScheduleService) RunDue(now time.Time) error {
+	run, err := s.run(model.DateOf(now.UTC()), false)
+	if err != nil {
+		return err
+	}
+	errs := make([]error, 0, len(run.Failed)+len(run.Skipped))
+	for _, failure := range run.Failed {
+		errs = append(errs, fmt.Errorf("schedule %d run on %s: %s", failure.ScheduleID, failure.RunDate, failure.Error))
+	}
+	for _, skipped := range run.Skipped {
+		errs = append(errs, fmt.Errorf("schedule %d run on %s skipped: %s", skipped.ScheduleID, skipped.RunDate, skipped.Error))
+	}
+	return errors.Join(errs...)
+}
+
+// run pays in the runs due by today. Schedules that have already failed today are left out unless retryFailed.
+func (s *defaultScheduleService) run(today model.Date, retryFailed bool) (*model.ScheduleRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	active := model.ScheduleStatusActive
+	schedules, err := s.repo.ListSchedules(model.ScheduleFilter{Status: &active})
+	if err != nil {
+		return nil, err
+	}
+
+	run := &model.ScheduleRun{Contributions: []*model.ScheduledContribution{}, Failed: []model.ScheduleFailure{}, Skipped: []model.ScheduleFailure{}}
+	for _, schedule := range schedules {
+		if !retryFailed && s.failedOn[schedule.ID] == today {
+			continue
+		}
+		for schedule.Due(today) {
+			if !s.runNext(run, schedule, today) {
+				break
+			}
+		}
+	}
+	return run, nil
+}
+
+// runNext pays in a schedule's next run and moves the schedule on, recording the outcome in run. A run rejected by
+// a business rule is skipped instead, and the schedule ends when its customer has been deleted. It reports whether
+// the schedule moved on, so its following run can be paid in too.
+func (s *defaultScheduleService) runNext(run *model.ScheduleRun, schedule *model.ContributionSchedule, today model.Date) bool {
+	runDate := schedule.NextRunDate
+	investments, err := s.payIn(schedule)
+	outcome := "paid in"
+	switch {
+	case errors.Is(err, ErrCustomerNotFound):
+		// The customer was deleted after setting up the schedule, so there is no one left to pay in for
+		run.Skipped = append(run.Skipped, scheduleFailure(schedule, runDate, fmt.Errorf("schedule ended: %w", err)))
+		schedule.Status = model.ScheduleStatusEnded
+		outcome = "ended"
+	case rejectsRun(err):
+		// Retrying won't get the run past the rule it breaks before later runs fall due, and paying it in late
+		// could count it towards the wrong tax year, so it is skipped
+		run.Skipped = append(run.Skipped, scheduleFailure(schedule, runDate, err))
+		schedule.Advance()
+		outcome = "skipped"
+	case err != nil:
+		s.failedOn[schedule.ID] = today
+		run.Failed = append(run.Failed, scheduleFailure(schedule, runDate, err))
+		return false
+	default:
+		run.Contributions = append(run.Contributions, &model.ScheduledContribution{
+			ScheduleID:  schedule.ID,
+			RunDate:     runDate,
+			Investments: investments,
+		})
+		schedule.Advance()
+	}
+
+	if _, err := s.repo.UpdateSchedule(schedule); err != nil {
+		if errors.Is(err, ErrScheduleNotFound) && schedule.Status == model.ScheduleStatusEnded {
+			// The schedule was deleted with its customer
+			return false
+		}
+		// Retrying the run only moves the schedule on, as a run that was paid in is never paid in again
+		s.failedOn[schedule.ID] = today
+		run.Failed = append(run.Failed, scheduleFailure(schedule, runDate, fmt.Errorf("%s but not moved on to the next run: %w", outcome, err)))
+		return false
+	}
+	delete(s.failedOn, schedule.ID)
+	return true
+}
+
+// rejectsRun reports whether err is a business rule rejecting a schedule's run, which retrying the same run won't
+// get past, rather than a failure that may clear, e.g. the database being unavailable
+func rejectsRun(err error) bool {
+	for _, rejection := range []error{
+		ErrISAAllowanceExceeded,
+		ErrFundNotOpen,
+		ErrFundNotFound,
+		ErrAllocationNotFound,
+		ErrEmployerInactive,
+		ErrContributionSourceNotAllowed,
+	} {
+		if errors.Is(err, rejection) {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleFailure reports why a schedule's run on runDate wasn't paid in
+func scheduleFailure(schedule *model.ContributionSchedule, runDate model.Date, err error) model.ScheduleFailure {
+	return model.ScheduleFailure{
+		ScheduleID: schedule.ID,
+		CustomerID: schedule.CustomerID,
+		RunDate:    runDate,
+		Error:      err.Error(),
+	}
+}
+
+// payIn invests a schedule's next run, into its fund or across the customer's target allocation. The investments
+// record the run, so a run an earlier attempt paid in without moving the schedule on isn't paid in again; its
+// investments are returned instead.
+func (s *defaultScheduleService) payIn(schedule *model.ContributionSchedule) ([]*model.Investment, error) {
+	key := &model.ScheduleRunKey{ScheduleID: schedule.ID, RunDate: schedule.NextRunDate}
+	paid, err := s.investmentService.ListInvestments(model.InvestmentFilter{Run: key})
+	if err != nil {
+		return nil, err
+	}
+	if len(paid) > 0 {
+		return paid, nil
+	}
+
+	investments, err := s.invest(schedule, key)
+	if errors.Is(err, ErrScheduleRunPaid) {
+		// Another server paid the run in since it was checked
+		return s.investmentService.ListInvestments(model.InvestmentFilter{Run: key})
+	}
+	return investments, err
+}
+
+// invest pays in one run of a schedule
+func (s *defaultScheduleService) invest(schedule *model.ContributionSchedule, key *model.ScheduleRunKey) ([]*model.Investment, error) {
+	if schedule.FundID == nil {
+		return s.investmentService.Contribute(model.ContributionCreate{
+			ClientID: schedule.CustomerID,
+			Amount:   schedule.Amount,
+			Source:   model.ContributionSourceRegular,
+			Run:      key,
+		})
+	}
+	investment, err := s.investmentService.NewInvestment(model.InvestmentCreate{
+		ClientID: schedule.CustomerID,
+		FundID:   *schedule.FundID,
+		Amount:   schedule.Amount,
+		Source:   model.ContributionSourceRegular,
+		Run:      key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []*model.Investment{investment}, nil
+}
+
+// today returns the clock's current date in UTC
+func (s *defaultScheduleService) today() model.Date {
+	return model.DateOf(s.clock.Now().UTC())
+}
@@ -0,0 +1,511 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cushon/internal/mocks"
+	"cushon/internal/model"
+)
+
+// scheduleToday is the date the mock clock tells in the contribution schedule tests
+var scheduleToday = model.NewDate(2026, time.October, 16)
+
+func TestDefaultScheduleService_CreateSchedule(t *testing.T) {
+	fundID := uint(1)
+	valid := func(change func(create *model.ContributionScheduleCreate)) model.ContributionScheduleCreate {
+		create := model.ContributionScheduleCreate{
+			FundID:    &fundID,
+			Amount:    model.NewMoney(10000, model.DefaultCurrency),
+			Frequency: model.ScheduleFrequencyMonthly,
+			StartDate: scheduleToday,
+		}
+		if change != nil {
+			change(&create)
+		}
+		return create
+	}
+
+	tests := []struct {
+		name        string
+		create      model.ContributionScheduleCreate
+		customerErr error
+		fundStatus  model.FundStatus
+		fundErr     error
+		allocation  *model.TargetAllocation
+		wantErr     error
+	}{
+		{
+			name:   "Into a fund",
+			create: valid(nil),
+		},
+		{
+			name:       "Across the customer's allocation",
+			create:     valid(func(create *model.ContributionScheduleCreate) { create.FundID = nil }),
+			allocation: &model.TargetAllocation{CustomerID: 1, Funds: []model.FundAllocation{{FundID: 1, Percentage: model.OneHundredPercent}}},
+		},
+		{
+			name:    "No allocation to invest across",
+			create:  valid(func(create *model.ContributionScheduleCreate) { create.FundID = nil }),
+			wantErr: ErrAllocationNotFound,
+		},
+		{
+			name: "Zero amount",
+			create: valid(func(create *model.ContributionScheduleCreate) {
+				create.Amount = model.NewMoney(0, model.DefaultCurrency)
+			}),
+			wantErr: ErrInvalidSchedule,
+		},
+		{
+			name:    "Foreign currency",
+			create:  valid(func(create *model.ContributionScheduleCreate) { create.Amount = model.NewMoney(10000, "USD") }),
+			wantErr: ErrInvalidSchedule,
+		},
+		{
+			name:    "Unknown frequency",
+			create:  valid(func(create *model.ContributionScheduleCreate) { create.Frequency = "fortnightly" }),
+			wantErr: ErrInvalidSchedule,
+		},
+		{
+			name:    "No start date",
+			create:  valid(func(create *model.ContributionScheduleCreate) { create.StartDate = model.Date{} }),
+			wantErr: ErrInvalidSchedule,
+		},
+		{
+			name:    "Start date in the past",
+			create:  valid(func(create *model.ContributionScheduleCreate) { create.StartDate = scheduleToday.AddDays(-1) }),
+			wantErr: ErrInvalidSchedule,
+		},
+		{
+			name: "End date before the start date",
+			create: valid(func(create *model.ContributionScheduleCreate) {
+				endDate := scheduleToday.AddDays(-1)
+				create.StartDate, create.EndDate = scheduleToday, &endDate
+			}),
+			wantErr: ErrInvalidSchedule,
+		},
+		{
+			name:        "Unknown customer",
+			create:      valid(nil),
+			customerErr: ErrCustomerNotFound,
+			wantErr:     ErrCustomerNotFound,
+		},
+		{
+			name:       "Closed fund",
+			create:     valid(nil),
+			fundStatus: model.FundStatusClosed,
+			wantErr:    ErrFundNotOpen,
+		},
+		{
+			name:    "Unknown fund",
+			create:  valid(nil),
+			fundErr: ErrFundNotFound,
+			wantErr: ErrFundNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fundStatus := tt.fundStatus
+			if fundStatus == "" {
+				fundStatus = model.FundStatusOpen
+			}
+			allocationRepo := &mocks.AllocationRepository{MockAllocation: tt.allocation}
+			if tt.allocation == nil {
+				allocationRepo.MockErr = ErrAllocationNotFound
+			}
+			service := NewDefaultScheduleService(
+				&mocks.ScheduleRepository{},
+				&mocks.CustomerRepository{MockCustomer: &model.Customer{ID: 1}, MockErr: tt.customerErr},
+				&mocks.EmployerRepository{},
+				allocationRepo,
+				&mocks.FundRepository{MockFund: &model.Fund{ID: 1, Status: fundStatus}, MockErr: tt.fundErr},
+				&mocks.InvestmentService{},
+				&mocks.Clock{MockNow: scheduleToday.Add(10 * time.Hour)},
+			)
+
+			got, err := service.CreateSchedule(1, tt.create)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateSchedule() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateSchedule() unexpected error = %v", err)
+			}
+			if got.CustomerID != 1 || got.Status != model.ScheduleStatusActive || got.NextRunDate != tt.create.StartDate {
+				t.Errorf("CreateSchedule() = %+v, want an active schedule first running on its start date", got)
+			}
+		})
+	}
+}
+
+func TestDefaultScheduleService_PauseResume(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     model.ScheduleStatus
+		pause      bool
+		wantStatus model.ScheduleStatus
+		wantErr    error
+	}{
+		{name: "Pause active", status: model.ScheduleStatusActive, pause: true, wantStatus: model.ScheduleStatusPaused},
+		{name: "Pause paused", status: model.ScheduleStatusPaused, pause: true, wantErr: ErrInvalidScheduleTransition},
+		{name: "Resume paused", status: model.ScheduleStatusPaused, wantStatus: model.ScheduleStatusActive},
+		{name: "Resume active", status: model.ScheduleStatusActive, wantErr: ErrInvalidScheduleTransition},
+		{name: "Resume ended", status: model.ScheduleStatusEnded, wantErr: ErrInvalidScheduleTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Paused since the start of the month, so resuming skips the runs that fell while it was paused
+			schedule := &model.ContributionSchedule{
+				ID:          1,
+				Frequency:   model.ScheduleFrequencyWeekly,
+				StartDate:   model.NewDate(2026, time.October, 1),
+				Status:      tt.status,
+				NextRunDate: model.NewDate(2026, time.October, 1),
+			}
+			service := NewDefaultScheduleService(
+				&mocks.ScheduleRepository{MockSchedule: schedule},
+				&mocks.CustomerRepository{},
+				&mocks.EmployerRepository{},
+				&mocks.AllocationRepository{},
+				&mocks.FundRepository{},
+				&mocks.InvestmentService{},
+				&mocks.Clock{MockNow: scheduleToday.Add(10 * time.Hour)},
+			)
+
+			var got *model.ContributionSchedule
+			var err error
+			if tt.pause {
+				got, err = service.PauseSchedule(1)
+			} else {
+				got, err = service.ResumeSchedule(1)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error = %v", err)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if !tt.pause && got.NextRunDate != model.NewDate(2026, time.October, 22) {
+				t.Errorf("NextRunDate = %s, want the first run after today", got.NextRunDate)
+			}
+		})
+	}
+}
+
+func TestDefaultScheduleService_RunSchedules(t *testing.T) {
+	fundID := uint(1)
+	// weekly returns a schedule into fund 1 whose next run is runsDue weeks before today's
+	weekly := func(runsDue int) *model.ContributionSchedule {
+		next := scheduleToday.AddDays(-7 * (runsDue - 1))
+		return &model.ContributionSchedule{
+			ID:          1,
+			CustomerID:  1,
+			FundID:      &fundID,
+			Amount:      model.NewMoney(10000, model.DefaultCurrency),
+			Frequency:   model.ScheduleFrequencyWeekly,
+			StartDate:   next,
+			Status:      model.ScheduleStatusActive,
+			NextRunDate: next,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		schedule      *model.ContributionSchedule
+		investErr     error
+		wantRunDates  []model.Date
+		wantNext      model.Date
+		wantFailed    bool
+		wantSkipped   []model.Date
+		wantEndStatus model.ScheduleStatus
+	}{
+		{
+			name:         "Due today",
+			schedule:     weekly(1),
+			wantRunDates: []model.Date{scheduleToday},
+			wantNext:     scheduleToday.AddDays(7),
+		},
+		{
+			name:         "Not due yet",
+			schedule:     weekly(0),
+			wantRunDates: []model.Date{},
+			wantNext:     scheduleToday.AddDays(7),
+		},
+		{
+			name:         "Runs missed while the server was down are caught up oldest first",
+			schedule:     weekly(3),
+			wantRunDates: []model.Date{scheduleToday.AddDays(-14), scheduleToday.AddDays(-7), scheduleToday},
+			wantNext:     scheduleToday.AddDays(7),
+		},
+		{
+			name: "Ends after its last run",
+			schedule: func() *model.ContributionSchedule {
+				schedule := weekly(2)
+				endDate := scheduleToday.AddDays(-3)
+				schedule.EndDate = &endDate
+				return schedule
+			}(),
+			wantRunDates:  []model.Date{scheduleToday.AddDays(-7)},
+			wantNext:      scheduleToday,
+			wantEndStatus: model.ScheduleStatusEnded,
+		},
+		{
+			name: "Paused",
+			schedule: func() *model.ContributionSchedule {
+				schedule := weekly(1)
+				schedule.Status = model.ScheduleStatusPaused
+				return schedule
+			}(),
+			wantRunDates:  []model.Date{},
+			wantNext:      scheduleToday,
+			wantEndStatus: model.ScheduleStatusPaused,
+		},
+		{
+			name:         "Investment fails and is retried",
+			schedule:     weekly(2),
+			investErr:    errors.New("database unavailable"),
+			wantRunDates: []model.Date{},
+			wantNext:     scheduleToday.AddDays(-7),
+			wantFailed:   true,
+		},
+		{
+			name:         "Runs over the ISA allowance are skipped",
+			schedule:     weekly(2),
+			investErr:    fmt.Errorf("%w: 0.00 GBP remaining", ErrISAAllowanceExceeded),
+			wantRunDates: []model.Date{},
+			wantSkipped:  []model.Date{scheduleToday.AddDays(-7), scheduleToday},
+			wantNext:     scheduleToday.AddDays(7),
+		},
+		{
+			name:         "Runs into a closed fund are skipped",
+			schedule:     weekly(1),
+			investErr:    ErrFundNotOpen,
+			wantRunDates: []model.Date{},
+			wantSkipped:  []model.Date{scheduleToday},
+			wantNext:     scheduleToday.AddDays(7),
+		},
+		{
+			name:          "Customer deleted",
+			schedule:      weekly(2),
+			investErr:     ErrCustomerNotFound,
+			wantRunDates:  []model.Date{},
+			wantSkipped:   []model.Date{scheduleToday.AddDays(-7)},
+			wantNext:      scheduleToday.AddDays(-7),
+			wantEndStatus: model.ScheduleStatusEnded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewDefaultScheduleService(
+				&mocks.ScheduleRepository{MockSchedules: []*model.ContributionSchedule{tt.schedule}},
+				&mocks.CustomerRepository{},
+				&mocks.EmployerRepository{},
+				&mocks.AllocationRepository{},
+				&mocks.FundRepository{},
+				&mocks.InvestmentService{MockInvestment: &model.Investment{ClientID: 1, FundID: 1}, MockErr: tt.investErr},
+				&mocks.Clock{MockNow: scheduleToday.Add(10 * time.Hour)},
+			)
+
+			run, err := service.RunSchedules()
+			if err != nil {
+				t.Fatalf("RunSchedules() error = %v", err)
+			}
+			if (len(run.Failed) > 0) != tt.wantFailed {
+				t.Fatalf("RunSchedules() failed = %+v, want failed %v", run.Failed, tt.wantFailed)
+			}
+			if len(run.Contributions) != len(tt.wantRunDates) {
+				t.Fatalf("RunSchedules() paid in %d runs, want %d", len(run.Contributions), len(tt.wantRunDates))
+			}
+			for i, contribution := range run.Contributions {
+				if contribution.RunDate != tt.wantRunDates[i] || len(contribution.Investments) != 1 {
+					t.Errorf("RunSchedules() contribution %d = %+v, want one investment for the run on %s", i, contribution, tt.wantRunDates[i])
+				}
+			}
+			skipped := []model.Date{}
+			for _, skip := range run.Skipped {
+				skipped = append(skipped, skip.RunDate)
+			}
+			if wantSkipped := append([]model.Date{}, tt.wantSkipped...); !reflect.DeepEqual(skipped, wantSkipped) {
+				t.Errorf("RunSchedules() skipped runs on %v, want %v", skipped, wantSkipped)
+			}
+			wantStatus := tt.wantEndStatus
+			if wantStatus == "" {
+				wantStatus = model.ScheduleStatusActive
+			}
+			if tt.schedule.NextRunDate != tt.wantNext || tt.schedule.Status != wantStatus {
+				t.Errorf("schedule = %s %s, want %s %s", tt.schedule.NextRunDate, tt.schedule.Status, tt.wantNext, wantStatus)
+			}
+		})
+	}
+}
+
+func TestDefaultScheduleService_RunDue(t *testing.T) {
+	fundID := uint(1)
+	schedule := &model.ContributionSchedule{
+		ID:          1,
+		CustomerID:  1,
+		FundID:      &fundID,
+		Amount:      model.NewMoney(10000, model.DefaultCurrency),
+		Frequency:   model.ScheduleFrequencyWeekly,
+		StartDate:   scheduleToday,
+		Status:      model.ScheduleStatusActive,
+		NextRunDate: scheduleToday,
+	}
+	investments := &mocks.InvestmentService{MockErr: ErrFundNotPriced}
+	clock := &mocks.Clock{}
+	service := NewDefaultScheduleService(
+		&mocks.ScheduleRepository{MockSchedules: []*model.ContributionSchedule{schedule}},
+		&mocks.CustomerRepository{},
+		&mocks.EmployerRepository{},
+		&mocks.AllocationRepository{},
+		&mocks.FundRepository{},
+		investments,
+		clock,
+	)
+
+	now := scheduleToday.Add(time.Hour)
+	if err := service.RunDue(now); err == nil || !strings.Contains(err.Error(), "schedule 1") {
+		t.Fatalf("RunDue() error = %v, want schedule 1's failure", err)
+	}
+	// A failed run is only retried by the scheduler the next day, but can be retried by hand straight away
+	investments.MockErr = nil
+	investments.MockInvestment = &model.Investment{ClientID: 1, FundID: 1}
+	if err := service.RunDue(now.Add(time.Minute)); err != nil || schedule.NextRunDate != scheduleToday {
+		t.Errorf("RunDue() = %v with next run %s, want the failed run left until tomorrow", err, schedule.NextRunDate)
+	}
+	clock.Set(now.Add(time.Minute))
+	if run, err := service.RunSchedules(); err != nil || len(run.Contributions) != 1 {
+		t.Fatalf("RunSchedules() = %+v, %v, want the failed run retried", run, err)
+	}
+
+	// The next run falls on a Friday and is missed until the server comes back on Monday
+	next := scheduleToday.AddDays(7)
+	if err := service.RunDue(next.AddDays(3).Add(time.Hour)); err != nil {
+		t.Fatalf("RunDue() error = %v", err)
+	}
+	if schedule.NextRunDate != next.AddDays(7) {
+		t.Errorf("NextRunDate = %s, want %s after catching up the missed run", schedule.NextRunDate, next.AddDays(7))
+	}
+}
+
+// flakySchedules is a schedule repository mock holding one schedule, which it fails to save while failUpdates is set
+type flakySchedules struct {
+	mocks.ScheduleRepository
+	schedule    model.ContributionSchedule
+	failUpdates bool
+}
+
+// ListSchedules returns a copy of the schedule
+func (r *flakySchedules) ListSchedules(filter model.ScheduleFilter) ([]*model.ContributionSchedule, error) {
+	schedule := r.schedule
+	return []*model.ContributionSchedule{&schedule}, nil
+}
+
+// UpdateSchedule saves the schedule unless failUpdates is set
+func (r *flakySchedules) UpdateSchedule(schedule *model.ContributionSchedule) (*model.ContributionSchedule, error) {
+	if r.failUpdates {
+		return nil, errors.New("connection lost")
+	}
+	r.schedule = *schedule
+	return schedule, nil
+}
+
+// ledger is an investment service mock that keeps the investments it makes and lists them by filter
+type ledger struct {
+	mocks.InvestmentService
+	investments []*model.Investment
+}
+
+// NewInvestment stores an investment recording the schedule run it pays in
+func (l *ledger) NewInvestment(create model.InvestmentCreate) (*model.Investment, error) {
+	investment := &model.Investment{
+		ID:       uint(len(l.investments) + 1),
+		ClientID: create.ClientID,
+		FundID:   create.FundID,
+		Source:   create.Source,
+		Amount:   create.Amount,
+	}
+	if create.Run != nil {
+		runDate := create.Run.RunDate
+		investment.ScheduleID, investment.RunDate = create.Run.ScheduleID, &runDate
+	}
+	l.investments = append(l.investments, investment)
+	return investment, nil
+}
+
+// ListInvestments returns the investments matching filter
+func (l *ledger) ListInvestments(filter model.InvestmentFilter) ([]*model.Investment, error) {
+	matching := make([]*model.Investment, 0)
+	for _, investment := range l.investments {
+		if filter.Matches(investment) {
+			matching = append(matching, investment)
+		}
+	}
+	return matching, nil
+}
+
+func TestDefaultScheduleService_RunSchedules_PaidInButNotMovedOn(t *testing.T) {
+	fundID := uint(1)
+	schedules := &flakySchedules{
+		schedule: model.ContributionSchedule{
+			ID:          1,
+			CustomerID:  1,
+			FundID:      &fundID,
+			Amount:      model.NewMoney(10000, model.DefaultCurrency),
+			Frequency:   model.ScheduleFrequencyWeekly,
+			StartDate:   scheduleToday,
+			Status:      model.ScheduleStatusActive,
+			NextRunDate: scheduleToday,
+		},
+		failUpdates: true,
+	}
+	investments := &ledger{}
+	service := NewDefaultScheduleService(
+		schedules,
+		&mocks.CustomerRepository{},
+		&mocks.EmployerRepository{},
+		&mocks.AllocationRepository{},
+		&mocks.FundRepository{},
+		investments,
+		&mocks.Clock{MockNow: scheduleToday.Add(10 * time.Hour)},
+	)
+
+	run, err := service.RunSchedules()
+	if err != nil {
+		t.Fatalf("RunSchedules() error = %v", err)
+	}
+	if len(run.Contributions) != 1 || len(run.Failed) != 1 || schedules.schedule.NextRunDate != scheduleToday {
+		t.Fatalf("RunSchedules() = %+v with next run %s, want the run paid in but reported as failed", run, schedules.schedule.NextRunDate)
+	}
+
+	// Retrying finds the run already paid in and only moves the schedule on
+	schedules.failUpdates = false
+	run, err = service.RunSchedules()
+	if err != nil {
+		t.Fatalf("RunSchedules() error = %v", err)
+	}
+	if len(run.Failed) != 0 || len(run.Contributions) != 1 || run.Contributions[0].Investments[0].ID != 1 {
+		t.Errorf("RunSchedules() = %+v, want the run's first investment reported", run)
+	}
+	if len(investments.investments) != 1 {
+		t.Errorf("paid in %d investments, want the run paid in once", len(investments.investments))
+	}
+	if schedules.schedule.NextRunDate != scheduleToday.AddDays(7) {
+		t.Errorf("NextRunDate = %s, want %s", schedules.schedule.NextRunDate, scheduleToday.AddDays(7))
+	}
+}
@@ -11,18 +11,30 @@ class APIClient:
             "X-API-Key": api_key
         }
 
-    def make_request(self, method: str, endpoint: str, data: Dict[str, Any] = None) -> Dict[str, Any]:
+    def make_request(self, method: str, endpoint: str, data: Dict[str, Any] = None,
+                     idempotency_key: Optional[str] = None) -> Dict[str, Any]:
         url = f"{self.base_url}/api{endpoint}"
+        headers = dict(self.headers)
+        if idempotency_key is not None:
+            headers["Idempotency-Key"] = idempotency_key
         
         try:
             if method == "GET":
-                response = requests.get(url, headers=self.headers, verify=False)
+                response = requests.get(url, headers=headers, verify=False)
             elif method == "POST":
-                response = requests.post(url, json=data, headers=self.headers, verify=False)
+                response = requests.post(url, json=data, headers=headers, verify=False)
+            elif method == "PATCH":
+                response = requests.patch(url, json=data, headers=headers, verify=False)
+            elif method == "PUT":
+                response = requests.put(url, json=data, headers=headers, verify=False)
+            elif method == "DELETE":
+                response = requests.delete(url, headers=headers, verify=False)
             else:
                 raise ValueError(f"Unsupported HTTP method: {method}")
             
             response.raise_for_status()
+            if response.status_code == 204:
+                return None
             return response.json()
         except requests.exceptions.RequestException as e:
             print(f"Error making request to {url}: {e}")
@@ -30,33 +42,240 @@ class APIClient:
                 print(f"Response: {e.response.text}")
             raise
 
-    def create_customer(self, name: str, employer_id: Optional[int] = None) -> Dict[str, Any]:
+    def create_customer(self, name: str, employer_id: Optional[int] = None,
+                        idempotency_key: Optional[str] = None) -> Dict[str, Any]:
         data = {"name": name}
         if employer_id is not None:
             data["employer_id"] = employer_id
-        return self.make_request("POST", "/customers", data)
+        return self.make_request("POST", "/customers", data, idempotency_key)
 
-    def create_fund(self, name: str) -> Dict[str, Any]:
-        data = {"name": name}
-        return self.make_request("POST", "/funds", data)
+    def get_customer(self, customer_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/customers/{customer_id}")
+
+    def get_customers_by_employer(self, employer_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/customers?employer_id={employer_id}")
+
+    def update_customer(self, customer_id: int, **fields: Any) -> Dict[str, Any]:
+        return self.make_request("PATCH", f"/customers/{customer_id}", fields)
+
+    def get_portfolio(self, customer_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/customers/{customer_id}/portfolio")
+
+    def get_isa_allowance(self, customer_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/customers/{customer_id}/isa-allowance")
+
+    def get_allocation(self, customer_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/customers/{customer_id}/allocation")
+
+    def set_allocation(self, customer_id: int, funds: Dict[int, str]) -> Dict[str, Any]:
+        data = {"funds": [{"fund_id": fund_id, "percentage": percentage} for fund_id, percentage in funds.items()]}
+        return self.make_request("PUT", f"/customers/{customer_id}/allocation", data)
+
+    def delete_allocation(self, customer_id: int) -> None:
+        self.make_request("DELETE", f"/customers/{customer_id}/allocation")
+
+    def preview_rebalance(self, customer_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/customers/{customer_id}/rebalance")
+
+    def rebalance(self, customer_id: int) -> Dict[str, Any]:
+        return self.make_request("POST", f"/customers/{customer_id}/rebalance")
+
+    def create_fund(self, name: str, isin: str, asset_class: str, risk_rating: int,
+                    ongoing_charges: str = "0", currency: str = "GBP", description: str = "",
+                    idempotency_key: Optional[str] = None) -> Dict[str, Any]:
+        data = {
+            "name": name,
+            "isin": isin,
+            "asset_class": asset_class,
+            "risk_rating": risk_rating,
+            "ongoing_charges": ongoing_charges,
+            "currency": currency,
+            "description": description,
+        }
+        return self.make_request("POST", "/funds", data, idempotency_key)
+
+    def get_all_funds(self, **filters: Any) -> Dict[str, Any]:
+        query = "&".join(f"{key}={value}" for key, value in filters.items())
+        return self.make_request("GET", f"/funds?{query}" if query else "/funds")
 
-    def get_all_funds(self) -> Dict[str, Any]:
-        return self.make_request("GET", "/funds")
+    def get_fund(self, fund_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/funds/{fund_id}")
 
-    def create_investment(self, client_id: int, fund_id: int, amount: float) -> Dict[str, Any]:
+    def close_fund(self, fund_id: int) -> Dict[str, Any]:
+        return self.make_request("POST", f"/funds/{fund_id}/close")
+
+    def add_fund_price(self, fund_id: int, date: str, nav: str) -> Dict[str, Any]:
+        data = {"date": date, "nav": nav}
+        return self.make_request("POST", f"/funds/{fund_id}/prices", data)
+
+    def get_fund_prices(self, fund_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/funds/{fund_id}/prices")
+
+    def get_latest_fund_price(self, fund_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/funds/{fund_id}/prices/latest")
+
+    def create_investment(self, client_id: int, fund_id: int, amount: str, currency: str = "GBP",
+                          source: Optional[str] = None, idempotency_key: Optional[str] = None) -> Dict[str, Any]:
+        data = {
+            "client_id": client_id,
+            "fund_id": fund_id,
+            "amount": {"amount": amount, "currency": currency}
+        }
+        if source is not None:
+            data["source"] = source
+        return self.make_request("POST", "/investments", data, idempotency_key)
+
+    def salary_contribution(self, client_id: int, fund_id: int, salary: str,
+                            employee_rate: Optional[str] = None, currency: str = "GBP") -> Dict[str, Any]:
         data = {
             "client_id": client_id,
             "fund_id": fund_id,
-            "amount": amount
+            "salary": {"amount": salary, "currency": currency}
         }
-        return self.make_request("POST", "/investments", data)
+        if employee_rate is not None:
+            data["employee_rate"] = employee_rate
+        return self.make_request("POST", "/salary-contributions", data)
+
+    def contribute(self, client_id: int, amount: str, source: Optional[str] = None,
+                   currency: str = "GBP", idempotency_key: Optional[str] = None) -> Dict[str, Any]:
+        data = {"client_id": client_id, "amount": {"amount": amount, "currency": currency}}
+        if source is not None:
+            data["source"] = source
+        return self.make_request("POST", "/contributions", data, idempotency_key)
+
+    def withdraw(self, client_id: int, fund_id: int, amount: Optional[str] = None,
+                 units: Optional[str] = None, currency: str = "GBP") -> Dict[str, Any]:
+        data = {"client_id": client_id, "fund_id": fund_id}
+        if amount is not None:
+            data["amount"] = {"amount": amount, "currency": currency}
+        if units is not None:
+            data["units"] = units
+        return self.make_request("POST", "/withdrawals", data)
+
+    def switch(self, client_id: int, from_fund_id: int, to_fund_id: int, amount: Optional[str] = None,
+               units: Optional[str] = None, percentage: Optional[str] = None, currency: str = "GBP") -> Dict[str, Any]:
+        data = {"client_id": client_id, "from_fund_id": from_fund_id, "to_fund_id": to_fund_id}
+        if amount is not None:
+            data["amount"] = {"amount": amount, "currency": currency}
+        if units is not None:
+            data["units"] = units
+        if percentage is not None:
+            data["percentage"] = percentage
+        return self.make_request("POST", "/switches", data)
+
+    def get_switch(self, switch_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/switches/{switch_id}")
 
     def get_investment(self, investment_id: int) -> Dict[str, Any]:
         return self.make_request("GET", f"/investments/{investment_id}")
 
-    def create_employer(self, name: str) -> Dict[str, Any]:
+    def create_employer(self, name: str, idempotency_key: Optional[str] = None) -> Dict[str, Any]:
         data = {"name": name}
-        return self.make_request("POST", "/employers", data) 
+        return self.make_request("POST", "/employers", data, idempotency_key)
+
+    def get_employer(self, employer_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/employers/{employer_id}")
+
+    def update_employer(self, employer_id: int, **fields: Any) -> Dict[str, Any]:
+        return self.make_request("PATCH", f"/employers/{employer_id}", fields)
+
+    def import_payroll(self, employer_id: int, payroll_csv: str) -> Dict[str, Any]:
+        url = f"{self.base_url}/api/employers/{employer_id}/payroll"
+        headers = dict(self.headers, **{"Content-Type": "text/csv"})
+        response = requests.post(url, data=payroll_csv.encode(), headers=headers, verify=False)
+        response.raise_for_status()
+        return response.json()
+
+    def get_employer_customers(self, employer_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/employers/{employer_id}/customers")
+
+    def deactivate_employer(self, employer_id: int) -> Dict[str, Any]:
+        return self.make_request("POST", f"/employers/{employer_id}/deactivate")
     
-    def get_investments_by_client(self, client_id: int) -> Dict[str, Any]:
-        return self.make_request("GET", f"/investments?client_id={client_id}")
\ No newline at end of file
+    def get_investments_by_client(self, client_id: int, status: Optional[str] = None) -> Dict[str, Any]:
+        query = f"client_id={client_id}" + (f"&status={status}" if status else "")
+        return self.make_request("GET", f"/investments?{query}")
+
+    def get_investments_by_status(self, status: str) -> Dict[str, Any]:
+        return self.make_request("GET", f"/investments?status={status}")
+
+    def update_investment_status(self, investment_id: int, status: str) -> Dict[str, Any]:
+        return self.make_request("PATCH", f"/investments/{investment_id}", {"status": status})
+
+    def cancel_investment(self, investment_id: int) -> Dict[str, Any]:
+        return self.make_request("POST", f"/investments/{investment_id}/cancel")
+
+    def run_dealing(self, dealing_date: str) -> Dict[str, Any]:
+        return self.make_request("POST", "/dealing/runs", {"dealing_date": dealing_date})
+
+    def get_dealing_batches(self, dealing_date: Optional[str] = None, fund_id: Optional[int] = None) -> Dict[str, Any]:
+        params = []
+        if dealing_date is not None:
+            params.append(f"date={dealing_date}")
+        if fund_id is not None:
+            params.append(f"fund_id={fund_id}")
+        query = f"?{'&'.join(params)}" if params else ""
+        return self.make_request("GET", f"/dealing/batches{query}")
+
+    def get_dealing_batch(self, batch_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/dealing/batches/{batch_id}")
+
+    def run_rebalancing(self) -> Dict[str, Any]:
+        return self.make_request("POST", "/rebalancing/runs")
+
+    def create_glide_path(self, name: str, stages: Dict[int, Dict[int, str]]) -> Dict[str, Any]:
+        data = {
+            "name": name,
+            "stages": [
+                {
+                    "years_to_retirement": years,
+                    "funds": [{"fund_id": fund_id, "percentage": percentage} for fund_id, percentage in funds.items()],
+                }
+                for years, funds in stages.items()
+            ],
+        }
+        return self.make_request("POST", "/glide-paths", data)
+
+    def get_glide_paths(self) -> Dict[str, Any]:
+        return self.make_request("GET", "/glide-paths")
+
+    def get_glide_path(self, glide_path_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/glide-paths/{glide_path_id}")
+
+    def run_glide_paths(self) -> Dict[str, Any]:
+        return self.make_request("POST", "/glide-paths/runs")
+
+    def create_schedule(self, customer_id: int, amount: str, frequency: str, start_date: str,
+                        fund_id: Optional[int] = None, end_date: Optional[str] = None,
+                        currency: str = "GBP") -> Dict[str, Any]:
+        data = {
+            "amount": {"amount": amount, "currency": currency},
+            "frequency": frequency,
+            "start_date": start_date,
+        }
+        if fund_id is not None:
+            data["fund_id"] = fund_id
+        if end_date is not None:
+            data["end_date"] = end_date
+        return self.make_request("POST", f"/customers/{customer_id}/schedules", data)
+
+    def get_schedules(self, customer_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/customers/{customer_id}/schedules")
+
+    def get_schedule(self, schedule_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/schedules/{schedule_id}")
+
+    def pause_schedule(self, schedule_id: int) -> Dict[str, Any]:
+        return self.make_request("POST", f"/schedules/{schedule_id}/pause")
+
+    def resume_schedule(self, schedule_id: int) -> Dict[str, Any]:
+        return self.make_request("POST", f"/schedules/{schedule_id}/resume")
+
+    def run_schedules(self) -> Dict[str, Any]:
+        return self.make_request("POST", "/schedules/runs")
+
+    def get_fund_returns(self, fund_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/funds/{fund_id}/returns")
+
+    def get_customer_returns(self, customer_id: int) -> Dict[str, Any]:
+        return self.make_request("GET", f"/customers/{customer_id}/returns")
@@ -1,6 +1,10 @@
 #!/usr/bin/env python3
 
 import json
+import uuid
+from datetime import date, timedelta
+
+import requests
 import urllib3
 from api_client import APIClient
 
@@ -34,15 +38,15 @@ def main():
     # Create multiple funds
     print("\nCreating funds...")
     
-    fund1 = client.create_fund("Fund1")
+    fund1 = client.create_fund("Fund1", "IE00B4L5Y983", "equity", 6, "0.2")
     print(f"Created fund: {json.dumps(fund1, indent=2)}")
     fund1_id = fund1["id"]
 
-    fund2 = client.create_fund("Fund2")
+    fund2 = client.create_fund("Fund2", "GB00B3X7QG63", "bond", 3, "0.1")
     print(f"Created fund: {json.dumps(fund2, indent=2)}")
     fund2_id = fund2["id"]
 
-    fund3 = client.create_fund("Fund3")
+    fund3 = client.create_fund("Fund3", "GB0002634946", "multi_asset", 4, "0.25")
     print(f"Created fund: {json.dumps(fund3, indent=2)}")
     fund3_id = fund3["id"]
 
@@ -51,6 +55,23 @@ def main():
     all_funds = client.get_all_funds()
     print(f"All funds: {json.dumps(all_funds, indent=2)}")
 
+    # Get the lower risk funds, cheapest first
+    print("\nGetting funds with a risk rating up to 4...")
+    low_risk_funds = client.get_all_funds(risk_max=4, sort="ongoing_charges")
+    print(f"Low risk funds: {json.dumps(low_risk_funds, indent=2)}")
+
+    # Price the funds so investments can be converted into units
+    print("\nPricing funds...")
+    today = date.today()
+    for fund_id, nav in ((fund1_id, "2.500000"), (fund2_id, "1.250000"), (fund3_id, "3.000000")):
+        client.add_fund_price(fund_id, (today - timedelta(days=1)).isoformat(), "1.000000")
+        price = client.add_fund_price(fund_id, today.isoformat(), nav)
+        print(f"Priced fund: {json.dumps(price, indent=2)}")
+
+    print("\nGetting the price history of Fund1...")
+    fund1_prices = client.get_fund_prices(fund1_id)
+    print(f"Fund1 prices: {json.dumps(fund1_prices, indent=2)}")
+
     # Create investments for both customers
     print("\nCreating investments...")
     
@@ -58,14 +79,14 @@ def main():
     retail_investment1 = client.create_investment(
         client_id=retail_customer_id,
         fund_id=fund1_id,
-        amount=2000.0
+        amount="2000.00"
     )
     print(f"Created retail customer investment 1: {json.dumps(retail_investment1, indent=2)}")
 
     retail_investment2 = client.create_investment(
         client_id=retail_customer_id,
         fund_id=fund3_id,
-        amount=1500.0
+        amount="1500.00"
     )
     print(f"Created retail customer investment 2: {json.dumps(retail_investment2, indent=2)}")
 
@@ -73,10 +94,106 @@ def main():
     employed_investment = client.create_investment(
         client_id=employed_customer_id,
         fund_id=fund2_id,
-        amount=3000.0
+        amount="3000.00"
     )
     print(f"Created employed customer investment: {json.dumps(employed_investment, indent=2)}")
 
+    # Tech Corp pays into its employees' pensions by salary sacrifice, matching them up to 5% of salary
+    print("\nSetting Tech Corp's contribution scheme...")
+    employer = client.update_employer(employer_id, contribution_scheme={
+        "employee_rate": "5",
+        "employer_rate": "5",
+        "matching": True,
+        "employer_cap": {"amount": "300.00", "currency": "GBP"},
+        "salary_sacrifice": True
+    })
+    print(f"Updated employer: {json.dumps(employer, indent=2)}")
+
+    print("\nPaying in the employed customer's contributions for this month's salary...")
+    salary_contributions = client.salary_contribution(employed_customer_id, fund2_id, "3500.00", employee_rate="4")
+    print(f"Created salary contributions: {json.dumps(salary_contributions, indent=2)}")
+    assert [c["source"] for c in salary_contributions] == ["salary_sacrifice", "employer"], \
+        "salary contributions weren't split between the employee and the employer"
+
+    # Tech Corp sends its monthly payroll file: a row for a customer it doesn't employ rejects the whole file
+    print("\nImporting a payroll file with a row for another employer's customer...")
+    payroll_rows = [
+        f"{employed_customer_id},{fund1_id},salary_sacrifice,175.00",
+        f"{employed_customer_id},{fund1_id},employer,105.00",
+    ]
+    bad_payroll = "contributions,total\n3,380.00\nclient_id,fund_id,source,amount\n" + "\n".join(
+        payroll_rows + [f"{retail_customer_id},{fund1_id},employee,100.00"])
+    try:
+        client.import_payroll(employer_id, bad_payroll)
+        raise AssertionError("a payroll file with an invalid row was imported")
+    except requests.exceptions.HTTPError as e:
+        assert e.response.status_code == 422, f"unexpected status {e.response.status_code}"
+        print(f"Rejected payroll file: {json.dumps(e.response.json(), indent=2)}")
+
+    print("\nImporting Tech Corp's payroll file...")
+    payroll = client.import_payroll(
+        employer_id, "contributions,total\n2,280.00\nclient_id,fund_id,source,amount\n" + "\n".join(payroll_rows))
+    print(f"Imported payroll: {json.dumps(payroll, indent=2)}")
+    assert payroll["contributions"] == 2, "not every payroll contribution was imported"
+
+    # Customers can set a target allocation once and contribute cash without choosing funds each time
+    print("\nSetting the retail customer's target allocation to 60% Fund1 and 40% Fund3...")
+    allocation = client.set_allocation(retail_customer_id, {fund1_id: "60", fund3_id: "40"})
+    print(f"Target allocation: {json.dumps(allocation, indent=2)}")
+
+    print("\nContributing 500.00 across the retail customer's allocation...")
+    contributions = client.contribute(retail_customer_id, "500.00")
+    print(f"Created contributions: {json.dumps(contributions, indent=2)}")
+    assert [(c["fund_id"], c["amount"]["amount"]) for c in contributions] == [(fund1_id, "300.00"), (fund3_id, "200.00")], \
+        "the contribution wasn't split according to the allocation"
+    assert all(c.get("isa") for c in contributions), "the retail customer's contributions weren't paid into their ISA"
+
+    # Employees who haven't chosen an allocation contribute into their employer's default fund
+    print("\nSetting Tech Corp's default fund...")
+    employer = client.update_employer(employer_id, default_fund_id=fund2_id)
+    print(f"Updated employer: {json.dumps(employer, indent=2)}")
+
+    employed_allocation = client.get_allocation(employed_customer_id)
+    print(f"Employed customer allocation: {json.dumps(employed_allocation, indent=2)}")
+    assert employed_allocation["employer_default"], "the employer's default fund wasn't used"
+
+    default_contributions = client.contribute(employed_customer_id, "150.00", source="employee")
+    print(f"Created contributions: {json.dumps(default_contributions, indent=2)}")
+    assert [c["fund_id"] for c in default_contributions] == [fund2_id], "the contribution didn't go into the default fund"
+    assert not any(c.get("isa") for c in default_contributions), "the employed customer's contribution went into an ISA"
+
+    # Retail customers invest through an ISA, so their investments count towards the annual allowance
+    print("\nGetting the retail customer's ISA allowance...")
+    isa_allowance = client.get_isa_allowance(retail_customer_id)
+    print(f"Retail customer ISA allowance: {json.dumps(isa_allowance, indent=2)}")
+
+    # Retry an investment as a client would after a timeout: the retry replays the first response
+    print("\nRetrying an investment with the same idempotency key...")
+    idempotency_key = str(uuid.uuid4())
+    first_attempt = client.create_investment(retail_customer_id, fund3_id, "100.00", idempotency_key=idempotency_key)
+    retried_attempt = client.create_investment(retail_customer_id, fund3_id, "100.00", idempotency_key=idempotency_key)
+    print(f"First attempt created investment {first_attempt['id']}, retry returned investment {retried_attempt['id']}")
+    assert first_attempt["id"] == retried_attempt["id"], "retrying with the same idempotency key created a duplicate"
+
+    # Deal the retail customer's first investment, and cancel an order they changed their mind about
+    print("\nPlacing and settling retail customer investment 1...")
+    client.update_investment_status(retail_investment1["id"], "placed")
+    settled_investment = client.update_investment_status(retail_investment1["id"], "settled")
+    print(f"Settled investment: {json.dumps(settled_investment, indent=2)}")
+
+    print("\nCancelling a pending retail customer investment...")
+    unwanted_investment = client.create_investment(
+        client_id=retail_customer_id,
+        fund_id=fund2_id,
+        amount="250.00"
+    )
+    cancelled_investment = client.cancel_investment(unwanted_investment["id"])
+    print(f"Cancelled investment: {json.dumps(cancelled_investment, indent=2)}")
+
+    print("\nGetting all pending investments...")
+    pending_investments = client.get_investments_by_status("pending")
+    print(f"Pending investments: {json.dumps(pending_investments, indent=2)}")
+
     # Get individual investments
     print("\nGetting individual investments...")
     retrieved_retail_investment1 = client.get_investment(retail_investment1["id"])
@@ -97,5 +214,152 @@ def main():
     employed_investments = client.get_investments_by_client(employed_customer_id)
     print(f"All investments for employed customer: {json.dumps(employed_investments, indent=2)}")
 
+    # Withdraw from the retail customer's holdings, once by amount and once by units
+    print("\nWithdrawing from the retail customer's holdings...")
+    withdrawal_by_amount = client.withdraw(retail_customer_id, fund1_id, amount="500.00")
+    print(f"Withdrawal by amount: {json.dumps(withdrawal_by_amount, indent=2)}")
+
+    withdrawal_by_units = client.withdraw(retail_customer_id, fund3_id, units="100.000000")
+    print(f"Withdrawal by units: {json.dumps(withdrawal_by_units, indent=2)}")
+
+    # Move half of the employed customer's holding in Fund2 into Fund1
+    print("\nSwitching half of the employed customer's Fund2 holding into Fund1...")
+    fund_switch = client.switch(employed_customer_id, fund2_id, fund1_id, percentage="50")
+    print(f"Switch: {json.dumps(fund_switch, indent=2)}")
+
+    retrieved_switch = client.get_switch(fund_switch["id"])
+    print(f"Retrieved switch: {json.dumps(retrieved_switch, indent=2)}")
+
+    # Withdrawals have pulled the retail customer's portfolio away from their 60/40 allocation. Rebalancing
+    # switches between their funds to restore it once a fund drifts further than the tolerance from its target.
+    print("\nPreviewing the retail customer's rebalance...")
+    rebalance_preview = client.preview_rebalance(retail_customer_id)
+    print(f"Rebalance preview: {json.dumps(rebalance_preview, indent=2)}")
+    assert not rebalance_preview["executed"], "previewing a rebalance made trades"
+
+    print("\nRebalancing the retail customer's portfolio...")
+    rebalance = client.rebalance(retail_customer_id)
+    print(f"Rebalance: {json.dumps(rebalance, indent=2)}")
+    assert len(rebalance["switches"] if rebalance["executed"] else rebalance["trades"]) == len(rebalance["trades"]), \
+        "the rebalance didn't make a switch for each trade"
+
+    print("\nRebalancing every customer with a target allocation...")
+    rebalancing_run = client.run_rebalancing()
+    print(f"Rebalancing run: {json.dumps(rebalancing_run, indent=2)}")
+    assert all(r["customer_id"] != retail_customer_id for r in rebalancing_run["rebalanced"]), \
+        "a portfolio that was just rebalanced was rebalanced again"
+
+    # Lifestyling moves pension savers from growth funds into lower-risk funds as they approach retirement.
+    # The server does this by itself each weekday before rebalancing.
+    print("\nCreating a glide path that de-risks over the last ten years before retirement...")
+    glide_path = client.create_glide_path("Balanced lifestyle", {
+        10: {fund1_id: "100"},
+        5: {fund1_id: "50", fund2_id: "50"},
+        0: {fund2_id: "100"},
+    })
+    print(f"Created glide path: {json.dumps(glide_path, indent=2)}")
+    assert [s["years_to_retirement"] for s in glide_path["stages"]] == [10, 5, 0], \
+        "the glide path's stages aren't ordered from the furthest from retirement"
+
+    retrieved_glide_path = client.get_glide_path(glide_path["id"])
+    print(f"Retrieved glide path: {json.dumps(retrieved_glide_path, indent=2)}")
+    print(f"All glide paths: {json.dumps(client.get_glide_paths(), indent=2)}")
+
+    print("\nOpting the employed customer into the glide path, seven years before they retire at 67...")
+    lifestyled_customer = client.update_customer(
+        employed_customer_id,
+        date_of_birth=today.replace(year=today.year - 60).isoformat(),
+        retirement_age=67,
+        glide_path_id=glide_path["id"],
+    )
+    print(f"Updated customer: {json.dumps(lifestyled_customer, indent=2)}")
+
+    print("\nMoving customers along their glide paths...")
+    glide_path_run = client.run_glide_paths()
+    print(f"Glide path run: {json.dumps(glide_path_run, indent=2)}")
+    moves = [m for m in glide_path_run["moved"] if m["customer_id"] == employed_customer_id]
+    assert moves and moves[0]["years_to_retirement"] == 7, "the employed customer wasn't moved along their glide path"
+    assert client.run_glide_paths()["moved"] == [], "a customer already on their stage was moved again"
+
+    # Contribution schedules pay in regularly without the customer doing anything. The server pays in each
+    # schedule's runs as they fall due, catching up any missed while it was down.
+    print("\nSetting up a monthly contribution into a fund for the retail customer...")
+    fund_schedule = client.create_schedule(retail_customer_id, "50.00", "monthly", today.isoformat(), fund_id=fund1_id)
+    print(f"Created schedule: {json.dumps(fund_schedule, indent=2)}")
+
+    print("\nSetting up a weekly contribution across the employed customer's allocation...")
+    allocation_schedule = client.create_schedule(
+        employed_customer_id, "25.00", "weekly", today.isoformat(),
+        end_date=(today + timedelta(days=30)).isoformat(),
+    )
+    print(f"Created schedule: {json.dumps(allocation_schedule, indent=2)}")
+
+    print("\nPaying in the contribution schedules' runs that are due...")
+    schedule_run = client.run_schedules()
+    print(f"Schedule run: {json.dumps(schedule_run, indent=2)}")
+    paid_in = {c["schedule_id"] for c in schedule_run["contributions"]}
+    assert {fund_schedule["id"], allocation_schedule["id"]} <= paid_in, "today's scheduled contributions weren't paid in"
+    assert not schedule_run["skipped"] and not schedule_run["failed"], "a scheduled contribution wasn't paid in"
+    assert all(
+        investment["schedule_id"] == c["schedule_id"] and investment["run_date"] == c["run_date"]
+        for c in schedule_run["contributions"] for investment in c["investments"]
+    ), "scheduled investments don't record the run they pay in"
+    assert not {c["schedule_id"] for c in client.run_schedules()["contributions"]} & paid_in, \
+        "a scheduled contribution was paid in twice"
+
+    retrieved_schedule = client.get_schedule(fund_schedule["id"])
+    print(f"Retrieved schedule: {json.dumps(retrieved_schedule, indent=2)}")
+    assert retrieved_schedule["next_run_date"] > today.isoformat(), "the schedule wasn't moved on to its next run"
+    print(f"Retail customer's schedules: {json.dumps(client.get_schedules(retail_customer_id), indent=2)}")
+
+    print("\nPausing and resuming the retail customer's schedule...")
+    paused_schedule = client.pause_schedule(fund_schedule["id"])
+    print(f"Paused schedule: {json.dumps(paused_schedule, indent=2)}")
+    resumed_schedule = client.resume_schedule(fund_schedule["id"])
+    print(f"Resumed schedule: {json.dumps(resumed_schedule, indent=2)}")
+    assert resumed_schedule["status"] == "active", "the schedule wasn't resumed"
+
+    # Deal today's pending orders in one batch per fund. The server does this by itself once the cut-off has
+    # passed, so the run is refused before the cut-off and at weekends.
+    print("\nDealing today's pending orders...")
+    try:
+        batches = client.run_dealing(today.isoformat())
+        print(f"Dealing batches: {json.dumps(batches, indent=2)}")
+    except requests.exceptions.HTTPError:
+        print("Orders can't be dealt yet, they stay pending until the cut-off")
+
+    todays_batches = client.get_dealing_batches(dealing_date=today.isoformat())
+    print(f"Today's dealing batches: {json.dumps(todays_batches, indent=2)}")
+    if todays_batches:
+        batch = client.get_dealing_batch(todays_batches[0]["id"])
+        print(f"Retrieved dealing batch: {json.dumps(batch, indent=2)}")
+
+    # Value each customer's portfolio at the latest fund prices
+    print("\nGetting the retail customer's portfolio...")
+    retail_portfolio = client.get_portfolio(retail_customer_id)
+    print(f"Retail customer portfolio: {json.dumps(retail_portfolio, indent=2)}")
+
+    print("\nGetting the employed customer's portfolio...")
+    employed_portfolio = client.get_portfolio(employed_customer_id)
+    print(f"Employed customer portfolio: {json.dumps(employed_portfolio, indent=2)}")
+
+    # Funds' returns are time-weighted from their prices; customers' are money-weighted from what they paid in
+    # and took out. Periods that start before the history does are reported as partial.
+    print("\nGetting Fund1's returns...")
+    fund_returns = client.get_fund_returns(fund1_id)
+    print(f"Fund1 returns: {json.dumps(fund_returns, indent=2)}")
+    assert [r["period"] for r in fund_returns["returns"]] == ["1M", "3M", "YTD", "1Y", "since_inception"], \
+        "the fund's returns aren't reported over every standard period"
+    since_inception = fund_returns["returns"][-1]
+    assert since_inception["start_date"] == (today - timedelta(days=1)).isoformat(), \
+        "the fund's return since inception doesn't start at its first price"
+    assert since_inception["return"] == "150.000", "Fund1 didn't return 150% from 1.00 to 2.50"
+
+    print("\nGetting the retail customer's returns...")
+    customer_returns = client.get_customer_returns(retail_customer_id)
+    print(f"Retail customer returns: {json.dumps(customer_returns, indent=2)}")
+    assert all(r["partial"] for r in customer_returns["returns"] if r["period"] != "since_inception"), \
+        "returns over periods longer than the customer's history aren't reported as partial"
+
 if __name__ == "__main__":
     main() 
\ No newline at end of file